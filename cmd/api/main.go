@@ -3,21 +3,44 @@ package main
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/yourusername/gobank/internal/adapter/handler"
+	"github.com/yourusername/gobank/internal/adapter/mailer"
 	"github.com/yourusername/gobank/internal/adapter/repository/postgres"
 	redisRepo "github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/domain/service"
 	"github.com/yourusername/gobank/internal/infrastructure/config"
 	"github.com/yourusername/gobank/internal/infrastructure/database"
 	"github.com/yourusername/gobank/internal/infrastructure/logger"
 	"github.com/yourusername/gobank/internal/infrastructure/server"
+	"github.com/yourusername/gobank/internal/infrastructure/tracing"
+	"github.com/yourusername/gobank/internal/pkg/accountnumber"
+	"github.com/yourusername/gobank/internal/pkg/fxrate"
+	"github.com/yourusername/gobank/internal/pkg/memo"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
 	"github.com/yourusername/gobank/internal/pkg/password"
+	"github.com/yourusername/gobank/internal/pkg/secretbox"
 	"github.com/yourusername/gobank/internal/pkg/token"
 	"github.com/yourusername/gobank/internal/pkg/validator"
 	accountUsecase "github.com/yourusername/gobank/internal/usecase/account"
+	"github.com/yourusername/gobank/internal/usecase/accountcache"
+	alertUsecase "github.com/yourusername/gobank/internal/usecase/alert"
+	"github.com/yourusername/gobank/internal/usecase/apikey"
+	auditUsecase "github.com/yourusername/gobank/internal/usecase/audit"
+	"github.com/yourusername/gobank/internal/usecase/cleanup"
+	"github.com/yourusername/gobank/internal/usecase/compliance"
+	"github.com/yourusername/gobank/internal/usecase/events"
+	feeUsecase "github.com/yourusername/gobank/internal/usecase/fee"
+	fxUsecase "github.com/yourusername/gobank/internal/usecase/fx"
+	"github.com/yourusername/gobank/internal/usecase/outbox"
+	"github.com/yourusername/gobank/internal/usecase/ownership"
+	recurringUsecase "github.com/yourusername/gobank/internal/usecase/recurring"
+	statsUsecase "github.com/yourusername/gobank/internal/usecase/stats"
 	transferUsecase "github.com/yourusername/gobank/internal/usecase/transfer"
 	userUsecase "github.com/yourusername/gobank/internal/usecase/user"
+	"github.com/yourusername/gobank/internal/usecase/webhook"
 )
 
 func main() {
@@ -29,79 +52,380 @@ func main() {
 	appLogger := logger.New(cfg.Server.Environment)
 	appLogger.Info().Str("environment", cfg.Server.Environment).Msg("Starting GoBank API")
 
+	accountNumbers, err := accountnumber.NewFormat(cfg.Account.NumberPrefix, cfg.Account.NumberLength, cfg.Account.NumberCheckDigit)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Invalid account number format")
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	shutdownTracing, err := tracing.Setup(ctx, &cfg.Tracing)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+
 	db, err := database.NewPostgresDB(ctx, &cfg.Database)
 	if err != nil {
 		appLogger.Fatal().Err(err).Msg("Failed to connect to PostgreSQL")
 	}
-	defer db.Close()
 	appLogger.Info().Msg("Connected to PostgreSQL")
+	metrics.RegisterDBPool(db.Pool)
 
 	redisDB, err := database.NewRedisDB(ctx, &cfg.Redis)
 	if err != nil {
 		appLogger.Fatal().Err(err).Msg("Failed to connect to Redis")
 	}
-	defer redisDB.Close()
 	appLogger.Info().Msg("Connected to Redis")
 
 	userRepo := postgres.NewUserRepository(db)
 	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
-	accountRepo := postgres.NewAccountRepository(db)
+	resetTokenRepo := postgres.NewPasswordResetTokenRepository(db)
+	accountRepo := postgres.NewAccountRepository(db, accountNumbers)
 	transactionRepo := postgres.NewTransactionRepository(db)
 	transferRepo := postgres.NewTransferRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	auditLogRepo := postgres.NewAuditLogRepository(db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
+	recurringRepo := postgres.NewRecurringTransferRepository(db)
+	allowlistRepo := postgres.NewAllowedDestinationRepository(db)
+	balanceAlertRepo := postgres.NewBalanceAlertRepository(db)
+	webhookRepo := postgres.NewWebhookRepository(db)
+	webhookDeliveryRepo := postgres.NewWebhookDeliveryRepository(db)
 
-	passwordHasher := password.NewHasher()
+	passwordHasher := password.NewHasherWithCost(cfg.Auth.PasswordBcryptCost)
 
-	jwtManager := token.NewJWTManager(
-		cfg.JWT.SecretKey,
-		cfg.JWT.AccessTokenExpiry,
-		cfg.JWT.RefreshTokenExpiry,
-		cfg.JWT.Issuer,
-	)
+	jwtKeys, err := token.ParseKeys(cfg.JWT.AdditionalKeys)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Invalid JWT_ADDITIONAL_KEYS")
+	}
+	var jwtManager token.JWTManager
+	switch cfg.JWT.Algorithm {
+	case "HS256":
+		jwtManager = token.NewJWTManager(
+			cfg.JWT.KeyID,
+			cfg.JWT.SecretKey,
+			jwtKeys,
+			cfg.JWT.AccessTokenExpiry,
+			cfg.JWT.RefreshTokenExpiry,
+			cfg.JWT.Issuer,
+		)
+	case "RS256":
+		privateKey, err := token.LoadRSAPrivateKey(cfg.JWT.PrivateKeyFile)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Invalid JWT_PRIVATE_KEY_FILE")
+		}
+		publicKeys, err := token.LoadRSAPublicKeys(jwtKeys)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Invalid JWT_ADDITIONAL_KEYS")
+		}
+		jwtManager = token.NewRS256JWTManager(
+			cfg.JWT.KeyID,
+			privateKey,
+			publicKeys,
+			cfg.JWT.AccessTokenExpiry,
+			cfg.JWT.RefreshTokenExpiry,
+			cfg.JWT.Issuer,
+		)
+	default:
+		appLogger.Fatal().Str("algorithm", cfg.JWT.Algorithm).Msg("Invalid JWT_ALGORITHM: want HS256 or RS256")
+	}
+
+	serviceTokens := token.NewServiceTokenManager(cfg.RateLimit.ServiceTokenSecret, cfg.RateLimit.ServiceTokenMaxAge)
+
+	totpSecrets, err := secretbox.New(cfg.Auth.TOTPEncryptionKey)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Invalid TOTP_ENCRYPTION_KEY")
+	}
 
 	validatorInstance := validator.New()
 
-	rateLimiter := redisRepo.NewRateLimiter(redisDB, cfg.RateLimit.RequestsPerMinute)
+	rateLimitAlgorithm := redisRepo.ParseRateLimitAlgorithm(cfg.RateLimit.Algorithm)
+	rateLimiter := redisRepo.NewRateLimiter(redisDB, cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.BurstSize, rateLimitAlgorithm)
+	// With the token bucket, own-account transfers may burst a full minute's allowance.
+	internalLimiter := redisRepo.NewRateLimiter(redisDB, cfg.RateLimit.InternalTransferRequestsPerMinute, 0, rateLimitAlgorithm)
+	maintenanceMode := redisRepo.NewMaintenanceMode(redisDB, cfg.Maintenance.Enabled)
+	idempotencyStore := redisRepo.NewIdempotencyStore(redisDB, cfg.Redis.IdempotencyTTL, cfg.Redis.IdempotencyLockTTL)
+
+	cacheRepo := redisRepo.NewCacheRepository(redisDB)
+
+	auditService := auditUsecase.NewAuditService(auditLogRepo, appLogger)
+
+	appMailer := mailer.NewLogMailer(appLogger)
 
 	userService := userUsecase.NewUserService(
 		userRepo,
 		refreshTokenRepo,
+		resetTokenRepo,
+		auditService,
+		cacheRepo,
+		appMailer,
 		passwordHasher,
 		jwtManager,
+		totpSecrets,
 		cfg,
+		appLogger,
 	)
 
+	openingDeposits, err := accountUsecase.ParseOpeningDepositMinimums(cfg.Account.MinOpeningDeposit)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Invalid ACCOUNT_MIN_OPENING_DEPOSIT")
+	}
+
+	ownershipChecker := ownership.NewChecker(accountRepo, cacheRepo, int(cfg.Redis.OwnershipCacheTTL.Seconds()))
+	accountCache := accountcache.NewCache(accountRepo, cacheRepo, int(cfg.Redis.AccountCacheTTL.Seconds()))
+
+	balanceAlertService := alertUsecase.NewBalanceAlertService(balanceAlertRepo, accountRepo, outboxRepo, auditService)
+
 	accountService := accountUsecase.NewAccountService(
 		accountRepo,
 		transactionRepo,
+		transferRepo,
+		allowlistRepo,
+		outboxRepo,
+		ownershipChecker,
+		accountCache,
+		balanceAlertService,
+		db,
+		auditService,
+		openingDeposits,
 	)
 
+	var rateProvider service.FXRateProvider
+	switch cfg.FX.Provider {
+	case "http":
+		rateProvider = fxrate.NewHTTP(cfg.FX.ProviderURL, cfg.FX.ProviderTimeout)
+	default:
+		staticRates, err := fxrate.ParseStatic(cfg.FX.StaticRates)
+		if err != nil {
+			appLogger.Fatal().Err(err).Msg("Invalid FX_STATIC_RATES")
+		}
+		rateProvider = staticRates
+	}
+	allowedPairs, err := fxUsecase.ParsePairs(cfg.FX.AllowedPairs)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Invalid FX_ALLOWED_PAIRS")
+	}
+	fxService := fxUsecase.NewFXService(rateProvider, cacheRepo, cfg.FX.RateTTL, allowedPairs)
+
+	feeSchedule, err := feeUsecase.ParseSchedule(cfg.Transfer.Fees)
+	if err != nil {
+		appLogger.Fatal().Err(err).Msg("Invalid TRANSFER_FEES")
+	}
+	feeService := feeUsecase.NewFeeService(feeSchedule)
+
+	memoSanitizer := memo.NewRegexSanitizer(memo.ParseMode(cfg.Transfer.MemoFilterMode), cfg.Transfer.MemoBlocklist)
+
 	transferService := transferUsecase.NewTransferService(
 		accountRepo,
 		transferRepo,
 		transactionRepo,
+		outboxRepo,
+		allowlistRepo,
+		fxService,
+		feeService,
 		db,
+		ownershipChecker,
+		accountCache,
+		balanceAlertService,
+		auditService,
+		memoSanitizer,
+		accountNumbers,
+		cfg,
+	)
+
+	recurringService := recurringUsecase.NewRecurringTransferService(
+		recurringRepo,
+		accountRepo,
+		transferRepo,
+		allowlistRepo,
+		db,
+		auditService,
+		memoSanitizer,
+		cfg,
+	)
+
+	apiKeyService := apikey.NewAPIKeyService(apiKeyRepo, userRepo, auditService)
+
+	statsService := statsUsecase.NewStatsService(accountRepo, cacheRepo, int(cfg.Redis.StatsCacheTTL.Seconds()))
+
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, webhookDeliveryRepo, webhook.DispatcherConfig{
+		Interval:            cfg.Webhook.DispatchInterval,
+		BatchSize:           cfg.Webhook.BatchSize,
+		Timeout:             cfg.Webhook.Timeout,
+		MaxAttempts:         cfg.Webhook.MaxAttempts,
+		RetryBackoff:        cfg.Webhook.RetryBackoff,
+		MaxRetryBackoff:     cfg.Webhook.MaxRetryBackoff,
+		AllowPrivateTargets: cfg.Webhook.AllowPrivateTargets,
+	}, appLogger)
+	webhookService := webhook.NewWebhookService(
+		webhookRepo,
+		webhookDeliveryRepo,
+		webhookDispatcher,
+		auditService,
+		cfg.Webhook.MaxPerUser,
+		cfg.Webhook.AllowHTTP,
+		cfg.Webhook.AllowPrivateTargets,
 	)
 
+	complianceService := compliance.NewComplianceService(accountRepo, transferRepo, auditService, cfg.Compliance.ExportSigningKey)
+
 	userHandler := handler.NewUserHandler(userService, validatorInstance)
 	accountHandler := handler.NewAccountHandler(accountService, validatorInstance)
 	transferHandler := handler.NewTransferHandler(transferService, validatorInstance)
+	recurringHandler := handler.NewRecurringTransferHandler(recurringService, validatorInstance)
+	balanceAlertHandler := handler.NewBalanceAlertHandler(balanceAlertService, validatorInstance)
+	transactionHandler := handler.NewTransactionHandler(accountService)
+	adminHandler := handler.NewAdminHandler(accountService, userService, statsService, validatorInstance)
+	adminUserHandler := handler.NewAdminUserHandler(userService, validatorInstance)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, validatorInstance)
+	webhookHandler := handler.NewWebhookHandler(webhookService, validatorInstance)
+	auditHandler := handler.NewAuditHandler(auditService)
+	complianceHandler := handler.NewComplianceHandler(complianceService, cfg.Server.WriteTimeout)
+	rateLimitHandler := handler.NewRateLimitHandler(rateLimiter)
+	eventHub := events.NewHub(
+		cfg.Events.BufferSize,
+		cfg.Events.MaxStreamsPerUser,
+		events.ParsePolicy(cfg.Events.SlowConsumerPolicy),
+		appLogger,
+	)
+	eventHandler := handler.NewEventHandler(eventHub, cfg.Events.HeartbeatInterval, cfg.Server.WriteTimeout)
 	healthHandler := handler.NewHealthHandler(db, redisDB)
+	jwksHandler := handler.NewJWKSHandler(jwtManager)
+
+	sweepers := []cleanup.Sweeper{
+		{
+			Name:      "refresh_token",
+			Retention: cfg.Cleanup.RefreshTokenRetention,
+			Sweep:     refreshTokenRepo.DeleteExpired,
+		},
+		{
+			Name:      "password_reset_token",
+			Retention: cfg.Cleanup.PasswordResetTokenRetention,
+			Sweep:     resetTokenRepo.DeleteExpired,
+		},
+		{
+			Name:      "idempotency_key",
+			Retention: cfg.Cleanup.IdempotencyKeyRetention,
+			Sweep:     transferRepo.ClearIdempotencyKeys,
+		},
+		{
+			Name:      "outbox_event",
+			Retention: cfg.Cleanup.OutboxRetention,
+			Sweep:     outboxRepo.DeletePublished,
+		},
+		{
+			Name:      "webhook_delivery",
+			Retention: cfg.Cleanup.WebhookRetention,
+			Sweep:     webhookDeliveryRepo.DeleteFinished,
+		},
+	}
+	if cfg.Account.DormancyPeriod > 0 {
+		sweepers = append(sweepers, cleanup.Sweeper{
+			Name:      "dormant_account",
+			Retention: cfg.Account.DormancyPeriod,
+			Sweep:     accountRepo.FreezeDormant,
+		})
+	}
+
+	cleanupJob := cleanup.NewJob(cfg.Cleanup.Interval, appLogger, sweepers...)
+
+	// Background workers run until shutdown, which waits for them to exit
+	// before closing the pools they use.
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	runWorker := func(start func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			start(jobCtx)
+		}()
+	}
+
+	runWorker(cleanupJob.Start)
+
+	eventBus := redisRepo.NewEventBus(redisDB, appLogger)
+	runWorker(func(ctx context.Context) {
+		eventBus.Subscribe(ctx, eventHub.Dispatch)
+	})
+
+	outboxRelay := outbox.NewRelay(
+		outboxRepo,
+		db,
+		outbox.NewFanoutPublisher(
+			outbox.NewLogPublisher(appLogger),
+			eventBus,
+			alertUsecase.NewEmailNotifier(userRepo, appMailer),
+			webhookService,
+		),
+		cfg.Outbox.RelayInterval,
+		cfg.Outbox.BatchSize,
+		appLogger,
+	)
+	runWorker(outboxRelay.Start)
+	runWorker(webhookDispatcher.Start)
+
+	transferScheduler := transferUsecase.NewScheduler(
+		transferService,
+		cfg.Transfer.SchedulerInterval,
+		cfg.Transfer.SchedulerBatchSize,
+		appLogger,
+	)
+	runWorker(transferScheduler.Start)
+
+	recurringScheduler := recurringUsecase.NewScheduler(
+		recurringService,
+		cfg.Transfer.SchedulerInterval,
+		cfg.Transfer.SchedulerBatchSize,
+		appLogger,
+	)
+	runWorker(recurringScheduler.Start)
 
 	srv := server.NewServer(&server.ServerDeps{
-		Config:          cfg,
-		Logger:          appLogger,
-		UserHandler:     userHandler,
-		AccountHandler:  accountHandler,
-		TransferHandler: transferHandler,
-		HealthHandler:   healthHandler,
-		JWTManager:      jwtManager,
-		RateLimiter:     rateLimiter,
+		Config:              cfg,
+		Logger:              appLogger,
+		UserHandler:         userHandler,
+		AccountHandler:      accountHandler,
+		TransferHandler:     transferHandler,
+		RecurringHandler:    recurringHandler,
+		BalanceAlertHandler: balanceAlertHandler,
+		TransactionHandler:  transactionHandler,
+		AdminHandler:        adminHandler,
+		AdminUserHandler:    adminUserHandler,
+		APIKeyHandler:       apiKeyHandler,
+		WebhookHandler:      webhookHandler,
+		AuditHandler:        auditHandler,
+		ComplianceHandler:   complianceHandler,
+		RateLimitHandler:    rateLimitHandler,
+		EventHandler:        eventHandler,
+		HealthHandler:       healthHandler,
+		JWKSHandler:         jwksHandler,
+		JWTManager:          jwtManager,
+		ServiceTokens:       serviceTokens,
+		RateLimiter:         rateLimiter,
+		InternalLimiter:     internalLimiter,
+		Maintenance:         maintenanceMode,
+		Idempotency:         idempotencyStore,
+		AccountService:      accountService,
+		UserService:         userService,
+		APIKeyService:       apiKeyService,
+		TxManager:           db,
+		ShutdownHooks:       []func(){eventHub.Close},
+		StopWorkers: func() {
+			stopJobs()
+			workers.Wait()
+		},
+		DB:    db,
+		Redis: redisDB,
 	})
 
 	if err := srv.Run(); err != nil {
 		appLogger.Fatal().Err(err).Msg("Server error")
 	}
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		appLogger.Error().Err(err).Msg("Failed to flush traces")
+	}
 }
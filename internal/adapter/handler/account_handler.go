@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"encoding/csv"
+	"fmt"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -39,10 +41,7 @@ func (h *AccountHandler) Create(c *gin.Context) {
 	}
 
 	if errors := h.validator.Validate(&input); len(errors) > 0 {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"error":  apperror.ErrValidation,
-			"errors": errors,
-		})
+		validationFailed(c, errors)
 		return
 	}
 
@@ -52,7 +51,7 @@ func (h *AccountHandler) Create(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, account.ToResponse())
+	c.JSON(http.StatusCreated, account.ToResponse(amountFormat(c)))
 }
 
 func (h *AccountHandler) GetByID(c *gin.Context) {
@@ -75,7 +74,229 @@ func (h *AccountHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, account.ToResponse())
+	c.JSON(http.StatusOK, account.ToResponse(amountFormat(c)))
+}
+
+func (h *AccountHandler) UpdateSettings(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountIDStr := c.Param("id")
+	accountID, err := uuid.Parse(accountIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.UpdateAccountSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	account, err := h.accountService.UpdateSettings(c.Request.Context(), userID.(uuid.UUID), accountID, &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse(amountFormat(c)))
+}
+
+func (h *AccountHandler) UpdateLimits(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountIDStr := c.Param("id")
+	accountID, err := uuid.Parse(accountIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.UpdateAccountLimitsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	account, err := h.accountService.UpdateLimits(c.Request.Context(), userID.(uuid.UUID), accountID, &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse(amountFormat(c)))
+}
+
+func (h *AccountHandler) UpdateStatus(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountIDStr := c.Param("id")
+	accountID, err := uuid.Parse(accountIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.UpdateAccountStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	account, err := h.accountService.UpdateStatus(c.Request.Context(), userID.(uuid.UUID), accountID, input.Status)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse(amountFormat(c)))
+}
+
+// RequestReactivation asks an admin to lift a dormancy freeze.
+func (h *AccountHandler) RequestReactivation(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountIDStr := c.Param("id")
+	accountID, err := uuid.Parse(accountIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	account, err := h.accountService.RequestReactivation(c.Request.Context(), userID.(uuid.UUID), accountID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, account.ToResponse(amountFormat(c)))
+}
+
+// ListAllowedDestinations returns the accounts this account may pay. An empty
+// list means transfers out of the account are unrestricted.
+func (h *AccountHandler) ListAllowedDestinations(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	destinations, err := h.accountService.ListAllowedDestinations(c.Request.Context(), userID.(uuid.UUID), accountID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": destinations})
+}
+
+func (h *AccountHandler) AddAllowedDestination(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.AddAllowedDestinationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	destinations, err := h.accountService.AddAllowedDestination(c.Request.Context(), userID.(uuid.UUID), accountID, &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": destinations})
+}
+
+func (h *AccountHandler) RemoveAllowedDestination(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	destinationID, err := uuid.Parse(c.Param("destinationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if err := h.accountService.RemoveAllowedDestination(c.Request.Context(), userID.(uuid.UUID), accountID, destinationID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Close closes an account with a zero balance. Its history is kept.
+func (h *AccountHandler) Close(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if err := h.accountService.Close(c.Request.Context(), userID.(uuid.UUID), accountID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 func (h *AccountHandler) List(c *gin.Context) {
@@ -85,8 +306,7 @@ func (h *AccountHandler) List(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	page, pageSize := pageParams(c)
 
 	accounts, total, err := h.accountService.GetByUserID(c.Request.Context(), userID.(uuid.UUID), page, pageSize)
 	if err != nil {
@@ -94,20 +314,13 @@ func (h *AccountHandler) List(c *gin.Context) {
 		return
 	}
 
+	format := amountFormat(c)
 	responses := make([]*entity.AccountResponse, len(accounts))
 	for i, account := range accounts {
-		responses[i] = account.ToResponse()
+		responses[i] = account.ToResponse(format)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": responses,
-		"pagination": gin.H{
-			"page":       page,
-			"page_size":  pageSize,
-			"total":      total,
-			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
-		},
-	})
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
 }
 
 func (h *AccountHandler) GetTransactions(c *gin.Context) {
@@ -124,27 +337,240 @@ func (h *AccountHandler) GetTransactions(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	page, pageSize := pageParams(c)
+
+	filter, ok := parseTransactionFilter(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if usesCursor(c) {
+		h.getTransactionsByCursor(c, userID.(uuid.UUID), accountID, filter, pageSize)
+		return
+	}
+
+	transactions, total, err := h.accountService.GetTransactions(c.Request.Context(), userID.(uuid.UUID), accountID, filter, page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.TransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		responses[i] = tx.ToResponse(format)
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+// GetTransaction returns one transaction on the account, with the transfer
+// it belongs to, if any.
+func (h *AccountHandler) GetTransaction(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("transactionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	detail, err := h.accountService.GetAccountTransaction(c.Request.Context(), userID.(uuid.UUID), accountID, transactionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail.ToResponse(amountFormat(c)))
+}
 
-	transactions, total, err := h.accountService.GetTransactions(c.Request.Context(), userID.(uuid.UUID), accountID, page, pageSize)
+func (h *AccountHandler) getTransactionsByCursor(c *gin.Context, userID, accountID uuid.UUID, filter *entity.TransactionFilter, pageSize int) {
+	transactions, next, err := h.accountService.GetTransactionsCursor(c.Request.Context(), userID, accountID, filter, c.Query(cursorParam), pageSize)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
+	format := amountFormat(c)
 	responses := make([]*entity.TransactionResponse, len(transactions))
 	for i, tx := range transactions {
-		responses[i] = tx.ToResponse()
+		responses[i] = tx.ToResponse(format)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": responses,
-		"pagination": gin.H{
-			"page":       page,
-			"page_size":  pageSize,
-			"total":      total,
-			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
-		},
+	c.JSON(http.StatusOK, NewCursorPage(responses, next))
+}
+
+// SpendingSummary totals the account's debits by category between the
+// optional start and end (RFC 3339). The range defaults to the current
+// calendar month.
+func (h *AccountHandler) SpendingSummary(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := now
+	if v := c.Query("start"); v != "" {
+		if start, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+	}
+	if v := c.Query("end"); v != "" {
+		if end, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+	}
+
+	summary, err := h.accountService.SpendingSummary(c.Request.Context(), userID.(uuid.UUID), accountID, start.UTC(), end.UTC())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary.ToResponse(amountFormat(c)))
+}
+
+// Statement streams a CSV statement of the account's transactions between the
+// optional start_date and end_date, oldest first, framed by opening and
+// closing balance rows.
+func (h *AccountHandler) Statement(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	filter, ok := parseTransactionFilter(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+	start, end := filter.DateRange()
+
+	statement, err := h.accountService.GetStatement(c.Request.Context(), userID.(uuid.UUID), accountID, start, end)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%s-%s-%s.csv", statement.AccountNumber,
+		statement.StartDate.Format("20060102"), statement.EndDate.Format("20060102"))
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	format := amountFormat(c)
+	scale := statement.Currency.DisplayScale()
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"date", "type", "amount", "balance_after", "description"})
+	_ = writer.Write([]string{
+		statement.StartDate.Format(time.RFC3339),
+		"opening_balance",
+		"",
+		format.Format(statement.OpeningBalance, scale),
+		"Opening balance",
+	})
+
+	count := 0
+	err = h.accountService.StreamStatement(c.Request.Context(), statement, func(tx *entity.Transaction) error {
+		if err := writer.Write([]string{
+			tx.CreatedAt.UTC().Format(time.RFC3339),
+			string(tx.Type),
+			format.Format(tx.Amount, scale),
+			format.Format(tx.BalanceAfter, scale),
+			tx.Description,
+		}); err != nil {
+			return err
+		}
+
+		count++
+		if count%100 == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
 	})
+	if err != nil {
+		writer.Flush()
+		_ = c.Error(err)
+		return
+	}
+
+	_ = writer.Write([]string{
+		statement.EndDate.Format(time.RFC3339),
+		"closing_balance",
+		"",
+		format.Format(statement.ClosingBalance, scale),
+		"Closing balance",
+	})
+	writer.Flush()
+}
+
+// parseTransactionFilter reads the optional start_date and end_date (RFC3339)
+// query parameters. It reports false if either is malformed or the range is
+// inverted.
+func parseTransactionFilter(c *gin.Context) (*entity.TransactionFilter, bool) {
+	filter := &entity.TransactionFilter{}
+
+	if v := c.Query("start_date"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, false
+		}
+		t = t.UTC()
+		filter.StartDate = &t
+	}
+
+	if v := c.Query("end_date"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, false
+		}
+		t = t.UTC()
+		filter.EndDate = &t
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return nil, false
+	}
+
+	if v := c.Query("category"); v != "" {
+		filter.Category = entity.NormalizeCategory(v)
+	}
+
+	return filter, true
 }
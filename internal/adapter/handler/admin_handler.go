@@ -0,0 +1,395 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+const (
+	maxImportBytes = 5 << 20
+	maxImportRows  = 5000
+)
+
+type AdminHandler struct {
+	accountService service.AccountService
+	userService    service.UserService
+	statsService   service.StatsService
+	validator      validator.Validator
+}
+
+func NewAdminHandler(accountService service.AccountService, userService service.UserService, statsService service.StatsService, validator validator.Validator) *AdminHandler {
+	return &AdminHandler{
+		accountService: accountService,
+		userService:    userService,
+		statsService:   statsService,
+		validator:      validator,
+	}
+}
+
+// ImportTransactions applies reconciliation adjustments from a CSV with a
+// header row of type,amount[,description]. The file may be sent as the
+// "file" multipart field or as the raw request body. With dry_run=true the
+// rows are validated against the current balance but nothing is written.
+func (h *AdminHandler) ImportTransactions(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountIDStr := c.Param("id")
+	accountID, err := uuid.Parse(accountIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	middleware.SetBodyLimit(c, maxImportBytes)
+
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	rows, err := parseImportCSV(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.New("INVALID_CSV", err.Error(), http.StatusBadRequest)})
+		return
+	}
+
+	report, err := h.accountService.ImportTransactions(c.Request.Context(), adminID.(uuid.UUID), accountID, rows, dryRun)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	} else if dryRun {
+		status = http.StatusOK
+	}
+
+	c.JSON(status, report)
+}
+
+// SuspendUser blocks a user from logging in and revokes their sessions. The
+// JSON body with a reason is optional.
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.SuspendUserInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	user, err := h.userService.Suspend(c.Request.Context(), adminID.(uuid.UUID), userID, input.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	user, err := h.userService.Reactivate(c.Request.Context(), adminID.(uuid.UUID), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ForceLogout ends all of a user's sessions, e.g. when their account is
+// compromised.
+func (h *AdminHandler) ForceLogout(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	count, err := h.userService.ForceLogout(c.Request.Context(), adminID.(uuid.UUID), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":             "User logged out from all devices",
+		"sessions_terminated": count,
+	})
+}
+
+// SearchAccounts lists accounts across all users, optionally filtered by
+// status, currency, min_balance and user_id.
+func (h *AdminHandler) SearchAccounts(c *gin.Context) {
+	filter, ok := parseAccountSearchFilter(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	page, pageSize := pageParams(c)
+
+	accounts, total, err := h.accountService.Search(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.AdminAccountResponse, len(accounts))
+	for i, account := range accounts {
+		responses[i] = account.ToAdminResponse(format)
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+// parseAccountSearchFilter reads the optional account search parameters. It
+// reports false if any of them is malformed.
+func parseAccountSearchFilter(c *gin.Context) (*entity.AccountSearchFilter, bool) {
+	filter := &entity.AccountSearchFilter{}
+
+	if v := c.Query("status"); v != "" {
+		status := entity.AccountStatus(v)
+		if !status.IsValid() {
+			return nil, false
+		}
+		filter.Status = &status
+	}
+
+	if v := c.Query("currency"); v != "" {
+		currency := entity.Currency(strings.ToUpper(v))
+		if !currency.IsValid() {
+			return nil, false
+		}
+		filter.Currency = &currency
+	}
+
+	if v := c.Query("min_balance"); v != "" {
+		minBalance, err := decimal.NewFromString(v)
+		if err != nil {
+			return nil, false
+		}
+		filter.MinBalance = &minBalance
+	}
+
+	if v := c.Query("user_id"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			return nil, false
+		}
+		filter.UserID = &userID
+	}
+
+	return filter, true
+}
+
+func (h *AdminHandler) ListReactivationRequests(c *gin.Context) {
+	page, pageSize := pageParams(c)
+
+	accounts, total, err := h.accountService.GetPendingReactivations(c.Request.Context(), page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.AccountResponse, len(accounts))
+	for i, account := range accounts {
+		responses[i] = account.ToResponse(format)
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+func (h *AdminHandler) ApproveReactivation(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	account, err := h.accountService.ApproveReactivation(c.Request.Context(), adminID.(uuid.UUID), accountID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse(amountFormat(c)))
+}
+
+// UpdateBalanceRules sets an account's minimum balance and overdraft limit.
+func (h *AdminHandler) UpdateBalanceRules(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.UpdateBalanceRulesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	account, err := h.accountService.UpdateBalanceRules(c.Request.Context(), adminID.(uuid.UUID), accountID, &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account.ToResponse(amountFormat(c)))
+}
+
+func (h *AdminHandler) BalanceStats(c *gin.Context) {
+	aggregates, err := h.statsService.BalancesByCurrency(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.BalanceAggregateResponse, len(aggregates))
+	for i, aggregate := range aggregates {
+		responses[i] = aggregate.ToResponse(format)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": responses})
+}
+
+func parseImportCSV(r io.Reader) ([]*entity.TransactionImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("missing CSV header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["type"]; !ok {
+		return nil, errors.New("CSV header must include a type column")
+	}
+	if _, ok := columns["amount"]; !ok {
+		return nil, errors.New("CSV header must include an amount column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []*entity.TransactionImportRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, errors.New("CSV has too many rows")
+		}
+
+		rows = append(rows, &entity.TransactionImportRow{
+			Line:        line,
+			Type:        field(record, "type"),
+			Amount:      field(record, "amount"),
+			Description: field(record, "description"),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("CSV has no rows")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	rows, err := parseImportCSV(strings.NewReader("Type, Amount, Description\ncredit,10.50,Bank fee refund\ndebit,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+
+	if rows[0].Line != 2 || rows[0].Type != "credit" || rows[0].Amount != "10.50" || rows[0].Description != "Bank fee refund" {
+		t.Fatalf("first row = %+v", rows[0])
+	}
+	// A short record leaves the missing columns empty for the service to judge.
+	if rows[1].Line != 3 || rows[1].Type != "debit" || rows[1].Amount != "2" || rows[1].Description != "" {
+		t.Fatalf("second row = %+v", rows[1])
+	}
+}
+
+func TestParseImportCSVRejectsFile(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"empty", ""},
+		{"no type column", "amount,description\n10,x\n"},
+		{"no amount column", "type,description\ncredit,x\n"},
+		{"header only", "type,amount\n"},
+		{"broken quoting", "type,amount\ncredit,\"10\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rows, err := parseImportCSV(strings.NewReader(tt.csv)); err == nil {
+				t.Fatalf("parseImportCSV accepted it: %+v", rows)
+			}
+		})
+	}
+}
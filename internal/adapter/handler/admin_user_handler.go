@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+// AdminUserHandler lets admins list, inspect and manage user accounts.
+type AdminUserHandler struct {
+	userService service.UserService
+	validator   validator.Validator
+}
+
+func NewAdminUserHandler(userService service.UserService, validator validator.Validator) *AdminUserHandler {
+	return &AdminUserHandler{
+		userService: userService,
+		validator:   validator,
+	}
+}
+
+// List returns a page of users. search matches a substring of the email or
+// full name, case-insensitively.
+func (h *AdminUserHandler) List(c *gin.Context) {
+	page, pageSize := pageParams(c)
+	search := strings.TrimSpace(c.Query("search"))
+
+	users, total, err := h.userService.List(c.Request.Context(), page, pageSize, search)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, users, page, pageSize, total))
+}
+
+func (h *AdminUserHandler) GetByID(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	user, err := h.userService.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ChangeRole sets a user's role and ends their sessions so the new role
+// applies from their next login.
+func (h *AdminUserHandler) ChangeRole(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.ChangeRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	user, err := h.userService.ChangeRole(c.Request.Context(), adminID.(uuid.UUID), userID, input.Role)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Delete removes a user who has no account history; others must be
+// suspended instead.
+func (h *AdminUserHandler) Delete(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if err := h.userService.Delete(c.Request.Context(), adminID.(uuid.UUID), userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+// amountFormat reads the precision query parameter. precision=full returns
+// amounts at storage precision instead of rounding for display.
+func amountFormat(c *gin.Context) entity.AmountFormat {
+	if c.Query("precision") == "full" {
+		return entity.AmountFull
+	}
+	return entity.AmountDisplay
+}
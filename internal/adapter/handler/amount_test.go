@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+func TestAmountFormat(t *testing.T) {
+	tests := map[string]entity.AmountFormat{
+		"":                 entity.AmountDisplay,
+		"?precision=full":  entity.AmountFull,
+		"?precision=FULL":  entity.AmountDisplay,
+		"?precision=other": entity.AmountDisplay,
+	}
+
+	for query, want := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/accounts"+query, nil)
+		if got := amountFormat(c); got != want {
+			t.Errorf("amountFormat(%q) = %d, want %d", query, got, want)
+		}
+	}
+}
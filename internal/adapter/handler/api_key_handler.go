@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	validator     validator.Validator
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService, validator validator.Validator) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validator:     validator,
+	}
+}
+
+// Create issues a new key. The plaintext key is only ever returned here.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	var input entity.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	key, err := h.apiKeyService.Create(c.Request.Context(), userID.(uuid.UUID), &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	keys, err := h.apiKeyService.List(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if keys == nil {
+		keys = []*entity.APIKey{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), userID.(uuid.UUID), keyID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
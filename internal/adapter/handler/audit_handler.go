@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List returns audit entries filtered either by the acting user (user_id) or
+// by the affected entity (entity_type and entity_id). Exactly one of the two
+// filters is required.
+func (h *AuditHandler) List(c *gin.Context) {
+	page, pageSize := pageParams(c)
+
+	userIDStr := c.Query("user_id")
+	entityType := c.Query("entity_type")
+	entityIDStr := c.Query("entity_id")
+
+	var logs []*entity.AuditLog
+	var total int64
+
+	switch {
+	case userIDStr != "" && entityType == "" && entityIDStr == "":
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+		logs, total, err = h.auditService.GetByUserID(c.Request.Context(), userID, page, pageSize)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+	case userIDStr == "" && entityType != "" && entityIDStr != "":
+		entityID, err := uuid.Parse(entityIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+		logs, total, err = h.auditService.GetByEntity(c.Request.Context(), entityType, entityID, page, pageSize)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.New(
+			"INVALID_FILTER",
+			"Filter by user_id, or by entity_type and entity_id",
+			http.StatusBadRequest,
+		)})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, logs, page, pageSize, total))
+}
+
+// ListRefreshTokenReuse returns detected refresh token replays, newest first.
+// Each entry carries the affected user, the client IP and user agent, and the
+// revoked token family.
+func (h *AuditHandler) ListRefreshTokenReuse(c *gin.Context) {
+	page, pageSize := pageParams(c)
+
+	logs, total, err := h.auditService.GetByAction(c.Request.Context(), entity.AuditActionRefreshTokenReuse, page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, logs, page, pageSize, total))
+}
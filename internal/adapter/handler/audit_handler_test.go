@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+)
+
+// auditByAction answers GetByAction from a fixed set of entries. Other
+// methods are not implemented.
+type auditByAction struct {
+	service.AuditService
+	logs   []*entity.AuditLog
+	action string
+}
+
+func (s *auditByAction) GetByAction(_ context.Context, action string, _, _ int) ([]*entity.AuditLog, int64, error) {
+	s.action = action
+	return s.logs, int64(len(s.logs)), nil
+}
+
+func TestListRefreshTokenReuse(t *testing.T) {
+	userID, familyID := uuid.New(), uuid.New()
+	logs := &auditByAction{logs: []*entity.AuditLog{{
+		ID:         uuid.New(),
+		UserID:     &userID,
+		Action:     entity.AuditActionRefreshTokenReuse,
+		EntityType: "user",
+		EntityID:   &userID,
+		NewValues:  map[string]interface{}{"family_id": familyID.String()},
+		IPAddress:  "203.0.113.7",
+		CreatedAt:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}}}
+
+	router := gin.New()
+	router.GET("/admin/security/refresh-token-reuse", NewAuditHandler(logs).ListRefreshTokenReuse)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/security/refresh-token-reuse", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", w.Code, w.Body.String())
+	}
+	if logs.action != entity.AuditActionRefreshTokenReuse {
+		t.Fatalf("listed action %q, want %q", logs.action, entity.AuditActionRefreshTokenReuse)
+	}
+
+	var page struct {
+		Data []struct {
+			UserID    uuid.UUID              `json:"user_id"`
+			IPAddress string                 `json:"ip_address"`
+			NewValues map[string]interface{} `json:"new_values"`
+			CreatedAt time.Time              `json:"created_at"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 1 {
+		t.Fatalf("got %d events, want 1", len(page.Data))
+	}
+	event := page.Data[0]
+	if event.UserID != userID || event.IPAddress != "203.0.113.7" || event.CreatedAt.IsZero() {
+		t.Fatalf("event = %+v", event)
+	}
+	if event.NewValues["family_id"] != familyID.String() {
+		t.Fatalf("family_id = %v, want %s", event.NewValues["family_id"], familyID)
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+type BalanceAlertHandler struct {
+	alertService service.BalanceAlertService
+	validator    validator.Validator
+}
+
+func NewBalanceAlertHandler(alertService service.BalanceAlertService, validator validator.Validator) *BalanceAlertHandler {
+	return &BalanceAlertHandler{
+		alertService: alertService,
+		validator:    validator,
+	}
+}
+
+func (h *BalanceAlertHandler) Create(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	var input entity.CreateBalanceAlertInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	alert, err := h.alertService.Create(c.Request.Context(), userID.(uuid.UUID), &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, alert.ToResponse(amountFormat(c)))
+}
+
+func (h *BalanceAlertHandler) List(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	page, pageSize := pageParams(c)
+
+	alerts, total, err := h.alertService.List(c.Request.Context(), userID.(uuid.UUID), page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.BalanceAlertResponse, len(alerts))
+	for i, alert := range alerts {
+		responses[i] = alert.ToResponse(format)
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+func (h *BalanceAlertHandler) GetByID(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	alert, err := h.alertService.GetByID(c.Request.Context(), userID.(uuid.UUID), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert.ToResponse(amountFormat(c)))
+}
+
+// Update changes an alert. Sending "active": true re-arms a one-shot alert
+// that has fired.
+func (h *BalanceAlertHandler) Update(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.UpdateBalanceAlertInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	alert, err := h.alertService.Update(c.Request.Context(), userID.(uuid.UUID), id, &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert.ToResponse(amountFormat(c)))
+}
+
+func (h *BalanceAlertHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if err := h.alertService.Delete(c.Request.Context(), userID.(uuid.UUID), id); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
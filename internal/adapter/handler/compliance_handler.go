@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// exportSignatureHeader is sent as a trailer once the export has been
+// written, since the signature is only known at the end.
+const exportSignatureHeader = "X-Export-Signature"
+
+type ComplianceHandler struct {
+	complianceService service.ComplianceService
+	writeTimeout      time.Duration
+}
+
+func NewComplianceHandler(complianceService service.ComplianceService, writeTimeout time.Duration) *ComplianceHandler {
+	return &ComplianceHandler{
+		complianceService: complianceService,
+		writeTimeout:      writeTimeout,
+	}
+}
+
+// Export streams a signed regulatory export of all accounts and of the
+// transfers created between from and to, as csv (default) or jsonl.
+func (h *ComplianceHandler) Export(c *gin.Context) {
+	adminID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	if !h.complianceService.Enabled() {
+		handleError(c, apperror.ErrComplianceExportDisabled)
+		return
+	}
+
+	format := entity.ComplianceExportFormat(c.DefaultQuery("format", string(entity.ComplianceExportCSV)))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok || !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+	if now := time.Now().UTC(); to.After(now) {
+		to = now
+	}
+
+	contentType := "text/csv"
+	if format == entity.ComplianceExportJSONL {
+		contentType = "application/x-ndjson"
+	}
+	filename := fmt.Sprintf("compliance-%s-%s.%s", from.Format("20060102"), to.Format("20060102"), format)
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Trailer", exportSignatureHeader)
+	c.Status(http.StatusOK)
+
+	// An export can outlast the server's write timeout, so each write gets
+	// a fresh deadline instead.
+	w := &deadlineWriter{
+		ResponseWriter: c.Writer,
+		rc:             http.NewResponseController(c.Writer),
+		timeout:        h.writeTimeout,
+	}
+
+	summary, err := h.complianceService.Export(c.Request.Context(), adminID.(uuid.UUID), format, from, to, w)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Writer.Header().Set(exportSignatureHeader, summary.Signature)
+}
+
+type deadlineWriter struct {
+	gin.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+func (w *deadlineWriter) Write(p []byte) (int, error) {
+	if w.timeout > 0 {
+		_ = w.rc.SetWriteDeadline(time.Now().Add(w.timeout))
+	}
+	return w.ResponseWriter.Write(p)
+}
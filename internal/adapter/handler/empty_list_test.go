@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+// The empty* services answer every list call with no results. Other methods
+// are not implemented.
+
+type emptyAccounts struct{ service.AccountService }
+
+func (emptyAccounts) GetByUserID(context.Context, uuid.UUID, int, int) ([]*entity.Account, int64, error) {
+	return nil, 0, nil
+}
+
+func (emptyAccounts) GetTransactions(context.Context, uuid.UUID, uuid.UUID, *entity.TransactionFilter, int, int) ([]*entity.Transaction, int64, error) {
+	return nil, 0, nil
+}
+
+func (emptyAccounts) GetAllTransactions(context.Context, uuid.UUID, int, int, *entity.TransactionFilter) ([]*entity.AccountTransaction, int64, error) {
+	return nil, 0, nil
+}
+
+func (emptyAccounts) Search(context.Context, *entity.AccountSearchFilter, int, int) ([]*entity.Account, int64, error) {
+	return nil, 0, nil
+}
+
+func (emptyAccounts) GetPendingReactivations(context.Context, int, int) ([]*entity.Account, int64, error) {
+	return nil, 0, nil
+}
+
+type emptyTransfers struct{ service.TransferService }
+
+func (emptyTransfers) GetByUserID(context.Context, uuid.UUID, int, int) ([]*entity.Transfer, int64, error) {
+	return nil, 0, nil
+}
+
+func (emptyTransfers) GetByAccountID(context.Context, uuid.UUID, uuid.UUID, entity.AccountTransferDirection, int, int) ([]*entity.Transfer, int64, error) {
+	return nil, 0, nil
+}
+
+type emptyUsers struct{ service.UserService }
+
+func (emptyUsers) List(context.Context, int, int, string) ([]*entity.User, int64, error) {
+	return nil, 0, nil
+}
+
+type emptyRecurring struct {
+	service.RecurringTransferService
+}
+
+func (emptyRecurring) List(context.Context, uuid.UUID, int, int) ([]*entity.RecurringTransfer, int64, error) {
+	return nil, 0, nil
+}
+
+type emptyAlerts struct{ service.BalanceAlertService }
+
+func (emptyAlerts) List(context.Context, uuid.UUID, int, int) ([]*entity.BalanceAlert, int64, error) {
+	return nil, 0, nil
+}
+
+type emptyWebhooks struct{ service.WebhookService }
+
+func (emptyWebhooks) ListDeliveries(context.Context, uuid.UUID, uuid.UUID, int, int) ([]*entity.WebhookDelivery, int64, error) {
+	return nil, 0, nil
+}
+
+type emptyAudit struct{ service.AuditService }
+
+func (emptyAudit) GetByUserID(context.Context, uuid.UUID, int, int) ([]*entity.AuditLog, int64, error) {
+	return nil, 0, nil
+}
+
+func (emptyAudit) GetByAction(context.Context, string, int, int) ([]*entity.AuditLog, int64, error) {
+	return nil, 0, nil
+}
+
+func TestEmptyListsShareContract(t *testing.T) {
+	v := validator.New()
+	id := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		target  string
+		handler gin.HandlerFunc
+	}{
+		{"accounts", "/accounts", NewAccountHandler(emptyAccounts{}, v).List},
+		{"account transactions", "/accounts/" + id + "/transactions", NewAccountHandler(emptyAccounts{}, v).GetTransactions},
+		{"account transfers", "/accounts/" + id + "/transfers", NewTransferHandler(emptyTransfers{}, v).ListByAccount},
+		{"transfers", "/transfers", NewTransferHandler(emptyTransfers{}, v).List},
+		{"transactions", "/transactions", NewTransactionHandler(emptyAccounts{}).List},
+		{"recurring transfers", "/recurring-transfers", NewRecurringTransferHandler(emptyRecurring{}, v).List},
+		{"balance alerts", "/alerts", NewBalanceAlertHandler(emptyAlerts{}, v).List},
+		{"webhook deliveries", "/webhooks/" + id + "/deliveries", NewWebhookHandler(emptyWebhooks{}, v).ListDeliveries},
+		{"admin accounts", "/admin/accounts", NewAdminHandler(emptyAccounts{}, nil, nil, v).SearchAccounts},
+		{"admin reactivation requests", "/admin/accounts/reactivation-requests", NewAdminHandler(emptyAccounts{}, nil, nil, v).ListReactivationRequests},
+		{"admin users", "/admin/users", NewAdminUserHandler(emptyUsers{}, v).List},
+		{"audit logs", "/admin/audit-logs?user_id=" + id, NewAuditHandler(emptyAudit{}).List},
+		{"refresh token reuse", "/admin/security/refresh-token-reuse", NewAuditHandler(emptyAudit{}).ListRefreshTokenReuse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserIDKey, uuid.New())
+				c.Next()
+			})
+			router.GET("/*path", func(c *gin.Context) {
+				c.Params = append(c.Params, gin.Param{Key: "id", Value: id})
+				tt.handler(c)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("got %d, want 200: %s", w.Code, w.Body.String())
+			}
+
+			var page struct {
+				Data       json.RawMessage `json:"data"`
+				Pagination struct {
+					Page       int             `json:"page"`
+					PageSize   int             `json:"page_size"`
+					Total      int64           `json:"total"`
+					TotalPages int64           `json:"total_pages"`
+					HasNext    bool            `json:"has_next"`
+					HasPrev    bool            `json:"has_prev"`
+					Links      json.RawMessage `json:"links"`
+				} `json:"pagination"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+				t.Fatal(err)
+			}
+
+			if string(page.Data) != "[]" {
+				t.Errorf("data = %s, want []", page.Data)
+			}
+			p := page.Pagination
+			if p.Page != 1 || p.PageSize != defaultPageSize || p.Total != 0 || p.TotalPages != 0 || p.HasNext || p.HasPrev {
+				t.Errorf("pagination = %+v, want page 1 of 0 with no neighbours", p)
+			}
+			if string(p.Links) != "{}" {
+				t.Errorf("links = %s, want {}", p.Links)
+			}
+		})
+	}
+}
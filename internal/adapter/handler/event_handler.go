@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+type EventHandler struct {
+	streams      service.EventStreamService
+	heartbeat    time.Duration
+	writeTimeout time.Duration
+}
+
+func NewEventHandler(streams service.EventStreamService, heartbeat, writeTimeout time.Duration) *EventHandler {
+	return &EventHandler{
+		streams:      streams,
+		heartbeat:    heartbeat,
+		writeTimeout: writeTimeout,
+	}
+}
+
+// Stream sends the caller's events as server-sent events until the client
+// goes away or the server disconnects the stream. Each write gets its own
+// deadline so the server-wide write timeout does not end a healthy stream.
+func (h *EventHandler) Stream(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	sub, err := h.streams.Subscribe(userID.(uuid.UUID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(c.Writer)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	write := func(format string, args ...interface{}) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+		if _, err := fmt.Fprintf(c.Writer, format, args...); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write(": connected\n\n") {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if !write("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.EventType, event.Payload) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": ping\n\n") {
+				return
+			}
+		}
+	}
+}
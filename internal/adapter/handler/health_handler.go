@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,23 +34,48 @@ func (h *HealthHandler) Health(c *gin.Context) {
 	})
 }
 
+// readyCheckTimeout bounds each dependency check, so a hung dependency fails
+// the probe instead of stalling it.
+const readyCheckTimeout = 2 * time.Second
+
+// Ready checks every dependency concurrently and reports each one's state
+// and how long it took to answer.
 func (h *HealthHandler) Ready(c *gin.Context) {
-	checks := make(map[string]string)
-	healthy := true
-
-	if err := h.db.Ping(c.Request.Context()); err != nil {
-		checks["database"] = "unhealthy: " + err.Error()
-		healthy = false
-	} else {
-		checks["database"] = "healthy"
+	probes := map[string]func(ctx context.Context) (string, bool){
+		"database":   pingState(h.db.Ping),
+		"redis":      pingState(h.redis.Ping),
+		"migrations": h.migrationState,
 	}
 
-	if err := h.redis.Ping(c.Request.Context()); err != nil {
-		checks["redis"] = "unhealthy: " + err.Error()
-		healthy = false
-	} else {
-		checks["redis"] = "healthy"
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		checks    = make(map[string]string, len(probes))
+		latencies = make(map[string]int64, len(probes))
+		healthy   = true
+	)
+	for name, probe := range probes {
+		wg.Add(1)
+		go func(name string, probe func(ctx context.Context) (string, bool)) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(c.Request.Context(), readyCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			state, ok := probe(ctx)
+			elapsed := time.Since(start)
+
+			mu.Lock()
+			defer mu.Unlock()
+			checks[name] = state
+			latencies[name] = elapsed.Milliseconds()
+			if !ok {
+				healthy = false
+			}
+		}(name, probe)
 	}
+	wg.Wait()
 
 	status := http.StatusOK
 	statusText := "ready"
@@ -56,12 +85,46 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 	}
 
 	c.JSON(status, gin.H{
-		"status":    statusText,
-		"checks":    checks,
-		"timestamp": time.Now().UTC(),
+		"status":     statusText,
+		"checks":     checks,
+		"latency_ms": latencies,
+		"timestamp":  time.Now().UTC(),
 	})
 }
 
+// pingState adapts a ping to a readiness probe.
+func pingState(ping func(ctx context.Context) error) func(ctx context.Context) (string, bool) {
+	return func(ctx context.Context) (string, bool) {
+		if err := ping(ctx); err != nil {
+			return "unhealthy: " + err.Error(), false
+		}
+		return "healthy", true
+	}
+}
+
+// migrationState compares the schema version recorded in the database with
+// the one this build expects, so an instance deployed before its migrations
+// have run stays out of rotation.
+func (h *HealthHandler) migrationState(ctx context.Context) (string, bool) {
+	expected, err := strconv.ParseInt(database.ExpectedMigrationVersion, 10, 64)
+	if err != nil {
+		return fmt.Sprintf("unhealthy: invalid expected version %q", database.ExpectedMigrationVersion), false
+	}
+
+	current, dirty, err := h.db.MigrationVersion(ctx)
+	switch {
+	case err != nil:
+		return "unhealthy: " + err.Error(), false
+	case dirty:
+		return fmt.Sprintf("dirty at version %d", current), false
+	case current < expected:
+		return "behind", false
+	case current > expected:
+		return "ahead", false
+	}
+	return "healthy", true
+}
+
 func (h *HealthHandler) Info(c *gin.Context) {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
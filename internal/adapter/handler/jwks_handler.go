@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/pkg/token"
+)
+
+type JWKSHandler struct {
+	jwtManager token.JWTManager
+}
+
+func NewJWKSHandler(jwtManager token.JWTManager) *JWKSHandler {
+	return &JWKSHandler{
+		jwtManager: jwtManager,
+	}
+}
+
+// Keys serves the public keys that validate access tokens, so other services
+// can verify them without a shared secret.
+func (h *JWKSHandler) Keys(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtManager.JWKS())
+}
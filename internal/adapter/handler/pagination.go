@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	cursorParam     = "cursor"
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// Page is the response shape for lists paged by offset. An empty result is
+// still a normal 200 response: data is [], total is 0 and total_pages is 0,
+// while page and page_size echo the (normalized) request.
+type Page[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type Pagination struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+	HasPrev    bool  `json:"has_prev"`
+	Links      Links `json:"links"`
+}
+
+// Links are the request's own path and query with only the page changed.
+// Each is omitted when there is no such page.
+type Links struct {
+	Next *string `json:"next,omitempty"`
+	Prev *string `json:"prev,omitempty"`
+}
+
+// NewPage builds an offset page from one page of items and the total number
+// of matching items.
+func NewPage[T any](c *gin.Context, items []T, page, pageSize int, total int64) *Page[T] {
+	p := &Page[T]{
+		Data:       items,
+		Pagination: buildPagination(c, page, pageSize, total),
+	}
+	if p.Data == nil {
+		p.Data = []T{}
+	}
+	return p
+}
+
+// buildPagination describes the requested page of total items. A page past
+// the end has a previous page, the last one, but no next page.
+func buildPagination(c *gin.Context, page, pageSize int, total int64) Pagination {
+	page, pageSize = normalizePage(page, pageSize)
+
+	p := Pagination{
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	if total > 0 {
+		p.TotalPages = (total + int64(pageSize) - 1) / int64(pageSize)
+	}
+
+	p.HasNext = int64(page) < p.TotalPages
+	p.HasPrev = page > 1 && p.TotalPages > 0
+
+	if p.HasNext {
+		next := pageURL(c, page+1, pageSize)
+		p.Links.Next = &next
+	}
+	if p.HasPrev {
+		prevPage := page - 1
+		if int64(prevPage) > p.TotalPages {
+			prevPage = int(p.TotalPages)
+		}
+		prev := pageURL(c, prevPage, pageSize)
+		p.Links.Prev = &prev
+	}
+
+	return p
+}
+
+// pageURL returns the request's path and query with page and page_size set.
+func pageURL(c *gin.Context, page, pageSize int) string {
+	query := c.Request.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(pageSize))
+	return c.Request.URL.Path + "?" + query.Encode()
+}
+
+// pageParams reads the page and page_size query parameters. Missing or
+// invalid values fall back to the defaults the services apply.
+func pageParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
+	return normalizePage(page, pageSize)
+}
+
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxPageSize {
+		pageSize = defaultPageSize
+	}
+	return page, pageSize
+}
+
+// CursorPage is the response shape for lists paged by cursor. It replaces the
+// offset "pagination" block whenever the request carries a cursor parameter;
+// an empty cursor requests the first page. NextCursor is omitted on the last
+// page.
+type CursorPage[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// NewCursorPage builds a page from the items and the cursor of the following
+// page, which is empty on the last page.
+func NewCursorPage[T any](items []T, nextCursor string) *CursorPage[T] {
+	page := &CursorPage[T]{Data: items}
+	if page.Data == nil {
+		page.Data = []T{}
+	}
+
+	if nextCursor != "" {
+		page.HasMore = true
+		page.NextCursor = &nextCursor
+	}
+
+	return page
+}
+
+// usesCursor reports whether the caller asked for cursor pagination.
+func usesCursor(c *gin.Context) bool {
+	_, ok := c.GetQuery(cursorParam)
+	return ok
+}
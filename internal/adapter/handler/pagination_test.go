@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func testContext(target string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c
+}
+
+func TestCursorPageLastPage(t *testing.T) {
+	body, err := json.Marshal(NewCursorPage([]string{"a", "b"}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body); got != `{"data":["a","b"],"has_more":false}` {
+		t.Fatalf("last page = %s, want no next_cursor and has_more false", got)
+	}
+}
+
+func TestCursorPageWithMore(t *testing.T) {
+	body, err := json.Marshal(NewCursorPage([]string{"a"}, "abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body); got != `{"data":["a"],"next_cursor":"abc","has_more":true}` {
+		t.Fatalf("page with more = %s", got)
+	}
+}
+
+func TestCursorPageEmpty(t *testing.T) {
+	body, err := json.Marshal(NewCursorPage[string](nil, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body); got != `{"data":[],"has_more":false}` {
+		t.Fatalf("empty page = %s, want data []", got)
+	}
+}
+
+func TestUsesCursor(t *testing.T) {
+	tests := map[string]bool{
+		"/accounts/x/transactions":                 false,
+		"/accounts/x/transactions?page=2":          false,
+		"/accounts/x/transactions?cursor=":         true,
+		"/accounts/x/transactions?cursor=abc&page": true,
+	}
+	for target, want := range tests {
+		if got := usesCursor(testContext(target)); got != want {
+			t.Errorf("usesCursor(%s) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestOffsetPageLastPage(t *testing.T) {
+	page := NewPage(testContext("/transfers?page=3&page_size=10&status=completed"), []int{1, 2}, 3, 10, 22)
+
+	if page.Pagination.HasNext || page.Pagination.Links.Next != nil {
+		t.Fatalf("last offset page has a next page: %+v", page.Pagination)
+	}
+	if !page.Pagination.HasPrev || page.Pagination.Links.Prev == nil {
+		t.Fatalf("last offset page has no previous page: %+v", page.Pagination)
+	}
+	if got := *page.Pagination.Links.Prev; got != "/transfers?page=2&page_size=10&status=completed" {
+		t.Fatalf("prev link = %s", got)
+	}
+}
+
+func TestOffsetPageEmptyPastFirstPage(t *testing.T) {
+	page := NewPage[int](testContext("/transfers?page=3"), nil, 3, 10, 0)
+
+	if page.Data == nil || len(page.Data) != 0 {
+		t.Fatalf("data = %v, want an empty slice", page.Data)
+	}
+	p := page.Pagination
+	if p.TotalPages != 0 || p.HasNext || p.HasPrev || p.Links.Next != nil || p.Links.Prev != nil {
+		t.Fatalf("empty result has neighbours: %+v", p)
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+type RateLimitHandler struct {
+	limiter *redis.RateLimiter
+}
+
+func NewRateLimitHandler(limiter *redis.RateLimiter) *RateLimitHandler {
+	return &RateLimitHandler{
+		limiter: limiter,
+	}
+}
+
+// Status reports the caller's general rate-limit budget. The request that
+// asks is itself counted by the rate-limit middleware, so the figures match
+// the X-RateLimit-* headers on the same response.
+func (h *RateLimitHandler) Status(c *gin.Context) {
+	remaining, resetAt, err := h.limiter.Peek(c.Request.Context(), middleware.RateLimitKey(c))
+	if err != nil {
+		handleError(c, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to read rate limit", 500))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"limit":     h.limiter.GetLimit(),
+		"remaining": remaining,
+		"reset_at":  resetAt,
+	})
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestRateLimitStatusMatchesHeaders(t *testing.T) {
+	limiter := redis.NewRateLimiter(testutil.Redis(t), 5, 0, redis.AlgorithmSlidingWindow)
+	userID := uuid.New()
+
+	router := gin.New()
+	router.GET("/users/me/rate-limit", func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, userID)
+		c.Next()
+	}, middleware.RateLimit(limiter), NewRateLimitHandler(limiter).Status)
+
+	for i := 1; i <= 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/me/rate-limit", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status request %d got %d, want 200", i, w.Code)
+		}
+
+		var status struct {
+			Limit     int `json:"limit"`
+			Remaining int `json:"remaining"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatal(err)
+		}
+		if status.Limit != 5 || status.Remaining != 5-i {
+			t.Fatalf("status after %d requests = %+v, want limit 5 and %d remaining", i, status, 5-i)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != strconv.Itoa(status.Remaining) {
+			t.Fatalf("X-RateLimit-Remaining = %s, body says %d", got, status.Remaining)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+type RecurringTransferHandler struct {
+	recurringService service.RecurringTransferService
+	validator        validator.Validator
+}
+
+func NewRecurringTransferHandler(recurringService service.RecurringTransferService, validator validator.Validator) *RecurringTransferHandler {
+	return &RecurringTransferHandler{
+		recurringService: recurringService,
+		validator:        validator,
+	}
+}
+
+func (h *RecurringTransferHandler) Create(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	var input entity.CreateRecurringTransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	recurring, err := h.recurringService.Create(c.Request.Context(), userID.(uuid.UUID), &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, recurring.ToResponse(amountFormat(c)))
+}
+
+func (h *RecurringTransferHandler) List(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	page, pageSize := pageParams(c)
+
+	recurrings, total, err := h.recurringService.List(c.Request.Context(), userID.(uuid.UUID), page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.RecurringTransferResponse, len(recurrings))
+	for i, recurring := range recurrings {
+		responses[i] = recurring.ToResponse(format)
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+func (h *RecurringTransferHandler) GetByID(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	recurring, err := h.recurringService.GetByID(c.Request.Context(), userID.(uuid.UUID), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurring.ToResponse(amountFormat(c)))
+}
+
+// Cancel stops a standing order. Runs already turned into scheduled transfers
+// still go ahead.
+func (h *RecurringTransferHandler) Cancel(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	recurring, err := h.recurringService.Cancel(c.Request.Context(), userID.(uuid.UUID), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurring.ToResponse(amountFormat(c)))
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+type TransactionHandler struct {
+	accountService service.AccountService
+}
+
+func NewTransactionHandler(accountService service.AccountService) *TransactionHandler {
+	return &TransactionHandler{
+		accountService: accountService,
+	}
+}
+
+// List returns the activity feed across all of the caller's accounts. It
+// accepts the same start_date and end_date parameters as the per-account
+// listing, plus type=credit|debit.
+func (h *TransactionHandler) List(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	filter, ok := parseTransactionFilter(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if v := c.Query("type"); v != "" {
+		txType := entity.TransactionType(v)
+		if txType != entity.TransactionTypeCredit && txType != entity.TransactionTypeDebit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+		filter.Type = &txType
+	}
+
+	page, pageSize := pageParams(c)
+
+	transactions, total, err := h.accountService.GetAllTransactions(c.Request.Context(), userID.(uuid.UUID), page, pageSize, filter)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.AccountTransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		responses[i] = tx.ToResponse(format)
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+func (h *TransactionHandler) GetByID(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	transactionIDStr := c.Param("id")
+	transactionID, err := uuid.Parse(transactionIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	detail, err := h.accountService.GetTransaction(c.Request.Context(), userID.(uuid.UUID), transactionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail.ToResponse(amountFormat(c)))
+}
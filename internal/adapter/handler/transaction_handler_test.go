@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+)
+
+// feedService serves GetAllTransactions from fixed rows and records the
+// filter it was given. Other methods are not implemented.
+type feedService struct {
+	service.AccountService
+	rows   []*entity.AccountTransaction
+	filter *entity.TransactionFilter
+}
+
+func (s *feedService) GetAllTransactions(_ context.Context, _ uuid.UUID, _, _ int, filter *entity.TransactionFilter) ([]*entity.AccountTransaction, int64, error) {
+	s.filter = filter
+	return s.rows, int64(len(s.rows)), nil
+}
+
+func listTransactions(accounts *feedService, query string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.GET("/transactions", func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, uuid.New())
+		c.Next()
+	}, NewTransactionHandler(accounts).List)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/transactions"+query, nil))
+	return w
+}
+
+func TestListTransactionsIncludesAccount(t *testing.T) {
+	accountID := uuid.New()
+	amount := decimal.RequireFromString("12.5")
+	accounts := &feedService{rows: []*entity.AccountTransaction{{
+		Transaction:   entity.NewTransaction(accountID, entity.TransactionTypeCredit, amount, amount, "Salary", nil),
+		AccountNumber: "1234567890",
+		Currency:      "EUR",
+	}}}
+
+	w := listTransactions(accounts, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list got %d, want 200", w.Code)
+	}
+
+	var page struct {
+		Data []struct {
+			AccountID     uuid.UUID `json:"account_id"`
+			AccountNumber string    `json:"account_number"`
+			Currency      string    `json:"currency"`
+			Amount        string    `json:"amount"`
+		} `json:"data"`
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 1 || page.Pagination.Total != 1 {
+		t.Fatalf("list = %s", w.Body.String())
+	}
+	row := page.Data[0]
+	if row.AccountID != accountID || row.AccountNumber != "1234567890" || row.Currency != "EUR" || row.Amount != "12.50" {
+		t.Fatalf("row = %+v", row)
+	}
+}
+
+func TestListTransactionsFilters(t *testing.T) {
+	accounts := &feedService{}
+
+	w := listTransactions(accounts, "?type=debit&start_date=2024-05-01T00:00:00Z&end_date=2024-05-31T23:59:59Z")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list got %d, want 200", w.Code)
+	}
+	filter := accounts.filter
+	if filter.Type == nil || *filter.Type != entity.TransactionTypeDebit {
+		t.Fatalf("type filter = %v, want debit", filter.Type)
+	}
+	if filter.StartDate == nil || !filter.StartDate.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("start date = %v", filter.StartDate)
+	}
+
+	for _, query := range []string{
+		"?type=refund",
+		"?start_date=yesterday",
+		"?start_date=2024-06-01T00:00:00Z&end_date=2024-05-01T00:00:00Z",
+	} {
+		if w := listTransactions(&feedService{}, query); w.Code != http.StatusBadRequest {
+			t.Errorf("list%s got %d, want 400", query, w.Code)
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"encoding/csv"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/yourusername/gobank/internal/adapter/middleware"
 	"github.com/yourusername/gobank/internal/domain/entity"
 	"github.com/yourusername/gobank/internal/domain/service"
@@ -44,10 +46,7 @@ func (h *TransferHandler) Create(c *gin.Context) {
 	}
 
 	if errors := h.validator.Validate(&input); len(errors) > 0 {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"error":  apperror.ErrValidation,
-			"errors": errors,
-		})
+		validationFailed(c, errors)
 		return
 	}
 
@@ -57,7 +56,111 @@ func (h *TransferHandler) Create(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, transfer.ToResponse())
+	c.JSON(http.StatusCreated, transfer.ToResponse(amountFormat(c)))
+}
+
+// Move transfers money from the account in the URL to another of the
+// caller's accounts.
+func (h *TransferHandler) Move(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.MoveFundsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	idempotencyKey := c.GetHeader("X-Idempotency-Key")
+	if idempotencyKey != "" {
+		input.IdempotencyKey = idempotencyKey
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	transfer, err := h.transferService.Move(c.Request.Context(), userID.(uuid.UUID), accountID, &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer.ToResponse(amountFormat(c)))
+}
+
+type transferBatchItem struct {
+	Index    int                      `json:"index"`
+	Status   string                   `json:"status"`
+	Transfer *entity.TransferResponse `json:"transfer,omitempty"`
+	Error    *apperror.AppError       `json:"error,omitempty"`
+}
+
+// CreateBatch makes several transfers in one request and reports the outcome
+// of each. The response is 201 when every transfer was made and 207 when any
+// failed.
+func (h *TransferHandler) CreateBatch(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	var input entity.CreateTransferBatchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	idempotencyKey := c.GetHeader("X-Idempotency-Key")
+	if idempotencyKey != "" {
+		input.IdempotencyKey = idempotencyKey
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	results := h.transferService.CreateBatch(c.Request.Context(), userID.(uuid.UUID), &input)
+
+	format := amountFormat(c)
+	items := make([]transferBatchItem, len(results))
+	succeeded := 0
+	for i, result := range results {
+		items[i] = transferBatchItem{Index: i}
+		if result.Err != nil {
+			items[i].Status = "failed"
+			items[i].Error = apperror.GetAppError(result.Err)
+			if items[i].Error == nil {
+				items[i].Error = apperror.ErrInternalServer
+			}
+			continue
+		}
+		items[i].Status = "succeeded"
+		items[i].Transfer = result.Transfer.ToResponse(format)
+		succeeded++
+	}
+
+	status := http.StatusCreated
+	if succeeded < len(items) {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, gin.H{
+		"succeeded": succeeded,
+		"failed":    len(items) - succeeded,
+		"results":   items,
+	})
 }
 
 func (h *TransferHandler) GetByID(c *gin.Context) {
@@ -80,7 +183,201 @@ func (h *TransferHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, transfer.ToResponse())
+	c.JSON(http.StatusOK, transfer.ToResponse(amountFormat(c)))
+}
+
+func (h *TransferHandler) GetByReference(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	referenceNumber := c.Param("ref")
+	if referenceNumber == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	transfer, err := h.transferService.GetByReferenceNumber(c.Request.Context(), userID.(uuid.UUID), referenceNumber)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer.ToResponse(amountFormat(c)))
+}
+
+func (h *TransferHandler) Refund(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	transferIDStr := c.Param("id")
+	transferID, err := uuid.Parse(transferIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.RefundTransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	amount, err := decimal.NewFromString(input.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrInvalidAmount})
+		return
+	}
+
+	refund, err := h.transferService.PartialRefund(c.Request.Context(), userID.(uuid.UUID), transferID, amount)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, refund.ToResponse(amountFormat(c)))
+}
+
+// Retry re-attempts a failed scheduled transfer and returns the new transfer.
+func (h *TransferHandler) Retry(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	transfer, err := h.transferService.Retry(c.Request.Context(), userID.(uuid.UUID), transferID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer.ToResponse(amountFormat(c)))
+}
+
+// Reverse undoes a completed transfer. Routed for operators only.
+func (h *TransferHandler) Reverse(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	transferIDStr := c.Param("id")
+	transferID, err := uuid.Parse(transferIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	var input entity.ReverseTransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	reversal, err := h.transferService.Reverse(c.Request.Context(), userID.(uuid.UUID), transferID, input.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reversal.ToResponse(amountFormat(c)))
+}
+
+// Export streams the caller's transfers as CSV. The optional from and to
+// query parameters accept RFC 3339 timestamps or YYYY-MM-DD dates; a date in
+// "to" is inclusive of the whole day.
+func (h *TransferHandler) Export(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="transfers.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{
+		"reference_number",
+		"direction",
+		"counterparty_account",
+		"amount",
+		"currency",
+		"status",
+		"description",
+		"created_at",
+		"completed_at",
+	})
+
+	format := amountFormat(c)
+	count := 0
+	err := h.transferService.Export(c.Request.Context(), userID.(uuid.UUID), from, to, func(row *entity.TransferExportRow) error {
+		completedAt := ""
+		if row.CompletedAt != nil {
+			completedAt = row.CompletedAt.UTC().Format(time.RFC3339)
+		}
+
+		if err := writer.Write([]string{
+			row.ReferenceNumber,
+			string(row.Direction),
+			row.CounterpartyAccount,
+			format.Format(row.Amount, row.Currency.DisplayScale()),
+			string(row.Currency),
+			string(row.Status),
+			row.Description,
+			row.CreatedAt.UTC().Format(time.RFC3339),
+			completedAt,
+		}); err != nil {
+			return err
+		}
+
+		count++
+		if count%100 == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	writer.Flush()
+
+	if err != nil {
+		_ = c.Error(err)
+	}
 }
 
 func (h *TransferHandler) List(c *gin.Context) {
@@ -90,8 +387,7 @@ func (h *TransferHandler) List(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	page, pageSize := pageParams(c)
 
 	transfers, total, err := h.transferService.GetByUserID(c.Request.Context(), userID.(uuid.UUID), page, pageSize)
 	if err != nil {
@@ -99,18 +395,85 @@ func (h *TransferHandler) List(c *gin.Context) {
 		return
 	}
 
+	format := amountFormat(c)
 	responses := make([]*entity.TransferResponse, len(transfers))
 	for i, t := range transfers {
-		responses[i] = t.ToResponse()
+		responses[i] = t.ToResponse(format)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": responses,
-		"pagination": gin.H{
-			"page":       page,
-			"page_size":  pageSize,
-			"total":      total,
-			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
-		},
-	})
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+// ListByAccount pages through the transfers on one of the caller's accounts.
+// direction=in or out limits them to transfers into or out of it.
+func (h *TransferHandler) ListByAccount(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	direction := entity.AccountTransferDirection(c.DefaultQuery("direction", string(entity.AccountTransfersAll)))
+	if !direction.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	page, pageSize := pageParams(c)
+
+	transfers, total, err := h.transferService.GetByAccountID(c.Request.Context(), userID.(uuid.UUID), accountID, direction, page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	format := amountFormat(c)
+	responses := make([]*entity.TransferResponse, len(transfers))
+	for i, t := range transfers {
+		responses[i] = t.ToResponse(format)
+	}
+
+	c.JSON(http.StatusOK, NewPage(c, responses, page, pageSize, total))
+}
+
+// parseDateRange reads the from and to query parameters. Missing bounds are
+// left open. Returns false if either value is malformed.
+func parseDateRange(c *gin.Context) (time.Time, time.Time, bool) {
+	from := time.Unix(0, 0).UTC()
+	to := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	if v := c.Query("from"); v != "" {
+		t, _, err := parseDateParam(v)
+		if err != nil {
+			return from, to, false
+		}
+		from = t
+	}
+
+	if v := c.Query("to"); v != "" {
+		t, dateOnly, err := parseDateParam(v)
+		if err != nil {
+			return from, to, false
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+
+	return from, to, true
+}
+
+func parseDateParam(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), false, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	return t, true, err
 }
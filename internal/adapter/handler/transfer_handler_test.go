@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+// exportService serves Export from a fixed set of rows and records the range
+// it was asked for. Other methods are not implemented.
+type exportService struct {
+	service.TransferService
+	rows     []*entity.TransferExportRow
+	from, to time.Time
+}
+
+func (s *exportService) Export(_ context.Context, _ uuid.UUID, from, to time.Time, fn func(*entity.TransferExportRow) error) error {
+	s.from, s.to = from, to
+	for _, row := range s.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportTransfers(t *testing.T, transfers *exportService, query string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := gin.New()
+	router.GET("/transfers/export", func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, uuid.New())
+		c.Next()
+	}, NewTransferHandler(transfers, validator.New()).Export)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/transfers/export"+query, nil))
+	return w
+}
+
+func TestExportTransfersCSV(t *testing.T) {
+	completedAt := time.Date(2024, 5, 1, 9, 30, 5, 0, time.UTC)
+	transfers := &exportService{rows: []*entity.TransferExportRow{{
+		ReferenceNumber:     "TRF-20240501-ABCD1234",
+		Direction:           entity.TransferDirectionOutgoing,
+		CounterpartyAccount: "******7890",
+		Amount:              decimal.RequireFromString("25.5"),
+		Currency:            "USD",
+		Status:              entity.TransferStatusCompleted,
+		Description:         "Rent, May",
+		CreatedAt:           time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC),
+		CompletedAt:         &completedAt,
+	}}}
+
+	w := exportTransfers(t, transfers, "?format=csv")
+	if w.Code != http.StatusOK {
+		t.Fatalf("export got %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/csv" {
+		t.Fatalf("Content-Type = %q, want text/csv", got)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"reference_number", "direction", "counterparty_account", "amount", "currency", "status", "description", "created_at", "completed_at"},
+		{"TRF-20240501-ABCD1234", "outgoing", "******7890", "25.50", "USD", "completed", "Rent, May", "2024-05-01T09:30:00Z", "2024-05-01T09:30:05Z"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("export =\n%q\nwant\n%q", records, want)
+	}
+}
+
+func TestExportTransfersDateRange(t *testing.T) {
+	transfers := &exportService{}
+
+	if w := exportTransfers(t, transfers, "?from=2024-05-01&to=2024-05-31"); w.Code != http.StatusOK {
+		t.Fatalf("export got %d, want 200", w.Code)
+	}
+	if want := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC); !transfers.from.Equal(want) {
+		t.Fatalf("from = %v, want %v", transfers.from, want)
+	}
+	// A date in "to" covers the whole day.
+	if want := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC); !transfers.to.Equal(want) {
+		t.Fatalf("to = %v, want %v", transfers.to, want)
+	}
+}
+
+func TestExportTransfersRejectsBadQuery(t *testing.T) {
+	for _, query := range []string{"?format=pdf", "?from=yesterday"} {
+		if w := exportTransfers(t, &exportService{}, query); w.Code != http.StatusBadRequest {
+			t.Errorf("export%s got %d, want 400", query, w.Code)
+		}
+	}
+}
@@ -32,10 +32,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 
 	if errors := h.validator.Validate(&input); len(errors) > 0 {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"error":  apperror.ErrValidation,
-			"errors": errors,
-		})
+		validationFailed(c, errors)
 		return
 	}
 
@@ -48,10 +45,11 @@ func (h *UserHandler) Register(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User registered successfully",
 		"user": gin.H{
-			"id":         user.ID,
-			"email":      user.Email,
-			"full_name":  user.FullName,
-			"created_at": user.CreatedAt,
+			"id":             user.ID,
+			"email":          user.Email,
+			"full_name":      user.FullName,
+			"email_verified": user.EmailVerified,
+			"created_at":     user.CreatedAt,
 		},
 	})
 }
@@ -64,10 +62,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 	}
 
 	if errors := h.validator.Validate(&input); len(errors) > 0 {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"error":  apperror.ErrValidation,
-			"errors": errors,
-		})
+		validationFailed(c, errors)
 		return
 	}
 
@@ -80,6 +75,122 @@ func (h *UserHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, tokens)
 }
 
+// TwoFactorLogin completes a login for a user with 2FA enabled, exchanging
+// the challenge token from Login and a TOTP code for session tokens.
+func (h *UserHandler) TwoFactorLogin(c *gin.Context) {
+	var input entity.TwoFactorLoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	tokens, err := h.userService.CompleteTwoFactorLogin(c.Request.Context(), &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var input entity.VerifyEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	user, err := h.userService.VerifyEmail(c.Request.Context(), input.Token)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+		"user": gin.H{
+			"id":                user.ID,
+			"email":             user.Email,
+			"email_verified":    user.EmailVerified,
+			"email_verified_at": user.EmailVerifiedAt,
+		},
+	})
+}
+
+// ResendVerification always answers 202 for a well-formed request, whether or
+// not the email is registered.
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	var input entity.ResendVerificationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	if err := h.userService.ResendVerification(c.Request.Context(), input.Email); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the email is registered and unverified, a verification email has been sent"})
+}
+
+// ForgotPassword answers 200 for any well-formed request, whether or not the
+// email is registered.
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var input entity.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	if err := h.userService.ForgotPassword(c.Request.Context(), input.Email); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the email is registered, a password reset email has been sent"})
+}
+
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var input entity.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	if err := h.userService.ResetPassword(c.Request.Context(), &input); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
+
 func (h *UserHandler) RefreshToken(c *gin.Context) {
 	var input struct {
 		RefreshToken string `json:"refresh_token" validate:"required"`
@@ -120,6 +231,25 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	count, err := h.userService.LogoutAll(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":             "Logged out from all devices",
+		"sessions_terminated": count,
+	})
+}
+
 func (h *UserHandler) GetMe(c *gin.Context) {
 	userID, exists := c.Get(middleware.UserIDKey)
 	if !exists {
@@ -134,12 +264,59 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"id":         user.ID,
-		"email":      user.Email,
-		"full_name":  user.FullName,
-		"role":       user.Role,
-		"created_at": user.CreatedAt,
-		"updated_at": user.UpdatedAt,
+		"id":                 user.ID,
+		"email":              user.Email,
+		"full_name":          user.FullName,
+		"role":               user.Role,
+		"two_factor_enabled": user.TwoFactorEnabled,
+		"created_at":         user.CreatedAt,
+		"updated_at":         user.UpdatedAt,
+	})
+}
+
+func (h *UserHandler) EnableTwoFactor(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	enrollment, err := h.userService.EnableTwoFactor(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *UserHandler) ConfirmTwoFactor(c *gin.Context) {
+	userID, exists := c.Get(middleware.UserIDKey)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": apperror.ErrUnauthorized})
+		return
+	}
+
+	var input entity.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+		return
+	}
+
+	if errors := h.validator.Validate(&input); len(errors) > 0 {
+		validationFailed(c, errors)
+		return
+	}
+
+	user, err := h.userService.ConfirmTwoFactor(c.Request.Context(), userID.(uuid.UUID), input.Code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Two-factor authentication enabled",
+		"two_factor_enabled": user.TwoFactorEnabled,
 	})
 }
 
@@ -157,10 +334,7 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 	}
 
 	if errors := h.validator.Validate(&input); len(errors) > 0 {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{
-			"error":  apperror.ErrValidation,
-			"errors": errors,
-		})
+		validationFailed(c, errors)
 		return
 	}
 
@@ -171,12 +345,26 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"id":         user.ID,
-		"email":      user.Email,
-		"full_name":  user.FullName,
-		"role":       user.Role,
-		"created_at": user.CreatedAt,
-		"updated_at": user.UpdatedAt,
+		"id":                 user.ID,
+		"email":              user.Email,
+		"full_name":          user.FullName,
+		"role":               user.Role,
+		"two_factor_enabled": user.TwoFactorEnabled,
+		"created_at":         user.CreatedAt,
+		"updated_at":         user.UpdatedAt,
+	})
+}
+
+func validationFailed(c *gin.Context, errors []apperror.ValidationError) {
+	fields := make([]string, len(errors))
+	for i, e := range errors {
+		fields[i] = e.Field
+	}
+	c.Set(middleware.ValidationFieldsKey, fields)
+
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":  apperror.ErrValidation,
+		"errors": errors,
 	})
 }
 
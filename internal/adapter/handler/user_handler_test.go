@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+// logouts records the refresh tokens Logout is called with. Other methods
+// are not implemented.
+type logouts struct {
+	service.UserService
+	tokens []string
+}
+
+func (s *logouts) Logout(_ context.Context, refreshToken string) error {
+	s.tokens = append(s.tokens, refreshToken)
+	return nil
+}
+
+func TestLogoutTwiceReturnsSameResponse(t *testing.T) {
+	users := &logouts{}
+	router := gin.New()
+	router.POST("/auth/logout", NewUserHandler(users, validator.New()).Logout)
+
+	var bodies []string
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", strings.NewReader(`{"refresh_token":"abc"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("logout %d got %d: %s", i+1, w.Code, w.Body.String())
+		}
+		bodies = append(bodies, w.Body.String())
+	}
+
+	if bodies[0] != bodies[1] {
+		t.Fatalf("retried logout answered %s, first answered %s", bodies[1], bodies[0])
+	}
+	if len(users.tokens) != 2 || users.tokens[1] != "abc" {
+		t.Fatalf("Logout called with %v", users.tokens)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/yourusername/gobank/internal/adapter/middleware"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/validator"
+)
+
+func postInvalidTransfer(t *testing.T, enabled bool) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.ValidationLogging(&logger.Logger{Logger: &log}, enabled))
+	router.POST("/transfers", func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, uuid.New())
+		c.Next()
+	}, NewTransferHandler(nil, validator.New()).Create)
+
+	body := fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":"-31337"}`, uuid.New(), uuid.New())
+	req := httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid transfer got %d, want 422", w.Code)
+	}
+	return buf.String()
+}
+
+func TestValidationFailureLoggedWhenEnabled(t *testing.T) {
+	logged := postInvalidTransfer(t, true)
+
+	if !strings.Contains(logged, "Validation failed") {
+		t.Fatalf("no validation log line: %s", logged)
+	}
+	if !strings.Contains(logged, `"route":"/transfers"`) || !strings.Contains(strings.ToLower(logged), "amount") {
+		t.Fatalf("log line does not name the route and failing field: %s", logged)
+	}
+	if strings.Contains(logged, "31337") {
+		t.Fatalf("log line includes the submitted value: %s", logged)
+	}
+}
+
+func TestValidationFailureNotLoggedWhenDisabled(t *testing.T) {
+	if logged := postInvalidTransfer(t, false); logged != "" {
+		t.Fatalf("validation failure logged while disabled: %s", logged)
+	}
+}
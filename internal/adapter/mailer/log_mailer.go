@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+)
+
+// logMailer writes messages to the application log instead of sending them.
+// It stands in for a real mail provider in development.
+type logMailer struct {
+	logger *logger.Logger
+}
+
+func NewLogMailer(log *logger.Logger) service.Mailer {
+	return &logMailer{logger: log}
+}
+
+func (m *logMailer) Send(_ context.Context, to, subject, body string) error {
+	m.logger.Info().
+		Str("to", to).
+		Str("subject", subject).
+		Str("body", body).
+		Msg("Email")
+	return nil
+}
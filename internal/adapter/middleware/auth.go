@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
 	"github.com/yourusername/gobank/internal/pkg/apperror"
 	"github.com/yourusername/gobank/internal/pkg/token"
 )
@@ -12,13 +14,39 @@ import (
 const (
 	AuthorizationHeader = "Authorization"
 	AuthorizationType   = "Bearer"
+	APIKeyHeader        = "X-API-Key"
 	UserIDKey           = "user_id"
 	UserEmailKey        = "user_email"
 	UserRoleKey         = "user_role"
+	APIKeyKey           = "api_key"
+	TokenIssuedAtKey    = "token_issued_at"
 )
 
-func Auth(jwtManager token.JWTManager) gin.HandlerFunc {
+// Auth authenticates the request with either a bearer access token or an
+// X-API-Key header. API-key requests also carry the key in the context so
+// RequireScope and ScopeByMethod can restrict them.
+func Auth(jwtManager token.JWTManager, apiKeys service.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if rawKey := c.GetHeader(APIKeyHeader); rawKey != "" {
+			key, user, err := apiKeys.Authenticate(c.Request.Context(), rawKey)
+			if err != nil {
+				appErr := apperror.GetAppError(err)
+				if appErr == nil {
+					appErr = apperror.ErrInternalServer
+				}
+				c.AbortWithStatusJSON(appErr.StatusCode, gin.H{"error": appErr})
+				return
+			}
+
+			c.Set(UserIDKey, user.ID)
+			c.Set(UserEmailKey, user.Email)
+			c.Set(UserRoleKey, string(user.Role))
+			c.Set(APIKeyKey, key)
+
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader(AuthorizationHeader)
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
@@ -52,6 +80,9 @@ func Auth(jwtManager token.JWTManager) gin.HandlerFunc {
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(UserEmailKey, claims.Email)
 		c.Set(UserRoleKey, claims.Role)
+		if claims.IssuedAt != nil {
+			c.Set(TokenIssuedAtKey, claims.IssuedAt.Time)
+		}
 
 		c.Next()
 	}
@@ -87,3 +118,59 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 		})
 	}
 }
+
+// RequireScope rejects API-key requests whose key lacks scope. Requests made
+// with an access token act with the user's full rights and pass through.
+func RequireScope(scope entity.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasScope(c, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": apperror.ErrInsufficientScope,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ScopeByMethod requires the read scope for safe methods and writeScope for
+// everything else.
+func ScopeByMethod(writeScope entity.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := writeScope
+		if isReadMethod(c.Request.Method) {
+			scope = entity.ScopeRead
+		}
+
+		if !hasScope(c, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": apperror.ErrInsufficientScope,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// AccessTokenOnly rejects requests authenticated with an API key, so a leaked
+// key cannot be used to mint or revoke keys.
+func AccessTokenOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(APIKeyKey); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": apperror.ErrInsufficientScope,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func hasScope(c *gin.Context, scope entity.APIKeyScope) bool {
+	value, ok := c.Get(APIKeyKey)
+	if !ok {
+		return true
+	}
+	key, ok := value.(*entity.APIKey)
+	return ok && key.HasScope(scope)
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// apiKeyStub authenticates the raw keys it was given. Other methods are not
+// implemented.
+type apiKeyStub struct {
+	service.APIKeyService
+	keys map[string]*entity.APIKey
+}
+
+func (s apiKeyStub) Authenticate(_ context.Context, rawKey string) (*entity.APIKey, *entity.User, error) {
+	key, ok := s.keys[rawKey]
+	if !ok {
+		return nil, nil, apperror.ErrUnauthorized
+	}
+	return key, &entity.User{ID: key.UserID, Role: entity.RoleUser}, nil
+}
+
+func scopedRouter(keys apiKeyStub) *gin.Engine {
+	router := gin.New()
+	transfers := router.Group("/transfers", Auth(nil, keys), ScopeByMethod(entity.ScopeTransfer))
+	transfers.GET("", func(c *gin.Context) { c.Status(http.StatusOK) })
+	transfers.POST("", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.POST("/api-keys", Auth(nil, keys), AccessTokenOnly(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+func withAPIKey(router http.Handler, method, path, rawKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(APIKeyHeader, rawKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestReadOnlyKeyCannotTransfer(t *testing.T) {
+	keys := apiKeyStub{keys: map[string]*entity.APIKey{
+		"read": {UserID: uuid.New(), Scopes: []entity.APIKeyScope{entity.ScopeRead}},
+	}}
+	router := scopedRouter(keys)
+
+	w := withAPIKey(router, http.MethodPost, "/transfers", "read")
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("transfer with a read-only key got %d, want 403", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "INSUFFICIENT_SCOPE") {
+		t.Fatalf("transfer with a read-only key returned %s, want INSUFFICIENT_SCOPE", w.Body.String())
+	}
+
+	if w := withAPIKey(router, http.MethodGet, "/transfers", "read"); w.Code != http.StatusOK {
+		t.Fatalf("listing transfers with a read-only key got %d, want 200", w.Code)
+	}
+}
+
+func TestScopeByMethodAllowsGrantedScopes(t *testing.T) {
+	keys := apiKeyStub{keys: map[string]*entity.APIKey{
+		"transfer": {UserID: uuid.New(), Scopes: []entity.APIKeyScope{entity.ScopeTransfer}},
+		"admin":    {UserID: uuid.New(), Scopes: []entity.APIKeyScope{entity.ScopeAdmin}},
+	}}
+	router := scopedRouter(keys)
+
+	for _, rawKey := range []string{"transfer", "admin"} {
+		if w := withAPIKey(router, http.MethodPost, "/transfers", rawKey); w.Code != http.StatusCreated {
+			t.Errorf("transfer with the %s key got %d, want 201", rawKey, w.Code)
+		}
+	}
+
+	// Without the read scope a transfer key cannot list.
+	if w := withAPIKey(router, http.MethodGet, "/transfers", "transfer"); w.Code != http.StatusForbidden {
+		t.Errorf("listing with a transfer-only key got %d, want 403", w.Code)
+	}
+}
+
+func TestAccessTokenOnlyRejectsAPIKeys(t *testing.T) {
+	keys := apiKeyStub{keys: map[string]*entity.APIKey{
+		"admin": {UserID: uuid.New(), Scopes: []entity.APIKeyScope{entity.ScopeAdmin}},
+	}}
+
+	if w := withAPIKey(scopedRouter(keys), http.MethodPost, "/api-keys", "admin"); w.Code != http.StatusForbidden {
+		t.Fatalf("minting a key with an API key got %d, want 403", w.Code)
+	}
+}
+
+func TestScopesDoNotApplyToAccessTokens(t *testing.T) {
+	router := gin.New()
+	router.POST("/transfers", withUser(uuid.New()), ScopeByMethod(entity.ScopeTransfer), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	if w := serve(router, http.MethodPost, "/transfers"); w.Code != http.StatusCreated {
+		t.Fatalf("transfer with an access token got %d, want 201", w.Code)
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// cappedBuffer keeps the first limit bytes written to it and counts the
+// rest, so a large body is noticed without being held in memory.
+type cappedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+	size  int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.size += len(p)
+	if room := b.limit - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			b.buf.Write(p[:room])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *cappedBuffer) truncated() bool {
+	return b.size > b.limit
+}
+
+// bodyCaptureWriter copies the response body into a cappedBuffer as it is
+// written. Flushing and streaming pass through untouched.
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body *cappedBuffer
+}
+
+func (w *bodyCaptureWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.Write([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// extend write deadlines on streamed responses.
+func (w *bodyCaptureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// captureRequestBody reads up to limit bytes of the request body for logging
+// and puts them back in front of the rest, so the handler still sees the
+// whole body.
+func captureRequestBody(c *gin.Context, limit int) *cappedBuffer {
+	body := &cappedBuffer{limit: limit}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return body
+	}
+
+	head, _ := io.ReadAll(io.LimitReader(c.Request.Body, int64(limit)+1))
+	body.Write(head)
+	c.Request.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(head), c.Request.Body),
+		Closer: c.Request.Body,
+	}
+	return body
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// addBody adds body to the log event under key, with credentials redacted
+// by redactJSON. Bodies over the size cap, or that are not JSON, are
+// described but never logged: neither can be redacted reliably.
+func addBody(event *zerolog.Event, key string, body *cappedBuffer, redactJSON func([]byte) ([]byte, bool)) {
+	switch {
+	case body.size == 0:
+		return
+	case body.truncated():
+		event.Str(key, "omitted: larger than LOG_HTTP_BODY_MAX_BYTES").Int(key+"_size", body.size)
+	default:
+		redacted, ok := redactJSON(body.buf.Bytes())
+		if !ok {
+			event.Str(key, "omitted: not JSON").Int(key+"_size", body.size)
+			return
+		}
+		event.RawJSON(key, redacted)
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unlimitedBodyKey holds the request body as it was before BodyLimit
+// wrapped it, so SetBodyLimit can apply a different limit.
+const unlimitedBodyKey = "unlimited_body"
+
+// BodyLimit caps request bodies at maxBytes. Reading past the limit fails
+// with *http.MaxBytesError without the rest of the body being read, which
+// JSONLimits turns into a 413. Only request bodies are limited, not
+// responses. A maxBytes of 0 or less disables the limit.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		c.Set(unlimitedBodyKey, c.Request.Body)
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// SetBodyLimit replaces the limit BodyLimit put on the request body, for the
+// few handlers that accept uploads larger than an ordinary API request. It
+// must be called before the body is read.
+func SetBodyLimit(c *gin.Context, maxBytes int64) {
+	body := c.Request.Body
+	if original, ok := c.Get(unlimitedBodyKey); ok {
+		body = original.(io.ReadCloser)
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, body, maxBytes)
+}
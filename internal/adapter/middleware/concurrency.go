@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// ConcurrencyLimit caps the number of requests served at once and sheds the
+// rest with 503 instead of queueing them on the database pool. A max of zero
+// or less disables the limit.
+func ConcurrencyLimit(max int) gin.HandlerFunc {
+	if max <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	sem := make(chan struct{}, max)
+
+	return func(c *gin.Context) {
+		select {
+		case sem <- struct{}{}:
+		default:
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": apperror.ErrServiceUnavailable,
+			})
+			return
+		}
+
+		metrics.HTTPInFlightRequests.Inc()
+		defer func() {
+			metrics.HTTPInFlightRequests.Dec()
+			<-sem
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConcurrencyLimitShedsBeyondMax(t *testing.T) {
+	const max = 3
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	router := gin.New()
+	router.Use(ConcurrencyLimit(max))
+	router.GET("/slow", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var wg sync.WaitGroup
+	codes := make(chan int, max)
+	for i := 0; i < max; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- serve(router, http.MethodGet, "/slow").Code
+		}()
+		<-entered
+	}
+
+	w := serve(router, http.MethodGet, "/fast")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("request beyond the cap got %d, want 503", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatal("shed request has no Retry-After")
+	}
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("request within the cap got %d, want 200", code)
+		}
+	}
+
+	if w := serve(router, http.MethodGet, "/fast"); w.Code != http.StatusOK {
+		t.Fatalf("request after the others finished got %d, want 200", w.Code)
+	}
+}
+
+func TestConcurrencyLimitDisabled(t *testing.T) {
+	router := gin.New()
+	router.Use(ConcurrencyLimit(0))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	if w := serve(router, http.MethodGet, "/"); w.Code != http.StatusOK {
+		t.Fatalf("request with the limit disabled got %d, want 200", w.Code)
+	}
+}
@@ -1,32 +1,60 @@
 package middleware
 
 import (
+	"slices"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-Idempotency-Key"},
-		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	})
+// CORSOptions is the cross-origin policy. An origin of "*" allows any
+// origin, but only without AllowCredentials; the config refuses the
+// combination, and CORS ignores the wildcard if it gets it anyway.
+type CORSOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
 }
 
-func CORSProduction(allowedOrigins []string) gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowOrigins:     allowedOrigins,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-Idempotency-Key"},
+// CORS applies opts to cross-origin requests. Requests from origins not in
+// AllowedOrigins are rejected with 403. With no allowed origins, no CORS
+// headers are sent and browsers keep to same-origin requests.
+func CORS(opts CORSOptions) gin.HandlerFunc {
+	if len(opts.AllowedOrigins) == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	config := cors.Config{
+		AllowMethods:     opts.AllowedMethods,
+		AllowHeaders:     opts.AllowedHeaders,
 		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	})
+		AllowCredentials: opts.AllowCredentials,
+		MaxAge:           opts.MaxAge,
+	}
+	switch {
+	case !slices.Contains(opts.AllowedOrigins, "*"):
+		config.AllowOrigins = opts.AllowedOrigins
+	case !opts.AllowCredentials:
+		config.AllowAllOrigins = true
+	default:
+		// Never let every site make credentialed requests; fall back to
+		// the origins that are listed explicitly.
+		config.AllowOrigins = slices.DeleteFunc(slices.Clone(opts.AllowedOrigins), func(origin string) bool {
+			return origin == "*"
+		})
+		if len(config.AllowOrigins) == 0 {
+			return func(c *gin.Context) {
+				c.Next()
+			}
+		}
+	}
+
+	return cors.New(config)
 }
 
 func SecurityHeaders() gin.HandlerFunc {
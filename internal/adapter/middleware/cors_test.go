@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func corsRequest(t *testing.T, opts CORSOptions, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := gin.New()
+	router.Use(CORS(opts))
+	router.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", origin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSAllowsListedOrigin(t *testing.T) {
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET"},
+		AllowCredentials: true,
+	}
+
+	w := corsRequest(t, opts, "https://app.example.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("Access-Control-Allow-Origin = %q", got)
+	}
+
+	w = corsRequest(t, opts, "https://evil.example.com")
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("unlisted origin got status %d, want 403", w.Code)
+	}
+}
+
+func TestCORSWildcardWithoutCredentials(t *testing.T) {
+	w := corsRequest(t, CORSOptions{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET"},
+	}, "https://anywhere.example.com")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Fatalf("Access-Control-Allow-Credentials = %q, want none", got)
+	}
+}
+
+func TestCORSWildcardIsIgnoredWithCredentials(t *testing.T) {
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"*", "https://app.example.com"},
+		AllowedMethods:   []string{"GET"},
+		AllowCredentials: true,
+	}
+
+	w := corsRequest(t, opts, "https://evil.example.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("unlisted origin was allowed: Access-Control-Allow-Origin = %q", got)
+	}
+
+	w = corsRequest(t, opts, "https://app.example.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("listed origin: Access-Control-Allow-Origin = %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+const IdempotencyKeyHeader = "X-Idempotency-Key"
+
+// maxIdempotentResponseBytes caps the responses Idempotency records. Money
+// movement responses are far smaller; anything larger is not recorded.
+const maxIdempotentResponseBytes = 64 << 10
+
+// Idempotency makes a request carrying X-Idempotency-Key safe to retry. The
+// first request with a key runs normally and its successful response is
+// recorded; a retry with the same key and payload gets that response back
+// with Idempotent-Replayed set instead of running again. Reusing a key for a
+// different payload, or while its first request is still running, is
+// rejected with 409. Failed requests release the key so they can be retried.
+// Keys are scoped to the caller, so it must run after Auth.
+func Idempotency(store *redis.IdempotencyStore, log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		scopedKey := idempotencyScope(c) + ":" + key
+		requestHash := idempotencyRequestHash(c, body)
+
+		claim, stored, err := store.Begin(c.Request.Context(), scopedKey, requestHash)
+		if err != nil {
+			if appErr := apperror.GetAppError(err); appErr != nil {
+				c.AbortWithStatusJSON(appErr.StatusCode, gin.H{"error": appErr})
+				return
+			}
+			// Without the store a retry could apply twice, so refuse rather
+			// than fail open.
+			log.Error().Err(err).Msg("Idempotency store unavailable")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": apperror.ErrServiceUnavailable})
+			return
+		}
+		if stored != nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		response := &cappedBuffer{limit: maxIdempotentResponseBytes}
+		c.Writer = &bodyCaptureWriter{ResponseWriter: c.Writer, body: response}
+
+		c.Next()
+
+		// The client may have given up and be about to retry, which is
+		// exactly when the outcome has to be recorded.
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := c.Writer.Status()
+		if status < 200 || status >= 300 || response.truncated() {
+			if err := store.Release(ctx, scopedKey, claim); err != nil {
+				log.Error().Err(err).Msg("Failed to release idempotency key")
+			}
+			return
+		}
+
+		err = store.Complete(ctx, scopedKey, requestHash, &redis.IdempotentResponse{
+			StatusCode:  status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        response.buf.Bytes(),
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to record idempotent response")
+		}
+	}
+}
+
+// idempotencyScope keeps one caller's keys apart from another's.
+func idempotencyScope(c *gin.Context) string {
+	if userID, exists := c.Get(UserIDKey); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// idempotencyRequestHash identifies the request a key was first used for:
+// the same key sent to another route or with another body is a different
+// request.
+func idempotencyRequestHash(c *gin.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
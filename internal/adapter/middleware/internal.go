@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/token"
+)
+
+const (
+	ServiceTokenHeader = "X-Service-Token"
+	InternalServiceKey = "internal_service"
+)
+
+// ServiceIdentity marks requests carrying a valid signed service token so the
+// rate limiters skip them. Invalid tokens are logged and the request is
+// limited like any other client.
+func ServiceIdentity(manager *token.ServiceTokenManager, log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.GetHeader(ServiceTokenHeader)
+		if tokenString == "" || !manager.Enabled() {
+			c.Next()
+			return
+		}
+
+		service, err := manager.Verify(tokenString, time.Now())
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("path", c.Request.URL.Path).
+				Str("client_ip", c.ClientIP()).
+				Msg("Rejected internal service token")
+			c.Next()
+			return
+		}
+
+		c.Set(InternalServiceKey, service)
+		log.Info().
+			Str("service", service).
+			Str("method", c.Request.Method).
+			Str("path", c.Request.URL.Path).
+			Msg("Internal service bypassed rate limit")
+
+		c.Next()
+	}
+}
+
+func isInternalService(c *gin.Context) bool {
+	_, ok := c.Get(InternalServiceKey)
+	return ok
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/token"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func serviceTokenRouter(manager *token.ServiceTokenManager, log *logger.Logger, limit gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Use(ServiceIdentity(manager, log))
+	if limit != nil {
+		router.Use(limit)
+	}
+	router.GET("/accounts", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(InternalServiceKey))
+	})
+	return router
+}
+
+func getWithServiceToken(router http.Handler, tokenString string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+	if tokenString != "" {
+		req.Header.Set(ServiceTokenHeader, tokenString)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestServiceIdentity(t *testing.T) {
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	manager := token.NewServiceTokenManager("secret", time.Minute)
+	router := serviceTokenRouter(manager, &logger.Logger{Logger: &log}, nil)
+
+	valid, err := manager.Generate("ledger-sync", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := getWithServiceToken(router, valid); w.Body.String() != "ledger-sync" {
+		t.Fatalf("valid token identified as %q, want ledger-sync", w.Body.String())
+	}
+	if !strings.Contains(buf.String(), "Internal service bypassed rate limit") {
+		t.Fatalf("bypass not logged: %s", buf.String())
+	}
+
+	buf.Reset()
+	forged, err := token.NewServiceTokenManager("guessed", time.Minute).Generate("ledger-sync", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := getWithServiceToken(router, forged); w.Body.String() != "" {
+		t.Fatalf("forged token identified as %q", w.Body.String())
+	}
+	if !strings.Contains(buf.String(), "Rejected internal service token") {
+		t.Fatalf("rejected token not logged: %s", buf.String())
+	}
+}
+
+func TestServiceTokenBypassesRateLimit(t *testing.T) {
+	limiter := redis.NewRateLimiter(testutil.Redis(t), 2, 0, redis.AlgorithmSlidingWindow)
+	manager := token.NewServiceTokenManager("secret", time.Minute)
+	router := serviceTokenRouter(manager, testutil.Logger(), RateLimit(limiter))
+
+	valid, err := manager.Generate("ledger-sync", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if w := getWithServiceToken(router, valid); w.Code != http.StatusOK {
+			t.Fatalf("internal request %d got %d, want 200", i+1, w.Code)
+		}
+	}
+
+	forged, err := token.NewServiceTokenManager("guessed", time.Minute).Generate("ledger-sync", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if w := getWithServiceToken(router, forged); w.Code != http.StatusOK {
+			t.Fatalf("request %d with a forged token got %d, want 200", i+1, w.Code)
+		}
+	}
+	if w := getWithServiceToken(router, forged); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the limit with a forged token got %d, want 429", w.Code)
+	}
+}
+
+func TestServiceTokenDoesNotBypassIPRateLimit(t *testing.T) {
+	limiter := redis.NewRateLimiter(testutil.Redis(t), 2, 0, redis.AlgorithmSlidingWindow)
+	manager := token.NewServiceTokenManager("secret", time.Minute)
+	limit := RateLimitByIPWithConfig(limiter, RouteLimit{Name: testutil.Key(t), Requests: 2, Window: time.Minute})
+	router := serviceTokenRouter(manager, testutil.Logger(), limit)
+
+	valid, err := manager.Generate("ledger-sync", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if w := getWithServiceToken(router, valid); w.Code != http.StatusOK {
+			t.Fatalf("internal request %d got %d, want 200", i+1, w.Code)
+		}
+	}
+	if w := getWithServiceToken(router, valid); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("internal request over the per-IP limit got %d, want 429", w.Code)
+	}
+}
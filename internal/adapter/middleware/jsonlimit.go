@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/jsonlimit"
+)
+
+// JSONLimits rejects JSON request bodies that nest deeper or carry longer
+// arrays than limits allow, before a handler binds them. Other content types
+// pass through untouched.
+func JSONLimits(limits jsonlimit.Limits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limits.Enabled() || c.Request.Body == nil || c.ContentType() != gin.MIMEJSON {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": apperror.ErrRequestTooLarge})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": apperror.ErrBadRequest})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		switch err := limits.Check(body); {
+		case errors.Is(err, jsonlimit.ErrTooDeep):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": apperror.ErrJSONTooDeep})
+			return
+		case errors.Is(err, jsonlimit.ErrArrayTooLong):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": apperror.ErrJSONArrayTooLong})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/pkg/jsonlimit"
+)
+
+// jsonLimitRouter echoes the body it receives, so tests can check what the
+// handler would bind.
+func jsonLimitRouter(maxBytes int64, limits jsonlimit.Limits) *gin.Engine {
+	router := gin.New()
+	router.Use(BodyLimit(maxBytes), JSONLimits(limits))
+	router.POST("/transfers/batch", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, gin.MIMEPlain, body)
+	})
+	return router
+}
+
+func post(router http.Handler, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/transfers/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	return body.Error.Code
+}
+
+func TestJSONLimitsRejectsOverDeepPayload(t *testing.T) {
+	router := jsonLimitRouter(0, jsonlimit.Limits{MaxDepth: 3})
+
+	w := post(router, gin.MIMEJSON, `{"a":{"b":{"c":{"d":1}}}}`)
+	if w.Code != http.StatusBadRequest || errorCode(t, w) != "JSON_TOO_DEEP" {
+		t.Fatalf("over-deep payload got %d %s, want 400 JSON_TOO_DEEP", w.Code, w.Body.String())
+	}
+}
+
+func TestJSONLimitsRejectsOverLongArray(t *testing.T) {
+	router := jsonLimitRouter(0, jsonlimit.Limits{MaxArrayLength: 2})
+
+	w := post(router, "application/json; charset=utf-8", `{"transfers":[{},{},{}]}`)
+	if w.Code != http.StatusBadRequest || errorCode(t, w) != "JSON_ARRAY_TOO_LONG" {
+		t.Fatalf("over-long array got %d %s, want 400 JSON_ARRAY_TOO_LONG", w.Code, w.Body.String())
+	}
+}
+
+func TestJSONLimitsPassesBodyThrough(t *testing.T) {
+	router := jsonLimitRouter(0, jsonlimit.Limits{MaxDepth: 3, MaxArrayLength: 2})
+
+	body := `{"transfers":[{"amount":"1"},{"amount":"2"}]}`
+	w := post(router, gin.MIMEJSON, body)
+	if w.Code != http.StatusOK || w.Body.String() != body {
+		t.Fatalf("accepted payload got %d %q, want the body unchanged", w.Code, w.Body.String())
+	}
+
+	// Only JSON bodies are checked.
+	if w := post(router, gin.MIMEPlain, `[[[[[1]]]]]`); w.Code != http.StatusOK {
+		t.Fatalf("plain text body got %d, want 200", w.Code)
+	}
+}
+
+func TestJSONLimitsReportsOversizedBody(t *testing.T) {
+	router := jsonLimitRouter(16, jsonlimit.Limits{MaxDepth: 3})
+
+	w := post(router, gin.MIMEJSON, `{"transfers":["`+strings.Repeat("x", 64)+`"]}`)
+	if w.Code != http.StatusRequestEntityTooLarge || errorCode(t, w) != "REQUEST_TOO_LARGE" {
+		t.Fatalf("oversized body got %d %s, want 413 REQUEST_TOO_LARGE", w.Code, w.Body.String())
+	}
+}
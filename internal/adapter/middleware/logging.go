@@ -1,14 +1,20 @@
 package middleware
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+	"github.com/yourusername/gobank/internal/pkg/redact"
 )
 
-const RequestIDKey = "request_id"
+const (
+	RequestIDKey        = "request_id"
+	ValidationFieldsKey = "validation_fields"
+)
 
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -22,12 +28,22 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
-func Logging(log *logger.Logger) gin.HandlerFunc {
+// Logging logs each request once it has been served. With logBodies it
+// also logs JSON request and response bodies of up to maxBodyBytes, with
+// credentials redacted.
+func Logging(log *logger.Logger, logBodies bool, maxBodyBytes int) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
+		var requestBody, responseBody *cappedBuffer
+		if logBodies {
+			requestBody = captureRequestBody(c, maxBodyBytes)
+			responseBody = &cappedBuffer{limit: maxBodyBytes}
+			c.Writer = &bodyCaptureWriter{ResponseWriter: c.Writer, body: responseBody}
+		}
+
 		c.Next()
 
 		latency := time.Since(start)
@@ -36,6 +52,12 @@ func Logging(log *logger.Logger) gin.HandlerFunc {
 		method := c.Request.Method
 		userAgent := c.Request.UserAgent()
 
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequestDuration.WithLabelValues(method, route, strconv.Itoa(statusCode)).Observe(latency.Seconds())
+
 		requestID, _ := c.Get(RequestIDKey)
 
 		logEvent := log.Info()
@@ -53,7 +75,44 @@ func Logging(log *logger.Logger) gin.HandlerFunc {
 			Int("status", statusCode).
 			Dur("latency", latency).
 			Str("client_ip", clientIP).
-			Str("user_agent", userAgent).
-			Msg("HTTP request")
+			Str("user_agent", userAgent)
+
+		if logBodies {
+			addBody(logEvent, "request_body", requestBody, redact.Request)
+			addBody(logEvent, "response_body", responseBody, redact.JSON)
+		}
+
+		logEvent.Msg("HTTP request")
+	}
+}
+
+// ValidationLogging records requests that failed input validation. The metric
+// is always updated; the log line, which carries only the failing field names
+// and never their values, is emitted when enabled.
+func ValidationLogging(log *logger.Logger, enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		value, exists := c.Get(ValidationFieldsKey)
+		if !exists {
+			return
+		}
+		fields, _ := value.([]string)
+
+		route := c.FullPath()
+		metrics.ValidationFailuresTotal.WithLabelValues(route).Inc()
+
+		if !enabled {
+			return
+		}
+
+		requestID, _ := c.Get(RequestIDKey)
+		log.Info().
+			Str("request_id", requestID.(string)).
+			Str("method", c.Request.Method).
+			Str("route", route).
+			Strs("fields", fields).
+			Str("client_ip", c.ClientIP()).
+			Msg("Validation failed")
 	}
 }
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// Maintenance rejects requests with 503 while maintenance mode is on. Reads
+// pass through when allowReads is set, and admins are never blocked. It must
+// run after Auth for the admin bypass to take effect.
+func Maintenance(mode *redis.MaintenanceMode, allowReads bool, retryAfter time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !mode.IsEnabled(c.Request.Context()) {
+			c.Next()
+			return
+		}
+
+		if allowReads && isReadMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		if role, exists := c.Get(UserRoleKey); exists && role == string(entity.RoleAdmin) {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error": apperror.ErrMaintenance,
+		})
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func maintenanceRouter(mode *redis.MaintenanceMode, allowReads bool, role entity.UserRole) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if role != "" {
+			c.Set(UserRoleKey, string(role))
+		}
+		c.Next()
+	})
+	router.Use(Maintenance(mode, allowReads, 2*time.Minute))
+	router.Any("/accounts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func serve(router http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMaintenanceBlocksWrites(t *testing.T) {
+	router := maintenanceRouter(redis.NewMaintenanceMode(nil, true), true, entity.RoleUser)
+
+	w := serve(router, http.MethodPost, "/accounts")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("POST during maintenance got %d, want 503", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "120" {
+		t.Fatalf("Retry-After = %q, want 120", got)
+	}
+}
+
+func TestMaintenanceReads(t *testing.T) {
+	mode := redis.NewMaintenanceMode(nil, true)
+
+	if w := serve(maintenanceRouter(mode, true, entity.RoleUser), http.MethodGet, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("GET with reads allowed got %d, want 200", w.Code)
+	}
+	if w := serve(maintenanceRouter(mode, false, entity.RoleUser), http.MethodGet, "/accounts"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("GET with reads blocked got %d, want 503", w.Code)
+	}
+}
+
+func TestMaintenanceAdminBypass(t *testing.T) {
+	router := maintenanceRouter(redis.NewMaintenanceMode(nil, true), false, entity.RoleAdmin)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		if w := serve(router, method, "/accounts"); w.Code != http.StatusOK {
+			t.Fatalf("admin %s during maintenance got %d, want 200", method, w.Code)
+		}
+	}
+}
+
+func TestMaintenanceToggledAtRuntime(t *testing.T) {
+	mode := redis.NewMaintenanceMode(testutil.Redis(t), false)
+	ctx := context.Background()
+	router := maintenanceRouter(mode, true, entity.RoleUser)
+
+	if w := serve(router, http.MethodPost, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("POST with maintenance off got %d, want 200", w.Code)
+	}
+
+	if err := mode.Set(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = mode.Set(context.Background(), false) })
+
+	if w := serve(router, http.MethodPost, "/accounts"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("POST with maintenance on got %d, want 503", w.Code)
+	}
+
+	if err := mode.Set(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(router, http.MethodPost, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("POST after maintenance was turned off got %d, want 200", w.Code)
+	}
+}
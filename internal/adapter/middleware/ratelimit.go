@@ -1,62 +1,189 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/domain/service"
 	"github.com/yourusername/gobank/internal/pkg/apperror"
 )
 
 func RateLimit(limiter *redis.RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		key := c.ClientIP()
-
-		if userID, exists := c.Get(UserIDKey); exists {
-			key = fmt.Sprintf("user:%v", userID)
-		}
-
-		allowed, remaining, err := limiter.Allow(c.Request.Context(), key)
-		if err != nil {
+		if isInternalService(c) {
 			c.Next()
 			return
 		}
 
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limiter.GetLimit()))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+		applyLimit(c, limiter, RateLimitKey(c))
+	}
+}
 
-		if !allowed {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": apperror.ErrTooManyRequests,
-			})
-			return
-		}
+// RouteLimit overrides the default rate limit for a route. Name keeps the
+// route's counters apart from every other limit's.
+type RouteLimit struct {
+	Name     string
+	Requests int
+	Window   time.Duration
+}
+
+// RateLimitWithConfig is RateLimit with the route's own limit instead of
+// limiter's.
+func RateLimitWithConfig(limiter *redis.RateLimiter, limit RouteLimit) gin.HandlerFunc {
+	return RateLimit(limiter.ForRoute(limit.Name, limit.Requests, limit.Window))
+}
 
+// applyLimit charges the request to key and rejects it with 429 once the
+// limit is reached. The limiter failing open keeps Redis outages from taking
+// the API down.
+func applyLimit(c *gin.Context, limiter *redis.RateLimiter, key string) {
+	applyLimitN(c, limiter, key, 1)
+}
+
+// applyLimitN is applyLimit for a request that costs as much as n requests.
+func applyLimitN(c *gin.Context, limiter *redis.RateLimiter, key string, n int) {
+	result, err := limiter.CheckN(c.Request.Context(), key, n)
+	if err != nil {
 		c.Next()
+		return
+	}
+
+	c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limiter.GetLimit()))
+	c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", result.Remaining))
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(result.RetryAfter)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error": apperror.ErrTooManyRequests,
+		})
+		return
 	}
+
+	c.Next()
 }
 
+// retryAfterSeconds rounds d up to whole seconds, at least one, as the
+// Retry-After header requires.
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// RateLimitKey is the counter RateLimit charges for the request: the
+// authenticated user if there is one, otherwise the client IP.
+func RateLimitKey(c *gin.Context) string {
+	if userID, exists := c.Get(UserIDKey); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return c.ClientIP()
+}
+
+// RateLimitByIP limits requests per client IP. It guards the credential
+// endpoints, so unlike RateLimit it applies to internal services too: a
+// leaked service token must not lift the limit on guessing passwords.
 func RateLimitByIP(limiter *redis.RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		key := fmt.Sprintf("ip:%s", c.ClientIP())
+		applyLimit(c, limiter, fmt.Sprintf("ip:%s", c.ClientIP()))
+	}
+}
+
+// RateLimitByIPWithConfig is RateLimitByIP with the route's own limit
+// instead of limiter's.
+func RateLimitByIPWithConfig(limiter *redis.RateLimiter, limit RouteLimit) gin.HandlerFunc {
+	return RateLimitByIP(limiter.ForRoute(limit.Name, limit.Requests, limit.Window))
+}
 
-		allowed, remaining, err := limiter.Allow(c.Request.Context(), key)
-		if err != nil {
+// TransferRateLimit charges transfers between two accounts owned by the caller
+// against the internal limiter instead of the general per-user budget. The
+// source account is read from the body's from_account_id, or from the
+// fromParam path parameter on routes that name it in the path. A nil
+// internalLimiter charges every transfer to the general budget. A batch is
+// charged one request per transfer in it.
+func TransferRateLimit(limiter, internalLimiter *redis.RateLimiter, accountService service.AccountService, fromParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isInternalService(c) {
 			c.Next()
 			return
 		}
 
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", limiter.GetLimit()))
-		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+		if c.Request.Method != http.MethodPost {
+			applyLimit(c, limiter, RateLimitKey(c))
+			return
+		}
+		if n := batchSize(c); n > 0 {
+			applyLimitN(c, limiter, RateLimitKey(c), n)
+			return
+		}
 
-		if !allowed {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": apperror.ErrTooManyRequests,
-			})
+		userID, exists := c.Get(UserIDKey)
+		if internalLimiter == nil || !exists || !isOwnAccountTransfer(c, accountService, userID.(uuid.UUID), fromParam) {
+			applyLimit(c, limiter, RateLimitKey(c))
 			return
 		}
 
-		c.Next()
+		applyLimit(c, internalLimiter, fmt.Sprintf("internal:user:%v", userID))
 	}
 }
+
+// batchSize is the number of transfers in a batch request's transfers
+// array, or 0 if the body is not a batch.
+func batchSize(c *gin.Context) int {
+	body, ok := peekBody(c)
+	if !ok {
+		return 0
+	}
+
+	var input struct {
+		Transfers []json.RawMessage `json:"transfers"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		return 0
+	}
+	return len(input.Transfers)
+}
+
+// peekBody reads the request body and puts it back for the handler.
+func peekBody(c *gin.Context) ([]byte, bool) {
+	if c.Request.Body == nil {
+		return nil, false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err == nil
+}
+
+func isOwnAccountTransfer(c *gin.Context, accountService service.AccountService, userID uuid.UUID, fromParam string) bool {
+	body, ok := peekBody(c)
+	if !ok {
+		return false
+	}
+
+	var input struct {
+		FromAccountID uuid.UUID `json:"from_account_id"`
+		ToAccountID   uuid.UUID `json:"to_account_id"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		return false
+	}
+	if fromParam != "" {
+		input.FromAccountID, _ = uuid.Parse(c.Param(fromParam))
+	}
+	if input.FromAccountID == uuid.Nil || input.ToAccountID == uuid.Nil {
+		return false
+	}
+
+	owned, err := accountService.OwnsAccounts(c.Request.Context(), userID, input.FromAccountID, input.ToAccountID)
+	return err == nil && owned
+}
@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+// ownershipStub answers OwnsAccounts from a fixed set of accounts.
+type ownershipStub struct {
+	service.AccountService
+	accounts map[uuid.UUID]uuid.UUID
+}
+
+func (s ownershipStub) OwnsAccounts(_ context.Context, userID uuid.UUID, accountIDs ...uuid.UUID) (bool, error) {
+	for _, id := range accountIDs {
+		if s.accounts[id] != userID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// withUser stands in for Auth.
+func withUser(userID uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(UserIDKey, userID)
+		c.Next()
+	}
+}
+
+func postTransfer(router http.Handler, path, body string) int {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestTransferRateLimitOwnAccountsSkipGeneralBudget(t *testing.T) {
+	redisDB := testutil.Redis(t)
+	ctx := context.Background()
+
+	general := redis.NewRateLimiter(redisDB, 3, 0, redis.AlgorithmSlidingWindow)
+	internal := general.ForRoute("internal", 100, time.Minute)
+
+	userID := uuid.New()
+	checking, savings, someoneElses := uuid.New(), uuid.New(), uuid.New()
+	accounts := ownershipStub{accounts: map[uuid.UUID]uuid.UUID{
+		checking:     userID,
+		savings:      userID,
+		someoneElses: uuid.New(),
+	}}
+
+	router := gin.New()
+	router.Use(withUser(userID), TransferRateLimit(general, internal, accounts, ""))
+	router.POST("/transfers", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	own := fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":"1.00"}`, checking, savings)
+	for i := 0; i < 10; i++ {
+		if code := postTransfer(router, "/transfers", own); code != http.StatusCreated {
+			t.Fatalf("own-account transfer %d got status %d", i+1, code)
+		}
+	}
+
+	remaining, _, err := general.Peek(ctx, fmt.Sprintf("user:%v", userID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 3 {
+		t.Fatalf("general budget remaining = %d after own-account transfers, want 3", remaining)
+	}
+
+	external := fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":"1.00"}`, checking, someoneElses)
+	for i := 0; i < 3; i++ {
+		if code := postTransfer(router, "/transfers", external); code != http.StatusCreated {
+			t.Fatalf("external transfer %d got status %d", i+1, code)
+		}
+	}
+	if code := postTransfer(router, "/transfers", external); code != http.StatusTooManyRequests {
+		t.Fatalf("external transfer over the general limit got status %d, want 429", code)
+	}
+
+	// The general limit being used up does not hold back own-account moves.
+	if code := postTransfer(router, "/transfers", own); code != http.StatusCreated {
+		t.Fatalf("own-account transfer after the general limit got status %d", code)
+	}
+}
+
+func TestTransferRateLimitReadsSourceAccountFromPath(t *testing.T) {
+	redisDB := testutil.Redis(t)
+	ctx := context.Background()
+
+	general := redis.NewRateLimiter(redisDB, 3, 0, redis.AlgorithmSlidingWindow)
+	internal := general.ForRoute("internal", 100, time.Minute)
+
+	userID := uuid.New()
+	checking, savings := uuid.New(), uuid.New()
+	accounts := ownershipStub{accounts: map[uuid.UUID]uuid.UUID{checking: userID, savings: userID}}
+
+	router := gin.New()
+	router.POST("/accounts/:id/move", withUser(userID), TransferRateLimit(general, internal, accounts, "id"),
+		func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	body := fmt.Sprintf(`{"to_account_id":%q,"amount":"1.00"}`, savings)
+	for i := 0; i < 5; i++ {
+		if code := postTransfer(router, "/accounts/"+checking.String()+"/move", body); code != http.StatusCreated {
+			t.Fatalf("move %d got status %d", i+1, code)
+		}
+	}
+
+	remaining, _, err := general.Peek(ctx, fmt.Sprintf("user:%v", userID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 3 {
+		t.Fatalf("general budget remaining = %d after moves, want 3", remaining)
+	}
+}
+
+func TestTransferRateLimitChargesBatchesPerTransfer(t *testing.T) {
+	redisDB := testutil.Redis(t)
+	ctx := context.Background()
+
+	general := redis.NewRateLimiter(redisDB, 5, 0, redis.AlgorithmSlidingWindow)
+	internal := general.ForRoute("internal", 100, time.Minute)
+
+	userID := uuid.New()
+	checking, savings := uuid.New(), uuid.New()
+	accounts := ownershipStub{accounts: map[uuid.UUID]uuid.UUID{checking: userID, savings: userID}}
+
+	router := gin.New()
+	router.Use(withUser(userID), TransferRateLimit(general, internal, accounts, ""))
+	router.POST("/transfers/batch", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	transfer := fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q,"amount":"1.00"}`, checking, savings)
+	batch := func(n int) string {
+		return `{"transfers":[` + strings.TrimSuffix(strings.Repeat(transfer+",", n), ",") + `]}`
+	}
+
+	if code := postTransfer(router, "/transfers/batch", batch(3)); code != http.StatusCreated {
+		t.Fatalf("batch of 3 got status %d", code)
+	}
+	remaining, _, err := general.Peek(ctx, fmt.Sprintf("user:%v", userID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 2 {
+		t.Fatalf("general budget remaining = %d after a batch of 3, want 2", remaining)
+	}
+
+	if code := postTransfer(router, "/transfers/batch", batch(3)); code != http.StatusTooManyRequests {
+		t.Fatalf("batch of 3 with 2 left got status %d, want 429", code)
+	}
+	if code := postTransfer(router, "/transfers/batch", batch(2)); code != http.StatusCreated {
+		t.Fatalf("batch of 2 with 2 left got status %d", code)
+	}
+}
+
+func TestIsOwnAccountTransfer(t *testing.T) {
+	userID := uuid.New()
+	checking, savings, someoneElses := uuid.New(), uuid.New(), uuid.New()
+	accounts := ownershipStub{accounts: map[uuid.UUID]uuid.UUID{
+		checking:     userID,
+		savings:      userID,
+		someoneElses: uuid.New(),
+	}}
+
+	tests := []struct {
+		name      string
+		path      string
+		fromParam string
+		body      string
+		want      bool
+	}{
+		{"own accounts", "/transfers", "", fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q}`, checking, savings), true},
+		{"someone else's destination", "/transfers", "", fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q}`, checking, someoneElses), false},
+		{"missing source", "/transfers", "", fmt.Sprintf(`{"to_account_id":%q}`, savings), false},
+		{"malformed body", "/transfers", "", `{"from_account_id":`, false},
+		{"source from path", "/accounts/" + checking.String() + "/move", "id", fmt.Sprintf(`{"to_account_id":%q}`, savings), true},
+		{"path overrides body", "/accounts/" + someoneElses.String() + "/move", "id", fmt.Sprintf(`{"from_account_id":%q,"to_account_id":%q}`, checking, savings), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			var body string
+			router := gin.New()
+			handler := func(c *gin.Context) {
+				got = isOwnAccountTransfer(c, accounts, userID, tt.fromParam)
+				data, _ := c.GetRawData()
+				body = string(data)
+			}
+			router.POST("/transfers", handler)
+			router.POST("/accounts/:id/move", handler)
+
+			postTransfer(router, tt.path, tt.body)
+			if got != tt.want {
+				t.Fatalf("isOwnAccountTransfer = %v, want %v", got, tt.want)
+			}
+			if body != tt.body {
+				t.Fatalf("body after the check = %q, want it left for the handler", body)
+			}
+		})
+	}
+}
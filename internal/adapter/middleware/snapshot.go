@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// ReadSnapshot runs the rest of the chain inside a read-only transaction, so
+// a handler that makes several queries sees one consistent snapshot instead
+// of a mix of states from before and after a concurrent transfer. Use it
+// only on read routes; it holds a connection for the whole request.
+func ReadSnapshot(txManager repository.TransactionManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := txManager.WithReadOnlyTransaction(c.Request.Context(), func(ctx context.Context) error {
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			return nil
+		})
+		if err != nil && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": apperror.ErrInternalServer})
+		}
+	}
+}
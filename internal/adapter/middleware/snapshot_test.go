@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gobank/internal/domain/repository"
+)
+
+type snapshotKey struct{}
+
+// snapshots marks the context it hands to fn, and fails with err once fn
+// returns. WithTransaction is not implemented.
+type snapshots struct {
+	repository.TransactionManager
+	err error
+}
+
+func (s *snapshots) WithReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(context.WithValue(ctx, snapshotKey{}, true)); err != nil {
+		return err
+	}
+	return s.err
+}
+
+func snapshotRouter(txManager repository.TransactionManager, handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.GET("/dashboard", ReadSnapshot(txManager), handler)
+	return router
+}
+
+func TestReadSnapshotRunsHandlerInTransaction(t *testing.T) {
+	var inSnapshot bool
+	router := snapshotRouter(&snapshots{}, func(c *gin.Context) {
+		inSnapshot, _ = c.Request.Context().Value(snapshotKey{}).(bool)
+		c.Status(http.StatusOK)
+	})
+
+	if w := serve(router, http.MethodGet, "/dashboard"); w.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", w.Code)
+	}
+	if !inSnapshot {
+		t.Fatal("handler ran outside the read-only transaction")
+	}
+}
+
+func TestReadSnapshotFailure(t *testing.T) {
+	failing := &snapshots{err: errors.New("could not begin")}
+
+	// Nothing written yet: the failure is reported.
+	router := snapshotRouter(failing, func(c *gin.Context) {})
+	if w := serve(router, http.MethodGet, "/dashboard"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("unwritten response got %d, want 500", w.Code)
+	}
+
+	// A response already sent is left alone.
+	router = snapshotRouter(failing, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+	})
+	if w := serve(router, http.MethodGet, "/dashboard"); w.Code != http.StatusOK {
+		t.Fatalf("written response got %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// RejectSuspended aborts requests whose access token belongs to a suspended
+// user. It must run after Auth. Lookup failures are let through so a cache
+// outage does not take down every authenticated route.
+func RejectSuspended(userService service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get(UserIDKey)
+		if !exists {
+			c.Next()
+			return
+		}
+
+		suspended, err := userService.IsSuspended(c.Request.Context(), userID.(uuid.UUID))
+		if err == nil && suspended {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": apperror.ErrUserSuspended,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RejectRevokedSessions aborts requests whose access token was issued before
+// an admin force-logged-out its user. API-key requests carry no session and
+// pass through. As with RejectSuspended, lookup failures are let through.
+func RejectRevokedSessions(userService service.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get(UserIDKey)
+		issuedAt, issued := c.Get(TokenIssuedAtKey)
+		if !exists || !issued {
+			c.Next()
+			return
+		}
+
+		revoked, err := userService.IsTokenRevoked(c.Request.Context(), userID.(uuid.UUID), issuedAt.(time.Time))
+		if err == nil && revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": apperror.ErrSessionRevoked,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/service"
+)
+
+// suspensions answers IsSuspended from a fixed set of users. Other methods
+// are not implemented.
+type suspensions struct {
+	service.UserService
+	suspended map[uuid.UUID]bool
+	err       error
+}
+
+func (s *suspensions) IsSuspended(_ context.Context, userID uuid.UUID) (bool, error) {
+	return s.suspended[userID], s.err
+}
+
+func suspensionRouter(users service.UserService, userID uuid.UUID) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(UserIDKey, userID)
+		c.Next()
+	}, RejectSuspended(users))
+	router.GET("/accounts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestRejectSuspended(t *testing.T) {
+	suspendedID, activeID := uuid.New(), uuid.New()
+	users := &suspensions{suspended: map[uuid.UUID]bool{suspendedID: true}}
+
+	if w := serve(suspensionRouter(users, suspendedID), http.MethodGet, "/accounts"); w.Code != http.StatusForbidden {
+		t.Fatalf("suspended user got %d, want 403", w.Code)
+	}
+	if w := serve(suspensionRouter(users, activeID), http.MethodGet, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("active user got %d, want 200", w.Code)
+	}
+}
+
+func TestRejectSuspendedLetsLookupFailuresThrough(t *testing.T) {
+	userID := uuid.New()
+	users := &suspensions{suspended: map[uuid.UUID]bool{userID: true}, err: errors.New("cache down")}
+
+	if w := serve(suspensionRouter(users, userID), http.MethodGet, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("request during a lookup failure got %d, want 200", w.Code)
+	}
+}
+
+// revocations answers IsTokenRevoked as if the user was force-logged-out at
+// revokedAt. Other methods are not implemented.
+type revocations struct {
+	service.UserService
+	revokedAt time.Time
+	err       error
+}
+
+func (r *revocations) IsTokenRevoked(_ context.Context, _ uuid.UUID, issuedAt time.Time) (bool, error) {
+	return !issuedAt.After(r.revokedAt), r.err
+}
+
+func revocationRouter(users service.UserService, issuedAt *time.Time) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(UserIDKey, uuid.New())
+		if issuedAt != nil {
+			c.Set(TokenIssuedAtKey, *issuedAt)
+		}
+		c.Next()
+	}, RejectRevokedSessions(users))
+	router.GET("/accounts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestRejectRevokedSessions(t *testing.T) {
+	revokedAt := time.Now()
+	users := &revocations{revokedAt: revokedAt}
+
+	before, after := revokedAt.Add(-time.Minute), revokedAt.Add(time.Second)
+	if w := serve(revocationRouter(users, &before), http.MethodGet, "/accounts"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("token issued before the force-logout got %d, want 401", w.Code)
+	}
+	if w := serve(revocationRouter(users, &after), http.MethodGet, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("token issued after the force-logout got %d, want 200", w.Code)
+	}
+}
+
+func TestRejectRevokedSessionsSkipsAPIKeysAndLookupFailures(t *testing.T) {
+	users := &revocations{revokedAt: time.Now()}
+
+	// API-key requests carry no issue time.
+	if w := serve(revocationRouter(users, nil), http.MethodGet, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("API-key request got %d, want 200", w.Code)
+	}
+
+	before := users.revokedAt.Add(-time.Minute)
+	users.err = errors.New("cache down")
+	if w := serve(revocationRouter(users, &before), http.MethodGet, "/accounts"); w.Code != http.StatusOK {
+		t.Fatalf("request during a lookup failure got %d, want 200", w.Code)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,42 +14,34 @@ import (
 	"github.com/yourusername/gobank/internal/domain/entity"
 	"github.com/yourusername/gobank/internal/domain/repository"
 	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/accountnumber"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
 )
 
 type accountRepository struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	numbers *accountnumber.Format
 }
 
-func NewAccountRepository(db *database.PostgresDB) repository.AccountRepository {
-	return &accountRepository{pool: db.Pool}
+func NewAccountRepository(db *database.PostgresDB, numbers *accountnumber.Format) repository.AccountRepository {
+	return &accountRepository{pool: db.Pool, numbers: numbers}
 }
 
 func (r *accountRepository) Create(ctx context.Context, account *entity.Account) error {
 	if account.AccountNumber == "" {
-		account.AccountNumber = generateAccountNumber()
+		accountNumber, err := r.numbers.Generate()
+		if err != nil {
+			return err
+		}
+		account.AccountNumber = accountNumber
 	}
 
 	query := `
-		INSERT INTO accounts (id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO accounts (id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
-	if tx, ok := ctx.Value(database.TxKey{}).(pgx.Tx); ok {
-		_, err := tx.Exec(ctx, query,
-			account.ID,
-			account.UserID,
-			account.AccountNumber,
-			account.AccountType,
-			account.Currency,
-			account.Balance,
-			account.Status,
-			account.CreatedAt,
-			account.UpdatedAt,
-		)
-		return err
-	}
-
-	_, err := r.pool.Exec(ctx, query,
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		account.ID,
 		account.UserID,
 		account.AccountNumber,
@@ -59,18 +51,25 @@ func (r *accountRepository) Create(ctx context.Context, account *entity.Account)
 		account.Status,
 		account.CreatedAt,
 		account.UpdatedAt,
+		account.RequireMemo,
+		account.StatusReason,
+		account.ReactivationRequestedAt,
+		account.DailyTransferLimit,
+		account.MinBalance,
+		account.OverdraftLimit,
+		account.Version,
 	)
 	return err
 }
 
 func (r *accountRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
 	query := `
-		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at
+		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, deleted_at, version
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	account := &entity.Account{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&account.ID,
 		&account.UserID,
 		&account.AccountNumber,
@@ -80,6 +79,14 @@ func (r *accountRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.
 		&account.Status,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+		&account.RequireMemo,
+		&account.StatusReason,
+		&account.ReactivationRequestedAt,
+		&account.DailyTransferLimit,
+		&account.MinBalance,
+		&account.OverdraftLimit,
+		&account.DeletedAt,
+		&account.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -92,22 +99,14 @@ func (r *accountRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.
 
 func (r *accountRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
 	query := `
-		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at
+		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, deleted_at, version
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		FOR UPDATE
 	`
 
 	account := &entity.Account{}
-	var row pgx.Row
-
-	if tx, ok := ctx.Value(database.TxKey{}).(pgx.Tx); ok {
-		row = tx.QueryRow(ctx, query, id)
-	} else {
-		row = r.pool.QueryRow(ctx, query, id)
-	}
-
-	err := row.Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&account.ID,
 		&account.UserID,
 		&account.AccountNumber,
@@ -117,6 +116,14 @@ func (r *accountRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID)
 		&account.Status,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+		&account.RequireMemo,
+		&account.StatusReason,
+		&account.ReactivationRequestedAt,
+		&account.DailyTransferLimit,
+		&account.MinBalance,
+		&account.OverdraftLimit,
+		&account.DeletedAt,
+		&account.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -129,12 +136,12 @@ func (r *accountRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID)
 
 func (r *accountRepository) GetByAccountNumber(ctx context.Context, accountNumber string) (*entity.Account, error) {
 	query := `
-		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at
+		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, deleted_at, version
 		FROM accounts
-		WHERE account_number = $1
+		WHERE account_number = $1 AND deleted_at IS NULL
 	`
 	account := &entity.Account{}
-	err := r.pool.QueryRow(ctx, query, accountNumber).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, accountNumber).Scan(
 		&account.ID,
 		&account.UserID,
 		&account.AccountNumber,
@@ -144,6 +151,14 @@ func (r *accountRepository) GetByAccountNumber(ctx context.Context, accountNumbe
 		&account.Status,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+		&account.RequireMemo,
+		&account.StatusReason,
+		&account.ReactivationRequestedAt,
+		&account.DailyTransferLimit,
+		&account.MinBalance,
+		&account.OverdraftLimit,
+		&account.DeletedAt,
+		&account.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -156,13 +171,13 @@ func (r *accountRepository) GetByAccountNumber(ctx context.Context, accountNumbe
 
 func (r *accountRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.Account, error) {
 	query := `
-		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at
+		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, deleted_at, version
 		FROM accounts
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +196,14 @@ func (r *accountRepository) GetByUserID(ctx context.Context, userID uuid.UUID, l
 			&account.Status,
 			&account.CreatedAt,
 			&account.UpdatedAt,
+			&account.RequireMemo,
+			&account.StatusReason,
+			&account.ReactivationRequestedAt,
+			&account.DailyTransferLimit,
+			&account.MinBalance,
+			&account.OverdraftLimit,
+			&account.DeletedAt,
+			&account.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -190,55 +213,344 @@ func (r *accountRepository) GetByUserID(ctx context.Context, userID uuid.UUID, l
 }
 
 func (r *accountRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
-	query := `SELECT COUNT(*) FROM accounts WHERE user_id = $1`
+	query := `SELECT COUNT(*) FROM accounts WHERE user_id = $1 AND deleted_at IS NULL`
 	var count int64
-	err := r.pool.QueryRow(ctx, query, userID).Scan(&count)
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&count)
 	return count, err
 }
 
-func (r *accountRepository) Update(ctx context.Context, account *entity.Account) error {
+func (r *accountRepository) AggregateBalances(ctx context.Context) ([]*entity.BalanceAggregate, error) {
 	query := `
-		UPDATE accounts
-		SET account_type = $2, currency = $3, status = $4, updated_at = NOW()
-		WHERE id = $1
+		SELECT currency, account_type, COUNT(*), COALESCE(SUM(balance), 0)
+		FROM accounts
+		WHERE deleted_at IS NULL
+		GROUP BY currency, account_type
+		ORDER BY currency, account_type
 	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	if tx, ok := ctx.Value(database.TxKey{}).(pgx.Tx); ok {
-		_, err := tx.Exec(ctx, query,
-			account.ID,
-			account.AccountType,
-			account.Currency,
-			account.Status,
+	var aggregates []*entity.BalanceAggregate
+	for rows.Next() {
+		aggregate := &entity.BalanceAggregate{}
+		if err := rows.Scan(
+			&aggregate.Currency,
+			&aggregate.AccountType,
+			&aggregate.AccountCount,
+			&aggregate.TotalBalance,
+		); err != nil {
+			return nil, err
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+	return aggregates, rows.Err()
+}
+
+// FreezeDormant freezes active, funded accounts with no transactions since
+// inactiveSince. Accounts that never had a transaction are judged by their
+// creation time. An account.status_changed event is written for each frozen
+// account in the same statement.
+func (r *accountRepository) FreezeDormant(ctx context.Context, inactiveSince time.Time) (int64, error) {
+	query := `
+		WITH frozen AS (
+			UPDATE accounts a
+			SET status = 'frozen', status_reason = 'dormant', version = version + 1, updated_at = NOW()
+			WHERE a.status = 'active' AND a.deleted_at IS NULL
+				AND a.balance > 0
+				AND a.created_at < $1
+				AND NOT EXISTS (
+					SELECT 1 FROM transactions t
+					WHERE t.account_id = a.id AND t.created_at >= $1
+				)
+			RETURNING a.id, a.account_number, a.user_id
 		)
+		INSERT INTO outbox_events (event_type, aggregate_type, aggregate_id, payload)
+		SELECT $2, 'account', id, jsonb_build_object(
+			'account_id', id,
+			'account_number', account_number,
+			'user_id', user_id,
+			'previous_status', 'active',
+			'status', 'frozen'
+		)
+		FROM frozen
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, inactiveSince, entity.EventAccountStatusChanged)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *accountRepository) GetPendingReactivations(ctx context.Context, limit, offset int) ([]*entity.Account, error) {
+	query := `
+		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, deleted_at, version
+		FROM accounts
+		WHERE status = 'frozen' AND status_reason = 'dormant' AND reactivation_requested_at IS NOT NULL
+		ORDER BY reactivation_requested_at ASC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*entity.Account
+	for rows.Next() {
+		account := &entity.Account{}
+		if err := rows.Scan(
+			&account.ID,
+			&account.UserID,
+			&account.AccountNumber,
+			&account.AccountType,
+			&account.Currency,
+			&account.Balance,
+			&account.Status,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+			&account.RequireMemo,
+			&account.StatusReason,
+			&account.ReactivationRequestedAt,
+			&account.DailyTransferLimit,
+			&account.MinBalance,
+			&account.OverdraftLimit,
+			&account.DeletedAt,
+			&account.Version,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *accountRepository) CountPendingReactivations(ctx context.Context) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM accounts
+		WHERE status = 'frozen' AND status_reason = 'dormant' AND reactivation_requested_at IS NOT NULL
+	`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query).Scan(&count)
+	return count, err
+}
+
+func (r *accountRepository) Search(ctx context.Context, filter *entity.AccountSearchFilter, limit, offset int) ([]*entity.Account, error) {
+	where, args := accountSearchConditions(filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, deleted_at, version
+		FROM accounts
+		WHERE %s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*entity.Account
+	for rows.Next() {
+		account := &entity.Account{}
+		if err := rows.Scan(
+			&account.ID,
+			&account.UserID,
+			&account.AccountNumber,
+			&account.AccountType,
+			&account.Currency,
+			&account.Balance,
+			&account.Status,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+			&account.RequireMemo,
+			&account.StatusReason,
+			&account.ReactivationRequestedAt,
+			&account.DailyTransferLimit,
+			&account.MinBalance,
+			&account.OverdraftLimit,
+			&account.DeletedAt,
+			&account.Version,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *accountRepository) CountSearch(ctx context.Context, filter *entity.AccountSearchFilter) (int64, error) {
+	where, args := accountSearchConditions(filter)
+	query := `SELECT COUNT(*) FROM accounts WHERE ` + where
+
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// accountSearchConditions builds the WHERE clause for filter. Values are only
+// ever passed as positional parameters, never written into the SQL.
+func accountSearchConditions(filter *entity.AccountSearchFilter) (string, []interface{}) {
+	conditions := []string{"TRUE"}
+	var args []interface{}
+
+	if filter != nil {
+		if filter.Status != nil {
+			args = append(args, *filter.Status)
+			conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+		}
+		if filter.Currency != nil {
+			args = append(args, *filter.Currency)
+			conditions = append(conditions, fmt.Sprintf("currency = $%d", len(args)))
+		}
+		if filter.MinBalance != nil {
+			args = append(args, *filter.MinBalance)
+			conditions = append(conditions, fmt.Sprintf("balance >= $%d", len(args)))
+		}
+		if filter.UserID != nil {
+			args = append(args, *filter.UserID)
+			conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *accountRepository) ExportOpenedBefore(ctx context.Context, before time.Time, fn func(*entity.Account) error) error {
+	query := `
+		SELECT id, user_id, account_number, account_type, currency, balance, status, created_at, updated_at, require_memo, status_reason, reactivation_requested_at, daily_transfer_limit, min_balance, overdraft_limit, deleted_at, version
+		FROM accounts
+		WHERE created_at < $1
+		ORDER BY created_at, id
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, before)
+	if err != nil {
 		return err
 	}
+	defer rows.Close()
+
+	for rows.Next() {
+		account := &entity.Account{}
+		if err := rows.Scan(
+			&account.ID,
+			&account.UserID,
+			&account.AccountNumber,
+			&account.AccountType,
+			&account.Currency,
+			&account.Balance,
+			&account.Status,
+			&account.CreatedAt,
+			&account.UpdatedAt,
+			&account.RequireMemo,
+			&account.StatusReason,
+			&account.ReactivationRequestedAt,
+			&account.DailyTransferLimit,
+			&account.MinBalance,
+			&account.OverdraftLimit,
+			&account.DeletedAt,
+			&account.Version,
+		); err != nil {
+			return err
+		}
+		if err := fn(account); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
 
-	_, err := r.pool.Exec(ctx, query,
+// Update writes the account's mutable fields if its version is still the
+// one that was read, and advances account.Version. A concurrent write since
+// the read fails with ErrConflict, and an attempt to change the currency with
+// ErrCurrencyImmutable.
+func (r *accountRepository) Update(ctx context.Context, account *entity.Account) error {
+	// Currency is fixed at creation; it only appears in the WHERE clause so an
+	// attempt to change it is detected instead of silently applied.
+	query := `
+		UPDATE accounts
+		SET account_type = $2, status = $3, require_memo = $5, status_reason = $6,
+			reactivation_requested_at = $7, daily_transfer_limit = $8, min_balance = $10, overdraft_limit = $11,
+			version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND currency = $4 AND version = $9
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		account.ID,
 		account.AccountType,
-		account.Currency,
 		account.Status,
+		account.Currency,
+		account.RequireMemo,
+		account.StatusReason,
+		account.ReactivationRequestedAt,
+		account.DailyTransferLimit,
+		account.Version,
+		account.MinBalance,
+		account.OverdraftLimit,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.updateMissed(ctx, account)
+	}
+	account.Version++
+	return nil
+}
+
+// updateMissed explains why a versioned update matched no row. An account
+// that no longer exists is left for the caller's own checks.
+func (r *accountRepository) updateMissed(ctx context.Context, account *entity.Account) error {
+	query := `SELECT currency FROM accounts WHERE id = $1`
+	var currency entity.Currency
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, account.ID).Scan(&currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if currency != account.Currency {
+		return apperror.ErrCurrencyImmutable
+	}
+	return apperror.ErrConflict
 }
 
-func (r *accountRepository) UpdateBalance(ctx context.Context, id uuid.UUID, newBalance decimal.Decimal) error {
+// Close soft-deletes an open account with a zero balance. It reports false if
+// the account is already closed or still holds funds.
+func (r *accountRepository) Close(ctx context.Context, id uuid.UUID) (bool, error) {
 	query := `
 		UPDATE accounts
-		SET balance = $2, updated_at = NOW()
-		WHERE id = $1
+		SET deleted_at = NOW(), status = 'inactive', status_reason = 'closed', version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND balance = 0
 	`
 
-	if tx, ok := ctx.Value(database.TxKey{}).(pgx.Tx); ok {
-		_, err := tx.Exec(ctx, query, id, newBalance)
-		return err
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, id)
+	if err != nil {
+		return false, err
 	}
-
-	_, err := r.pool.Exec(ctx, query, id, newBalance)
-	return err
+	return tag.RowsAffected() > 0, nil
 }
 
-func generateAccountNumber() string {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return fmt.Sprintf("%010d", rng.Int63n(10000000000))
+// UpdateBalance sets the account's balance under the same version check as
+// Update. Callers on the transfer path hold the row lock, so for them the
+// check only fails if the account was read before it was locked.
+func (r *accountRepository) UpdateBalance(ctx context.Context, account *entity.Account, newBalance decimal.Decimal) error {
+	query := `
+		UPDATE accounts
+		SET balance = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND version = $3
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, account.ID, newBalance, account.Version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.ErrConflict
+	}
+	account.Version++
+	return nil
 }
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestAccountUpdateKeepsCurrency(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+	repo := newAccountRepository(t, db)
+
+	account := createAccount(t, db, createUser(t, db).ID, "USD", "50")
+
+	changed := *account
+	changed.Currency = "EUR"
+	if err := repo.Update(ctx, &changed); !errors.Is(err, apperror.ErrCurrencyImmutable) {
+		t.Fatalf("Update with a new currency = %v, want ErrCurrencyImmutable", err)
+	}
+
+	got, err := repo.GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Currency != "USD" {
+		t.Fatalf("currency after a refused update = %s, want USD", got.Currency)
+	}
+
+	got.Status = entity.AccountStatusFrozen
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update keeping the currency: %v", err)
+	}
+	got, err = repo.GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != entity.AccountStatusFrozen || got.Currency != "USD" {
+		t.Fatalf("account after update = %s %s, want frozen USD", got.Status, got.Currency)
+	}
+}
+
+func TestAggregateBalances(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+	repo := newAccountRepository(t, db)
+
+	alice := createUser(t, db).ID
+	bob := createUser(t, db).ID
+
+	createAccount(t, db, alice, "USD", "100.25")
+	createAccount(t, db, bob, "USD", "50")
+	createAccount(t, db, bob, "EUR", "10.5")
+	createAccount(t, db, alice, "GBP", "0")
+
+	savings := entity.NewAccount(alice, "", entity.AccountTypeSavings, "USD")
+	savings.Balance = decimal.RequireFromString("1000")
+	if err := repo.Create(ctx, savings); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closed accounts are not holdings.
+	closed := createAccount(t, db, bob, "EUR", "0")
+	if ok, err := repo.Close(ctx, closed.ID); err != nil || !ok {
+		t.Fatalf("close account: %v, %v", ok, err)
+	}
+
+	aggregates, err := repo.AggregateBalances(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		currency    entity.Currency
+		accountType entity.AccountType
+		count       int64
+		total       string
+	}{
+		{"EUR", entity.AccountTypeChecking, 1, "10.5"},
+		{"GBP", entity.AccountTypeChecking, 1, "0"},
+		{"USD", entity.AccountTypeChecking, 2, "150.25"},
+		{"USD", entity.AccountTypeSavings, 1, "1000"},
+	}
+	if len(aggregates) != len(want) {
+		t.Fatalf("got %d aggregates, want %d", len(aggregates), len(want))
+	}
+	for i, w := range want {
+		got := aggregates[i]
+		if got.Currency != w.currency || got.AccountType != w.accountType || got.AccountCount != w.count ||
+			!got.TotalBalance.Equal(decimal.RequireFromString(w.total)) {
+			t.Errorf("aggregate %d = %s %s %d %s, want %s %s %d %s", i,
+				got.Currency, got.AccountType, got.AccountCount, got.TotalBalance,
+				w.currency, w.accountType, w.count, w.total)
+		}
+	}
+}
+
+func TestAccountUpdateConflictsWithSweeps(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+	repo := newAccountRepository(t, db)
+	userID := createUser(t, db).ID
+
+	t.Run("frozen as dormant", func(t *testing.T) {
+		account := createAccount(t, db, userID, "USD", "100")
+		if _, err := db.Pool.Exec(ctx, `UPDATE accounts SET created_at = NOW() - INTERVAL '1 year' WHERE id = $1`, account.ID); err != nil {
+			t.Fatal(err)
+		}
+		stale, err := repo.GetByID(ctx, account.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.FreezeDormant(ctx, time.Now().Add(-24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		stale.RequireMemo = true
+		if err := repo.Update(ctx, stale); !errors.Is(err, apperror.ErrConflict) {
+			t.Fatalf("Update after a dormancy freeze = %v, want ErrConflict", err)
+		}
+		got, err := repo.GetByID(ctx, account.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.IsDormant() {
+			t.Fatalf("status after a refused update = %s (%s), want dormant", got.Status, got.StatusReason)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		account := createAccount(t, db, userID, "USD", "0")
+		stale, err := repo.GetByID(ctx, account.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := repo.Close(ctx, account.ID); err != nil || !ok {
+			t.Fatalf("close account: %v, %v", ok, err)
+		}
+
+		stale.Status = entity.AccountStatusFrozen
+		if err := repo.Update(ctx, stale); !errors.Is(err, apperror.ErrConflict) {
+			t.Fatalf("Update after close = %v, want ErrConflict", err)
+		}
+	})
+}
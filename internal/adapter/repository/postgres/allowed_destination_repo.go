@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+type allowedDestinationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAllowedDestinationRepository(db *database.PostgresDB) repository.AllowedDestinationRepository {
+	return &allowedDestinationRepository{pool: db.Pool}
+}
+
+func (r *allowedDestinationRepository) Add(ctx context.Context, accountID, destinationAccountID uuid.UUID) error {
+	query := `
+		INSERT INTO account_allowed_destinations (account_id, destination_account_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, accountID, destinationAccountID)
+	return err
+}
+
+func (r *allowedDestinationRepository) Remove(ctx context.Context, accountID, destinationAccountID uuid.UUID) (bool, error) {
+	query := `DELETE FROM account_allowed_destinations WHERE account_id = $1 AND destination_account_id = $2`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, accountID, destinationAccountID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *allowedDestinationRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]*entity.AllowedDestination, error) {
+	query := `
+		SELECT d.destination_account_id, a.account_number, d.created_at
+		FROM account_allowed_destinations d
+		JOIN accounts a ON a.id = d.destination_account_id
+		WHERE d.account_id = $1
+		ORDER BY d.created_at
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var destinations []*entity.AllowedDestination
+	for rows.Next() {
+		destination := &entity.AllowedDestination{}
+		if err := rows.Scan(
+			&destination.AccountID,
+			&destination.AccountNumber,
+			&destination.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		destinations = append(destinations, destination)
+	}
+	return destinations, rows.Err()
+}
+
+func (r *allowedDestinationRepository) IsAllowed(ctx context.Context, accountID, destinationAccountID uuid.UUID) (bool, error) {
+	query := `
+		SELECT NOT EXISTS (SELECT 1 FROM account_allowed_destinations WHERE account_id = $1)
+			OR EXISTS (SELECT 1 FROM account_allowed_destinations WHERE account_id = $1 AND destination_account_id = $2)
+	`
+
+	var allowed bool
+	if err := database.Querier(ctx, r.pool).QueryRow(ctx, query, accountID, destinationAccountID).Scan(&allowed); err != nil {
+		return false, err
+	}
+	return allowed, nil
+}
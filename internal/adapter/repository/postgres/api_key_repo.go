@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+type apiKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(db *database.PostgresDB) repository.APIKeyRepository {
+	return &apiKeyRepository{pool: db.Pool}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, user_id, name, key_prefix, key_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.KeyPrefix,
+		key.KeyHash,
+		scopesToStrings(key.Scopes),
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+	return err
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, scopes, last_used_at, expires_at, revoked_at, created_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+	key := &entity.APIKey{}
+	var scopes []string
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, keyHash).Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.KeyPrefix,
+		&key.KeyHash,
+		&scopes,
+		&key.LastUsedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	key.Scopes = stringsToScopes(scopes)
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.APIKey, error) {
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, scopes, last_used_at, expires_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*entity.APIKey
+	for rows.Next() {
+		key := &entity.APIKey{}
+		var scopes []string
+		if err := rows.Scan(
+			&key.ID,
+			&key.UserID,
+			&key.Name,
+			&key.KeyPrefix,
+			&key.KeyHash,
+			&scopes,
+			&key.LastUsedAt,
+			&key.ExpiresAt,
+			&key.RevokedAt,
+			&key.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		key.Scopes = stringsToScopes(scopes)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE api_keys SET last_used_at = NOW() WHERE id = $1`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id)
+	return err
+}
+
+func scopesToStrings(scopes []entity.APIKeyScope) []string {
+	out := make([]string, len(scopes))
+	for i, s := range scopes {
+		out[i] = string(s)
+	}
+	return out
+}
+
+func stringsToScopes(scopes []string) []entity.APIKeyScope {
+	out := make([]entity.APIKeyScope, len(scopes))
+	for i, s := range scopes {
+		out[i] = entity.APIKeyScope(s)
+	}
+	return out
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+type balanceAlertRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBalanceAlertRepository(db *database.PostgresDB) repository.BalanceAlertRepository {
+	return &balanceAlertRepository{pool: db.Pool}
+}
+
+func (r *balanceAlertRepository) Create(ctx context.Context, alert *entity.BalanceAlert) error {
+	query := `
+		INSERT INTO balance_alerts (id, user_id, account_id, condition, threshold, currency, recurring, notify_email, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		alert.ID,
+		alert.UserID,
+		alert.AccountID,
+		alert.Condition,
+		alert.Threshold,
+		alert.Currency,
+		alert.Recurring,
+		alert.NotifyEmail,
+		alert.Active,
+		alert.CreatedAt,
+		alert.UpdatedAt,
+	)
+	return err
+}
+
+func (r *balanceAlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.BalanceAlert, error) {
+	query := `
+		SELECT id, user_id, account_id, condition, threshold, currency, recurring, notify_email, active, last_triggered_at, created_at, updated_at
+		FROM balance_alerts
+		WHERE id = $1
+	`
+	alert := &entity.BalanceAlert{}
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&alert.ID,
+		&alert.UserID,
+		&alert.AccountID,
+		&alert.Condition,
+		&alert.Threshold,
+		&alert.Currency,
+		&alert.Recurring,
+		&alert.NotifyEmail,
+		&alert.Active,
+		&alert.LastTriggeredAt,
+		&alert.CreatedAt,
+		&alert.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func (r *balanceAlertRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.BalanceAlert, error) {
+	query := `
+		SELECT id, user_id, account_id, condition, threshold, currency, recurring, notify_email, active, last_triggered_at, created_at, updated_at
+		FROM balance_alerts
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*entity.BalanceAlert
+	for rows.Next() {
+		alert := &entity.BalanceAlert{}
+		if err := rows.Scan(
+			&alert.ID,
+			&alert.UserID,
+			&alert.AccountID,
+			&alert.Condition,
+			&alert.Threshold,
+			&alert.Currency,
+			&alert.Recurring,
+			&alert.NotifyEmail,
+			&alert.Active,
+			&alert.LastTriggeredAt,
+			&alert.CreatedAt,
+			&alert.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func (r *balanceAlertRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `SELECT COUNT(*) FROM balance_alerts WHERE user_id = $1`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *balanceAlertRepository) Update(ctx context.Context, alert *entity.BalanceAlert) error {
+	query := `
+		UPDATE balance_alerts
+		SET threshold = $2, recurring = $3, notify_email = $4, active = $5, updated_at = $6
+		WHERE id = $1
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		alert.ID,
+		alert.Threshold,
+		alert.Recurring,
+		alert.NotifyEmail,
+		alert.Active,
+		alert.UpdatedAt,
+	)
+	return err
+}
+
+func (r *balanceAlertRepository) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	query := `DELETE FROM balance_alerts WHERE id = $1 AND user_id = $2`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *balanceAlertRepository) GetActiveByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]*entity.BalanceAlert, error) {
+	query := `
+		SELECT id, user_id, account_id, condition, threshold, currency, recurring, notify_email, active, last_triggered_at, created_at, updated_at
+		FROM balance_alerts
+		WHERE account_id = ANY($1) AND active
+		ORDER BY created_at
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*entity.BalanceAlert
+	for rows.Next() {
+		alert := &entity.BalanceAlert{}
+		if err := rows.Scan(
+			&alert.ID,
+			&alert.UserID,
+			&alert.AccountID,
+			&alert.Condition,
+			&alert.Threshold,
+			&alert.Currency,
+			&alert.Recurring,
+			&alert.NotifyEmail,
+			&alert.Active,
+			&alert.LastTriggeredAt,
+			&alert.CreatedAt,
+			&alert.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func (r *balanceAlertRepository) MarkTriggered(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE balance_alerts
+		SET last_triggered_at = $2, active = recurring, updated_at = $2
+		WHERE id = $1
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, at)
+	return err
+}
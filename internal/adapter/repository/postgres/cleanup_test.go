@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/reference"
+	"github.com/yourusername/gobank/internal/testutil"
+	"github.com/yourusername/gobank/internal/usecase/cleanup"
+)
+
+func TestCleanupSweepRemovesExpiredRowsOfEachType(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	user := createUser(t, db)
+	from := createAccount(t, db, user.ID, "USD", "100")
+	to := createAccount(t, db, user.ID, "USD", "0")
+
+	refreshTokens := NewRefreshTokenRepository(db)
+	resetTokens := NewPasswordResetTokenRepository(db)
+	transfers := NewTransferRepository(db)
+
+	for _, expiresAt := range []time.Time{now.Add(-time.Hour), now.Add(time.Hour)} {
+		err := refreshTokens.Create(ctx, &entity.RefreshToken{
+			ID: uuid.New(), UserID: user.ID, FamilyID: uuid.New(),
+			TokenHash: uuid.NewString(), ExpiresAt: expiresAt, CreatedAt: now,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = resetTokens.Create(ctx, &entity.PasswordResetToken{
+			ID: uuid.New(), UserID: user.ID,
+			TokenHash: uuid.NewString(), ExpiresAt: expiresAt, CreatedAt: now,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, createdAt := range []time.Time{now.Add(-48 * time.Hour), now} {
+		key := uuid.NewString()
+		transfer := entity.NewTransfer(from.ID, to.ID, decimal.NewFromInt(1), "USD", &key)
+		transfer.CreatedAt = createdAt
+		ref, err := reference.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		transfer.ReferenceNumber = ref
+		if err := transfers.Create(ctx, transfer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	cleanup.NewJob(time.Minute, &logger.Logger{Logger: &log},
+		cleanup.Sweeper{Name: "refresh_token", Sweep: refreshTokens.DeleteExpired},
+		cleanup.Sweeper{Name: "password_reset_token", Sweep: resetTokens.DeleteExpired},
+		cleanup.Sweeper{Name: "idempotency_key", Retention: 24 * time.Hour, Sweep: transfers.ClearIdempotencyKeys},
+	).RunOnce(ctx)
+
+	for _, check := range []struct {
+		name  string
+		query string
+	}{
+		{"refresh_token", `SELECT COUNT(*) FROM refresh_tokens`},
+		{"password_reset_token", `SELECT COUNT(*) FROM password_reset_tokens`},
+		{"idempotency_key", `SELECT COUNT(*) FROM transfers WHERE idempotency_key IS NOT NULL`},
+	} {
+		var left int
+		if err := db.Pool.QueryRow(ctx, check.query).Scan(&left); err != nil {
+			t.Fatal(err)
+		}
+		if left != 1 {
+			t.Errorf("%s: %d rows left after the sweep, want only the unexpired one", check.name, left)
+		}
+	}
+}
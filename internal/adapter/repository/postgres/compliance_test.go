@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/reference"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestExportForComplianceDateRange(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+	transfers := NewTransferRepository(db)
+
+	user := createUser(t, db)
+	from := createAccount(t, db, user.ID, "USD", "100")
+	to := createAccount(t, db, user.ID, "USD", "0")
+
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	created := map[uuid.UUID]time.Time{}
+	for _, createdAt := range []time.Time{
+		start.Add(-time.Second), // before the range
+		start,                   // first instant, included
+		end.Add(-time.Second),   // last second, included
+		end,                     // end is exclusive
+	} {
+		transfer := entity.NewTransfer(from.ID, to.ID, decimal.NewFromInt(1), "USD", nil)
+		transfer.CreatedAt = createdAt
+		ref, err := reference.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		transfer.ReferenceNumber = ref
+		if err := transfers.Create(ctx, transfer); err != nil {
+			t.Fatal(err)
+		}
+		created[transfer.ID] = createdAt
+	}
+
+	var got []*entity.ComplianceTransferRow
+	err := transfers.ExportForCompliance(ctx, start, end, func(row *entity.ComplianceTransferRow) error {
+		got = append(got, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("exported %d transfers, want 2", len(got))
+	}
+	if !got[0].CreatedAt.Equal(start) || !got[1].CreatedAt.Equal(end.Add(-time.Second)) {
+		t.Fatalf("exported transfers created at %v and %v", got[0].CreatedAt, got[1].CreatedAt)
+	}
+	for _, row := range got {
+		if _, ok := created[row.ID]; !ok {
+			t.Fatalf("exported unknown transfer %s", row.ID)
+		}
+		if row.FromAccountNumber != from.AccountNumber || row.ToAccountNumber != to.AccountNumber {
+			t.Fatalf("exported accounts %s -> %s, want full account numbers", row.FromAccountNumber, row.ToAccountNumber)
+		}
+	}
+}
+
+func TestExportOpenedBeforeIncludesClosedAccounts(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+	accounts := newAccountRepository(t, db)
+
+	user := createUser(t, db)
+	closed := createAccount(t, db, user.ID, "USD", "0")
+	if _, err := accounts.Close(ctx, closed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cutoff := time.Now().Add(time.Minute)
+	later := createAccount(t, db, user.ID, "USD", "0")
+	if _, err := db.Pool.Exec(ctx, `UPDATE accounts SET created_at = $2 WHERE id = $1`, later.ID, cutoff); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[uuid.UUID]*entity.Account{}
+	err := accounts.ExportOpenedBefore(ctx, cutoff, func(account *entity.Account) error {
+		seen[account.ID] = account
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if account := seen[closed.ID]; account == nil || account.DeletedAt == nil {
+		t.Fatalf("closed account exported as %+v, want it with its closing time", account)
+	}
+	if seen[later.ID] != nil {
+		t.Fatal("account opened after the cutoff was exported")
+	}
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestFreezeDormantActivityBoundary(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+	repo := newAccountRepository(t, db)
+	transactions := NewTransactionRepository(db)
+
+	cutoff := time.Now().UTC().Add(-90 * 24 * time.Hour).Truncate(time.Second)
+	userID := createUser(t, db).ID
+
+	// account opens a funded account at openedAt, with its last transaction
+	// at lastActivity if that is set.
+	account := func(balance string, openedAt time.Time, lastActivity *time.Time) *entity.Account {
+		t.Helper()
+
+		account := createAccount(t, db, userID, "USD", balance)
+		if _, err := db.Pool.Exec(ctx, `UPDATE accounts SET created_at = $2 WHERE id = $1`, account.ID, openedAt); err != nil {
+			t.Fatal(err)
+		}
+		if lastActivity != nil {
+			amount := decimal.RequireFromString("1")
+			transaction := entity.NewTransaction(account.ID, entity.TransactionTypeCredit, amount, amount, "test", nil)
+			if err := transactions.Create(ctx, transaction); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.Pool.Exec(ctx, `UPDATE transactions SET created_at = $2 WHERE id = $1`, transaction.ID, *lastActivity); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return account
+	}
+	at := func(offset time.Duration) *time.Time {
+		ts := cutoff.Add(offset)
+		return &ts
+	}
+
+	longAgo := cutoff.Add(-365 * 24 * time.Hour)
+	idleJustPast := account("100", longAgo, at(-time.Second))
+	activeAtCutoff := account("100", longAgo, at(0))
+	activeSince := account("100", longAgo, at(time.Hour))
+	neverUsed := account("100", longAgo, nil)
+	openedAfterCutoff := account("100", cutoff.Add(time.Hour), nil)
+	empty := account("0", longAgo, at(-time.Hour))
+	inactive := account("100", longAgo, at(-time.Hour))
+	inactive.Status = entity.AccountStatusInactive
+	if err := repo.Update(ctx, inactive); err != nil {
+		t.Fatal(err)
+	}
+
+	frozen, err := repo.FreezeDormant(ctx, cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frozen != 2 {
+		t.Fatalf("froze %d accounts, want 2", frozen)
+	}
+
+	events, err := NewOutboxRepository(db).GetUnpublishedForUpdate(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evented := make(map[uuid.UUID]bool)
+	for _, event := range events {
+		if event.EventType != entity.EventAccountStatusChanged {
+			t.Errorf("event type = %s, want %s", event.EventType, entity.EventAccountStatusChanged)
+		}
+		var payload struct {
+			UserID         uuid.UUID            `json:"user_id"`
+			PreviousStatus entity.AccountStatus `json:"previous_status"`
+			Status         entity.AccountStatus `json:"status"`
+		}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.UserID != userID || payload.PreviousStatus != entity.AccountStatusActive || payload.Status != entity.AccountStatusFrozen {
+			t.Errorf("event payload = %+v, want %s active -> frozen", payload, userID)
+		}
+		evented[event.AggregateID] = true
+	}
+	if len(events) != 2 || !evented[idleJustPast.ID] || !evented[neverUsed.ID] {
+		t.Fatalf("got %d status events, want one for each frozen account", len(events))
+	}
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		dormant bool
+	}{
+		{"last activity just before the cutoff", idleJustPast.ID, true},
+		{"last activity at the cutoff", activeAtCutoff.ID, false},
+		{"activity since the cutoff", activeSince.ID, false},
+		{"no activity, opened before the cutoff", neverUsed.ID, true},
+		{"no activity, opened after the cutoff", openedAfterCutoff.ID, false},
+		{"zero balance", empty.ID, false},
+		{"already inactive", inactive.ID, false},
+	}
+	for _, tt := range tests {
+		got, err := repo.GetByID(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.IsDormant() != tt.dormant {
+			t.Errorf("%s: status %s (%s), dormant = %v, want %v", tt.name, got.Status, got.StatusReason, got.IsDormant(), tt.dormant)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/accountnumber"
+)
+
+func newAccountRepository(t *testing.T, db *database.PostgresDB) *accountRepository {
+	t.Helper()
+
+	numbers, err := accountnumber.NewFormat("", 10, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAccountRepository(db, numbers).(*accountRepository)
+}
+
+func createUser(t *testing.T, db *database.PostgresDB) *entity.User {
+	t.Helper()
+
+	user := entity.NewUser(uuid.NewString()+"@example.com", "hash", "Test User")
+	if err := NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func createAccount(t *testing.T, db *database.PostgresDB, userID uuid.UUID, currency entity.Currency, balance string) *entity.Account {
+	t.Helper()
+
+	account := entity.NewAccount(userID, "", entity.AccountTypeChecking, currency)
+	account.Balance = decimal.RequireFromString(balance)
+	if err := newAccountRepository(t, db).Create(context.Background(), account); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	return account
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+type outboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *database.PostgresDB) repository.OutboxRepository {
+	return &outboxRepository{pool: db.Pool}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, event *entity.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_events (id, event_type, aggregate_type, aggregate_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		event.ID,
+		event.EventType,
+		event.AggregateType,
+		event.AggregateID,
+		event.Payload,
+		event.CreatedAt,
+	)
+	return err
+}
+
+// GetUnpublishedForUpdate returns the oldest unpublished events and locks
+// them for the surrounding transaction. Rows locked by another relay are
+// skipped rather than waited on.
+func (r *outboxRepository) GetUnpublishedForUpdate(ctx context.Context, limit int) ([]*entity.OutboxEvent, error) {
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, attempts, last_error, created_at, published_at
+		FROM outbox_events
+		WHERE published_at IS NULL
+		ORDER BY created_at ASC
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*entity.OutboxEvent
+	for rows.Next() {
+		event := &entity.OutboxEvent{}
+		if err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.Payload,
+			&event.Attempts,
+			&event.LastError,
+			&event.CreatedAt,
+			&event.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE outbox_events
+		SET published_at = NOW(), attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $2
+		WHERE id = $1
+	`
+
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, lastError)
+	return err
+}
+
+func (r *outboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox_events WHERE published_at IS NOT NULL AND published_at < $1`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
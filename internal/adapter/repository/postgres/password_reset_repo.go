@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+type passwordResetTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasswordResetTokenRepository(db *database.PostgresDB) repository.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{pool: db.Pool}
+}
+
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// Consume marks an unused, unexpired token as used and returns it. It returns
+// nil if there is no such token, so a token can only be redeemed once even
+// under concurrent requests.
+func (r *passwordResetTokenRepository) Consume(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+	`
+	token := &entity.PasswordResetToken{}
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// InvalidateByUserID marks every outstanding token of the user as used.
+func (r *passwordResetTokenRepository) InvalidateByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, userID)
+	return err
+}
+
+func (r *passwordResetTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, `DELETE FROM password_reset_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+type recurringTransferRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRecurringTransferRepository(db *database.PostgresDB) repository.RecurringTransferRepository {
+	return &recurringTransferRepository{pool: db.Pool}
+}
+
+func (r *recurringTransferRepository) Create(ctx context.Context, recurring *entity.RecurringTransfer) error {
+	query := `
+		INSERT INTO recurring_transfers (id, user_id, from_account_id, to_account_id, amount, currency, description, frequency, timezone, start_at, end_date, next_run_at, run_count, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		recurring.ID,
+		recurring.UserID,
+		recurring.FromAccountID,
+		recurring.ToAccountID,
+		recurring.Amount,
+		recurring.Currency,
+		recurring.Description,
+		recurring.Interval,
+		recurring.Timezone,
+		recurring.StartAt,
+		recurring.EndDate,
+		recurring.NextRunAt,
+		recurring.RunCount,
+		recurring.Status,
+		recurring.CreatedAt,
+		recurring.UpdatedAt,
+	)
+	return err
+}
+
+func (r *recurringTransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.RecurringTransfer, error) {
+	query := `
+		SELECT id, user_id, from_account_id, to_account_id, amount, currency, description, frequency, timezone, start_at, end_date, next_run_at, run_count, status, created_at, updated_at
+		FROM recurring_transfers
+		WHERE id = $1
+	`
+	recurring := &entity.RecurringTransfer{}
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&recurring.ID,
+		&recurring.UserID,
+		&recurring.FromAccountID,
+		&recurring.ToAccountID,
+		&recurring.Amount,
+		&recurring.Currency,
+		&recurring.Description,
+		&recurring.Interval,
+		&recurring.Timezone,
+		&recurring.StartAt,
+		&recurring.EndDate,
+		&recurring.NextRunAt,
+		&recurring.RunCount,
+		&recurring.Status,
+		&recurring.CreatedAt,
+		&recurring.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (r *recurringTransferRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.RecurringTransfer, error) {
+	query := `
+		SELECT id, user_id, from_account_id, to_account_id, amount, currency, description, frequency, timezone, start_at, end_date, next_run_at, run_count, status, created_at, updated_at
+		FROM recurring_transfers
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recurrings []*entity.RecurringTransfer
+	for rows.Next() {
+		recurring := &entity.RecurringTransfer{}
+		if err := rows.Scan(
+			&recurring.ID,
+			&recurring.UserID,
+			&recurring.FromAccountID,
+			&recurring.ToAccountID,
+			&recurring.Amount,
+			&recurring.Currency,
+			&recurring.Description,
+			&recurring.Interval,
+			&recurring.Timezone,
+			&recurring.StartAt,
+			&recurring.EndDate,
+			&recurring.NextRunAt,
+			&recurring.RunCount,
+			&recurring.Status,
+			&recurring.CreatedAt,
+			&recurring.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		recurrings = append(recurrings, recurring)
+	}
+	return recurrings, rows.Err()
+}
+
+func (r *recurringTransferRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `SELECT COUNT(*) FROM recurring_transfers WHERE user_id = $1`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *recurringTransferRepository) GetDueForUpdate(ctx context.Context, now time.Time, limit int) ([]*entity.RecurringTransfer, error) {
+	query := `
+		SELECT id, user_id, from_account_id, to_account_id, amount, currency, description, frequency, timezone, start_at, end_date, next_run_at, run_count, status, created_at, updated_at
+		FROM recurring_transfers
+		WHERE status = 'active' AND next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recurrings []*entity.RecurringTransfer
+	for rows.Next() {
+		recurring := &entity.RecurringTransfer{}
+		if err := rows.Scan(
+			&recurring.ID,
+			&recurring.UserID,
+			&recurring.FromAccountID,
+			&recurring.ToAccountID,
+			&recurring.Amount,
+			&recurring.Currency,
+			&recurring.Description,
+			&recurring.Interval,
+			&recurring.Timezone,
+			&recurring.StartAt,
+			&recurring.EndDate,
+			&recurring.NextRunAt,
+			&recurring.RunCount,
+			&recurring.Status,
+			&recurring.CreatedAt,
+			&recurring.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		recurrings = append(recurrings, recurring)
+	}
+	return recurrings, rows.Err()
+}
+
+func (r *recurringTransferRepository) UpdateSchedule(ctx context.Context, recurring *entity.RecurringTransfer) error {
+	query := `
+		UPDATE recurring_transfers
+		SET next_run_at = $2, run_count = $3, status = $4, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, recurring.ID, recurring.NextRunAt, recurring.RunCount, recurring.Status)
+	return err
+}
+
+func (r *recurringTransferRepository) Cancel(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE recurring_transfers
+		SET status = 'cancelled', next_run_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND status = 'active'
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
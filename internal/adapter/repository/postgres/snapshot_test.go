@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestReadOnlyTransactionSeesOneSnapshot(t *testing.T) {
+	db := testutil.Postgres(t)
+	accounts := newAccountRepository(t, db)
+
+	user := createUser(t, db)
+	from := createAccount(t, db, user.ID, "USD", "100")
+	to := createAccount(t, db, user.ID, "USD", "0")
+
+	err := db.WithReadOnlyTransaction(context.Background(), func(ctx context.Context) error {
+		before, err := accounts.GetByID(ctx, from.ID)
+		if err != nil {
+			return err
+		}
+
+		// A transfer commits between the two reads.
+		commit, err := db.Pool.Begin(context.Background())
+		if err != nil {
+			return err
+		}
+		if _, err := commit.Exec(context.Background(), `UPDATE accounts SET balance = balance - 40 WHERE id = $1`, from.ID); err != nil {
+			return err
+		}
+		if _, err := commit.Exec(context.Background(), `UPDATE accounts SET balance = balance + 40 WHERE id = $1`, to.ID); err != nil {
+			return err
+		}
+		if err := commit.Commit(context.Background()); err != nil {
+			return err
+		}
+
+		after, err := accounts.GetByID(ctx, to.ID)
+		if err != nil {
+			return err
+		}
+
+		// Both reads come from before the transfer, so no money is missing
+		// or counted twice.
+		if total := before.Balance.Add(after.Balance); !total.Equal(decimal.RequireFromString("100")) {
+			t.Errorf("snapshot total = %s (from %s, to %s), want 100", total, before.Balance, after.Balance)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Outside the snapshot the transfer is visible.
+	got, err := accounts.GetByID(context.Background(), to.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Balance.Equal(decimal.RequireFromString("40")) {
+		t.Fatalf("balance after the snapshot = %s, want 40", got.Balance)
+	}
+}
+
+func TestReadOnlyTransactionRejectsWrites(t *testing.T) {
+	db := testutil.Postgres(t)
+	accounts := newAccountRepository(t, db)
+
+	user := createUser(t, db)
+	account := createAccount(t, db, user.ID, "USD", "100")
+
+	err := db.WithReadOnlyTransaction(context.Background(), func(ctx context.Context) error {
+		stored, err := accounts.GetByID(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		return accounts.UpdateBalance(ctx, stored, decimal.RequireFromString("0"))
+	})
+	if err == nil {
+		t.Fatal("write inside a read-only transaction succeeded")
+	}
+}
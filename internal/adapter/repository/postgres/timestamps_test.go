@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestTimestampsRoundTripAsUTC(t *testing.T) {
+	db := testutil.Postgres(t)
+	ctx := context.Background()
+
+	user := createUser(t, db)
+	account := createAccount(t, db, user.ID, "USD", "0")
+
+	// Written in another zone, read back as the same instant in UTC.
+	written := time.Date(2024, 3, 10, 1, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+	if _, err := db.Pool.Exec(ctx, `UPDATE accounts SET created_at = $2 WHERE id = $1`, account.ID, written); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := newAccountRepository(t, db).GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CreatedAt.Location() != time.UTC {
+		t.Fatalf("created_at read in %v, want UTC", got.CreatedAt.Location())
+	}
+	if !got.CreatedAt.Equal(written) {
+		t.Fatalf("created_at = %v, want %v", got.CreatedAt, written)
+	}
+
+	body, err := json.Marshal(got.ToResponse(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response struct {
+		CreatedAt string `json:"created_at"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.CreatedAt != "2024-03-10T06:30:00Z" {
+		t.Fatalf("response created_at = %q, want 2024-03-10T06:30:00Z", response.CreatedAt)
+	}
+}
+
+func TestTimestampsScanAsUTCWhateverTheSessionZone(t *testing.T) {
+	db := testutil.Postgres(t)
+
+	err := db.WithTransaction(context.Background(), func(ctx context.Context) error {
+		tx, _ := database.TxFromContext(ctx)
+		if _, err := tx.Exec(ctx, `SET LOCAL TIME ZONE 'America/New_York'`); err != nil {
+			return err
+		}
+
+		var now time.Time
+		if err := tx.QueryRow(ctx, `SELECT now()`).Scan(&now); err != nil {
+			return err
+		}
+		if now.Location() != time.UTC {
+			t.Errorf("now() scanned in %v, want UTC", now.Location())
+		}
+		if !strings.HasSuffix(now.Format(time.RFC3339), "Z") {
+			t.Errorf("now() formats as %s, want a Z suffix", now.Format(time.RFC3339))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
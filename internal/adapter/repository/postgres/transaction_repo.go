@@ -3,14 +3,19 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 	"github.com/yourusername/gobank/internal/domain/entity"
 	"github.com/yourusername/gobank/internal/domain/repository"
 	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
 )
 
 type transactionRepository struct {
@@ -23,25 +28,11 @@ func NewTransactionRepository(db *database.PostgresDB) repository.TransactionRep
 
 func (r *transactionRepository) Create(ctx context.Context, transaction *entity.Transaction) error {
 	query := `
-		INSERT INTO transactions (id, account_id, type, amount, balance_after, description, reference_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO transactions (id, account_id, type, amount, balance_after, description, reference_id, created_at, category, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10::text[], '{}'))
 	`
 
-	if tx, ok := ctx.Value(database.TxKey{}).(pgx.Tx); ok {
-		_, err := tx.Exec(ctx, query,
-			transaction.ID,
-			transaction.AccountID,
-			transaction.Type,
-			transaction.Amount,
-			transaction.BalanceAfter,
-			transaction.Description,
-			transaction.ReferenceID,
-			transaction.CreatedAt,
-		)
-		return err
-	}
-
-	_, err := r.pool.Exec(ctx, query,
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		transaction.ID,
 		transaction.AccountID,
 		transaction.Type,
@@ -50,18 +41,20 @@ func (r *transactionRepository) Create(ctx context.Context, transaction *entity.
 		transaction.Description,
 		transaction.ReferenceID,
 		transaction.CreatedAt,
+		transaction.Category,
+		transaction.Tags,
 	)
 	return err
 }
 
 func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
 	query := `
-		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at
+		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at, category, tags
 		FROM transactions
 		WHERE id = $1
 	`
 	tx := &entity.Transaction{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&tx.ID,
 		&tx.AccountID,
 		&tx.Type,
@@ -70,6 +63,8 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*ent
 		&tx.Description,
 		&tx.ReferenceID,
 		&tx.CreatedAt,
+		&tx.Category,
+		&tx.Tags,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -82,13 +77,13 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*ent
 
 func (r *transactionRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*entity.Transaction, error) {
 	query := `
-		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at
+		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at, category, tags
 		FROM transactions
 		WHERE account_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.pool.Query(ctx, query, accountID, limit, offset)
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, accountID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +101,8 @@ func (r *transactionRepository) GetByAccountID(ctx context.Context, accountID uu
 			&tx.Description,
 			&tx.ReferenceID,
 			&tx.CreatedAt,
+			&tx.Category,
+			&tx.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -114,15 +111,186 @@ func (r *transactionRepository) GetByAccountID(ctx context.Context, accountID uu
 	return transactions, rows.Err()
 }
 
+// GetByAccountIDAfter returns the transactions matching filter that sort
+// after (cursor, cursorID) in newest-first order. Unlike an offset, the
+// position is stable when new transactions arrive between pages.
+func (r *transactionRepository) GetByAccountIDAfter(ctx context.Context, accountID uuid.UUID, filter *entity.TransactionFilter, cursor time.Time, cursorID uuid.UUID, limit int) ([]*entity.Transaction, error) {
+	where, args := accountTransactionConditions(accountID, filter)
+	args = append(args, cursor, cursorID, limit)
+
+	query := fmt.Sprintf(`
+		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at, category, tags
+		FROM transactions
+		WHERE %s AND (created_at, id) < ($%d, $%d)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d
+	`, where, len(args)-2, len(args)-1, len(args))
+
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*entity.Transaction
+	for rows.Next() {
+		tx := &entity.Transaction{}
+		if err := rows.Scan(
+			&tx.ID,
+			&tx.AccountID,
+			&tx.Type,
+			&tx.Amount,
+			&tx.BalanceAfter,
+			&tx.Description,
+			&tx.ReferenceID,
+			&tx.CreatedAt,
+			&tx.Category,
+			&tx.Tags,
+		); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, rows.Err()
+}
+
+// GetByUserID lists transactions on every account the user owns, newest
+// first, with each row's account number and currency.
+func (r *transactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter, limit, offset int) ([]*entity.AccountTransaction, error) {
+	where, args := userTransactionConditions(userID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.account_id, t.type, t.amount, t.balance_after, t.description, t.reference_id, t.created_at, t.category, t.tags,
+			a.account_number, a.currency
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE %s
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*entity.AccountTransaction
+	for rows.Next() {
+		tx := &entity.Transaction{}
+		entry := &entity.AccountTransaction{Transaction: tx}
+		if err := rows.Scan(
+			&tx.ID,
+			&tx.AccountID,
+			&tx.Type,
+			&tx.Amount,
+			&tx.BalanceAfter,
+			&tx.Description,
+			&tx.ReferenceID,
+			&tx.CreatedAt,
+			&tx.Category,
+			&tx.Tags,
+			&entry.AccountNumber,
+			&entry.Currency,
+		); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, entry)
+	}
+	return transactions, rows.Err()
+}
+
+func (r *transactionRepository) CountByUserID(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter) (int64, error) {
+	where, args := userTransactionConditions(userID, filter)
+	query := `
+		SELECT COUNT(*)
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE ` + where
+
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func userTransactionConditions(userID uuid.UUID, filter *entity.TransactionFilter) (string, []interface{}) {
+	conditions := []string{"a.user_id = $1"}
+	args := []interface{}{userID}
+
+	if filter != nil {
+		if filter.StartDate != nil {
+			args = append(args, *filter.StartDate)
+			conditions = append(conditions, fmt.Sprintf("t.created_at >= $%d", len(args)))
+		}
+		if filter.EndDate != nil {
+			args = append(args, *filter.EndDate)
+			conditions = append(conditions, fmt.Sprintf("t.created_at <= $%d", len(args)))
+		}
+		if filter.Type != nil {
+			args = append(args, *filter.Type)
+			conditions = append(conditions, fmt.Sprintf("t.type = $%d", len(args)))
+		}
+		if filter.Category != nil {
+			args = append(args, *filter.Category)
+			conditions = append(conditions, fmt.Sprintf("t.category = $%d", len(args)))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
 func (r *transactionRepository) GetByAccountIDAndDateRange(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time, limit, offset int) ([]*entity.Transaction, error) {
 	query := `
-		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at
+		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at, category, tags
 		FROM transactions
 		WHERE account_id = $1 AND created_at >= $2 AND created_at <= $3
 		ORDER BY created_at DESC
 		LIMIT $4 OFFSET $5
 	`
-	rows, err := r.pool.Query(ctx, query, accountID, startDate, endDate, limit, offset)
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, accountID, startDate, endDate, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*entity.Transaction
+	for rows.Next() {
+		tx := &entity.Transaction{}
+		if err := rows.Scan(
+			&tx.ID,
+			&tx.AccountID,
+			&tx.Type,
+			&tx.Amount,
+			&tx.BalanceAfter,
+			&tx.Description,
+			&tx.ReferenceID,
+			&tx.CreatedAt,
+			&tx.Category,
+			&tx.Tags,
+		); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, rows.Err()
+}
+
+// GetByAccountIDFiltered lists the account's transactions that match the
+// filter, newest first.
+func (r *transactionRepository) GetByAccountIDFiltered(ctx context.Context, accountID uuid.UUID, filter *entity.TransactionFilter, limit, offset int) ([]*entity.Transaction, error) {
+	where, args := accountTransactionConditions(accountID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT id, account_id, type, amount, balance_after, description, reference_id, created_at, category, tags
+		FROM transactions
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +308,8 @@ func (r *transactionRepository) GetByAccountIDAndDateRange(ctx context.Context,
 			&tx.Description,
 			&tx.ReferenceID,
 			&tx.CreatedAt,
+			&tx.Category,
+			&tx.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -148,10 +318,99 @@ func (r *transactionRepository) GetByAccountIDAndDateRange(ctx context.Context,
 	return transactions, rows.Err()
 }
 
+func (r *transactionRepository) CountByAccountIDFiltered(ctx context.Context, accountID uuid.UUID, filter *entity.TransactionFilter) (int64, error) {
+	where, args := accountTransactionConditions(accountID, filter)
+	query := `SELECT COUNT(*) FROM transactions WHERE ` + where
+
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func accountTransactionConditions(accountID uuid.UUID, filter *entity.TransactionFilter) (string, []interface{}) {
+	conditions := []string{"account_id = $1"}
+	args := []interface{}{accountID}
+
+	if filter != nil {
+		if filter.StartDate != nil {
+			args = append(args, *filter.StartDate)
+			conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+		}
+		if filter.EndDate != nil {
+			args = append(args, *filter.EndDate)
+			conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+		}
+		if filter.Type != nil {
+			args = append(args, *filter.Type)
+			conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+		}
+		if filter.Category != nil {
+			args = append(args, *filter.Category)
+			conditions = append(conditions, fmt.Sprintf("category = $%d", len(args)))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// SpendingByCategory totals the account's debits between start and end by
+// category, largest first. Uncategorized debits are grouped under a nil
+// category.
+func (r *transactionRepository) SpendingByCategory(ctx context.Context, accountID uuid.UUID, start, end time.Time) ([]*entity.CategorySpending, error) {
+	query := `
+		SELECT category, SUM(amount), COUNT(*)
+		FROM transactions
+		WHERE account_id = $1 AND type = $2 AND created_at >= $3 AND created_at <= $4
+		GROUP BY category
+		ORDER BY SUM(amount) DESC, category
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, accountID, entity.TransactionTypeDebit, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spending []*entity.CategorySpending
+	for rows.Next() {
+		c := &entity.CategorySpending{}
+		if err := rows.Scan(&c.Category, &c.Total, &c.Count); err != nil {
+			return nil, err
+		}
+		spending = append(spending, c)
+	}
+	return spending, rows.Err()
+}
+
+func (r *transactionRepository) GetBalanceBefore(ctx context.Context, accountID uuid.UUID, before time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT balance_after
+		FROM transactions
+		WHERE account_id = $1 AND created_at < $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+	var balance decimal.Decimal
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, accountID, before).Scan(&balance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return balance, nil
+}
+
 func (r *transactionRepository) CountByAccountID(ctx context.Context, accountID uuid.UUID) (int64, error) {
 	query := `SELECT COUNT(*) FROM transactions WHERE account_id = $1`
 	var count int64
-	err := r.pool.QueryRow(ctx, query, accountID).Scan(&count)
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, accountID).Scan(&count)
+	return count, err
+}
+
+func (r *transactionRepository) CountByAccountIDAndDateRange(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) (int64, error) {
+	query := `SELECT COUNT(*) FROM transactions WHERE account_id = $1 AND created_at >= $2 AND created_at <= $3`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, accountID, startDate, endDate).Scan(&count)
 	return count, err
 }
 
@@ -165,47 +424,98 @@ func NewTransferRepository(db *database.PostgresDB) repository.TransferRepositor
 
 func (r *transferRepository) Create(ctx context.Context, transfer *entity.Transfer) error {
 	query := `
-		INSERT INTO transfers (id, idempotency_key, from_account_id, to_account_id, amount, currency, status, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO transfers (id, idempotency_key, reference_number, from_account_id, to_account_id, amount, currency, status, created_at, refund_of, reversal_of, scheduled_at, exchange_rate, converted_amount, converted_currency, fee, description, request_hash, category, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, COALESCE($20::text[], '{}'))
 	`
 
-	if tx, ok := ctx.Value(database.TxKey{}).(pgx.Tx); ok {
-		_, err := tx.Exec(ctx, query,
-			transfer.ID,
-			transfer.IdempotencyKey,
-			transfer.FromAccountID,
-			transfer.ToAccountID,
-			transfer.Amount,
-			transfer.Currency,
-			transfer.Status,
-			transfer.CreatedAt,
-		)
-		return err
-	}
-
-	_, err := r.pool.Exec(ctx, query,
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		transfer.ID,
 		transfer.IdempotencyKey,
+		transfer.ReferenceNumber,
 		transfer.FromAccountID,
 		transfer.ToAccountID,
 		transfer.Amount,
 		transfer.Currency,
 		transfer.Status,
 		transfer.CreatedAt,
+		transfer.RefundOf,
+		transfer.ReversalOf,
+		transfer.ScheduledAt,
+		transfer.ExchangeRate,
+		transfer.ConvertedAmount,
+		transfer.ConvertedCurrency,
+		transfer.Fee,
+		transfer.Description,
+		transfer.RequestHash,
+		transfer.Category,
+		transfer.Tags,
 	)
+	return mapTransferInsertError(err)
+}
+
+// mapTransferInsertError reports a transfer whose idempotency key was stored
+// by a concurrent request as ErrDuplicateTransfer, so the caller can resolve
+// it against the transfer that won.
+func mapTransferInsertError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "transfers_idempotency_key_key" {
+		return apperror.ErrDuplicateTransfer
+	}
 	return err
 }
 
 func (r *transferRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Transfer, error) {
 	query := `
-		SELECT id, idempotency_key, from_account_id, to_account_id, amount, currency, status, created_at, completed_at
+		SELECT id, idempotency_key, reference_number, from_account_id, to_account_id, amount, currency, status, created_at, completed_at, refunded_amount, refund_of, reversal_of, scheduled_at, exchange_rate, converted_amount, converted_currency, fee, description, category, tags
+		FROM transfers
+		WHERE id = $1
+	`
+	transfer := &entity.Transfer{}
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&transfer.ID,
+		&transfer.IdempotencyKey,
+		&transfer.ReferenceNumber,
+		&transfer.FromAccountID,
+		&transfer.ToAccountID,
+		&transfer.Amount,
+		&transfer.Currency,
+		&transfer.Status,
+		&transfer.CreatedAt,
+		&transfer.CompletedAt,
+		&transfer.RefundedAmount,
+		&transfer.RefundOf,
+		&transfer.ReversalOf,
+		&transfer.ScheduledAt,
+		&transfer.ExchangeRate,
+		&transfer.ConvertedAmount,
+		&transfer.ConvertedCurrency,
+		&transfer.Fee,
+		&transfer.Description,
+		&transfer.Category,
+		&transfer.Tags,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (r *transferRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transfer, error) {
+	query := `
+		SELECT id, idempotency_key, reference_number, from_account_id, to_account_id, amount, currency, status, created_at, completed_at, refunded_amount, refund_of, reversal_of, scheduled_at, exchange_rate, converted_amount, converted_currency, fee, description, category, tags
 		FROM transfers
 		WHERE id = $1
+		FOR UPDATE
 	`
+
 	transfer := &entity.Transfer{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&transfer.ID,
 		&transfer.IdempotencyKey,
+		&transfer.ReferenceNumber,
 		&transfer.FromAccountID,
 		&transfer.ToAccountID,
 		&transfer.Amount,
@@ -213,6 +523,17 @@ func (r *transferRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity
 		&transfer.Status,
 		&transfer.CreatedAt,
 		&transfer.CompletedAt,
+		&transfer.RefundedAmount,
+		&transfer.RefundOf,
+		&transfer.ReversalOf,
+		&transfer.ScheduledAt,
+		&transfer.ExchangeRate,
+		&transfer.ConvertedAmount,
+		&transfer.ConvertedCurrency,
+		&transfer.Fee,
+		&transfer.Description,
+		&transfer.Category,
+		&transfer.Tags,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -225,14 +546,55 @@ func (r *transferRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity
 
 func (r *transferRepository) GetByIdempotencyKey(ctx context.Context, key string) (*entity.Transfer, error) {
 	query := `
-		SELECT id, idempotency_key, from_account_id, to_account_id, amount, currency, status, created_at, completed_at
+		SELECT id, idempotency_key, reference_number, from_account_id, to_account_id, amount, currency, status, created_at, completed_at, refunded_amount, refund_of, reversal_of, scheduled_at, exchange_rate, converted_amount, converted_currency, fee, description, category, tags, request_hash
 		FROM transfers
 		WHERE idempotency_key = $1
 	`
 	transfer := &entity.Transfer{}
-	err := r.pool.QueryRow(ctx, query, key).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, key).Scan(
+		&transfer.ID,
+		&transfer.IdempotencyKey,
+		&transfer.ReferenceNumber,
+		&transfer.FromAccountID,
+		&transfer.ToAccountID,
+		&transfer.Amount,
+		&transfer.Currency,
+		&transfer.Status,
+		&transfer.CreatedAt,
+		&transfer.CompletedAt,
+		&transfer.RefundedAmount,
+		&transfer.RefundOf,
+		&transfer.ReversalOf,
+		&transfer.ScheduledAt,
+		&transfer.ExchangeRate,
+		&transfer.ConvertedAmount,
+		&transfer.ConvertedCurrency,
+		&transfer.Fee,
+		&transfer.Description,
+		&transfer.Category,
+		&transfer.Tags,
+		&transfer.RequestHash,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (r *transferRepository) GetByReferenceNumber(ctx context.Context, referenceNumber string) (*entity.Transfer, error) {
+	query := `
+		SELECT id, idempotency_key, reference_number, from_account_id, to_account_id, amount, currency, status, created_at, completed_at, refunded_amount, refund_of, reversal_of, scheduled_at, exchange_rate, converted_amount, converted_currency, fee, description, category, tags
+		FROM transfers
+		WHERE reference_number = $1
+	`
+	transfer := &entity.Transfer{}
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, referenceNumber).Scan(
 		&transfer.ID,
 		&transfer.IdempotencyKey,
+		&transfer.ReferenceNumber,
 		&transfer.FromAccountID,
 		&transfer.ToAccountID,
 		&transfer.Amount,
@@ -240,6 +602,17 @@ func (r *transferRepository) GetByIdempotencyKey(ctx context.Context, key string
 		&transfer.Status,
 		&transfer.CreatedAt,
 		&transfer.CompletedAt,
+		&transfer.RefundedAmount,
+		&transfer.RefundOf,
+		&transfer.ReversalOf,
+		&transfer.ScheduledAt,
+		&transfer.ExchangeRate,
+		&transfer.ConvertedAmount,
+		&transfer.ConvertedCurrency,
+		&transfer.Fee,
+		&transfer.Description,
+		&transfer.Category,
+		&transfer.Tags,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -252,14 +625,14 @@ func (r *transferRepository) GetByIdempotencyKey(ctx context.Context, key string
 
 func (r *transferRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.Transfer, error) {
 	query := `
-		SELECT DISTINCT t.id, t.idempotency_key, t.from_account_id, t.to_account_id, t.amount, t.currency, t.status, t.created_at, t.completed_at
+		SELECT DISTINCT t.id, t.idempotency_key, t.reference_number, t.from_account_id, t.to_account_id, t.amount, t.currency, t.status, t.created_at, t.completed_at, t.refunded_amount, t.refund_of, t.reversal_of, t.scheduled_at, t.exchange_rate, t.converted_amount, t.converted_currency, t.fee, t.description, t.category, t.tags
 		FROM transfers t
 		JOIN accounts a ON (t.from_account_id = a.id OR t.to_account_id = a.id)
 		WHERE a.user_id = $1
 		ORDER BY t.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -271,6 +644,7 @@ func (r *transferRepository) GetByUserID(ctx context.Context, userID uuid.UUID,
 		if err := rows.Scan(
 			&transfer.ID,
 			&transfer.IdempotencyKey,
+			&transfer.ReferenceNumber,
 			&transfer.FromAccountID,
 			&transfer.ToAccountID,
 			&transfer.Amount,
@@ -278,6 +652,17 @@ func (r *transferRepository) GetByUserID(ctx context.Context, userID uuid.UUID,
 			&transfer.Status,
 			&transfer.CreatedAt,
 			&transfer.CompletedAt,
+			&transfer.RefundedAmount,
+			&transfer.RefundOf,
+			&transfer.ReversalOf,
+			&transfer.ScheduledAt,
+			&transfer.ExchangeRate,
+			&transfer.ConvertedAmount,
+			&transfer.ConvertedCurrency,
+			&transfer.Fee,
+			&transfer.Description,
+			&transfer.Category,
+			&transfer.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -286,6 +671,186 @@ func (r *transferRepository) GetByUserID(ctx context.Context, userID uuid.UUID,
 	return transfers, rows.Err()
 }
 
+// CountByUserID counts the transfers GetByUserID lists: those with either
+// side on one of the user's accounts, counted once even if both are.
+func (r *transferRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM transfers t
+		WHERE EXISTS (
+			SELECT 1 FROM accounts a
+			WHERE a.user_id = $1 AND a.id IN (t.from_account_id, t.to_account_id)
+		)
+	`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *transferRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID, direction entity.AccountTransferDirection, limit, offset int) ([]*entity.Transfer, error) {
+	query := `
+		SELECT t.id, t.idempotency_key, t.reference_number, t.from_account_id, t.to_account_id, t.amount, t.currency, t.status, t.created_at, t.completed_at, t.refunded_amount, t.refund_of, t.reversal_of, t.scheduled_at, t.exchange_rate, t.converted_amount, t.converted_currency, t.fee, t.description, t.category, t.tags
+		FROM transfers t
+		WHERE ` + accountTransferCondition(direction) + `
+		ORDER BY t.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, accountID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []*entity.Transfer
+	for rows.Next() {
+		transfer := &entity.Transfer{}
+		if err := rows.Scan(
+			&transfer.ID,
+			&transfer.IdempotencyKey,
+			&transfer.ReferenceNumber,
+			&transfer.FromAccountID,
+			&transfer.ToAccountID,
+			&transfer.Amount,
+			&transfer.Currency,
+			&transfer.Status,
+			&transfer.CreatedAt,
+			&transfer.CompletedAt,
+			&transfer.RefundedAmount,
+			&transfer.RefundOf,
+			&transfer.ReversalOf,
+			&transfer.ScheduledAt,
+			&transfer.ExchangeRate,
+			&transfer.ConvertedAmount,
+			&transfer.ConvertedCurrency,
+			&transfer.Fee,
+			&transfer.Description,
+			&transfer.Category,
+			&transfer.Tags,
+		); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, rows.Err()
+}
+
+func (r *transferRepository) CountByAccountID(ctx context.Context, accountID uuid.UUID, direction entity.AccountTransferDirection) (int64, error) {
+	query := `SELECT COUNT(*) FROM transfers t WHERE ` + accountTransferCondition(direction)
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, accountID).Scan(&count)
+	return count, err
+}
+
+// accountTransferCondition matches transfers on the given side of the
+// account passed as $1.
+func accountTransferCondition(direction entity.AccountTransferDirection) string {
+	switch direction {
+	case entity.AccountTransfersIn:
+		return "t.to_account_id = $1"
+	case entity.AccountTransfersOut:
+		return "t.from_account_id = $1"
+	default:
+		return "(t.from_account_id = $1 OR t.to_account_id = $1)"
+	}
+}
+
+func (r *transferRepository) ExportByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(*entity.TransferExportRow) error) error {
+	query := `
+		SELECT t.reference_number, fa.user_id = $1, ta.user_id = $1, fa.account_number, ta.account_number,
+			t.amount, t.currency, t.status, t.description, t.created_at, t.completed_at
+		FROM transfers t
+		JOIN accounts fa ON fa.id = t.from_account_id
+		JOIN accounts ta ON ta.id = t.to_account_id
+		WHERE (fa.user_id = $1 OR ta.user_id = $1) AND t.created_at >= $2 AND t.created_at < $3
+		ORDER BY t.created_at DESC
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &entity.TransferExportRow{}
+		var fromOwn, toOwn bool
+		var fromNumber, toNumber string
+		if err := rows.Scan(
+			&row.ReferenceNumber,
+			&fromOwn,
+			&toOwn,
+			&fromNumber,
+			&toNumber,
+			&row.Amount,
+			&row.Currency,
+			&row.Status,
+			&row.Description,
+			&row.CreatedAt,
+			&row.CompletedAt,
+		); err != nil {
+			return err
+		}
+
+		switch {
+		case fromOwn && toOwn:
+			row.Direction = entity.TransferDirectionInternal
+			row.CounterpartyAccount = toNumber
+		case fromOwn:
+			row.Direction = entity.TransferDirectionOutgoing
+			row.CounterpartyAccount = toNumber
+		default:
+			row.Direction = entity.TransferDirectionIncoming
+			row.CounterpartyAccount = fromNumber
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *transferRepository) ExportForCompliance(ctx context.Context, from, to time.Time, fn func(*entity.ComplianceTransferRow) error) error {
+	query := `
+		SELECT t.id, t.reference_number, fa.account_number, ta.account_number, t.amount, t.currency, t.fee,
+			t.exchange_rate, t.converted_amount, t.converted_currency, t.status, t.created_at, t.completed_at
+		FROM transfers t
+		JOIN accounts fa ON fa.id = t.from_account_id
+		JOIN accounts ta ON ta.id = t.to_account_id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		ORDER BY t.created_at, t.id
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &entity.ComplianceTransferRow{}
+		if err := rows.Scan(
+			&row.ID,
+			&row.ReferenceNumber,
+			&row.FromAccountNumber,
+			&row.ToAccountNumber,
+			&row.Amount,
+			&row.Currency,
+			&row.Fee,
+			&row.ExchangeRate,
+			&row.ConvertedAmount,
+			&row.ConvertedCurrency,
+			&row.Status,
+			&row.CreatedAt,
+			&row.CompletedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (r *transferRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entity.TransferStatus, completedAt *time.Time) error {
 	query := `
 		UPDATE transfers
@@ -293,15 +858,143 @@ func (r *transferRepository) UpdateStatus(ctx context.Context, id uuid.UUID, sta
 		WHERE id = $1
 	`
 
-	if tx, ok := ctx.Value(database.TxKey{}).(pgx.Tx); ok {
-		_, err := tx.Exec(ctx, query, id, status, completedAt)
-		return err
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, status, completedAt)
+	return err
+}
+
+func (r *transferRepository) GetDueScheduled(ctx context.Context, now time.Time, limit int) ([]*entity.Transfer, error) {
+	query := `
+		SELECT id, idempotency_key, reference_number, from_account_id, to_account_id, amount, currency, status, created_at, completed_at, refunded_amount, refund_of, reversal_of, scheduled_at, exchange_rate, converted_amount, converted_currency, fee, description, category, tags
+		FROM transfers
+		WHERE status = 'scheduled' AND scheduled_at <= $1
+		ORDER BY scheduled_at
+		LIMIT $2
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []*entity.Transfer
+	for rows.Next() {
+		transfer := &entity.Transfer{}
+		if err := rows.Scan(
+			&transfer.ID,
+			&transfer.IdempotencyKey,
+			&transfer.ReferenceNumber,
+			&transfer.FromAccountID,
+			&transfer.ToAccountID,
+			&transfer.Amount,
+			&transfer.Currency,
+			&transfer.Status,
+			&transfer.CreatedAt,
+			&transfer.CompletedAt,
+			&transfer.RefundedAmount,
+			&transfer.RefundOf,
+			&transfer.ReversalOf,
+			&transfer.ScheduledAt,
+			&transfer.ExchangeRate,
+			&transfer.ConvertedAmount,
+			&transfer.ConvertedCurrency,
+			&transfer.Fee,
+			&transfer.Description,
+			&transfer.Category,
+			&transfer.Tags,
+		); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, rows.Err()
+}
+
+func (r *transferRepository) GetLastTransferTime(ctx context.Context, fromAccountID, toAccountID uuid.UUID) (*time.Time, error) {
+	query := `
+		SELECT MAX(created_at)
+		FROM transfers
+		WHERE from_account_id = $1 AND to_account_id = $2 AND refund_of IS NULL AND reversal_of IS NULL AND status <> 'scheduled'
+	`
+
+	var lastAt *time.Time
+	if err := database.Querier(ctx, r.pool).QueryRow(ctx, query, fromAccountID, toAccountID).Scan(&lastAt); err != nil {
+		return nil, err
+	}
+	return lastAt, nil
+}
+
+func (r *transferRepository) SumOutboundSince(ctx context.Context, accountID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM transfers
+		WHERE from_account_id = $1 AND status = 'completed' AND completed_at >= $2
+			AND refund_of IS NULL AND reversal_of IS NULL
+	`
+
+	var total decimal.Decimal
+	if err := database.Querier(ctx, r.pool).QueryRow(ctx, query, accountID, since).Scan(&total); err != nil {
+		return decimal.Zero, err
+	}
+	return total, nil
+}
+
+func (r *transferRepository) SumPendingDebits(ctx context.Context, accountIDs []uuid.UUID, now time.Time) (map[uuid.UUID]decimal.Decimal, error) {
+	query := `
+		SELECT from_account_id, SUM(amount + fee)
+		FROM transfers
+		WHERE from_account_id = ANY($1) AND status = 'scheduled' AND scheduled_at <= $2
+		GROUP BY from_account_id
+	`
+
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, accountIDs, now)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	totals := make(map[uuid.UUID]decimal.Decimal)
+	for rows.Next() {
+		var accountID uuid.UUID
+		var total decimal.Decimal
+		if err := rows.Scan(&accountID, &total); err != nil {
+			return nil, err
+		}
+		totals[accountID] = total
+	}
+	return totals, rows.Err()
+}
 
-	_, err := r.pool.Exec(ctx, query, id, status, completedAt)
+func (r *transferRepository) SetFee(ctx context.Context, id uuid.UUID, fee decimal.Decimal) error {
+	query := `UPDATE transfers SET fee = $2 WHERE id = $1`
+
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, fee)
+	return err
+}
+
+func (r *transferRepository) AddRefundedAmount(ctx context.Context, id uuid.UUID, amount decimal.Decimal) error {
+	query := `
+		UPDATE transfers
+		SET refunded_amount = refunded_amount + $2
+		WHERE id = $1
+	`
+
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, amount)
 	return err
 }
 
+func (r *transferRepository) ClearIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		UPDATE transfers
+		SET idempotency_key = NULL, request_hash = ''
+		WHERE idempotency_key IS NOT NULL AND created_at < $1
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 type auditLogRepository struct {
 	pool *pgxpool.Pool
 }
@@ -315,7 +1008,13 @@ func (r *auditLogRepository) Create(ctx context.Context, log *entity.AuditLog) e
 		INSERT INTO audit_logs (id, user_id, action, entity_type, entity_id, old_values, new_values, ip_address, user_agent, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
-	_, err := r.pool.Exec(ctx, query,
+	// ip_address is INET, which rejects an empty string.
+	var ipAddress *string
+	if log.IPAddress != "" {
+		ipAddress = &log.IPAddress
+	}
+
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		log.ID,
 		log.UserID,
 		log.Action,
@@ -323,7 +1022,7 @@ func (r *auditLogRepository) Create(ctx context.Context, log *entity.AuditLog) e
 		log.EntityID,
 		log.OldValues,
 		log.NewValues,
-		log.IPAddress,
+		ipAddress,
 		log.UserAgent,
 		log.CreatedAt,
 	)
@@ -332,13 +1031,14 @@ func (r *auditLogRepository) Create(ctx context.Context, log *entity.AuditLog) e
 
 func (r *auditLogRepository) GetByEntityID(ctx context.Context, entityType string, entityID uuid.UUID, limit, offset int) ([]*entity.AuditLog, error) {
 	query := `
-		SELECT id, user_id, action, entity_type, entity_id, old_values, new_values, ip_address, user_agent, created_at
+		SELECT id, user_id, action, entity_type, entity_id, old_values, new_values,
+			COALESCE(host(ip_address), ''), COALESCE(user_agent, ''), created_at
 		FROM audit_logs
 		WHERE entity_type = $1 AND entity_id = $2
 		ORDER BY created_at DESC
 		LIMIT $3 OFFSET $4
 	`
-	rows, err := r.pool.Query(ctx, query, entityType, entityID, limit, offset)
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, entityType, entityID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -368,13 +1068,51 @@ func (r *auditLogRepository) GetByEntityID(ctx context.Context, entityType strin
 
 func (r *auditLogRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.AuditLog, error) {
 	query := `
-		SELECT id, user_id, action, entity_type, entity_id, old_values, new_values, ip_address, user_agent, created_at
+		SELECT id, user_id, action, entity_type, entity_id, old_values, new_values,
+			COALESCE(host(ip_address), ''), COALESCE(user_agent, ''), created_at
 		FROM audit_logs
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*entity.AuditLog
+	for rows.Next() {
+		log := &entity.AuditLog{}
+		if err := rows.Scan(
+			&log.ID,
+			&log.UserID,
+			&log.Action,
+			&log.EntityType,
+			&log.EntityID,
+			&log.OldValues,
+			&log.NewValues,
+			&log.IPAddress,
+			&log.UserAgent,
+			&log.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+func (r *auditLogRepository) GetByAction(ctx context.Context, action string, limit, offset int) ([]*entity.AuditLog, error) {
+	query := `
+		SELECT id, user_id, action, entity_type, entity_id, old_values, new_values,
+			COALESCE(host(ip_address), ''), COALESCE(user_agent, ''), created_at
+		FROM audit_logs
+		WHERE action = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, action, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -401,3 +1139,24 @@ func (r *auditLogRepository) GetByUserID(ctx context.Context, userID uuid.UUID,
 	}
 	return logs, rows.Err()
 }
+
+func (r *auditLogRepository) CountByEntityID(ctx context.Context, entityType string, entityID uuid.UUID) (int64, error) {
+	query := `SELECT COUNT(*) FROM audit_logs WHERE entity_type = $1 AND entity_id = $2`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, entityType, entityID).Scan(&count)
+	return count, err
+}
+
+func (r *auditLogRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `SELECT COUNT(*) FROM audit_logs WHERE user_id = $1`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *auditLogRepository) CountByAction(ctx context.Context, action string) (int64, error) {
+	query := `SELECT COUNT(*) FROM audit_logs WHERE action = $1`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, action).Scan(&count)
+	return count, err
+}
@@ -3,13 +3,17 @@ package postgres
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/yourusername/gobank/internal/domain/entity"
 	"github.com/yourusername/gobank/internal/domain/repository"
 	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
 )
 
 type userRepository struct {
@@ -22,36 +26,48 @@ func NewUserRepository(db *database.PostgresDB) repository.UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, full_name, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, email, password_hash, full_name, role, status, created_at, updated_at, email_verified, email_verified_at,
+			totp_secret, two_factor_enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
-	_, err := r.pool.Exec(ctx, query,
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		user.ID,
 		user.Email,
 		user.PasswordHash,
 		user.FullName,
 		user.Role,
+		user.Status,
 		user.CreatedAt,
 		user.UpdatedAt,
+		user.EmailVerified,
+		user.EmailVerifiedAt,
+		user.TOTPSecret,
+		user.TwoFactorEnabled,
 	)
 	return err
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, role, created_at, updated_at
+		SELECT id, email, password_hash, full_name, role, status, created_at, updated_at, email_verified, email_verified_at,
+			totp_secret, two_factor_enabled
 		FROM users
 		WHERE id = $1
 	`
 	user := &entity.User{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
 		&user.FullName,
 		&user.Role,
+		&user.Status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.EmailVerified,
+		&user.EmailVerifiedAt,
+		&user.TOTPSecret,
+		&user.TwoFactorEnabled,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -64,19 +80,25 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Use
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	query := `
-		SELECT id, email, password_hash, full_name, role, created_at, updated_at
+		SELECT id, email, password_hash, full_name, role, status, created_at, updated_at, email_verified, email_verified_at,
+			totp_secret, two_factor_enabled
 		FROM users
 		WHERE email = $1
 	`
 	user := &entity.User{}
-	err := r.pool.QueryRow(ctx, query, email).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.PasswordHash,
 		&user.FullName,
 		&user.Role,
+		&user.Status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.EmailVerified,
+		&user.EmailVerifiedAt,
+		&user.TOTPSecret,
+		&user.TwoFactorEnabled,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -90,28 +112,116 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users
-		SET email = $2, full_name = $3, role = $4, updated_at = NOW()
+		SET email = $2, full_name = $3, role = $4, status = $5, email_verified = $6,
+			email_verified_at = $7, totp_secret = $8, two_factor_enabled = $9, updated_at = NOW()
 		WHERE id = $1
 	`
-	_, err := r.pool.Exec(ctx, query,
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		user.ID,
 		user.Email,
 		user.FullName,
 		user.Role,
+		user.Status,
+		user.EmailVerified,
+		user.EmailVerifiedAt,
+		user.TOTPSecret,
+		user.TwoFactorEnabled,
 	)
 	return err
 }
 
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, passwordHash)
+	return err
+}
+
+// MarkEmailVerified verifies the user's email, provided it is still email.
+// It reports false if the user no longer exists or has changed address.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error) {
+	query := `
+		UPDATE users
+		SET email_verified = TRUE, email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+		WHERE id = $1 AND email = $2
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, email)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Delete removes a user along with their sessions and API keys. A user with
+// ledger history or standing orders is still referenced by them, and
+// ErrUserHasRecords is returned instead.
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.pool.Exec(ctx, query, id)
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return apperror.ErrUserHasRecords
+	}
 	return err
 }
 
+func (r *userRepository) List(ctx context.Context, search string, limit, offset int) ([]*entity.User, error) {
+	query := `
+		SELECT id, email, password_hash, full_name, role, status, created_at, updated_at, email_verified, email_verified_at,
+			totp_secret, two_factor_enabled
+		FROM users
+		WHERE $1 = '' OR email ILIKE '%' || $1 || '%' OR full_name ILIKE '%' || $1 || '%'
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, escapeLike(search), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		user := &entity.User{}
+		if err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.PasswordHash,
+			&user.FullName,
+			&user.Role,
+			&user.Status,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.EmailVerified,
+			&user.EmailVerifiedAt,
+			&user.TOTPSecret,
+			&user.TwoFactorEnabled,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *userRepository) CountAll(ctx context.Context, search string) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM users
+		WHERE $1 = '' OR email ILIKE '%' || $1 || '%' OR full_name ILIKE '%' || $1 || '%'
+	`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, escapeLike(search)).Scan(&count)
+	return count, err
+}
+
+// escapeLike makes s match literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 	var exists bool
-	err := r.pool.QueryRow(ctx, query, email).Scan(&exists)
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, email).Scan(&exists)
 	return exists, err
 }
 
@@ -125,12 +235,13 @@ func NewRefreshTokenRepository(db *database.PostgresDB) repository.RefreshTokenR
 
 func (r *refreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
-	_, err := r.pool.Exec(ctx, query,
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
 		token.ID,
 		token.UserID,
+		token.FamilyID,
 		token.TokenHash,
 		token.ExpiresAt,
 		token.CreatedAt,
@@ -140,14 +251,15 @@ func (r *refreshTokenRepository) Create(ctx context.Context, token *entity.Refre
 
 func (r *refreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token_hash, expires_at, created_at
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at
 		FROM refresh_tokens
 		WHERE token_hash = $1 AND expires_at > NOW()
 	`
 	token := &entity.RefreshToken{}
-	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, tokenHash).Scan(
 		&token.ID,
 		&token.UserID,
+		&token.FamilyID,
 		&token.TokenHash,
 		&token.ExpiresAt,
 		&token.CreatedAt,
@@ -161,20 +273,118 @@ func (r *refreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash s
 	return token, nil
 }
 
-func (r *refreshTokenRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+func (r *refreshTokenRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	query := `DELETE FROM refresh_tokens WHERE user_id = $1`
-	_, err := r.pool.Exec(ctx, query, userID)
-	return err
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *refreshTokenRepository) ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at
+		FROM refresh_tokens
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY created_at, id
+	`
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*entity.RefreshToken
+	for rows.Next() {
+		token := &entity.RefreshToken{}
+		if err := rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&token.FamilyID,
+			&token.TokenHash,
+			&token.ExpiresAt,
+			&token.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
 }
 
 func (r *refreshTokenRepository) DeleteByTokenHash(ctx context.Context, tokenHash string) error {
 	query := `DELETE FROM refresh_tokens WHERE token_hash = $1`
-	_, err := r.pool.Exec(ctx, query, tokenHash)
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query, tokenHash)
 	return err
 }
 
-func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
-	query := `DELETE FROM refresh_tokens WHERE expires_at < NOW()`
-	_, err := r.pool.Exec(ctx, query)
-	return err
+func (r *refreshTokenRepository) DeleteByFamilyID(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	query := `DELETE FROM refresh_tokens WHERE family_id = $1`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// MarkUsed moves a live token into used_refresh_tokens. It reports false if
+// the token was no longer live, e.g. because a concurrent request rotated it
+// first.
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, tokenHash string) (bool, error) {
+	query := `
+		WITH rotated AS (
+			DELETE FROM refresh_tokens
+			WHERE token_hash = $1
+			RETURNING token_hash, family_id, user_id, expires_at
+		)
+		INSERT INTO used_refresh_tokens (token_hash, family_id, user_id, expires_at)
+		SELECT token_hash, family_id, user_id, expires_at FROM rotated
+	`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, tokenHash)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetUsedByTokenHash returns a token that has already been rotated, or nil.
+// Only the user, family and expiry are populated.
+func (r *refreshTokenRepository) GetUsedByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	query := `
+		SELECT token_hash, family_id, user_id, expires_at
+		FROM used_refresh_tokens
+		WHERE token_hash = $1
+	`
+	token := &entity.RefreshToken{}
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, tokenHash).Scan(
+		&token.TokenHash,
+		&token.FamilyID,
+		&token.UserID,
+		&token.ExpiresAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// DeleteExpired removes live and rotated tokens that expired before the given
+// time. A rotated token is only worth keeping while it could still be
+// replayed.
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, `DELETE FROM refresh_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	deleted := tag.RowsAffected()
+
+	tag, err = database.Querier(ctx, r.pool).Exec(ctx, `DELETE FROM used_refresh_tokens WHERE expires_at < $1`, before)
+	if err != nil {
+		return deleted, err
+	}
+	return deleted + tag.RowsAffected(), nil
 }
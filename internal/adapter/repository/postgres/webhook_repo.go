@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+type webhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *database.PostgresDB) repository.WebhookRepository {
+	return &webhookRepository{pool: db.Pool}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, event_types, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		webhook.ID,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Secret,
+		webhook.EventTypes,
+		webhook.Active,
+		webhook.CreatedAt,
+	)
+	return err
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Webhook, error) {
+	query := `
+		SELECT id, user_id, url, secret, event_types, active, created_at
+		FROM webhooks
+		WHERE id = $1
+	`
+	webhook := &entity.Webhook{}
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.EventTypes,
+		&webhook.Active,
+		&webhook.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *webhookRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Webhook, error) {
+	query := `
+		SELECT id, user_id, url, secret, event_types, active, created_at
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	return r.query(ctx, query, userID)
+}
+
+func (r *webhookRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `SELECT COUNT(*) FROM webhooks WHERE user_id = $1`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *webhookRepository) GetActiveByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]*entity.Webhook, error) {
+	query := `
+		SELECT id, user_id, url, secret, event_types, active, created_at
+		FROM webhooks
+		WHERE user_id = ANY($1) AND active
+		ORDER BY created_at
+	`
+	return r.query(ctx, query, userIDs)
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	query := `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *webhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Webhook, error) {
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*entity.Webhook
+	for rows.Next() {
+		webhook := &entity.Webhook{}
+		if err := rows.Scan(
+			&webhook.ID,
+			&webhook.UserID,
+			&webhook.URL,
+			&webhook.Secret,
+			&webhook.EventTypes,
+			&webhook.Active,
+			&webhook.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+type webhookDeliveryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookDeliveryRepository(db *database.PostgresDB) repository.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{pool: db.Pool}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+	)
+	return err
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, webhook_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, delivered_at
+	`
+	return r.query(ctx, query, now, leaseUntil, limit)
+}
+
+func (r *webhookDeliveryRepository) RecordAttempt(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5, next_attempt_at = $6, delivered_at = $7
+		WHERE id = $1
+	`
+	_, err := database.Querier(ctx, r.pool).Exec(ctx, query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+	)
+	return err
+}
+
+func (r *webhookDeliveryRepository) GetByWebhookID(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	return r.query(ctx, query, webhookID, limit, offset)
+}
+
+func (r *webhookDeliveryRepository) CountByWebhookID(ctx context.Context, webhookID uuid.UUID) (int64, error) {
+	query := `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`
+	var count int64
+	err := database.Querier(ctx, r.pool).QueryRow(ctx, query, webhookID).Scan(&count)
+	return count, err
+}
+
+func (r *webhookDeliveryRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`
+	tag, err := database.Querier(ctx, r.pool).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *webhookDeliveryRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookDelivery, error) {
+	rows, err := database.Querier(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		delivery := &entity.WebhookDelivery{}
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.ResponseStatus,
+			&delivery.LastError,
+			&delivery.NextAttemptAt,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
@@ -6,10 +6,21 @@ import (
 	"fmt"
 	"time"
 
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/yourusername/gobank/internal/domain/service"
 	"github.com/yourusername/gobank/internal/infrastructure/database"
 )
 
+// incrScript increments KEYS[1] and, when that creates it, sets it to
+// expire after ARGV[1] seconds, so a counter never outlives its window.
+var incrScript = goredis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
 type cacheRepository struct {
 	redis *database.RedisDB
 }
@@ -23,20 +34,33 @@ func (r *cacheRepository) Get(ctx context.Context, key string) (string, error) {
 }
 
 func (r *cacheRepository) Set(ctx context.Context, key string, value interface{}, ttlSeconds int) error {
-	var data string
-	switch v := value.(type) {
-	case string:
-		data = v
-	default:
-		bytes, err := json.Marshal(value)
-		if err != nil {
-			return fmt.Errorf("failed to marshal value: %w", err)
-		}
-		data = string(bytes)
+	data, err := encodeValue(value)
+	if err != nil {
+		return err
 	}
 	return r.redis.Set(ctx, key, data, time.Duration(ttlSeconds)*time.Second)
 }
 
+func (r *cacheRepository) SetNX(ctx context.Context, key string, value interface{}, ttlSeconds int) (bool, error) {
+	data, err := encodeValue(value)
+	if err != nil {
+		return false, err
+	}
+	return r.redis.Client.SetNX(ctx, key, data, time.Duration(ttlSeconds)*time.Second).Result()
+}
+
+func (r *cacheRepository) GetDel(ctx context.Context, key string) (string, error) {
+	val, err := r.redis.Client.GetDel(ctx, key).Result()
+	if err == goredis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+func (r *cacheRepository) Incr(ctx context.Context, key string, ttlSeconds int) (int64, error) {
+	return incrScript.Run(ctx, r.redis.Client, []string{key}, ttlSeconds).Int64()
+}
+
 func (r *cacheRepository) Delete(ctx context.Context, key string) error {
 	return r.redis.Delete(ctx, key)
 }
@@ -56,43 +80,14 @@ func (r *cacheRepository) GetJSON(ctx context.Context, key string, dest interfac
 	return json.Unmarshal([]byte(data), dest)
 }
 
-type RateLimiter struct {
-	redis             *database.RedisDB
-	requestsPerMinute int
-	windowSize        time.Duration
-}
-
-func NewRateLimiter(redis *database.RedisDB, requestsPerMinute int) *RateLimiter {
-	return &RateLimiter{
-		redis:             redis,
-		requestsPerMinute: requestsPerMinute,
-		windowSize:        time.Minute,
+// encodeValue stores strings as they are and anything else as JSON.
+func encodeValue(value interface{}) (string, error) {
+	if v, ok := value.(string); ok {
+		return v, nil
 	}
-}
-
-func (rl *RateLimiter) Allow(ctx context.Context, key string) (bool, int, error) {
-	now := time.Now().Unix()
-	windowKey := fmt.Sprintf("ratelimit:%s:%d", key, now/60)
-
-	count, err := rl.redis.Incr(ctx, windowKey)
+	bytes, err := json.Marshal(value)
 	if err != nil {
-		return false, 0, err
-	}
-
-	if count == 1 {
-		if err := rl.redis.Expire(ctx, windowKey, rl.windowSize); err != nil {
-			return false, 0, err
-		}
-	}
-
-	remaining := rl.requestsPerMinute - int(count)
-	if remaining < 0 {
-		remaining = 0
+		return "", fmt.Errorf("failed to marshal value: %w", err)
 	}
-
-	return count <= int64(rl.requestsPerMinute), remaining, nil
-}
-
-func (rl *RateLimiter) GetLimit() int {
-	return rl.requestsPerMinute
+	return string(bytes), nil
 }
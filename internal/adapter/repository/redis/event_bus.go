@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+)
+
+const eventsChannel = "events"
+
+// EventBus carries published events to every API instance over Redis pub/sub,
+// so a stream receives an event whichever instance relayed it.
+type EventBus struct {
+	redis  *database.RedisDB
+	logger *logger.Logger
+}
+
+func NewEventBus(redis *database.RedisDB, log *logger.Logger) *EventBus {
+	return &EventBus{redis: redis, logger: log}
+}
+
+func (b *EventBus) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.redis.Client.Publish(ctx, eventsChannel, data).Err()
+}
+
+// Subscribe passes every event on the bus to fn until ctx is cancelled.
+func (b *EventBus) Subscribe(ctx context.Context, fn func(*entity.OutboxEvent)) {
+	pubsub := b.redis.Client.Subscribe(ctx, eventsChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			event := &entity.OutboxEvent{}
+			if err := json.Unmarshal([]byte(msg.Payload), event); err != nil {
+				b.logger.Warn().Err(err).Msg("Discarding malformed event from bus")
+				continue
+			}
+			fn(event)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+// IdempotentResponse is a response recorded so a retried request can be
+// answered without running it again.
+type IdempotentResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// idempotencyRecord is what is stored under a key. Response is nil while the
+// first request is still being processed, and Claim tells that request's
+// hold on the key apart from a later one's.
+type idempotencyRecord struct {
+	RequestHash string              `json:"request_hash"`
+	Claim       string              `json:"claim,omitempty"`
+	Response    *IdempotentResponse `json:"response,omitempty"`
+}
+
+// releaseScript deletes KEYS[1] only if it still holds ARGV[1], the claim
+// being released, so a request that outlived its claim cannot drop the
+// claim of the request that took the key over.
+var releaseScript = goredis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// IdempotencyStore records which request first used an idempotency key and
+// the response it got. A key is claimed for lockTTL while its request runs
+// and kept for ttl once the response is recorded.
+type IdempotencyStore struct {
+	redis   *database.RedisDB
+	ttl     time.Duration
+	lockTTL time.Duration
+}
+
+func NewIdempotencyStore(redis *database.RedisDB, ttl, lockTTL time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		redis:   redis,
+		ttl:     ttl,
+		lockTTL: lockTTL,
+	}
+}
+
+// Begin claims key for a request with the given hash. If the key is new it
+// returns the claim, and the caller must later Complete it or Release the
+// claim. If the same request already completed, its recorded response is
+// returned instead. A key used for a different request yields
+// ErrIdempotencyKeyConflict, and one whose request is still running yields
+// ErrIdempotencyRequestInProgress.
+func (s *IdempotencyStore) Begin(ctx context.Context, key, requestHash string) (string, *IdempotentResponse, error) {
+	pending, err := json.Marshal(idempotencyRecord{RequestHash: requestHash, Claim: uuid.NewString()})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	claimed, err := s.redis.Client.SetNX(ctx, idempotencyKeyPrefix+key, pending, s.lockTTL).Result()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if claimed {
+		return string(pending), nil, nil
+	}
+
+	data, err := s.redis.Get(ctx, idempotencyKeyPrefix+key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+	if data == "" {
+		// The claim expired between the two calls; the client can retry.
+		return "", nil, apperror.ErrIdempotencyRequestInProgress
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+	if record.RequestHash != requestHash {
+		return "", nil, apperror.ErrIdempotencyKeyConflict
+	}
+	if record.Response == nil {
+		return "", nil, apperror.ErrIdempotencyRequestInProgress
+	}
+	return "", record.Response, nil
+}
+
+// Complete records the response to the request that claimed key, so later
+// retries replay it.
+func (s *IdempotencyStore) Complete(ctx context.Context, key, requestHash string, response *IdempotentResponse) error {
+	data, err := json.Marshal(idempotencyRecord{RequestHash: requestHash, Response: response})
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	return s.redis.Set(ctx, idempotencyKeyPrefix+key, data, s.ttl)
+}
+
+// Release gives up a claim without recording a response, so a retry runs the
+// request again. It does nothing if the claim has expired and the key has
+// since been claimed again or completed.
+func (s *IdempotencyStore) Release(ctx context.Context, key, claim string) error {
+	return releaseScript.Run(ctx, s.redis.Client, []string{idempotencyKeyPrefix + key}, claim).Err()
+}
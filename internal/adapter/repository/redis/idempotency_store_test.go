@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestIdempotencyStoreReplaysCompletedRequest(t *testing.T) {
+	store := NewIdempotencyStore(testutil.Redis(t), time.Minute, time.Minute)
+	ctx := context.Background()
+	key := testutil.Key(t)
+
+	claim, stored, err := store.Begin(ctx, key, "hash")
+	if err != nil || stored != nil || claim == "" {
+		t.Fatalf("Begin = %q, %v, %v; want a new claim", claim, stored, err)
+	}
+
+	if _, _, err := store.Begin(ctx, key, "hash"); !errors.Is(err, apperror.ErrIdempotencyRequestInProgress) {
+		t.Fatalf("Begin while running = %v, want ErrIdempotencyRequestInProgress", err)
+	}
+	if _, _, err := store.Begin(ctx, key, "other"); !errors.Is(err, apperror.ErrIdempotencyKeyConflict) {
+		t.Fatalf("Begin with another request = %v, want ErrIdempotencyKeyConflict", err)
+	}
+
+	response := &IdempotentResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+	if err := store.Complete(ctx, key, "hash", response); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stored, err = store.Begin(ctx, key, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || stored.StatusCode != 201 || string(stored.Body) != `{"id":1}` {
+		t.Fatalf("replayed response = %+v", stored)
+	}
+}
+
+func TestIdempotencyStoreReleaseAllowsRetry(t *testing.T) {
+	store := NewIdempotencyStore(testutil.Redis(t), time.Minute, time.Minute)
+	ctx := context.Background()
+	key := testutil.Key(t)
+
+	claim, _, err := store.Begin(ctx, key, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Release(ctx, key, claim); err != nil {
+		t.Fatal(err)
+	}
+
+	if claim, _, err := store.Begin(ctx, key, "hash"); err != nil || claim == "" {
+		t.Fatalf("Begin after Release = %q, %v; want a new claim", claim, err)
+	}
+}
+
+func TestIdempotencyStoreReleaseKeepsNewerClaim(t *testing.T) {
+	store := NewIdempotencyStore(testutil.Redis(t), time.Minute, 50*time.Millisecond)
+	ctx := context.Background()
+	key := testutil.Key(t)
+
+	stale, _, err := store.Begin(ctx, key, "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first request outlives its claim and a retry takes the key over.
+	time.Sleep(100 * time.Millisecond)
+	current, _, err := store.Begin(ctx, key, "hash")
+	if err != nil || current == "" {
+		t.Fatalf("Begin after the claim expired = %q, %v", current, err)
+	}
+
+	if err := store.Release(ctx, key, stale); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Begin(ctx, key, "hash"); !errors.Is(err, apperror.ErrIdempotencyRequestInProgress) {
+		t.Fatalf("stale Release dropped the newer claim: Begin = %v", err)
+	}
+
+	if err := store.Release(ctx, key, current); err != nil {
+		t.Fatal(err)
+	}
+	if claim, _, err := store.Begin(ctx, key, "hash"); err != nil || claim == "" {
+		t.Fatalf("Begin after the current claim was released = %q, %v", claim, err)
+	}
+}
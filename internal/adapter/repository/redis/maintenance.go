@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+const maintenanceKey = "maintenance:enabled"
+
+// MaintenanceMode reports whether the API is in maintenance. It is on when
+// either the static config flag is set or the Redis key holds "1", so it can
+// be toggled at runtime without a redeploy.
+type MaintenanceMode struct {
+	redis   *database.RedisDB
+	enabled bool
+}
+
+func NewMaintenanceMode(redis *database.RedisDB, enabled bool) *MaintenanceMode {
+	return &MaintenanceMode{
+		redis:   redis,
+		enabled: enabled,
+	}
+}
+
+func (m *MaintenanceMode) IsEnabled(ctx context.Context) bool {
+	if m.enabled {
+		return true
+	}
+	val, err := m.redis.Get(ctx, maintenanceKey)
+	if err != nil {
+		return false
+	}
+	return val == "1"
+}
+
+func (m *MaintenanceMode) Set(ctx context.Context, enabled bool) error {
+	if !enabled {
+		return m.redis.Delete(ctx, maintenanceKey)
+	}
+	return m.redis.Set(ctx, maintenanceKey, "1", 0)
+}
@@ -0,0 +1,299 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+)
+
+// RateLimitAlgorithm selects how a RateLimiter counts requests.
+type RateLimitAlgorithm string
+
+const (
+	// AlgorithmSlidingWindow allows the limit over any rolling window.
+	AlgorithmSlidingWindow RateLimitAlgorithm = "sliding_window"
+	// AlgorithmTokenBucket refills the limit's worth of tokens over each
+	// window up to burstSize, so clients can burst briefly but not sustain
+	// more than the rate.
+	AlgorithmTokenBucket RateLimitAlgorithm = "token_bucket"
+)
+
+// ParseRateLimitAlgorithm returns the named algorithm, defaulting to
+// AlgorithmSlidingWindow.
+func ParseRateLimitAlgorithm(s string) RateLimitAlgorithm {
+	if RateLimitAlgorithm(s) == AlgorithmTokenBucket {
+		return AlgorithmTokenBucket
+	}
+	return AlgorithmSlidingWindow
+}
+
+// slidingWindowScript counts a request against a sliding-window log kept in
+// a sorted set of request timestamps (in microseconds). It drops entries
+// older than the window and adds the request, as one entry per unit of its
+// cost, only if the window still has room for all of them, atomically.
+// Redis's own clock is used so instances with skewed clocks agree.
+// Timestamps are formatted with %d because Lua would otherwise stringify
+// them in scientific notation. Returns {allowed, count after the request,
+// microseconds until enough requests leave the window to make room}.
+//
+// KEYS[1] = log key; ARGV[1] = window in microseconds, ARGV[2] = limit,
+// ARGV[3] = unique member prefix for this request, ARGV[4] = cost.
+var slidingWindowScript = goredis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local cost = tonumber(ARGV[4])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', string.format('%d', now - window))
+local count = redis.call('ZCARD', KEYS[1])
+if count + cost > limit then
+	local over = count + cost - limit
+	local oldest = redis.call('ZRANGE', KEYS[1], over - 1, over - 1, 'WITHSCORES')
+	local wait = window
+	if #oldest > 0 then
+		wait = tonumber(oldest[2]) + window - now
+	end
+	return {0, count, wait}
+end
+
+for i = 1, cost do
+	redis.call('ZADD', KEYS[1], string.format('%d', now), ARGV[3] .. ':' .. i)
+end
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+return {1, count + cost, 0}
+`)
+
+// slidingPeekScript returns the number of requests in the current window
+// and the microseconds until the oldest of them leaves it, or 0 if the
+// window is empty. It does not record a request.
+var slidingPeekScript = goredis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local window = tonumber(ARGV[1])
+
+local min = '(' .. string.format('%d', now - window)
+local count = redis.call('ZCOUNT', KEYS[1], min, '+inf')
+local oldest = redis.call('ZRANGEBYSCORE', KEYS[1], min, '+inf', 'WITHSCORES', 'LIMIT', 0, 1)
+if #oldest == 0 then
+	return {count, 0}
+end
+return {count, tonumber(oldest[2]) + window - now}
+`)
+
+// tokenBucketScript refills the bucket for the time since its last refill
+// and takes a token if one is available, atomically. The bucket is a hash
+// of its token count and last refill time (in microseconds, from Redis's
+// clock); a missing bucket is full. With ARGV[3] = 0 it only reports the
+// state. Returns {allowed, whole tokens left, microseconds until the next
+// whole token, or 0 if the bucket is full}.
+//
+// KEYS[1] = bucket key; ARGV[1] = microseconds per token, ARGV[2] =
+// capacity, ARGV[3] = tokens to take (0 to only report).
+var tokenBucketScript = goredis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local take = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / interval)
+
+local allowed = 0
+if take > 0 and tokens >= take then
+	tokens = tokens - take
+	allowed = 1
+end
+
+local wait = 0
+if take > 0 and allowed == 0 then
+	wait = math.ceil((take - tokens) * interval)
+elseif tokens < capacity then
+	wait = math.ceil((math.floor(tokens) + 1 - tokens) * interval)
+end
+
+if take > 0 then
+	redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', string.format('%d', now))
+	redis.call('PEXPIRE', KEYS[1], math.ceil(interval * capacity / 1000) + 1000)
+end
+return {allowed, math.floor(tokens), wait}
+`)
+
+// RateLimitResult is the outcome of counting one request.
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until a rejected request would be allowed.
+	RetryAfter time.Duration
+}
+
+// RateLimiter limits requests per key, either over a rolling window or with
+// a token bucket. Rejected requests are not counted.
+type RateLimiter struct {
+	redis      *database.RedisDB
+	name       string
+	limit      int
+	burstSize  int
+	algorithm  RateLimitAlgorithm
+	windowSize time.Duration
+}
+
+// NewRateLimiter creates a limiter of requestsPerMinute. burstSize is the
+// token bucket's capacity and is ignored by the sliding window; a
+// burstSize below 1 means a capacity of one minute's requests.
+func NewRateLimiter(redis *database.RedisDB, requestsPerMinute, burstSize int, algorithm RateLimitAlgorithm) *RateLimiter {
+	if burstSize < 1 {
+		burstSize = requestsPerMinute
+	}
+	return &RateLimiter{
+		redis:      redis,
+		limit:      requestsPerMinute,
+		burstSize:  burstSize,
+		algorithm:  algorithm,
+		windowSize: time.Minute,
+	}
+}
+
+// ForRoute returns a limiter of requests per window that uses the same
+// algorithm but counts under its own name, so its keys never collide with
+// rl's or another route's. A token bucket's capacity is one window's
+// requests.
+func (rl *RateLimiter) ForRoute(name string, requests int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		redis:      rl.redis,
+		name:       name,
+		limit:      requests,
+		burstSize:  requests,
+		algorithm:  rl.algorithm,
+		windowSize: window,
+	}
+}
+
+func (rl *RateLimiter) Allow(ctx context.Context, key string) (bool, int, error) {
+	result, err := rl.Check(ctx, key)
+	if err != nil {
+		return false, 0, err
+	}
+	return result.Allowed, result.Remaining, nil
+}
+
+// Check counts a request against key and reports whether it is allowed.
+func (rl *RateLimiter) Check(ctx context.Context, key string) (*RateLimitResult, error) {
+	return rl.CheckN(ctx, key, 1)
+}
+
+// CheckN counts a request that costs as much as n requests against key, and
+// allows it only if the whole cost fits. A cost above GetLimit is charged
+// as GetLimit, so such a request can still go through on a full budget.
+func (rl *RateLimiter) CheckN(ctx context.Context, key string, n int) (*RateLimitResult, error) {
+	if n < 1 {
+		n = 1
+	}
+	if limit := rl.GetLimit(); n > limit && limit > 0 {
+		n = limit
+	}
+
+	var (
+		reply []int64
+		err   error
+	)
+	if rl.algorithm == AlgorithmTokenBucket {
+		reply, err = tokenBucketScript.Run(ctx, rl.redis.Client, []string{rl.bucketKey(key)},
+			rl.tokenInterval().Microseconds(), rl.burstSize, n).Int64Slice()
+	} else {
+		reply, err = slidingWindowScript.Run(ctx, rl.redis.Client, []string{rl.logKey(key)},
+			rl.windowSize.Microseconds(), rl.limit, uuid.NewString(), n).Int64Slice()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RateLimitResult{Allowed: reply[0] == 1}
+	if rl.algorithm == AlgorithmTokenBucket {
+		result.Remaining = int(reply[1])
+	} else {
+		result.Remaining = rl.limit - int(reply[1])
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(reply[2]) * time.Microsecond
+	}
+
+	return result, nil
+}
+
+// Peek returns the requests remaining for key and when more become
+// available, without counting a request: when the oldest counted request
+// leaves the window, or when the bucket gains its next token. If nothing is
+// pending the reset time is now.
+func (rl *RateLimiter) Peek(ctx context.Context, key string) (int, time.Time, error) {
+	var remaining int
+	var wait int64
+
+	if rl.algorithm == AlgorithmTokenBucket {
+		reply, err := tokenBucketScript.Run(ctx, rl.redis.Client, []string{rl.bucketKey(key)},
+			rl.tokenInterval().Microseconds(), rl.burstSize, 0).Int64Slice()
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		remaining, wait = int(reply[1]), reply[2]
+	} else {
+		reply, err := slidingPeekScript.Run(ctx, rl.redis.Client, []string{rl.logKey(key)},
+			rl.windowSize.Microseconds()).Int64Slice()
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		remaining, wait = rl.limit-int(reply[0]), reply[1]
+	}
+
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return remaining, time.Now().UTC().Add(time.Duration(wait) * time.Microsecond), nil
+}
+
+// tokenInterval is how long the bucket takes to gain one token.
+func (rl *RateLimiter) tokenInterval() time.Duration {
+	if rl.limit < 1 {
+		return rl.windowSize
+	}
+	return rl.windowSize / time.Duration(rl.limit)
+}
+
+func (rl *RateLimiter) logKey(key string) string {
+	if rl.name != "" {
+		return fmt.Sprintf("ratelimit:%s:%s", rl.name, key)
+	}
+	return fmt.Sprintf("ratelimit:%s", key)
+}
+
+func (rl *RateLimiter) bucketKey(key string) string {
+	if rl.name != "" {
+		return fmt.Sprintf("ratelimit:bucket:%s:%s", rl.name, key)
+	}
+	return fmt.Sprintf("ratelimit:bucket:%s", key)
+}
+
+// GetLimit is the most requests a client can make at once: the window's
+// limit, or the bucket's capacity.
+func (rl *RateLimiter) GetLimit() int {
+	if rl.algorithm == AlgorithmTokenBucket {
+		return rl.burstSize
+	}
+	return rl.limit
+}
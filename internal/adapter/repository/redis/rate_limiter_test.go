@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+func TestRateLimiterPeekDoesNotCount(t *testing.T) {
+	redisDB := testutil.Redis(t)
+
+	for _, algorithm := range []RateLimitAlgorithm{AlgorithmSlidingWindow, AlgorithmTokenBucket} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			limiter := NewRateLimiter(redisDB, 5, 0, algorithm)
+			ctx := context.Background()
+			key := testutil.Key(t)
+
+			remaining, resetAt, err := limiter.Peek(ctx, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if remaining != 5 {
+				t.Fatalf("remaining before any request = %d, want 5", remaining)
+			}
+			if resetAt.After(time.Now().Add(time.Second)) {
+				t.Fatalf("reset at %v with nothing pending, want now", resetAt)
+			}
+
+			for i := 0; i < 2; i++ {
+				if allowed, _, err := limiter.Allow(ctx, key); err != nil || !allowed {
+					t.Fatalf("request %d: allowed = %v, %v", i+1, allowed, err)
+				}
+			}
+
+			for i := 0; i < 10; i++ {
+				remaining, resetAt, err = limiter.Peek(ctx, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if remaining != 3 {
+					t.Fatalf("peek %d: remaining = %d, want 3", i+1, remaining)
+				}
+			}
+			if !resetAt.After(time.Now()) {
+				t.Fatalf("reset at %v with requests pending, want a future time", resetAt)
+			}
+
+			// The next counted request sees the same budget the peeks did.
+			if _, left, err := limiter.Allow(ctx, key); err != nil || left != 2 {
+				t.Fatalf("request after peeking: remaining = %d, %v; want 2", left, err)
+			}
+		})
+	}
+}
+
+func TestRateLimiterCheckNChargesTheWholeCost(t *testing.T) {
+	redisDB := testutil.Redis(t)
+
+	for _, algorithm := range []RateLimitAlgorithm{AlgorithmSlidingWindow, AlgorithmTokenBucket} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			limiter := NewRateLimiter(redisDB, 5, 0, algorithm)
+			ctx := context.Background()
+			key := testutil.Key(t)
+
+			result, err := limiter.CheckN(ctx, key, 3)
+			if err != nil || !result.Allowed || result.Remaining != 2 {
+				t.Fatalf("cost 3 on a fresh budget = %+v, %v; want allowed with 2 left", result, err)
+			}
+
+			result, err = limiter.CheckN(ctx, key, 3)
+			if err != nil || result.Allowed {
+				t.Fatalf("cost 3 with 2 left = %+v, %v; want refused", result, err)
+			}
+			if result.RetryAfter <= 0 {
+				t.Fatalf("refused request retry after %v, want a wait", result.RetryAfter)
+			}
+
+			// A refused request is not counted.
+			if result, err := limiter.CheckN(ctx, key, 2); err != nil || !result.Allowed {
+				t.Fatalf("cost 2 with 2 left = %+v, %v; want allowed", result, err)
+			}
+		})
+	}
+}
+
+func TestRateLimiterCheckNCapsCostAtTheLimit(t *testing.T) {
+	redisDB := testutil.Redis(t)
+	limiter := NewRateLimiter(redisDB, 5, 0, AlgorithmSlidingWindow)
+	ctx := context.Background()
+	key := testutil.Key(t)
+
+	result, err := limiter.CheckN(ctx, key, 50)
+	if err != nil || !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("cost above the limit on a fresh budget = %+v, %v; want allowed using it all", result, err)
+	}
+}
@@ -1,10 +1,12 @@
 package entity
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/pkg/money"
 )
 
 type AccountType string
@@ -22,8 +24,56 @@ const (
 	CurrencyUSD Currency = "USD"
 	CurrencyEUR Currency = "EUR"
 	CurrencyGBP Currency = "GBP"
+
+	// AccountStatusReasonDormant marks accounts frozen for inactivity.
+	AccountStatusReasonDormant = "dormant"
+	// AccountStatusReasonClosed marks accounts closed by their owner.
+	AccountStatusReasonClosed = "closed"
+)
+
+// IsValid reports whether c is a supported currency.
+func (c Currency) IsValid() bool {
+	return c == CurrencyUSD || c == CurrencyEUR || c == CurrencyGBP
+}
+
+// IsValid reports whether s is a known account status.
+func (s AccountStatus) IsValid() bool {
+	return s == AccountStatusActive || s == AccountStatusInactive || s == AccountStatusFrozen
+}
+
+// displayScales holds the ISO 4217 minor units of each supported currency.
+var displayScales = map[Currency]int32{
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+	CurrencyGBP: 2,
+}
+
+// DisplayScale is the number of decimal places shown for amounts in c: its
+// minor units, or money.DisplayScale for a currency not in the table.
+func (c Currency) DisplayScale() int32 {
+	if scale, ok := displayScales[c]; ok {
+		return scale
+	}
+	return money.DisplayScale
+}
+
+// AmountFormat selects how amounts are rendered in responses.
+type AmountFormat int
+
+const (
+	// AmountDisplay rounds to the currency's display precision.
+	AmountDisplay AmountFormat = iota
+	// AmountFull keeps the stored precision, for reconciliation tooling.
+	AmountFull
 )
 
+func (f AmountFormat) Format(d decimal.Decimal, scale int32) string {
+	if f == AmountFull {
+		return money.FormatFull(d)
+	}
+	return money.Format(d, scale)
+}
+
 type Account struct {
 	ID            uuid.UUID       `json:"id"`
 	UserID        uuid.UUID       `json:"user_id"`
@@ -34,21 +84,129 @@ type Account struct {
 	Status        AccountStatus   `json:"status"`
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
+	RequireMemo   bool            `json:"require_memo"`
+	StatusReason  string          `json:"status_reason,omitempty"`
+
+	ReactivationRequestedAt *time.Time       `json:"reactivation_requested_at,omitempty"`
+	DailyTransferLimit      *decimal.Decimal `json:"daily_transfer_limit,omitempty"`
+	// MinBalance is the lowest a savings account may be debited to.
+	// OverdraftLimit is how far below zero any account may go.
+	MinBalance     decimal.Decimal `json:"min_balance"`
+	OverdraftLimit decimal.Decimal `json:"overdraft_limit"`
+	// DeletedAt is set when the account is closed. Closed accounts are kept
+	// for their history but are not returned by normal lookups.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version is incremented by every write, for optimistic concurrency.
+	Version int `json:"version"`
+
+	// PendingDebits is what due scheduled transfers will take from the
+	// account when the scheduler next runs. It is not stored; services fill
+	// it in where the available balance matters.
+	PendingDebits decimal.Decimal `json:"-"`
 }
 
 type CreateAccountInput struct {
 	AccountType AccountType `json:"account_type" validate:"required,oneof=checking savings"`
-	Currency    Currency    `json:"currency" validate:"required,oneof=USD EUR GBP"`
+	Currency    Currency    `json:"currency" validate:"required,currency"`
+	// InitialDeposit is credited when the account is opened. Some products
+	// require a minimum (ACCOUNT_MIN_OPENING_DEPOSIT).
+	InitialDeposit string `json:"initial_deposit"`
 }
 
 type AccountResponse struct {
-	ID            uuid.UUID       `json:"id"`
-	AccountNumber string          `json:"account_number"`
-	AccountType   AccountType     `json:"account_type"`
-	Currency      Currency        `json:"currency"`
-	Balance       string          `json:"balance"`
-	Status        AccountStatus   `json:"status"`
-	CreatedAt     time.Time       `json:"created_at"`
+	ID            uuid.UUID   `json:"id"`
+	AccountNumber string      `json:"account_number"`
+	AccountType   AccountType `json:"account_type"`
+	Currency      Currency    `json:"currency"`
+	// Balance is the current (ledger) balance.
+	//
+	// Deprecated: use CurrentBalance. Kept for existing clients.
+	Balance          string        `json:"balance"`
+	CurrentBalance   string        `json:"current_balance"`
+	AvailableBalance string        `json:"available_balance"`
+	Status           AccountStatus `json:"status"`
+	CreatedAt        time.Time     `json:"created_at"`
+	RequireMemo      bool          `json:"require_memo"`
+	StatusReason     string        `json:"status_reason,omitempty"`
+
+	ReactivationRequestedAt *time.Time `json:"reactivation_requested_at,omitempty"`
+	DailyTransferLimit      *string    `json:"daily_transfer_limit,omitempty"`
+	MinBalance              string     `json:"min_balance"`
+	OverdraftLimit          string     `json:"overdraft_limit"`
+}
+
+// AdminAccountResponse is an account as admins see it: with its owner and,
+// for closed accounts, when it was closed.
+type AdminAccountResponse struct {
+	*AccountResponse
+	UserID    uuid.UUID  `json:"user_id"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// AccountSearchFilter narrows an admin account search. Nil fields match any
+// account; closed accounts are included.
+type AccountSearchFilter struct {
+	Status     *AccountStatus
+	Currency   *Currency
+	MinBalance *decimal.Decimal
+	UserID     *uuid.UUID
+}
+
+type UpdateAccountSettingsInput struct {
+	RequireMemo *bool `json:"require_memo"`
+}
+
+// UpdateAccountLimitsInput sets the account's daily outbound transfer limit.
+// A null or missing limit removes it.
+type UpdateAccountLimitsInput struct {
+	DailyTransferLimit *string `json:"daily_transfer_limit"`
+}
+
+// UpdateBalanceRulesInput sets an account's balance floors. Missing fields
+// are left as they are; "0" removes a rule.
+type UpdateBalanceRulesInput struct {
+	MinBalance     *string `json:"min_balance"`
+	OverdraftLimit *string `json:"overdraft_limit"`
+}
+
+type UpdateAccountStatusInput struct {
+	Status AccountStatus `json:"status" validate:"required,oneof=active inactive frozen"`
+}
+
+// AllowedDestination is an account that an allowlisted account may pay.
+type AllowedDestination struct {
+	AccountID     uuid.UUID `json:"account_id"`
+	AccountNumber string    `json:"account_number"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AddAllowedDestinationInput struct {
+	AccountID     uuid.UUID `json:"account_id" validate:"required_without=AccountNumber"`
+	AccountNumber string    `json:"account_number" validate:"omitempty,max=20"`
+}
+
+// BalanceAggregate is the total held in accounts of one currency and type.
+type BalanceAggregate struct {
+	Currency     Currency        `json:"currency"`
+	AccountType  AccountType     `json:"account_type"`
+	AccountCount int64           `json:"account_count"`
+	TotalBalance decimal.Decimal `json:"total_balance"`
+}
+
+type BalanceAggregateResponse struct {
+	Currency     Currency    `json:"currency"`
+	AccountType  AccountType `json:"account_type"`
+	AccountCount int64       `json:"account_count"`
+	TotalBalance string      `json:"total_balance"`
+}
+
+func (b *BalanceAggregate) ToResponse(format AmountFormat) *BalanceAggregateResponse {
+	return &BalanceAggregateResponse{
+		Currency:     b.Currency,
+		AccountType:  b.AccountType,
+		AccountCount: b.AccountCount,
+		TotalBalance: format.Format(b.TotalBalance, b.Currency.DisplayScale()),
+	}
 }
 
 func NewAccount(userID uuid.UUID, accountNumber string, accountType AccountType, currency Currency) *Account {
@@ -63,25 +221,119 @@ func NewAccount(userID uuid.UUID, accountNumber string, accountType AccountType,
 		Status:        AccountStatusActive,
 		CreatedAt:     now,
 		UpdatedAt:     now,
+		Version:       1,
 	}
 }
 
-func (a *Account) ToResponse() *AccountResponse {
+func (a *Account) ToResponse(format AmountFormat) *AccountResponse {
+	var dailyTransferLimit *string
+	if a.DailyTransferLimit != nil {
+		limit := format.Format(*a.DailyTransferLimit, a.Currency.DisplayScale())
+		dailyTransferLimit = &limit
+	}
+
 	return &AccountResponse{
-		ID:            a.ID,
-		AccountNumber: a.AccountNumber,
-		AccountType:   a.AccountType,
-		Currency:      a.Currency,
-		Balance:       a.Balance.StringFixed(2),
-		Status:        a.Status,
-		CreatedAt:     a.CreatedAt,
+		ID:               a.ID,
+		AccountNumber:    a.AccountNumber,
+		AccountType:      a.AccountType,
+		Currency:         a.Currency,
+		Balance:          format.Format(a.Balance, a.Currency.DisplayScale()),
+		CurrentBalance:   format.Format(a.Balance, a.Currency.DisplayScale()),
+		AvailableBalance: format.Format(a.AvailableBalance(), a.Currency.DisplayScale()),
+		Status:           a.Status,
+		CreatedAt:        a.CreatedAt,
+		RequireMemo:      a.RequireMemo,
+		StatusReason:     a.StatusReason,
+
+		ReactivationRequestedAt: a.ReactivationRequestedAt,
+		DailyTransferLimit:      dailyTransferLimit,
+		MinBalance:              format.Format(a.MinBalance, a.Currency.DisplayScale()),
+		OverdraftLimit:          format.Format(a.OverdraftLimit, a.Currency.DisplayScale()),
+	}
+}
+
+func (a *Account) ToAdminResponse(format AmountFormat) *AdminAccountResponse {
+	return &AdminAccountResponse{
+		AccountResponse: a.ToResponse(format),
+		UserID:          a.UserID,
+		DeletedAt:       a.DeletedAt,
 	}
 }
 
+// AvailableBalance is the ledger balance less pending debits: what the owner
+// can still spend.
+func (a *Account) AvailableBalance() decimal.Decimal {
+	return a.Balance.Sub(a.PendingDebits)
+}
+
+func (a *Account) IsActive() bool {
+	return a.Status == AccountStatusActive
+}
+
+// CanDebit reports whether amount can be taken from the available balance
+// without taking it below its floor. Callers that need to tell an inactive or
+// frozen account from a short balance should check IsActive first.
 func (a *Account) CanDebit(amount decimal.Decimal) bool {
-	return a.Status == AccountStatusActive && a.Balance.GreaterThanOrEqual(amount)
+	return a.IsActive() && a.AvailableBalance().Sub(amount).GreaterThanOrEqual(a.BalanceFloor())
+}
+
+// BalanceFloor is the lowest the balance may be debited to: minus the
+// overdraft limit, or the minimum balance for a savings account if that is
+// higher.
+func (a *Account) BalanceFloor() decimal.Decimal {
+	floor := a.OverdraftLimit.Neg()
+	if a.AccountType == AccountTypeSavings && a.MinBalance.GreaterThan(floor) {
+		floor = a.MinBalance
+	}
+	return floor
+}
+
+// HasOverdraft reports whether the account may go below zero.
+func (a *Account) HasOverdraft() bool {
+	return a.OverdraftLimit.IsPositive()
 }
 
 func (a *Account) CanCredit() bool {
-	return a.Status == AccountStatusActive
+	return a.IsActive()
+}
+
+// WithinDailyLimit reports whether amount can be sent on top of sentToday
+// without exceeding the account's daily transfer limit, if it has one.
+func (a *Account) WithinDailyLimit(sentToday, amount decimal.Decimal) bool {
+	return a.DailyTransferLimit == nil || sentToday.Add(amount).LessThanOrEqual(*a.DailyTransferLimit)
+}
+
+// accountStatusTransitions lists the statuses an owner may move an account to
+// from each status. A frozen account must be unfrozen before it can be
+// deactivated.
+var accountStatusTransitions = map[AccountStatus][]AccountStatus{
+	AccountStatusActive:   {AccountStatusInactive, AccountStatusFrozen},
+	AccountStatusInactive: {AccountStatusActive},
+	AccountStatusFrozen:   {AccountStatusActive},
+}
+
+// CanTransitionTo reports whether the owner may move the account to status.
+// A dormancy freeze can only be lifted by an admin.
+func (a *Account) CanTransitionTo(status AccountStatus) bool {
+	if a.IsDormant() {
+		return false
+	}
+	for _, allowed := range accountStatusTransitions[a.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Account) IsDormant() bool {
+	return a.Status == AccountStatusFrozen && a.StatusReason == AccountStatusReasonDormant
+}
+
+// MaskAccountNumber hides all but the last four digits of an account number.
+func MaskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return strings.Repeat("*", len(accountNumber)-4) + accountNumber[len(accountNumber)-4:]
 }
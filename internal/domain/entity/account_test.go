@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountResponseAmountFormat(t *testing.T) {
+	account := NewAccount(uuid.New(), "", AccountTypeSavings, CurrencyUSD)
+	account.Balance = decimal.RequireFromString("1234.5678")
+
+	display := account.ToResponse(AmountDisplay)
+	if display.Balance != "1234.57" {
+		t.Fatalf("display balance = %s, want 1234.57", display.Balance)
+	}
+
+	full := account.ToResponse(AmountFull)
+	if full.Balance != "1234.5678" {
+		t.Fatalf("full balance = %s, want 1234.5678", full.Balance)
+	}
+}
+
+func TestAmountFormatAtDisplayPrecisionIsUnchanged(t *testing.T) {
+	amount := decimal.RequireFromString("10.25")
+
+	if got := AmountDisplay.Format(amount, CurrencyEUR.DisplayScale()); got != "10.25" {
+		t.Fatalf("display = %s, want 10.25", got)
+	}
+	if got := AmountFull.Format(amount, CurrencyEUR.DisplayScale()); got != "10.2500" {
+		t.Fatalf("full = %s, want 10.2500", got)
+	}
+}
+
+func TestCurrencyDisplayScale(t *testing.T) {
+	for _, currency := range []Currency{CurrencyUSD, CurrencyEUR, CurrencyGBP, "XYZ"} {
+		if got := currency.DisplayScale(); got != 2 {
+			t.Errorf("%s.DisplayScale() = %d, want 2", currency, got)
+		}
+	}
+}
+
+func TestDormantAccountCannotBeReactivatedByOwner(t *testing.T) {
+	account := NewAccount(uuid.New(), "", AccountTypeChecking, CurrencyUSD)
+	account.Status = AccountStatusFrozen
+
+	if !account.CanTransitionTo(AccountStatusActive) {
+		t.Fatal("owner cannot lift an ordinary freeze")
+	}
+
+	account.StatusReason = AccountStatusReasonDormant
+	if !account.IsDormant() {
+		t.Fatal("account frozen for dormancy is not dormant")
+	}
+	if account.CanTransitionTo(AccountStatusActive) {
+		t.Fatal("owner may lift a dormancy freeze")
+	}
+}
+
+func TestAccountResponseSeparatesAvailableBalance(t *testing.T) {
+	account := NewAccount(uuid.New(), "", AccountTypeChecking, CurrencyUSD)
+	account.Balance = decimal.RequireFromString("100")
+	account.PendingDebits = decimal.RequireFromString("30")
+
+	response := account.ToResponse(AmountDisplay)
+	if response.CurrentBalance != "100.00" || response.Balance != response.CurrentBalance {
+		t.Fatalf("current = %s, balance = %s; want both 100.00", response.CurrentBalance, response.Balance)
+	}
+	if response.AvailableBalance != "70.00" {
+		t.Fatalf("available = %s, want 70.00", response.AvailableBalance)
+	}
+
+	account.PendingDebits = decimal.Zero
+	if response := account.ToResponse(AmountDisplay); response.AvailableBalance != response.CurrentBalance {
+		t.Fatalf("without pending debits available = %s, current = %s", response.AvailableBalance, response.CurrentBalance)
+	}
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyScope string
+
+const (
+	// ScopeRead allows read-only requests.
+	ScopeRead APIKeyScope = "read"
+	// ScopeTransfer allows creating and refunding transfers.
+	ScopeTransfer APIKeyScope = "transfer"
+	// ScopeAdmin allows everything the owning user can do.
+	ScopeAdmin APIKeyScope = "admin"
+)
+
+type APIKey struct {
+	ID         uuid.UUID     `json:"id"`
+	UserID     uuid.UUID     `json:"user_id"`
+	Name       string        `json:"name"`
+	KeyPrefix  string        `json:"key_prefix"`
+	KeyHash    string        `json:"-"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type CreateAPIKeyInput struct {
+	Name      string        `json:"name" validate:"required,max=100"`
+	Scopes    []APIKeyScope `json:"scopes" validate:"required,min=1,dive,oneof=read transfer admin"`
+	ExpiresAt *time.Time    `json:"expires_at"`
+}
+
+// CreatedAPIKey is returned once, when the key is issued. The plaintext key
+// cannot be recovered afterwards.
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// HasScope reports whether the key grants scope. The admin scope grants all
+// others.
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "testing"
+
+func TestAPIKeyHasScope(t *testing.T) {
+	tests := []struct {
+		scopes []APIKeyScope
+		scope  APIKeyScope
+		want   bool
+	}{
+		{[]APIKeyScope{ScopeRead}, ScopeRead, true},
+		{[]APIKeyScope{ScopeRead}, ScopeTransfer, false},
+		{[]APIKeyScope{ScopeRead}, ScopeAdmin, false},
+		{[]APIKeyScope{ScopeRead, ScopeTransfer}, ScopeTransfer, true},
+		{[]APIKeyScope{ScopeAdmin}, ScopeTransfer, true},
+		{[]APIKeyScope{ScopeAdmin}, ScopeRead, true},
+		{nil, ScopeRead, false},
+	}
+
+	for _, tt := range tests {
+		key := &APIKey{Scopes: tt.scopes}
+		if got := key.HasScope(tt.scope); got != tt.want {
+			t.Errorf("key with %v HasScope(%s) = %v, want %v", tt.scopes, tt.scope, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type BalanceAlertCondition string
+
+const (
+	// BalanceAlertAbove fires when the balance rises above the threshold.
+	BalanceAlertAbove BalanceAlertCondition = "above"
+	// BalanceAlertBelow fires when the balance drops below the threshold.
+	BalanceAlertBelow BalanceAlertCondition = "below"
+	// BalanceAlertCrosses fires when the balance moves from one side of the
+	// threshold to the other, in either direction.
+	BalanceAlertCrosses BalanceAlertCondition = "crosses"
+)
+
+// BalanceAlert is a user's subscription to changes in an account's balance.
+// A one-shot alert deactivates once it fires; a recurring one fires each
+// time its condition newly holds.
+type BalanceAlert struct {
+	ID              uuid.UUID             `json:"id"`
+	UserID          uuid.UUID             `json:"user_id"`
+	AccountID       uuid.UUID             `json:"account_id"`
+	Condition       BalanceAlertCondition `json:"condition"`
+	Threshold       decimal.Decimal       `json:"threshold"`
+	Currency        Currency              `json:"currency"`
+	Recurring       bool                  `json:"recurring"`
+	NotifyEmail     bool                  `json:"notify_email"`
+	Active          bool                  `json:"active"`
+	LastTriggeredAt *time.Time            `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// Triggered reports whether a balance change from previous to current
+// fires the alert. Alerts fire on the change that makes their condition
+// true, not on every change while it stays true.
+func (a *BalanceAlert) Triggered(previous, current decimal.Decimal) bool {
+	switch a.Condition {
+	case BalanceAlertAbove:
+		return !previous.GreaterThan(a.Threshold) && current.GreaterThan(a.Threshold)
+	case BalanceAlertBelow:
+		return !previous.LessThan(a.Threshold) && current.LessThan(a.Threshold)
+	case BalanceAlertCrosses:
+		return previous.LessThan(a.Threshold) != current.LessThan(a.Threshold)
+	}
+	return false
+}
+
+// BalanceChange is one account's balance before and after a committed
+// posting, for alert evaluation.
+type BalanceChange struct {
+	Account  *Account
+	Previous decimal.Decimal
+	Current  decimal.Decimal
+}
+
+type CreateBalanceAlertInput struct {
+	AccountID   uuid.UUID `json:"account_id" validate:"required"`
+	Condition   string    `json:"condition" validate:"required,oneof=above below crosses"`
+	Threshold   string    `json:"threshold" validate:"required"`
+	Recurring   bool      `json:"recurring"`
+	NotifyEmail bool      `json:"notify_email"`
+}
+
+// UpdateBalanceAlertInput changes an alert. Setting active re-arms a one-shot
+// alert that has fired.
+type UpdateBalanceAlertInput struct {
+	Threshold   *string `json:"threshold"`
+	Recurring   *bool   `json:"recurring"`
+	NotifyEmail *bool   `json:"notify_email"`
+	Active      *bool   `json:"active"`
+}
+
+type BalanceAlertResponse struct {
+	ID              uuid.UUID             `json:"id"`
+	AccountID       uuid.UUID             `json:"account_id"`
+	Condition       BalanceAlertCondition `json:"condition"`
+	Threshold       string                `json:"threshold"`
+	Currency        Currency              `json:"currency"`
+	Recurring       bool                  `json:"recurring"`
+	NotifyEmail     bool                  `json:"notify_email"`
+	Active          bool                  `json:"active"`
+	LastTriggeredAt *time.Time            `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+}
+
+func (a *BalanceAlert) ToResponse(format AmountFormat) *BalanceAlertResponse {
+	return &BalanceAlertResponse{
+		ID:              a.ID,
+		AccountID:       a.AccountID,
+		Condition:       a.Condition,
+		Threshold:       format.Format(a.Threshold, a.Currency.DisplayScale()),
+		Currency:        a.Currency,
+		Recurring:       a.Recurring,
+		NotifyEmail:     a.NotifyEmail,
+		Active:          a.Active,
+		LastTriggeredAt: a.LastTriggeredAt,
+		CreatedAt:       a.CreatedAt,
+	}
+}
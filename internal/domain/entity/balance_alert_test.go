@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBalanceAlertTriggered(t *testing.T) {
+	tests := []struct {
+		condition         BalanceAlertCondition
+		previous, current string
+		want              bool
+	}{
+		{BalanceAlertBelow, "60", "40", true},
+		{BalanceAlertBelow, "40", "30", false}, // already below
+		{BalanceAlertBelow, "60", "50", false}, // at the threshold is not below
+		{BalanceAlertBelow, "50", "49.99", true},
+		{BalanceAlertAbove, "40", "60", true},
+		{BalanceAlertAbove, "60", "70", false},
+		{BalanceAlertAbove, "40", "50", false},
+		{BalanceAlertCrosses, "40", "60", true},
+		{BalanceAlertCrosses, "60", "40", true},
+		{BalanceAlertCrosses, "60", "70", false},
+		{"sideways", "60", "40", false},
+	}
+	for _, tt := range tests {
+		alert := &BalanceAlert{Condition: tt.condition, Threshold: decimal.RequireFromString("50")}
+		got := alert.Triggered(decimal.RequireFromString(tt.previous), decimal.RequireFromString(tt.current))
+		if got != tt.want {
+			t.Errorf("%s from %s to %s = %v, want %v", tt.condition, tt.previous, tt.current, got, tt.want)
+		}
+	}
+}
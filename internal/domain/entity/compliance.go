@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ComplianceExportFormat string
+
+const (
+	ComplianceExportCSV   ComplianceExportFormat = "csv"
+	ComplianceExportJSONL ComplianceExportFormat = "jsonl"
+)
+
+func (f ComplianceExportFormat) IsValid() bool {
+	return f == ComplianceExportCSV || f == ComplianceExportJSONL
+}
+
+// ComplianceTransferRow is a transfer as reported to regulators, with both
+// account numbers in full.
+type ComplianceTransferRow struct {
+	ID                uuid.UUID
+	ReferenceNumber   string
+	FromAccountNumber string
+	ToAccountNumber   string
+	Amount            decimal.Decimal
+	Currency          Currency
+	Fee               decimal.Decimal
+	ExchangeRate      *decimal.Decimal
+	ConvertedAmount   *decimal.Decimal
+	ConvertedCurrency *Currency
+	Status            TransferStatus
+	CreatedAt         time.Time
+	CompletedAt       *time.Time
+}
+
+// ComplianceExportSummary describes a finished compliance export.
+type ComplianceExportSummary struct {
+	Accounts  int64
+	Transfers int64
+	Signature string
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate quotes how many units of To one unit of From buys.
+type ExchangeRate struct {
+	From Currency        `json:"from"`
+	To   Currency        `json:"to"`
+	Rate decimal.Decimal `json:"rate"`
+	AsOf time.Time       `json:"as_of"`
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventTransferCompleted = "transfer.completed"
+	EventTransferRefunded  = "transfer.refunded"
+	EventTransferReversed  = "transfer.reversed"
+
+	EventBalanceAlertTriggered = "balance_alert.triggered"
+
+	EventAccountStatusChanged = "account.status_changed"
+)
+
+// EventTypes lists every event type the outbox carries.
+var EventTypes = []string{
+	EventTransferCompleted,
+	EventTransferRefunded,
+	EventTransferReversed,
+	EventBalanceAlertTriggered,
+	EventAccountStatusChanged,
+}
+
+// OutboxEvent is a domain event waiting to be published, or one that already
+// has been if PublishedAt is set.
+type OutboxEvent struct {
+	ID            uuid.UUID       `json:"id"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     *string         `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty"`
+}
+
+func NewOutboxEvent(eventType, aggregateType string, aggregateID uuid.UUID, payload interface{}) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		CreatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+// Recipients reads the users an event concerns from its payload.
+func (e *OutboxEvent) Recipients() []uuid.UUID {
+	var payload struct {
+		FromUserID *uuid.UUID `json:"from_user_id"`
+		ToUserID   *uuid.UUID `json:"to_user_id"`
+		UserID     *uuid.UUID `json:"user_id"`
+	}
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return nil
+	}
+
+	var recipients []uuid.UUID
+	if payload.FromUserID != nil {
+		recipients = append(recipients, *payload.FromUserID)
+	}
+	if payload.ToUserID != nil && (payload.FromUserID == nil || *payload.ToUserID != *payload.FromUserID) {
+		recipients = append(recipients, *payload.ToUserID)
+	}
+	if payload.UserID != nil {
+		recipients = append(recipients, *payload.UserID)
+	}
+	return recipients
+}
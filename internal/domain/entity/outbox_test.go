@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestOutboxEventRecipients(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		payload interface{}
+		want    []uuid.UUID
+	}{
+		{"transfer between users", map[string]uuid.UUID{"from_user_id": alice, "to_user_id": bob}, []uuid.UUID{alice, bob}},
+		{"transfer between own accounts", map[string]uuid.UUID{"from_user_id": alice, "to_user_id": alice}, []uuid.UUID{alice}},
+		{"account event", map[string]uuid.UUID{"user_id": bob}, []uuid.UUID{bob}},
+		{"no users", map[string]string{"amount": "1.00"}, nil},
+	}
+
+	for _, tt := range tests {
+		event, err := NewOutboxEvent(EventTransferCompleted, "transfer", uuid.New(), tt.payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := event.Recipients()
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: recipients = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: recipients = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/pkg/recurrence"
+)
+
+type RecurringTransferStatus string
+
+const (
+	RecurringTransferStatusActive    RecurringTransferStatus = "active"
+	RecurringTransferStatusCancelled RecurringTransferStatus = "cancelled"
+	RecurringTransferStatusCompleted RecurringTransferStatus = "completed"
+)
+
+// RecurringTransfer is a standing order. Each run materializes a scheduled
+// transfer, which is settled (or fails) like any other.
+type RecurringTransfer struct {
+	ID            uuid.UUID               `json:"id"`
+	UserID        uuid.UUID               `json:"user_id"`
+	FromAccountID uuid.UUID               `json:"from_account_id"`
+	ToAccountID   uuid.UUID               `json:"to_account_id"`
+	Amount        decimal.Decimal         `json:"amount"`
+	Currency      Currency                `json:"currency"`
+	Description   string                  `json:"description,omitempty"`
+	Interval      recurrence.Interval     `json:"interval"`
+	Timezone      string                  `json:"timezone"`
+	StartAt       time.Time               `json:"start_at"`
+	EndDate       *time.Time              `json:"end_date,omitempty"`
+	NextRunAt     *time.Time              `json:"next_run_at,omitempty"`
+	RunCount      int                     `json:"run_count"`
+	Status        RecurringTransferStatus `json:"status"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+type CreateRecurringTransferInput struct {
+	FromAccountID uuid.UUID  `json:"from_account_id" validate:"required"`
+	ToAccountID   uuid.UUID  `json:"to_account_id" validate:"required,nefield=FromAccountID"`
+	Amount        string     `json:"amount" validate:"required,decimalgt=0"`
+	Description   string     `json:"description" validate:"omitempty,max=255"`
+	Interval      string     `json:"interval" validate:"required,oneof=daily weekly monthly"`
+	Timezone      string     `json:"timezone" validate:"required,timezone"`
+	StartAt       time.Time  `json:"start_at" validate:"required"`
+	EndDate       *time.Time `json:"end_date"`
+}
+
+type RecurringTransferResponse struct {
+	ID            uuid.UUID               `json:"id"`
+	FromAccountID uuid.UUID               `json:"from_account_id"`
+	ToAccountID   uuid.UUID               `json:"to_account_id"`
+	Amount        string                  `json:"amount"`
+	Currency      Currency                `json:"currency"`
+	Description   string                  `json:"description,omitempty"`
+	Interval      recurrence.Interval     `json:"interval"`
+	Timezone      string                  `json:"timezone"`
+	StartAt       time.Time               `json:"start_at"`
+	EndDate       *time.Time              `json:"end_date,omitempty"`
+	NextRunAt     *time.Time              `json:"next_run_at,omitempty"`
+	RunCount      int                     `json:"run_count"`
+	Status        RecurringTransferStatus `json:"status"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+// Schedule returns the recurrence the standing order follows.
+func (r *RecurringTransfer) Schedule() (*recurrence.Schedule, error) {
+	return recurrence.New(r.Interval, r.StartAt, r.Timezone)
+}
+
+// Advance records that run number RunCount was materialized and moves
+// NextRunAt to the following run, completing the order once that would fall
+// after EndDate.
+func (r *RecurringTransfer) Advance(schedule *recurrence.Schedule) {
+	r.RunCount++
+	next := schedule.Occurrence(r.RunCount)
+	if r.EndDate != nil && next.After(*r.EndDate) {
+		r.NextRunAt = nil
+		r.Status = RecurringTransferStatusCompleted
+		return
+	}
+	r.NextRunAt = &next
+}
+
+func (r *RecurringTransfer) ToResponse(format AmountFormat) *RecurringTransferResponse {
+	return &RecurringTransferResponse{
+		ID:            r.ID,
+		FromAccountID: r.FromAccountID,
+		ToAccountID:   r.ToAccountID,
+		Amount:        format.Format(r.Amount, r.Currency.DisplayScale()),
+		Currency:      r.Currency,
+		Description:   r.Description,
+		Interval:      r.Interval,
+		Timezone:      r.Timezone,
+		StartAt:       r.StartAt,
+		EndDate:       r.EndDate,
+		NextRunAt:     r.NextRunAt,
+		RunCount:      r.RunCount,
+		Status:        r.Status,
+		CreatedAt:     r.CreatedAt,
+	}
+}
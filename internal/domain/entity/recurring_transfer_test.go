@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/gobank/internal/pkg/recurrence"
+)
+
+func TestRecurringTransferAdvance(t *testing.T) {
+	start := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := &RecurringTransfer{
+		Interval:  recurrence.Monthly,
+		Timezone:  "Europe/Berlin",
+		StartAt:   start,
+		EndDate:   &end,
+		NextRunAt: &start,
+		Status:    RecurringTransferStatusActive,
+	}
+
+	schedule, err := r.Schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Advance(schedule)
+	if want := time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC); r.NextRunAt == nil || !r.NextRunAt.Equal(want) {
+		t.Fatalf("next run = %v, want %v", r.NextRunAt, want)
+	}
+
+	// Mar 31 is past the end date.
+	r.Advance(schedule)
+	if r.NextRunAt != nil || r.Status != RecurringTransferStatusCompleted {
+		t.Fatalf("after the last run: next = %v, status = %s", r.NextRunAt, r.Status)
+	}
+	if r.RunCount != 2 {
+		t.Fatalf("run count = %d, want 2", r.RunCount)
+	}
+}
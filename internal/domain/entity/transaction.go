@@ -1,10 +1,17 @@
 package entity
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/pkg/money"
 )
 
 type TransactionType string
@@ -17,6 +24,8 @@ const (
 	TransferStatusPending   TransferStatus = "pending"
 	TransferStatusCompleted TransferStatus = "completed"
 	TransferStatusFailed    TransferStatus = "failed"
+	TransferStatusReversed  TransferStatus = "reversed"
+	TransferStatusScheduled TransferStatus = "scheduled"
 )
 
 type Transaction struct {
@@ -28,36 +37,155 @@ type Transaction struct {
 	Description  string          `json:"description"`
 	ReferenceID  *uuid.UUID      `json:"reference_id,omitempty"`
 	CreatedAt    time.Time       `json:"created_at"`
+
+	// Category and Tags let the owner track spending. A nil Category means
+	// uncategorized.
+	Category *string  `json:"category,omitempty"`
+	Tags     []string `json:"tags"`
 }
 
 type Transfer struct {
-	ID             uuid.UUID       `json:"id"`
-	IdempotencyKey *string         `json:"idempotency_key,omitempty"`
-	FromAccountID  uuid.UUID       `json:"from_account_id"`
-	ToAccountID    uuid.UUID       `json:"to_account_id"`
-	Amount         decimal.Decimal `json:"amount"`
-	Currency       Currency        `json:"currency"`
-	Status         TransferStatus  `json:"status"`
-	CreatedAt      time.Time       `json:"created_at"`
-	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	ID              uuid.UUID       `json:"id"`
+	IdempotencyKey  *string         `json:"idempotency_key,omitempty"`
+	ReferenceNumber string          `json:"reference_number"`
+	FromAccountID   uuid.UUID       `json:"from_account_id"`
+	ToAccountID     uuid.UUID       `json:"to_account_id"`
+	Amount          decimal.Decimal `json:"amount"`
+	Currency        Currency        `json:"currency"`
+	Status          TransferStatus  `json:"status"`
+	CreatedAt       time.Time       `json:"created_at"`
+	CompletedAt     *time.Time      `json:"completed_at,omitempty"`
+	RefundedAmount  decimal.Decimal `json:"refunded_amount"`
+	Fee             decimal.Decimal `json:"fee"`
+	RefundOf        *uuid.UUID      `json:"refund_of,omitempty"`
+	ReversalOf      *uuid.UUID      `json:"reversal_of,omitempty"`
+	ScheduledAt     *time.Time      `json:"scheduled_at,omitempty"`
+	Description     string          `json:"description,omitempty"`
+	RequestHash     string          `json:"-"`
+	// Category and Tags are copied to the sender's ledger entry when the
+	// transfer posts.
+	Category *string  `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+
+	// Set on cross-currency transfers. Amount is debited in Currency and
+	// ConvertedAmount = Amount * ExchangeRate is credited in ConvertedCurrency.
+	ExchangeRate      *decimal.Decimal `json:"exchange_rate,omitempty"`
+	ConvertedAmount   *decimal.Decimal `json:"converted_amount,omitempty"`
+	ConvertedCurrency *Currency        `json:"converted_currency,omitempty"`
 }
 
 type CreateTransferInput struct {
-	FromAccountID  uuid.UUID `json:"from_account_id" validate:"required"`
-	ToAccountID    uuid.UUID `json:"to_account_id" validate:"required,nefield=FromAccountID"`
-	Amount         string    `json:"amount" validate:"required"`
+	FromAccountID   uuid.UUID `json:"from_account_id" validate:"required"`
+	ToAccountID     uuid.UUID `json:"to_account_id" validate:"required_without=ToAccountNumber,nefield=FromAccountID"`
+	ToAccountNumber string    `json:"to_account_number" validate:"omitempty,max=20"`
+	Amount          string    `json:"amount" validate:"required,decimalgt=0"`
+	IdempotencyKey  string    `json:"idempotency_key" validate:"omitempty,max=255"`
+	Description     string    `json:"description" validate:"omitempty,max=255"`
+	// ScheduledAt defers the transfer to a future time. Funds are checked
+	// and moved when it runs, not when it is created.
+	ScheduledAt *time.Time `json:"scheduled_at"`
+	// AllowConversion lets a transfer between accounts in different
+	// currencies go through at the current exchange rate.
+	AllowConversion bool `json:"allow_conversion"`
+	// Category and Tags label the sender's side of the transfer.
+	Category string   `json:"category" validate:"omitempty,max=50"`
+	Tags     []string `json:"tags" validate:"omitempty,max=10,dive,required,max=30"`
+}
+
+// RequestHash fingerprints the parameters of a transfer request so a reused
+// idempotency key can be checked against the request that first used it.
+// The amount is passed parsed so that "100" and "100.00" hash the same.
+func (i *CreateTransferInput) RequestHash(userID uuid.UUID, amount decimal.Decimal) string {
+	parts := []string{
+		userID.String(),
+		i.FromAccountID.String(),
+		i.ToAccountID.String(),
+		amount.String(),
+		strings.TrimSpace(i.Description),
+	}
+	// Appended only when set so hashes of immediate transfers are unchanged.
+	if i.ScheduledAt != nil {
+		parts = append(parts, i.ScheduledAt.UTC().Format(time.RFC3339Nano))
+	}
+	if i.AllowConversion {
+		parts = append(parts, "allow_conversion")
+	}
+	if category := NormalizeCategory(i.Category); category != nil {
+		parts = append(parts, "category:"+*category)
+	}
+	if tags := NormalizeTags(i.Tags); len(tags) > 0 {
+		parts = append(parts, "tags:"+strings.Join(tags, ","))
+	}
+	payload := strings.Join(parts, "\x00")
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// MoveFundsInput moves money between two of the caller's accounts. The
+// source account is given by the URL.
+type MoveFundsInput struct {
+	ToAccountID    uuid.UUID `json:"to_account_id" validate:"required"`
+	Amount         string    `json:"amount" validate:"required,decimalgt=0"`
 	IdempotencyKey string    `json:"idempotency_key" validate:"omitempty,max=255"`
+	Description    string    `json:"description" validate:"omitempty,max=255"`
+}
+
+// CreateTransferBatchInput submits several transfers at once. Each transfer
+// without its own idempotency key gets one derived from IdempotencyKey and
+// its position, so a retried batch does not move money twice.
+type CreateTransferBatchInput struct {
+	IdempotencyKey string `json:"idempotency_key" validate:"omitempty,max=200"`
+	// AllOrNothing makes the whole batch one transaction: if any transfer
+	// fails, none is made.
+	AllOrNothing bool                  `json:"all_or_nothing"`
+	Transfers    []CreateTransferInput `json:"transfers" validate:"required,min=1,max=100,dive"`
+}
+
+// ItemIdempotencyKey returns the idempotency key of the transfer at index.
+func (i *CreateTransferBatchInput) ItemIdempotencyKey(index int) string {
+	if key := i.Transfers[index].IdempotencyKey; key != "" || i.IdempotencyKey == "" {
+		return key
+	}
+	return fmt.Sprintf("%s/%d", i.IdempotencyKey, index)
+}
+
+// TransferBatchResult is the outcome of one transfer in a batch: the
+// transfer, or the error that stopped it.
+type TransferBatchResult struct {
+	Transfer *Transfer
+	Err      error
 }
 
 type TransferResponse struct {
-	ID             uuid.UUID      `json:"id"`
-	FromAccountID  uuid.UUID      `json:"from_account_id"`
-	ToAccountID    uuid.UUID      `json:"to_account_id"`
-	Amount         string         `json:"amount"`
-	Currency       Currency       `json:"currency"`
-	Status         TransferStatus `json:"status"`
-	CreatedAt      time.Time      `json:"created_at"`
-	CompletedAt    *time.Time     `json:"completed_at,omitempty"`
+	ID              uuid.UUID      `json:"id"`
+	ReferenceNumber string         `json:"reference_number"`
+	FromAccountID   uuid.UUID      `json:"from_account_id"`
+	ToAccountID     uuid.UUID      `json:"to_account_id"`
+	Amount          string         `json:"amount"`
+	Currency        Currency       `json:"currency"`
+	Status          TransferStatus `json:"status"`
+	CreatedAt       time.Time      `json:"created_at"`
+	CompletedAt     *time.Time     `json:"completed_at,omitempty"`
+	RefundedAmount  string         `json:"refunded_amount"`
+	Fee             string         `json:"fee"`
+	RefundOf        *uuid.UUID     `json:"refund_of,omitempty"`
+	ReversalOf      *uuid.UUID     `json:"reversal_of,omitempty"`
+	ScheduledAt     *time.Time     `json:"scheduled_at,omitempty"`
+	Description     string         `json:"description,omitempty"`
+	Category        *string        `json:"category,omitempty"`
+	Tags            []string       `json:"tags,omitempty"`
+
+	ExchangeRate      *string   `json:"exchange_rate,omitempty"`
+	ConvertedAmount   *string   `json:"converted_amount,omitempty"`
+	ConvertedCurrency *Currency `json:"converted_currency,omitempty"`
+}
+
+type RefundTransferInput struct {
+	Amount string `json:"amount" validate:"required,decimalgt=0"`
+}
+
+type ReverseTransferInput struct {
+	Reason string `json:"reason" validate:"required,max=200"`
 }
 
 type TransactionResponse struct {
@@ -67,8 +195,128 @@ type TransactionResponse struct {
 	BalanceAfter string          `json:"balance_after"`
 	Description  string          `json:"description"`
 	CreatedAt    time.Time       `json:"created_at"`
+	Category     *string         `json:"category,omitempty"`
+	Tags         []string        `json:"tags"`
 }
 
+// AccountTransaction is a transaction listed alongside the account it belongs
+// to, for feeds that span several accounts.
+type AccountTransaction struct {
+	Transaction   *Transaction
+	AccountNumber string
+	Currency      Currency
+}
+
+type AccountTransactionResponse struct {
+	*TransactionResponse
+	AccountID     uuid.UUID `json:"account_id"`
+	AccountNumber string    `json:"account_number"`
+	Currency      Currency  `json:"currency"`
+}
+
+func (t *AccountTransaction) ToResponse(format AmountFormat) *AccountTransactionResponse {
+	resp := &AccountTransactionResponse{
+		TransactionResponse: t.Transaction.ToResponse(format),
+		AccountID:           t.Transaction.AccountID,
+		AccountNumber:       t.AccountNumber,
+		Currency:            t.Currency,
+	}
+	resp.Amount = format.Format(t.Transaction.Amount, t.Currency.DisplayScale())
+	resp.BalanceAfter = format.Format(t.Transaction.BalanceAfter, t.Currency.DisplayScale())
+	return resp
+}
+
+type TransactionDetail struct {
+	Transaction         *Transaction
+	Transfer            *Transfer
+	CounterpartyAccount *Account
+}
+
+type TransferSummary struct {
+	ID                  uuid.UUID      `json:"id"`
+	ReferenceNumber     string         `json:"reference_number"`
+	CounterpartyAccount string         `json:"counterparty_account,omitempty"`
+	Amount              string         `json:"amount"`
+	Currency            Currency       `json:"currency"`
+	Status              TransferStatus `json:"status"`
+	CreatedAt           time.Time      `json:"created_at"`
+}
+
+type TransactionDetailResponse struct {
+	*TransactionResponse
+	Transfer *TransferSummary `json:"transfer,omitempty"`
+}
+
+type TransferDirection string
+
+const (
+	TransferDirectionIncoming TransferDirection = "incoming"
+	TransferDirectionOutgoing TransferDirection = "outgoing"
+	TransferDirectionInternal TransferDirection = "internal"
+)
+
+// AccountTransferDirection selects an account's transfers by the side of
+// them the account is on.
+type AccountTransferDirection string
+
+const (
+	AccountTransfersIn  AccountTransferDirection = "in"
+	AccountTransfersOut AccountTransferDirection = "out"
+	AccountTransfersAll AccountTransferDirection = "all"
+)
+
+func (d AccountTransferDirection) IsValid() bool {
+	return d == AccountTransfersIn || d == AccountTransfersOut || d == AccountTransfersAll
+}
+
+type TransferExportRow struct {
+	ReferenceNumber     string
+	Direction           TransferDirection
+	CounterpartyAccount string
+	Amount              decimal.Decimal
+	Currency            Currency
+	Status              TransferStatus
+	Description         string
+	CreatedAt           time.Time
+	CompletedAt         *time.Time
+}
+
+type TransactionImportRow struct {
+	Line        int
+	Type        string
+	Amount      string
+	Description string
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowValid    ImportRowStatus = "valid"
+	ImportRowImported ImportRowStatus = "imported"
+	ImportRowError    ImportRowStatus = "error"
+)
+
+type TransactionImportResult struct {
+	Line          int             `json:"line"`
+	Status        ImportRowStatus `json:"status"`
+	TransactionID *uuid.UUID      `json:"transaction_id,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+type TransactionImportReport struct {
+	AccountID    uuid.UUID                  `json:"account_id"`
+	DryRun       bool                       `json:"dry_run"`
+	Total        int                        `json:"total"`
+	Imported     int                        `json:"imported"`
+	Failed       int                        `json:"failed"`
+	BalanceAfter string                     `json:"balance_after"`
+	Results      []*TransactionImportResult `json:"results"`
+}
+
+// AuditActionRefreshTokenReuse is recorded when a rotated refresh token is
+// replayed and its whole family is revoked.
+const AuditActionRefreshTokenReuse = "user.refresh_token_reuse"
+
 type AuditLog struct {
 	ID         uuid.UUID              `json:"id"`
 	UserID     *uuid.UUID             `json:"user_id,omitempty"`
@@ -82,6 +330,85 @@ type AuditLog struct {
 	CreatedAt  time.Time              `json:"created_at"`
 }
 
+// TransactionFilter narrows a transaction listing. A nil bound is open and a
+// nil Type matches both credits and debits.
+// Statement summarizes an account's activity over a period. Its
+// transactions are streamed separately.
+type Statement struct {
+	AccountID        uuid.UUID
+	AccountNumber    string
+	Currency         Currency
+	StartDate        time.Time
+	EndDate          time.Time
+	OpeningBalance   decimal.Decimal
+	ClosingBalance   decimal.Decimal
+	TransactionCount int64
+}
+
+type TransactionFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Type      *TransactionType
+	Category  *string
+}
+
+func (f *TransactionFilter) HasDateRange() bool {
+	return f != nil && (f.StartDate != nil || f.EndDate != nil)
+}
+
+// DateRange returns the filter's bounds with open ends replaced by the
+// earliest and latest representable dates.
+func (f *TransactionFilter) DateRange() (time.Time, time.Time) {
+	start := time.Unix(0, 0).UTC()
+	end := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	if f.StartDate != nil {
+		start = *f.StartDate
+	}
+	if f.EndDate != nil {
+		end = *f.EndDate
+	}
+	return start, end
+}
+
+// TransactionCursor marks a position in an account's transaction history,
+// which is ordered newest first by (created_at, id).
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c TransactionCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTransactionCursor decodes a token produced by Encode.
+func ParseTransactionCursor(token string) (TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return TransactionCursor{}, err
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return TransactionCursor{}, errors.New("malformed cursor")
+	}
+
+	var cursor TransactionCursor
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return TransactionCursor{}, err
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return TransactionCursor{}, err
+	}
+	return cursor, nil
+}
+
+func (t *Transaction) Cursor() TransactionCursor {
+	return TransactionCursor{CreatedAt: t.CreatedAt, ID: t.ID}
+}
+
 func NewTransfer(fromAccountID, toAccountID uuid.UUID, amount decimal.Decimal, currency Currency, idempotencyKey *string) *Transfer {
 	return &Transfer{
 		ID:             uuid.New(),
@@ -95,6 +422,84 @@ func NewTransfer(fromAccountID, toAccountID uuid.UUID, amount decimal.Decimal, c
 	}
 }
 
+// NormalizeCategory trims and lowercases a category so "Groceries" and
+// "groceries " group together. An empty category is nil: uncategorized.
+func NormalizeCategory(category string) *string {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return nil
+	}
+	return &category
+}
+
+// NormalizeTags trims, lowercases and de-duplicates tags, keeping their
+// order and dropping empty ones.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// CategorySpending totals an account's debits in one category. Category is
+// nil for uncategorized debits, fees included.
+type CategorySpending struct {
+	Category *string
+	Total    decimal.Decimal
+	Count    int64
+}
+
+// SpendingSummary groups an account's debits over a period by category.
+type SpendingSummary struct {
+	AccountID  uuid.UUID
+	Currency   Currency
+	StartDate  time.Time
+	EndDate    time.Time
+	Total      decimal.Decimal
+	Categories []*CategorySpending
+}
+
+type CategorySpendingResponse struct {
+	Category *string `json:"category"`
+	Total    string  `json:"total"`
+	Count    int64   `json:"count"`
+}
+
+type SpendingSummaryResponse struct {
+	AccountID  uuid.UUID                   `json:"account_id"`
+	Currency   Currency                    `json:"currency"`
+	StartDate  time.Time                   `json:"start_date"`
+	EndDate    time.Time                   `json:"end_date"`
+	Total      string                      `json:"total"`
+	Categories []*CategorySpendingResponse `json:"categories"`
+}
+
+func (s *SpendingSummary) ToResponse(format AmountFormat) *SpendingSummaryResponse {
+	resp := &SpendingSummaryResponse{
+		AccountID:  s.AccountID,
+		Currency:   s.Currency,
+		StartDate:  s.StartDate,
+		EndDate:    s.EndDate,
+		Total:      format.Format(s.Total, s.Currency.DisplayScale()),
+		Categories: make([]*CategorySpendingResponse, len(s.Categories)),
+	}
+	for i, c := range s.Categories {
+		resp.Categories[i] = &CategorySpendingResponse{
+			Category: c.Category,
+			Total:    format.Format(c.Total, s.Currency.DisplayScale()),
+			Count:    c.Count,
+		}
+	}
+	return resp
+}
+
 func NewTransaction(accountID uuid.UUID, txType TransactionType, amount, balanceAfter decimal.Decimal, description string, referenceID *uuid.UUID) *Transaction {
 	return &Transaction{
 		ID:           uuid.New(),
@@ -108,26 +513,102 @@ func NewTransaction(accountID uuid.UUID, txType TransactionType, amount, balance
 	}
 }
 
-func (t *Transfer) ToResponse() *TransferResponse {
-	return &TransferResponse{
-		ID:            t.ID,
-		FromAccountID: t.FromAccountID,
-		ToAccountID:   t.ToAccountID,
-		Amount:        t.Amount.StringFixed(2),
-		Currency:      t.Currency,
-		Status:        t.Status,
-		CreatedAt:     t.CreatedAt,
-		CompletedAt:   t.CompletedAt,
+func (t *Transfer) ToResponse(format AmountFormat) *TransferResponse {
+	resp := &TransferResponse{
+		ID:              t.ID,
+		ReferenceNumber: t.ReferenceNumber,
+		FromAccountID:   t.FromAccountID,
+		ToAccountID:     t.ToAccountID,
+		Amount:          format.Format(t.Amount, t.Currency.DisplayScale()),
+		Currency:        t.Currency,
+		Status:          t.Status,
+		CreatedAt:       t.CreatedAt,
+		CompletedAt:     t.CompletedAt,
+		RefundedAmount:  format.Format(t.RefundedAmount, t.Currency.DisplayScale()),
+		Fee:             format.Format(t.Fee, t.Currency.DisplayScale()),
+		RefundOf:        t.RefundOf,
+		ReversalOf:      t.ReversalOf,
+		ScheduledAt:     t.ScheduledAt,
+		Description:     t.Description,
+		Category:        t.Category,
+		Tags:            t.Tags,
+	}
+
+	if t.IsConverted() {
+		rate := t.ExchangeRate.String()
+		converted := format.Format(*t.ConvertedAmount, t.ConvertedCurrency.DisplayScale())
+		resp.ExchangeRate = &rate
+		resp.ConvertedAmount = &converted
+		resp.ConvertedCurrency = t.ConvertedCurrency
+	}
+
+	return resp
+}
+
+// IsConverted reports whether the transfer crossed currencies.
+func (t *Transfer) IsConverted() bool {
+	return t.ExchangeRate != nil && t.ConvertedAmount != nil && t.ConvertedCurrency != nil
+}
+
+// DebitAmount is the total taken from the source account: the amount plus
+// any fee.
+func (t *Transfer) DebitAmount() decimal.Decimal {
+	return t.Amount.Add(t.Fee)
+}
+
+// CreditAmount is the amount the destination account receives, in its own
+// currency.
+func (t *Transfer) CreditAmount() decimal.Decimal {
+	if t.IsConverted() {
+		return *t.ConvertedAmount
 	}
+	return t.Amount
+}
+
+// IsCompensating reports whether the transfer is itself a refund or reversal
+// of another transfer.
+func (t *Transfer) IsCompensating() bool {
+	return t.RefundOf != nil || t.ReversalOf != nil
 }
 
-func (t *Transaction) ToResponse() *TransactionResponse {
+// RefundableAmount is the part of the transfer that has not been refunded yet.
+func (t *Transfer) RefundableAmount() decimal.Decimal {
+	return t.Amount.Sub(t.RefundedAmount)
+}
+
+// ToResponse formats amounts with the default display scale, since a
+// transaction row does not carry its account's currency.
+func (t *Transaction) ToResponse(format AmountFormat) *TransactionResponse {
 	return &TransactionResponse{
 		ID:           t.ID,
 		Type:         t.Type,
-		Amount:       t.Amount.StringFixed(2),
-		BalanceAfter: t.BalanceAfter.StringFixed(2),
+		Amount:       format.Format(t.Amount, money.DisplayScale),
+		BalanceAfter: format.Format(t.BalanceAfter, money.DisplayScale),
 		Description:  t.Description,
 		CreatedAt:    t.CreatedAt,
+		Category:     t.Category,
+		Tags:         t.Tags,
+	}
+}
+
+func (d *TransactionDetail) ToResponse(format AmountFormat) *TransactionDetailResponse {
+	resp := &TransactionDetailResponse{
+		TransactionResponse: d.Transaction.ToResponse(format),
 	}
+
+	if d.Transfer != nil {
+		resp.Transfer = &TransferSummary{
+			ID:              d.Transfer.ID,
+			ReferenceNumber: d.Transfer.ReferenceNumber,
+			Amount:          format.Format(d.Transfer.Amount, d.Transfer.Currency.DisplayScale()),
+			Currency:        d.Transfer.Currency,
+			Status:          d.Transfer.Status,
+			CreatedAt:       d.Transfer.CreatedAt,
+		}
+		if d.CounterpartyAccount != nil {
+			resp.Transfer.CounterpartyAccount = MaskAccountNumber(d.CounterpartyAccount.AccountNumber)
+		}
+	}
+
+	return resp
 }
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestCreateTransferInputRequestHash(t *testing.T) {
+	userID := uuid.New()
+	base := CreateTransferInput{
+		FromAccountID: uuid.New(),
+		ToAccountID:   uuid.New(),
+		Amount:        "100",
+		Description:   "Rent",
+	}
+	hash := func(input CreateTransferInput, amount string) string {
+		return input.RequestHash(userID, decimal.RequireFromString(amount))
+	}
+	original := hash(base, "100")
+
+	same := base
+	same.Description = "  Rent "
+	if got := hash(same, "100.00"); got != original {
+		t.Fatal("hash differs for the same amount and memo written differently")
+	}
+
+	otherDestination := base
+	otherDestination.ToAccountID = uuid.New()
+	otherMemo := base
+	otherMemo.Description = "Deposit"
+	for name, got := range map[string]string{
+		"amount":      hash(base, "100.01"),
+		"destination": hash(otherDestination, "100"),
+		"memo":        hash(otherMemo, "100"),
+		"user":        base.RequestHash(uuid.New(), decimal.RequireFromString("100")),
+	} {
+		if got == original {
+			t.Errorf("hash unchanged by a different %s", name)
+		}
+	}
+}
@@ -13,14 +13,30 @@ const (
 	RoleAdmin UserRole = "admin"
 )
 
+type UserStatus string
+
+const (
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+)
+
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	FullName     string    `json:"full_name"`
-	Role         UserRole  `json:"role"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	FullName     string     `json:"full_name"`
+	Role         UserRole   `json:"role"`
+	Status       UserStatus `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+
+	// TOTPSecret is the encrypted TOTP secret. It is set when 2FA enrollment
+	// starts; TwoFactorEnabled is set once the first code is confirmed.
+	TOTPSecret       *string `json:"-"`
+	TwoFactorEnabled bool    `json:"two_factor_enabled"`
 }
 
 type CreateUserInput struct {
@@ -34,26 +50,87 @@ type UpdateUserInput struct {
 	Email    string `json:"email" validate:"omitempty,email,max=255"`
 }
 
+type SuspendUserInput struct {
+	Reason string `json:"reason" validate:"omitempty,max=500"`
+}
+
+type ChangeRoleInput struct {
+	Role UserRole `json:"role" validate:"required,oneof=user admin"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" validate:"required,max=255"`
+}
+
+type ResendVerificationInput struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" validate:"required,max=255"`
+	Code           string `json:"code" validate:"required,len=6,numeric"`
+}
+
 type LoginInput struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
 
+// AuthTokens is the result of a login. When the user has 2FA enabled, the
+// first step returns only TwoFactorRequired, a ChallengeToken and its
+// lifetime in ExpiresIn; the tokens come from completing the challenge.
 type AuthTokens struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	TokenType    string `json:"token_type"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty"`
 	ExpiresIn    int64  `json:"expires_in"`
+
+	TwoFactorRequired bool   `json:"2fa_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
 }
 
+// TwoFactorEnrollment is returned when 2FA enrollment starts. The URI is
+// meant to be rendered as a QR code.
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RefreshToken is one link in a rotation chain. FamilyID is shared by every
+// token descended from the same login.
 type RefreshToken struct {
 	ID        uuid.UUID `json:"id"`
 	UserID    uuid.UUID `json:"user_id"`
+	FamilyID  uuid.UUID `json:"family_id"`
 	TokenHash string    `json:"-"`
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// PasswordResetToken is a single-use token that lets a user set a new
+// password. Only its hash is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
 func NewUser(email, passwordHash, fullName string) *User {
 	now := time.Now().UTC()
 	return &User{
@@ -62,6 +139,7 @@ func NewUser(email, passwordHash, fullName string) *User {
 		PasswordHash: passwordHash,
 		FullName:     fullName,
 		Role:         RoleUser,
+		Status:       UserStatusActive,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
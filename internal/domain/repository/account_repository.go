@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -14,7 +15,22 @@ type AccountRepository interface {
 	GetByAccountNumber(ctx context.Context, accountNumber string) (*entity.Account, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.Account, error)
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Update and UpdateBalance only apply if account.Version still matches
+	// the stored row, returning apperror.ErrConflict otherwise. On success
+	// they advance account.Version.
 	Update(ctx context.Context, account *entity.Account) error
-	UpdateBalance(ctx context.Context, id uuid.UUID, newBalance decimal.Decimal) error
+	UpdateBalance(ctx context.Context, account *entity.Account, newBalance decimal.Decimal) error
+	Close(ctx context.Context, id uuid.UUID) (bool, error)
 	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Account, error)
+	AggregateBalances(ctx context.Context) ([]*entity.BalanceAggregate, error)
+	FreezeDormant(ctx context.Context, inactiveSince time.Time) (int64, error)
+	GetPendingReactivations(ctx context.Context, limit, offset int) ([]*entity.Account, error)
+	CountPendingReactivations(ctx context.Context) (int64, error)
+	// Search returns accounts matching filter, closed ones included, newest
+	// first.
+	Search(ctx context.Context, filter *entity.AccountSearchFilter, limit, offset int) ([]*entity.Account, error)
+	CountSearch(ctx context.Context, filter *entity.AccountSearchFilter) (int64, error)
+	// ExportOpenedBefore calls fn for every account, closed ones included,
+	// opened before the given time.
+	ExportOpenedBefore(ctx context.Context, before time.Time, fn func(*entity.Account) error) error
 }
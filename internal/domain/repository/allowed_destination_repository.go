@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+type AllowedDestinationRepository interface {
+	Add(ctx context.Context, accountID, destinationAccountID uuid.UUID) error
+	Remove(ctx context.Context, accountID, destinationAccountID uuid.UUID) (bool, error)
+	GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]*entity.AllowedDestination, error)
+	// IsAllowed reports whether accountID may pay destinationAccountID. An
+	// account with an empty allowlist may pay anyone.
+	IsAllowed(ctx context.Context, accountID, destinationAccountID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+type BalanceAlertRepository interface {
+	Create(ctx context.Context, alert *entity.BalanceAlert) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.BalanceAlert, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.BalanceAlert, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	Update(ctx context.Context, alert *entity.BalanceAlert) error
+	Delete(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	// GetActiveByAccountIDs returns the active alerts on any of the accounts.
+	GetActiveByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]*entity.BalanceAlert, error)
+	// MarkTriggered records that the alert fired at the given time and
+	// deactivates it if it is one-shot.
+	MarkTriggered(ctx context.Context, id uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, event *entity.OutboxEvent) error
+	GetUnpublishedForUpdate(ctx context.Context, limit int) ([]*entity.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
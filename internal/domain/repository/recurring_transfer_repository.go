@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+type RecurringTransferRepository interface {
+	Create(ctx context.Context, recurring *entity.RecurringTransfer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.RecurringTransfer, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.RecurringTransfer, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// GetDueForUpdate locks up to limit active orders due at now, skipping
+	// rows another instance has already locked.
+	GetDueForUpdate(ctx context.Context, now time.Time, limit int) ([]*entity.RecurringTransfer, error)
+	UpdateSchedule(ctx context.Context, recurring *entity.RecurringTransfer) error
+	Cancel(ctx context.Context, id, userID uuid.UUID) (bool, error)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/yourusername/gobank/internal/domain/entity"
 )
 
@@ -12,24 +13,61 @@ type TransactionRepository interface {
 	Create(ctx context.Context, transaction *entity.Transaction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
 	GetByAccountID(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*entity.Transaction, error)
+	GetByAccountIDAfter(ctx context.Context, accountID uuid.UUID, filter *entity.TransactionFilter, cursor time.Time, cursorID uuid.UUID, limit int) ([]*entity.Transaction, error)
 	GetByAccountIDAndDateRange(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time, limit, offset int) ([]*entity.Transaction, error)
 	CountByAccountID(ctx context.Context, accountID uuid.UUID) (int64, error)
+	CountByAccountIDAndDateRange(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) (int64, error)
+	GetByAccountIDFiltered(ctx context.Context, accountID uuid.UUID, filter *entity.TransactionFilter, limit, offset int) ([]*entity.Transaction, error)
+	CountByAccountIDFiltered(ctx context.Context, accountID uuid.UUID, filter *entity.TransactionFilter) (int64, error)
+	// SpendingByCategory totals the account's debits between start and end,
+	// grouped by category.
+	SpendingByCategory(ctx context.Context, accountID uuid.UUID, start, end time.Time) ([]*entity.CategorySpending, error)
+	// GetBalanceBefore returns the account's balance just before the given
+	// time, or zero if it had no transactions by then.
+	GetBalanceBefore(ctx context.Context, accountID uuid.UUID, before time.Time) (decimal.Decimal, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter, limit, offset int) ([]*entity.AccountTransaction, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID, filter *entity.TransactionFilter) (int64, error)
 }
 
 type TransferRepository interface {
 	Create(ctx context.Context, transfer *entity.Transfer) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.Transfer, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*entity.Transfer, error)
 	GetByIdempotencyKey(ctx context.Context, key string) (*entity.Transfer, error)
+	GetByReferenceNumber(ctx context.Context, referenceNumber string) (*entity.Transfer, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.Transfer, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// GetByAccountID lists transfers into, out of or either side of the
+	// account, newest first.
+	GetByAccountID(ctx context.Context, accountID uuid.UUID, direction entity.AccountTransferDirection, limit, offset int) ([]*entity.Transfer, error)
+	CountByAccountID(ctx context.Context, accountID uuid.UUID, direction entity.AccountTransferDirection) (int64, error)
+	ExportByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(*entity.TransferExportRow) error) error
+	ExportForCompliance(ctx context.Context, from, to time.Time, fn func(*entity.ComplianceTransferRow) error) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entity.TransferStatus, completedAt *time.Time) error
+	GetDueScheduled(ctx context.Context, now time.Time, limit int) ([]*entity.Transfer, error)
+	GetLastTransferTime(ctx context.Context, fromAccountID, toAccountID uuid.UUID) (*time.Time, error)
+	// SumOutboundSince totals the completed transfers out of accountID since
+	// the given time, excluding refunds and reversals.
+	SumOutboundSince(ctx context.Context, accountID uuid.UUID, since time.Time) (decimal.Decimal, error)
+	// SumPendingDebits totals, per source account, the scheduled transfers
+	// out of the given accounts that are due at now but have not run yet.
+	SumPendingDebits(ctx context.Context, accountIDs []uuid.UUID, now time.Time) (map[uuid.UUID]decimal.Decimal, error)
+	AddRefundedAmount(ctx context.Context, id uuid.UUID, amount decimal.Decimal) error
+	SetFee(ctx context.Context, id uuid.UUID, fee decimal.Decimal) error
+	ClearIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
 }
 
 type AuditLogRepository interface {
 	Create(ctx context.Context, log *entity.AuditLog) error
 	GetByEntityID(ctx context.Context, entityType string, entityID uuid.UUID, limit, offset int) ([]*entity.AuditLog, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.AuditLog, error)
+	GetByAction(ctx context.Context, action string, limit, offset int) ([]*entity.AuditLog, error)
+	CountByEntityID(ctx context.Context, entityType string, entityID uuid.UUID) (int64, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountByAction(ctx context.Context, action string) (int64, error)
 }
 
 type TransactionManager interface {
 	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	WithReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
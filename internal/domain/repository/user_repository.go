@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/gobank/internal/domain/entity"
@@ -14,12 +15,31 @@ type UserRepository interface {
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	// List returns users whose email or full name contains search, newest
+	// first. An empty search matches every user.
+	List(ctx context.Context, search string, limit, offset int) ([]*entity.User, error)
+	CountAll(ctx context.Context, search string) (int64, error)
 }
 
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *entity.RefreshToken) error
 	GetByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
-	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// ListActiveByUserID returns the user's unexpired refresh tokens, one
+	// per session, least recently used first.
+	ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.RefreshToken, error)
 	DeleteByTokenHash(ctx context.Context, tokenHash string) error
-	DeleteExpired(ctx context.Context) error
+	DeleteByFamilyID(ctx context.Context, familyID uuid.UUID) (int64, error)
+	MarkUsed(ctx context.Context, tokenHash string) (bool, error)
+	GetUsedByTokenHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+	Consume(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+	InvalidateByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entity.Webhook) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Webhook, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Webhook, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	// GetActiveByUserIDs returns the active webhooks of any of the users.
+	GetActiveByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]*entity.Webhook, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) (bool, error)
+}
+
+type WebhookDeliveryRepository interface {
+	// Create queues a delivery. A delivery of the same event to the same
+	// webhook that already exists is left as it is.
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// ClaimDue returns pending deliveries due at now and pushes their next
+	// attempt to leaseUntil, so no other dispatcher picks them up while they
+	// are being sent. A dispatcher that dies mid-send leaves them to be
+	// retried once the lease expires.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	// RecordAttempt stores the outcome of an attempt: the delivery's status,
+	// response status (nil if no response arrived), error and next attempt.
+	RecordAttempt(ctx context.Context, delivery *entity.WebhookDelivery) error
+	GetByWebhookID(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]*entity.WebhookDelivery, error)
+	CountByWebhookID(ctx context.Context, webhookID uuid.UUID) (int64, error)
+	// DeleteFinished removes succeeded and failed deliveries created before
+	// the given time.
+	DeleteFinished(ctx context.Context, before time.Time) (int64, error)
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/yourusername/gobank/internal/domain/entity"
 )
 
@@ -12,21 +15,181 @@ type UserService interface {
 	Login(ctx context.Context, input *entity.LoginInput) (*entity.AuthTokens, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*entity.AuthTokens, error)
 	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) (int64, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	Update(ctx context.Context, id uuid.UUID, input *entity.UpdateUserInput) (*entity.User, error)
+	Suspend(ctx context.Context, actorID, userID uuid.UUID, reason string) (*entity.User, error)
+	Reactivate(ctx context.Context, actorID, userID uuid.UUID) (*entity.User, error)
+	IsSuspended(ctx context.Context, userID uuid.UUID) (bool, error)
+	ForceLogout(ctx context.Context, actorID, userID uuid.UUID) (int64, error)
+	List(ctx context.Context, page, pageSize int, search string) ([]*entity.User, int64, error)
+	ChangeRole(ctx context.Context, actorID, userID uuid.UUID, role entity.UserRole) (*entity.User, error)
+	Delete(ctx context.Context, actorID, userID uuid.UUID) error
+	IsTokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error)
+	VerifyEmail(ctx context.Context, token string) (*entity.User, error)
+	ResendVerification(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, input *entity.ResetPasswordInput) error
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) (*entity.User, error)
+	CompleteTwoFactorLogin(ctx context.Context, input *entity.TwoFactorLoginInput) (*entity.AuthTokens, error)
 }
 
 type AccountService interface {
 	Create(ctx context.Context, userID uuid.UUID, input *entity.CreateAccountInput) (*entity.Account, error)
 	GetByID(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error)
+	UpdateStatus(ctx context.Context, userID, accountID uuid.UUID, status entity.AccountStatus) (*entity.Account, error)
+	UpdateSettings(ctx context.Context, userID, accountID uuid.UUID, input *entity.UpdateAccountSettingsInput) (*entity.Account, error)
+	UpdateLimits(ctx context.Context, userID, accountID uuid.UUID, input *entity.UpdateAccountLimitsInput) (*entity.Account, error)
+	Close(ctx context.Context, userID, accountID uuid.UUID) error
 	GetByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Account, int64, error)
-	GetTransactions(ctx context.Context, userID, accountID uuid.UUID, page, pageSize int) ([]*entity.Transaction, int64, error)
+	GetTransactions(ctx context.Context, userID, accountID uuid.UUID, filter *entity.TransactionFilter, page, pageSize int) ([]*entity.Transaction, int64, error)
+	GetAllTransactions(ctx context.Context, userID uuid.UUID, page, pageSize int, filter *entity.TransactionFilter) ([]*entity.AccountTransaction, int64, error)
+	GetTransactionsCursor(ctx context.Context, userID, accountID uuid.UUID, filter *entity.TransactionFilter, cursor string, pageSize int) ([]*entity.Transaction, string, error)
+	GetTransaction(ctx context.Context, userID, transactionID uuid.UUID) (*entity.TransactionDetail, error)
+	GetAccountTransaction(ctx context.Context, userID, accountID, transactionID uuid.UUID) (*entity.TransactionDetail, error)
+	GetStatement(ctx context.Context, userID, accountID uuid.UUID, start, end time.Time) (*entity.Statement, error)
+	SpendingSummary(ctx context.Context, userID, accountID uuid.UUID, start, end time.Time) (*entity.SpendingSummary, error)
+	StreamStatement(ctx context.Context, statement *entity.Statement, fn func(*entity.Transaction) error) error
+	ImportTransactions(ctx context.Context, adminID, accountID uuid.UUID, rows []*entity.TransactionImportRow, dryRun bool) (*entity.TransactionImportReport, error)
+	OwnsAccounts(ctx context.Context, userID uuid.UUID, accountIDs ...uuid.UUID) (bool, error)
+	RequestReactivation(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error)
+	ApproveReactivation(ctx context.Context, adminID, accountID uuid.UUID) (*entity.Account, error)
+	UpdateBalanceRules(ctx context.Context, adminID, accountID uuid.UUID, input *entity.UpdateBalanceRulesInput) (*entity.Account, error)
+	GetPendingReactivations(ctx context.Context, page, pageSize int) ([]*entity.Account, int64, error)
+	Search(ctx context.Context, filter *entity.AccountSearchFilter, page, pageSize int) ([]*entity.Account, int64, error)
+	ListAllowedDestinations(ctx context.Context, userID, accountID uuid.UUID) ([]*entity.AllowedDestination, error)
+	AddAllowedDestination(ctx context.Context, userID, accountID uuid.UUID, input *entity.AddAllowedDestinationInput) ([]*entity.AllowedDestination, error)
+	RemoveAllowedDestination(ctx context.Context, userID, accountID, destinationAccountID uuid.UUID) error
 }
 
 type TransferService interface {
 	Create(ctx context.Context, userID uuid.UUID, input *entity.CreateTransferInput) (*entity.Transfer, error)
+	CreateBatch(ctx context.Context, userID uuid.UUID, input *entity.CreateTransferBatchInput) []entity.TransferBatchResult
+	Move(ctx context.Context, userID, fromAccountID uuid.UUID, input *entity.MoveFundsInput) (*entity.Transfer, error)
 	GetByID(ctx context.Context, userID uuid.UUID, transferID uuid.UUID) (*entity.Transfer, error)
+	GetByReferenceNumber(ctx context.Context, userID uuid.UUID, referenceNumber string) (*entity.Transfer, error)
+	PartialRefund(ctx context.Context, userID, transferID uuid.UUID, amount decimal.Decimal) (*entity.Transfer, error)
+	Reverse(ctx context.Context, userID, transferID uuid.UUID, reason string) (*entity.Transfer, error)
+	Retry(ctx context.Context, userID, transferID uuid.UUID) (*entity.Transfer, error)
+	RunScheduled(ctx context.Context, now time.Time, limit int) (int, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Transfer, int64, error)
+	GetByAccountID(ctx context.Context, userID, accountID uuid.UUID, direction entity.AccountTransferDirection, page, pageSize int) ([]*entity.Transfer, int64, error)
+	Export(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(*entity.TransferExportRow) error) error
+}
+
+type RecurringTransferService interface {
+	Create(ctx context.Context, userID uuid.UUID, input *entity.CreateRecurringTransferInput) (*entity.RecurringTransfer, error)
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*entity.RecurringTransfer, error)
+	List(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.RecurringTransfer, int64, error)
+	Cancel(ctx context.Context, userID, id uuid.UUID) (*entity.RecurringTransfer, error)
+	RunDue(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+type BalanceAlertService interface {
+	Create(ctx context.Context, userID uuid.UUID, input *entity.CreateBalanceAlertInput) (*entity.BalanceAlert, error)
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*entity.BalanceAlert, error)
+	List(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.BalanceAlert, int64, error)
+	Update(ctx context.Context, userID, id uuid.UUID, input *entity.UpdateBalanceAlertInput) (*entity.BalanceAlert, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	BalanceAlertEvaluator
+}
+
+// BalanceAlertEvaluator fires balance alerts. Callers that change balances
+// pass every change made in their transaction, using its context.
+type BalanceAlertEvaluator interface {
+	Evaluate(txCtx context.Context, changes ...entity.BalanceChange) error
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, userID uuid.UUID, input *entity.CreateAPIKeyInput) (*entity.CreatedAPIKey, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*entity.APIKey, error)
+	Revoke(ctx context.Context, userID, keyID uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*entity.APIKey, *entity.User, error)
+}
+
+// WebhookService manages users' webhooks. As an EventPublisher it queues a
+// delivery of each event to every webhook subscribed to it.
+type WebhookService interface {
+	Create(ctx context.Context, userID uuid.UUID, input *entity.CreateWebhookInput) (*entity.CreatedWebhook, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*entity.Webhook, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, userID, id uuid.UUID, page, pageSize int) ([]*entity.WebhookDelivery, int64, error)
+	EventPublisher
+}
+
+type StatsService interface {
+	BalancesByCurrency(ctx context.Context) ([]*entity.BalanceAggregate, error)
+}
+
+type ComplianceService interface {
+	Enabled() bool
+	Export(ctx context.Context, actorID uuid.UUID, format entity.ComplianceExportFormat, from, to time.Time, w io.Writer) (*entity.ComplianceExportSummary, error)
+}
+
+type AuditService interface {
+	Record(
+		ctx context.Context,
+		userID *uuid.UUID,
+		action string,
+		entityType string,
+		entityID *uuid.UUID,
+		oldValues map[string]interface{},
+		newValues map[string]interface{},
+		ipAddress string,
+		userAgent string,
+	)
+	GetByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.AuditLog, int64, error)
+	GetByEntity(ctx context.Context, entityType string, entityID uuid.UUID, page, pageSize int) ([]*entity.AuditLog, int64, error)
+	GetByAction(ctx context.Context, action string, page, pageSize int) ([]*entity.AuditLog, int64, error)
+}
+
+// Mailer delivers email to users.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// MemoSanitizer inspects a transfer memo before it is stored. It may return a
+// rewritten memo, or an error if the memo must be rejected.
+type MemoSanitizer interface {
+	Sanitize(memo string) (string, error)
+}
+
+// FeeService prices transfers. The fee is charged to the sender in the
+// source account's currency, on top of the amount sent.
+type FeeService interface {
+	Calculate(accountType entity.AccountType, currency entity.Currency, amount decimal.Decimal) decimal.Decimal
+}
+
+// FXService returns the rate at which one unit of from converts to to.
+type FXService interface {
+	// Supports reports whether the deployment allows converting between
+	// the two currencies at all, regardless of rate availability.
+	Supports(from, to entity.Currency) bool
+	Rate(ctx context.Context, from, to entity.Currency) (decimal.Decimal, error)
+}
+
+// FXRateProvider quotes exchange rates from an external source. asOf is when
+// the rate was published, so callers can reject stale quotes.
+type FXRateProvider interface {
+	Quote(ctx context.Context, from, to string) (rate decimal.Decimal, asOf time.Time, err error)
+}
+
+// EventPublisher delivers outbox events. Delivery is at least once, so an
+// implementation may see the same event more than once.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *entity.OutboxEvent) error
+}
+
+type EventSubscription interface {
+	Events() <-chan *entity.OutboxEvent
+	Close()
+}
+
+// EventStreamService hands out live event streams. A stream's channel is
+// closed when the server disconnects it.
+type EventStreamService interface {
+	Subscribe(userID uuid.UUID) (EventSubscription, error)
 }
 
 type CacheService interface {
@@ -34,4 +197,13 @@ type CacheService interface {
 	Set(ctx context.Context, key string, value interface{}, ttlSeconds int) error
 	Delete(ctx context.Context, key string) error
 	Exists(ctx context.Context, key string) (bool, error)
+	// SetNX sets key only if it does not exist yet, and reports whether it
+	// did.
+	SetNX(ctx context.Context, key string, value interface{}, ttlSeconds int) (bool, error)
+	// GetDel returns the value at key and deletes it in one step, so only
+	// one of several concurrent callers gets it.
+	GetDel(ctx context.Context, key string) (string, error)
+	// Incr adds one to the counter at key and returns the new count. A
+	// counter expires ttlSeconds after it is created.
+	Incr(ctx context.Context, key string, ttlSeconds int) (int64, error)
 }
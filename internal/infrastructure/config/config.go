@@ -1,17 +1,40 @@
 package config
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	RateLimit RateLimitConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	RateLimit   RateLimitConfig
+	Cleanup     CleanupConfig
+	Maintenance MaintenanceConfig
+	Transfer    TransferConfig
+	FX          FXConfig
+	Account     AccountConfig
+	Outbox      OutboxConfig
+	Events      EventsConfig
+	Webhook     WebhookConfig
+	Compliance  ComplianceConfig
+	Tracing     TracingConfig
+	CORS        CORSConfig
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string      `mapstructure:"allowed_origins"`
+	AllowedMethods   []string      `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string      `mapstructure:"allowed_headers"`
+	AllowCredentials bool          `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age"`
 }
 
 type ServerConfig struct {
@@ -20,6 +43,13 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 	Environment     string        `mapstructure:"environment"`
+	MaxConcurrent   int           `mapstructure:"max_concurrent"`
+	LogValidation   bool          `mapstructure:"log_validation"`
+	LogBodies       bool          `mapstructure:"log_bodies"`
+	LogBodyMaxBytes int           `mapstructure:"log_body_max_bytes"`
+	JSONMaxDepth    int           `mapstructure:"json_max_depth"`
+	JSONMaxArrayLen int           `mapstructure:"json_max_array_length"`
+	MaxBodyBytes    int64         `mapstructure:"max_request_body_bytes"`
 }
 
 type DatabaseConfig struct {
@@ -35,22 +65,131 @@ type DatabaseConfig struct {
 }
 
 type RedisConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     string `mapstructure:"port"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
+	Host               string        `mapstructure:"host"`
+	Port               string        `mapstructure:"port"`
+	Password           string        `mapstructure:"password"`
+	DB                 int           `mapstructure:"db"`
+	OwnershipCacheTTL  time.Duration `mapstructure:"ownership_cache_ttl"`
+	StatsCacheTTL      time.Duration `mapstructure:"stats_cache_ttl"`
+	AccountCacheTTL    time.Duration `mapstructure:"account_cache_ttl"`
+	IdempotencyTTL     time.Duration `mapstructure:"idempotency_ttl"`
+	IdempotencyLockTTL time.Duration `mapstructure:"idempotency_lock_ttl"`
 }
 
 type JWTConfig struct {
+	Algorithm          string        `mapstructure:"algorithm"`
 	SecretKey          string        `mapstructure:"secret_key"`
+	PrivateKeyFile     string        `mapstructure:"private_key_file"`
+	KeyID              string        `mapstructure:"key_id"`
+	AdditionalKeys     string        `mapstructure:"additional_keys"`
 	AccessTokenExpiry  time.Duration `mapstructure:"access_token_expiry"`
 	RefreshTokenExpiry time.Duration `mapstructure:"refresh_token_expiry"`
 	Issuer             string        `mapstructure:"issuer"`
 }
 
+type AuthConfig struct {
+	RequireEmailVerification   bool          `mapstructure:"require_email_verification"`
+	EmailVerificationTTL       time.Duration `mapstructure:"email_verification_ttl"`
+	VerificationResendInterval time.Duration `mapstructure:"verification_resend_interval"`
+	PasswordResetTTL           time.Duration `mapstructure:"password_reset_ttl"`
+	PasswordResetInterval      time.Duration `mapstructure:"password_reset_interval"`
+	PasswordBcryptCost         int           `mapstructure:"password_bcrypt_cost"`
+	TOTPEncryptionKey          string        `mapstructure:"totp_encryption_key"`
+	TwoFactorChallengeTTL      time.Duration `mapstructure:"two_factor_challenge_ttl"`
+	MaxSessions                int           `mapstructure:"max_sessions"`
+	SessionLimitPolicy         string        `mapstructure:"session_limit_policy"`
+}
+
 type RateLimitConfig struct {
-	RequestsPerMinute int `mapstructure:"requests_per_minute"`
-	BurstSize         int `mapstructure:"burst_size"`
+	RequestsPerMinute                 int           `mapstructure:"requests_per_minute"`
+	BurstSize                         int           `mapstructure:"burst_size"`
+	Algorithm                         string        `mapstructure:"algorithm"`
+	AuthRequests                      int           `mapstructure:"auth_requests"`
+	AuthWindow                        time.Duration `mapstructure:"auth_window"`
+	InternalTransferBypass            bool          `mapstructure:"internal_transfer_bypass"`
+	InternalTransferRequestsPerMinute int           `mapstructure:"internal_transfer_requests_per_minute"`
+	ServiceTokenSecret                string        `mapstructure:"service_token_secret"`
+	ServiceTokenMaxAge                time.Duration `mapstructure:"service_token_max_age"`
+}
+
+type CleanupConfig struct {
+	Interval                    time.Duration `mapstructure:"interval"`
+	RefreshTokenRetention       time.Duration `mapstructure:"refresh_token_retention"`
+	PasswordResetTokenRetention time.Duration `mapstructure:"password_reset_token_retention"`
+	IdempotencyKeyRetention     time.Duration `mapstructure:"idempotency_key_retention"`
+	OutboxRetention             time.Duration `mapstructure:"outbox_retention"`
+	WebhookRetention            time.Duration `mapstructure:"webhook_retention"`
+}
+
+type MaintenanceConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	AllowReads bool          `mapstructure:"allow_reads"`
+	RetryAfter time.Duration `mapstructure:"retry_after"`
+}
+
+type TransferConfig struct {
+	PairCooldown       time.Duration `mapstructure:"pair_cooldown"`
+	FXRoundingMode     string        `mapstructure:"fx_rounding_mode"`
+	Fees               string        `mapstructure:"fees"`
+	MemoMaxLength      int           `mapstructure:"memo_max_length"`
+	MemoFilterMode     string        `mapstructure:"memo_filter_mode"`
+	MemoBlocklist      []string      `mapstructure:"memo_blocklist"`
+	SchedulerInterval  time.Duration `mapstructure:"scheduler_interval"`
+	SchedulerBatchSize int           `mapstructure:"scheduler_batch_size"`
+	TxMaxRetries       int           `mapstructure:"tx_max_retries"`
+}
+
+type FXConfig struct {
+	Provider        string        `mapstructure:"provider"`
+	ProviderURL     string        `mapstructure:"provider_url"`
+	ProviderTimeout time.Duration `mapstructure:"provider_timeout"`
+	StaticRates     string        `mapstructure:"static_rates"`
+	RateTTL         time.Duration `mapstructure:"rate_ttl"`
+	AllowedPairs    string        `mapstructure:"allowed_pairs"`
+}
+
+type AccountConfig struct {
+	DormancyPeriod    time.Duration `mapstructure:"dormancy_period"`
+	NumberPrefix      string        `mapstructure:"number_prefix"`
+	NumberLength      int           `mapstructure:"number_length"`
+	NumberCheckDigit  bool          `mapstructure:"number_check_digit"`
+	MinOpeningDeposit string        `mapstructure:"min_opening_deposit"`
+}
+
+type OutboxConfig struct {
+	RelayInterval time.Duration `mapstructure:"relay_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+}
+
+type EventsConfig struct {
+	BufferSize         int           `mapstructure:"buffer_size"`
+	SlowConsumerPolicy string        `mapstructure:"slow_consumer_policy"`
+	MaxStreamsPerUser  int           `mapstructure:"max_streams_per_user"`
+	HeartbeatInterval  time.Duration `mapstructure:"heartbeat_interval"`
+}
+
+type WebhookConfig struct {
+	DispatchInterval time.Duration `mapstructure:"dispatch_interval"`
+	BatchSize        int           `mapstructure:"batch_size"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	MaxAttempts      int           `mapstructure:"max_attempts"`
+	RetryBackoff     time.Duration `mapstructure:"retry_backoff"`
+	MaxRetryBackoff  time.Duration `mapstructure:"max_retry_backoff"`
+	MaxPerUser       int           `mapstructure:"max_per_user"`
+	AllowHTTP        bool          `mapstructure:"allow_http"`
+	// AllowPrivateTargets lets webhooks point at loopback and private
+	// addresses. Leave it off anywhere users can register webhooks.
+	AllowPrivateTargets bool `mapstructure:"allow_private_targets"`
+}
+
+type ComplianceConfig struct {
+	ExportSigningKey string `mapstructure:"export_signing_key"`
+}
+
+type TracingConfig struct {
+	Endpoint    string  `mapstructure:"endpoint"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
 }
 
 func Load() (*Config, error) {
@@ -77,6 +216,13 @@ func Load() (*Config, error) {
 			WriteTimeout:    viper.GetDuration("SERVER_WRITE_TIMEOUT"),
 			ShutdownTimeout: viper.GetDuration("SERVER_SHUTDOWN_TIMEOUT"),
 			Environment:     viper.GetString("ENVIRONMENT"),
+			MaxConcurrent:   viper.GetInt("SERVER_MAX_CONCURRENT_REQUESTS"),
+			LogValidation:   viper.GetBool("LOG_VALIDATION_FAILURES"),
+			LogBodies:       viper.GetBool("LOG_HTTP_BODIES"),
+			LogBodyMaxBytes: viper.GetInt("LOG_HTTP_BODY_MAX_BYTES"),
+			JSONMaxDepth:    viper.GetInt("SERVER_JSON_MAX_DEPTH"),
+			JSONMaxArrayLen: viper.GetInt("SERVER_JSON_MAX_ARRAY_LENGTH"),
+			MaxBodyBytes:    viper.GetInt64("MAX_REQUEST_BODY_BYTES"),
 		},
 		Database: DatabaseConfig{
 			Host:            viper.GetString("DB_HOST"),
@@ -90,26 +236,142 @@ func Load() (*Config, error) {
 			ConnMaxLifetime: viper.GetDuration("DB_CONN_MAX_LIFETIME"),
 		},
 		Redis: RedisConfig{
-			Host:     viper.GetString("REDIS_HOST"),
-			Port:     viper.GetString("REDIS_PORT"),
-			Password: viper.GetString("REDIS_PASSWORD"),
-			DB:       viper.GetInt("REDIS_DB"),
+			Host:               viper.GetString("REDIS_HOST"),
+			Port:               viper.GetString("REDIS_PORT"),
+			Password:           viper.GetString("REDIS_PASSWORD"),
+			DB:                 viper.GetInt("REDIS_DB"),
+			OwnershipCacheTTL:  viper.GetDuration("REDIS_OWNERSHIP_CACHE_TTL"),
+			StatsCacheTTL:      viper.GetDuration("REDIS_STATS_CACHE_TTL"),
+			AccountCacheTTL:    viper.GetDuration("REDIS_ACCOUNT_CACHE_TTL"),
+			IdempotencyTTL:     viper.GetDuration("REDIS_IDEMPOTENCY_TTL"),
+			IdempotencyLockTTL: viper.GetDuration("REDIS_IDEMPOTENCY_LOCK_TTL"),
 		},
 		JWT: JWTConfig{
+			Algorithm:          viper.GetString("JWT_ALGORITHM"),
 			SecretKey:          viper.GetString("JWT_SECRET_KEY"),
+			PrivateKeyFile:     viper.GetString("JWT_PRIVATE_KEY_FILE"),
+			KeyID:              viper.GetString("JWT_KEY_ID"),
+			AdditionalKeys:     viper.GetString("JWT_ADDITIONAL_KEYS"),
 			AccessTokenExpiry:  viper.GetDuration("JWT_ACCESS_TOKEN_EXPIRY"),
 			RefreshTokenExpiry: viper.GetDuration("JWT_REFRESH_TOKEN_EXPIRY"),
 			Issuer:             viper.GetString("JWT_ISSUER"),
 		},
+		Auth: AuthConfig{
+			RequireEmailVerification:   viper.GetBool("REQUIRE_EMAIL_VERIFICATION"),
+			EmailVerificationTTL:       viper.GetDuration("EMAIL_VERIFICATION_TTL"),
+			VerificationResendInterval: viper.GetDuration("EMAIL_VERIFICATION_RESEND_INTERVAL"),
+			PasswordResetTTL:           viper.GetDuration("PASSWORD_RESET_TTL"),
+			PasswordResetInterval:      viper.GetDuration("PASSWORD_RESET_INTERVAL"),
+			PasswordBcryptCost:         viper.GetInt("PASSWORD_BCRYPT_COST"),
+			TOTPEncryptionKey:          viper.GetString("TOTP_ENCRYPTION_KEY"),
+			TwoFactorChallengeTTL:      viper.GetDuration("TWO_FACTOR_CHALLENGE_TTL"),
+			MaxSessions:                viper.GetInt("AUTH_MAX_SESSIONS"),
+			SessionLimitPolicy:         viper.GetString("AUTH_SESSION_LIMIT_POLICY"),
+		},
 		RateLimit: RateLimitConfig{
-			RequestsPerMinute: viper.GetInt("RATE_LIMIT_REQUESTS_PER_MINUTE"),
-			BurstSize:         viper.GetInt("RATE_LIMIT_BURST_SIZE"),
+			RequestsPerMinute:                 viper.GetInt("RATE_LIMIT_REQUESTS_PER_MINUTE"),
+			BurstSize:                         viper.GetInt("RATE_LIMIT_BURST_SIZE"),
+			Algorithm:                         viper.GetString("RATE_LIMIT_ALGORITHM"),
+			AuthRequests:                      viper.GetInt("RATE_LIMIT_AUTH_REQUESTS"),
+			AuthWindow:                        viper.GetDuration("RATE_LIMIT_AUTH_WINDOW"),
+			InternalTransferBypass:            viper.GetBool("RATE_LIMIT_INTERNAL_TRANSFER_BYPASS"),
+			InternalTransferRequestsPerMinute: viper.GetInt("RATE_LIMIT_INTERNAL_TRANSFER_REQUESTS_PER_MINUTE"),
+			ServiceTokenSecret:                viper.GetString("RATE_LIMIT_SERVICE_TOKEN_SECRET"),
+			ServiceTokenMaxAge:                viper.GetDuration("RATE_LIMIT_SERVICE_TOKEN_MAX_AGE"),
+		},
+		Cleanup: CleanupConfig{
+			Interval:                    viper.GetDuration("CLEANUP_INTERVAL"),
+			RefreshTokenRetention:       viper.GetDuration("CLEANUP_REFRESH_TOKEN_RETENTION"),
+			PasswordResetTokenRetention: viper.GetDuration("CLEANUP_PASSWORD_RESET_TOKEN_RETENTION"),
+			IdempotencyKeyRetention:     viper.GetDuration("CLEANUP_IDEMPOTENCY_KEY_RETENTION"),
+			OutboxRetention:             viper.GetDuration("CLEANUP_OUTBOX_RETENTION"),
+			WebhookRetention:            viper.GetDuration("CLEANUP_WEBHOOK_RETENTION"),
+		},
+		Maintenance: MaintenanceConfig{
+			Enabled:    viper.GetBool("MAINTENANCE_ENABLED"),
+			AllowReads: viper.GetBool("MAINTENANCE_ALLOW_READS"),
+			RetryAfter: viper.GetDuration("MAINTENANCE_RETRY_AFTER"),
+		},
+		Transfer: TransferConfig{
+			PairCooldown:       viper.GetDuration("TRANSFER_PAIR_COOLDOWN"),
+			FXRoundingMode:     viper.GetString("TRANSFER_FX_ROUNDING_MODE"),
+			Fees:               viper.GetString("TRANSFER_FEES"),
+			MemoMaxLength:      viper.GetInt("TRANSFER_MEMO_MAX_LENGTH"),
+			MemoFilterMode:     viper.GetString("TRANSFER_MEMO_FILTER_MODE"),
+			MemoBlocklist:      strings.Split(viper.GetString("TRANSFER_MEMO_BLOCKLIST"), ","),
+			SchedulerInterval:  viper.GetDuration("TRANSFER_SCHEDULER_INTERVAL"),
+			SchedulerBatchSize: viper.GetInt("TRANSFER_SCHEDULER_BATCH_SIZE"),
+			TxMaxRetries:       viper.GetInt("TRANSFER_TX_MAX_RETRIES"),
+		},
+		FX: FXConfig{
+			Provider:        viper.GetString("FX_PROVIDER"),
+			ProviderURL:     viper.GetString("FX_PROVIDER_URL"),
+			ProviderTimeout: viper.GetDuration("FX_PROVIDER_TIMEOUT"),
+			StaticRates:     viper.GetString("FX_STATIC_RATES"),
+			RateTTL:         viper.GetDuration("FX_RATE_TTL"),
+			AllowedPairs:    viper.GetString("FX_ALLOWED_PAIRS"),
+		},
+		Account: AccountConfig{
+			DormancyPeriod:    viper.GetDuration("ACCOUNT_DORMANCY_PERIOD"),
+			NumberPrefix:      viper.GetString("ACCOUNT_NUMBER_PREFIX"),
+			NumberLength:      viper.GetInt("ACCOUNT_NUMBER_LENGTH"),
+			NumberCheckDigit:  viper.GetBool("ACCOUNT_NUMBER_CHECK_DIGIT"),
+			MinOpeningDeposit: viper.GetString("ACCOUNT_MIN_OPENING_DEPOSIT"),
+		},
+		Outbox: OutboxConfig{
+			RelayInterval: viper.GetDuration("OUTBOX_RELAY_INTERVAL"),
+			BatchSize:     viper.GetInt("OUTBOX_BATCH_SIZE"),
+		},
+		Events: EventsConfig{
+			BufferSize:         viper.GetInt("EVENTS_BUFFER_SIZE"),
+			SlowConsumerPolicy: viper.GetString("EVENTS_SLOW_CONSUMER_POLICY"),
+			MaxStreamsPerUser:  viper.GetInt("EVENTS_MAX_STREAMS_PER_USER"),
+			HeartbeatInterval:  viper.GetDuration("EVENTS_HEARTBEAT_INTERVAL"),
+		},
+		Webhook: WebhookConfig{
+			DispatchInterval:    viper.GetDuration("WEBHOOK_DISPATCH_INTERVAL"),
+			BatchSize:           viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			Timeout:             viper.GetDuration("WEBHOOK_TIMEOUT"),
+			MaxAttempts:         viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			RetryBackoff:        viper.GetDuration("WEBHOOK_RETRY_BACKOFF"),
+			MaxRetryBackoff:     viper.GetDuration("WEBHOOK_MAX_RETRY_BACKOFF"),
+			MaxPerUser:          viper.GetInt("WEBHOOK_MAX_PER_USER"),
+			AllowHTTP:           viper.GetBool("WEBHOOK_ALLOW_HTTP"),
+			AllowPrivateTargets: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_TARGETS"),
+		},
+		Compliance: ComplianceConfig{
+			ExportSigningKey: viper.GetString("COMPLIANCE_EXPORT_SIGNING_KEY"),
+		},
+		Tracing: TracingConfig{
+			Endpoint:    viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+			ServiceName: viper.GetString("OTEL_SERVICE_NAME"),
+			SampleRatio: viper.GetFloat64("OTEL_TRACES_SAMPLE_RATIO"),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   splitList(viper.GetString("CORS_ALLOWED_ORIGINS")),
+			AllowedMethods:   splitList(viper.GetString("CORS_ALLOWED_METHODS")),
+			AllowedHeaders:   splitList(viper.GetString("CORS_ALLOWED_HEADERS")),
+			AllowCredentials: viper.GetBool("CORS_ALLOW_CREDENTIALS"),
+			MaxAge:           viper.GetDuration("CORS_MAX_AGE"),
 		},
 	}
 
+	if err := config.CORS.validate(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// validate refuses a wildcard origin with credentials, which would let any
+// site make authenticated requests on a user's behalf.
+func (c *CORSConfig) validate() error {
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		return errors.New("CORS_ALLOWED_ORIGINS cannot contain * while CORS_ALLOW_CREDENTIALS is true")
+	}
+	return nil
+}
+
 func setDefaults() {
 	// Server defaults
 	viper.SetDefault("SERVER_PORT", "8080")
@@ -117,6 +379,13 @@ func setDefaults() {
 	viper.SetDefault("SERVER_WRITE_TIMEOUT", "15s")
 	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", "30s")
 	viper.SetDefault("ENVIRONMENT", "development")
+	viper.SetDefault("SERVER_MAX_CONCURRENT_REQUESTS", 200)
+	viper.SetDefault("LOG_VALIDATION_FAILURES", false)
+	viper.SetDefault("LOG_HTTP_BODIES", false)
+	viper.SetDefault("LOG_HTTP_BODY_MAX_BYTES", 4096)
+	viper.SetDefault("SERVER_JSON_MAX_DEPTH", 32)
+	viper.SetDefault("SERVER_JSON_MAX_ARRAY_LENGTH", 1000)
+	viper.SetDefault("MAX_REQUEST_BODY_BYTES", 1<<20)
 
 	// Database defaults
 	viper.SetDefault("DB_HOST", "localhost")
@@ -134,16 +403,130 @@ func setDefaults() {
 	viper.SetDefault("REDIS_PORT", "6379")
 	viper.SetDefault("REDIS_PASSWORD", "")
 	viper.SetDefault("REDIS_DB", 0)
+	viper.SetDefault("REDIS_OWNERSHIP_CACHE_TTL", "60s")
+	viper.SetDefault("REDIS_STATS_CACHE_TTL", "30s")
+	viper.SetDefault("REDIS_ACCOUNT_CACHE_TTL", "5s")
+	viper.SetDefault("REDIS_IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("REDIS_IDEMPOTENCY_LOCK_TTL", "30s")
 
 	// JWT defaults
+	viper.SetDefault("JWT_ALGORITHM", "HS256")
 	viper.SetDefault("JWT_SECRET_KEY", "your-super-secret-key-change-in-production")
+	viper.SetDefault("JWT_PRIVATE_KEY_FILE", "")
+	viper.SetDefault("JWT_KEY_ID", "default")
+	viper.SetDefault("JWT_ADDITIONAL_KEYS", "")
 	viper.SetDefault("JWT_ACCESS_TOKEN_EXPIRY", "15m")
 	viper.SetDefault("JWT_REFRESH_TOKEN_EXPIRY", "7d")
 	viper.SetDefault("JWT_ISSUER", "gobank")
 
+	// Auth defaults
+	viper.SetDefault("REQUIRE_EMAIL_VERIFICATION", false)
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", "24h")
+	viper.SetDefault("EMAIL_VERIFICATION_RESEND_INTERVAL", "60s")
+	viper.SetDefault("PASSWORD_RESET_TTL", "30m")
+	viper.SetDefault("PASSWORD_RESET_INTERVAL", "60s")
+	viper.SetDefault("PASSWORD_BCRYPT_COST", 12)
+	viper.SetDefault("TOTP_ENCRYPTION_KEY", "your-totp-encryption-key-change-in-production")
+	viper.SetDefault("TWO_FACTOR_CHALLENGE_TTL", "5m")
+	viper.SetDefault("AUTH_MAX_SESSIONS", 0)
+	viper.SetDefault("AUTH_SESSION_LIMIT_POLICY", "block")
+
 	// Rate limit defaults
 	viper.SetDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 60)
 	viper.SetDefault("RATE_LIMIT_BURST_SIZE", 10)
+	viper.SetDefault("RATE_LIMIT_ALGORITHM", "sliding_window")
+	viper.SetDefault("RATE_LIMIT_AUTH_REQUESTS", 5)
+	viper.SetDefault("RATE_LIMIT_AUTH_WINDOW", "1m")
+	viper.SetDefault("RATE_LIMIT_INTERNAL_TRANSFER_BYPASS", true)
+	viper.SetDefault("RATE_LIMIT_INTERNAL_TRANSFER_REQUESTS_PER_MINUTE", 300)
+	viper.SetDefault("RATE_LIMIT_SERVICE_TOKEN_SECRET", "")
+	viper.SetDefault("RATE_LIMIT_SERVICE_TOKEN_MAX_AGE", "5m")
+
+	// Cleanup defaults
+	viper.SetDefault("CLEANUP_INTERVAL", "1h")
+	viper.SetDefault("CLEANUP_REFRESH_TOKEN_RETENTION", "0s")
+	viper.SetDefault("CLEANUP_PASSWORD_RESET_TOKEN_RETENTION", "0s")
+	viper.SetDefault("CLEANUP_IDEMPOTENCY_KEY_RETENTION", "72h")
+	viper.SetDefault("CLEANUP_OUTBOX_RETENTION", "168h")
+	viper.SetDefault("CLEANUP_WEBHOOK_RETENTION", "720h")
+
+	// Maintenance defaults
+	viper.SetDefault("MAINTENANCE_ENABLED", false)
+	viper.SetDefault("MAINTENANCE_ALLOW_READS", true)
+	viper.SetDefault("MAINTENANCE_RETRY_AFTER", "5m")
+
+	// Transfer defaults
+	viper.SetDefault("TRANSFER_PAIR_COOLDOWN", "0s")
+	viper.SetDefault("TRANSFER_FX_ROUNDING_MODE", "half_even")
+	viper.SetDefault("TRANSFER_FEES", "")
+	viper.SetDefault("TRANSFER_MEMO_MAX_LENGTH", 255)
+	viper.SetDefault("TRANSFER_MEMO_FILTER_MODE", "off")
+	viper.SetDefault("TRANSFER_MEMO_BLOCKLIST", "")
+	viper.SetDefault("TRANSFER_SCHEDULER_INTERVAL", "30s")
+	viper.SetDefault("TRANSFER_SCHEDULER_BATCH_SIZE", 100)
+	viper.SetDefault("TRANSFER_TX_MAX_RETRIES", 3)
+
+	// FX defaults
+	viper.SetDefault("FX_PROVIDER", "static")
+	viper.SetDefault("FX_PROVIDER_URL", "")
+	viper.SetDefault("FX_PROVIDER_TIMEOUT", "5s")
+	viper.SetDefault("FX_STATIC_RATES", "")
+	viper.SetDefault("FX_RATE_TTL", "60s")
+	viper.SetDefault("FX_ALLOWED_PAIRS", "")
+
+	// Account defaults
+	viper.SetDefault("ACCOUNT_DORMANCY_PERIOD", "0s")
+	viper.SetDefault("ACCOUNT_NUMBER_PREFIX", "")
+	viper.SetDefault("ACCOUNT_NUMBER_LENGTH", 10)
+	viper.SetDefault("ACCOUNT_NUMBER_CHECK_DIGIT", false)
+	viper.SetDefault("ACCOUNT_MIN_OPENING_DEPOSIT", "")
+
+	// Outbox defaults
+	viper.SetDefault("OUTBOX_RELAY_INTERVAL", "5s")
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+
+	// Event stream defaults
+	viper.SetDefault("EVENTS_BUFFER_SIZE", 64)
+	viper.SetDefault("EVENTS_SLOW_CONSUMER_POLICY", "drop_oldest")
+	viper.SetDefault("EVENTS_MAX_STREAMS_PER_USER", 5)
+	viper.SetDefault("EVENTS_HEARTBEAT_INTERVAL", "15s")
+
+	// Webhook defaults
+	viper.SetDefault("WEBHOOK_DISPATCH_INTERVAL", "5s")
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 20)
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_RETRY_BACKOFF", "30s")
+	viper.SetDefault("WEBHOOK_MAX_RETRY_BACKOFF", "1h")
+	viper.SetDefault("WEBHOOK_MAX_PER_USER", 5)
+	viper.SetDefault("WEBHOOK_ALLOW_HTTP", false)
+	viper.SetDefault("WEBHOOK_ALLOW_PRIVATE_TARGETS", false)
+
+	// Compliance defaults
+	viper.SetDefault("COMPLIANCE_EXPORT_SIGNING_KEY", "")
+
+	// Tracing defaults
+	viper.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	viper.SetDefault("OTEL_SERVICE_NAME", "gobank")
+	viper.SetDefault("OTEL_TRACES_SAMPLE_RATIO", 1.0)
+
+	// CORS defaults suit a frontend dev server on localhost
+	viper.SetDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000")
+	viper.SetDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
+	viper.SetDefault("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization,X-Request-ID,X-Idempotency-Key")
+	viper.SetDefault("CORS_ALLOW_CREDENTIALS", true)
+	viper.SetDefault("CORS_MAX_AGE", "12h")
+}
+
+// splitList splits a comma-separated setting, dropping blank entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func (d *DatabaseConfig) DSN() string {
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ExpectedMigrationVersion is the schema version this build was written
+// against. It is a string so it can be set at build time with
+// -ldflags "-X github.com/yourusername/gobank/internal/infrastructure/database.ExpectedMigrationVersion=N".
+var ExpectedMigrationVersion = "27"
+
+// MigrationVersion returns the version golang-migrate last recorded in
+// schema_migrations and whether that migration was left dirty. A database
+// that has never been migrated reports version 0.
+func (db *PostgresDB) MigrationVersion(ctx context.Context) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := db.Pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	return version, dirty, nil
+}
@@ -2,10 +2,18 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/codes"
 )
 
 type PostgresDB struct {
@@ -31,6 +39,17 @@ func NewPostgresDB(ctx context.Context, cfg *config.DatabaseConfig) (*PostgresDB
 	}
 
 	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+	poolConfig.ConnConfig.Tracer = tracing.PgxTracer{}
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		// Scan timestamptz columns as UTC rather than the process-local zone.
+		conn.TypeMap().RegisterType(&pgtype.Type{
+			Name:  "timestamptz",
+			OID:   pgtype.TimestamptzOID,
+			Codec: &pgtype.TimestamptzCodec{ScanLocation: time.UTC},
+		})
+		return nil
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -54,15 +73,103 @@ func (db *PostgresDB) Ping(ctx context.Context) error {
 	return db.Pool.Ping(ctx)
 }
 
-type TxKey struct{}
+// txKey is the context key under which WithTransaction stores its pgx.Tx.
+// It is unexported so the transaction can only be put there by this package
+// and read back through TxFromContext or Querier.
+type txKey struct{}
+
+// Queryer is the query API shared by pgxpool.Pool and pgx.Tx.
+type Queryer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// TxFromContext returns the transaction ctx carries, if it carries one.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// Querier returns the transaction carried by ctx, if any, and pool
+// otherwise, so statements made inside a transaction join it.
+func Querier(ctx context.Context, pool *pgxpool.Pool) Queryer {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return pool
+}
 
 func (db *PostgresDB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	tx, err := db.Pool.Begin(ctx)
+	return db.withTx(ctx, pgx.TxOptions{}, fn)
+}
+
+// retryBaseDelay is the wait before the first retry of WithTransactionRetry;
+// each further retry waits twice as long, plus jitter.
+const retryBaseDelay = 10 * time.Millisecond
+
+// WithTransactionRetry is WithTransaction, run again up to maxRetries times
+// if Postgres aborts it with a serialization failure or deadlock. fn must be
+// safe to run more than once: it should read whatever it depends on afresh
+// inside the transaction, as its earlier attempts were rolled back.
+func (db *PostgresDB) WithTransactionRetry(ctx context.Context, maxRetries int, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		err := db.WithTransaction(ctx, fn)
+		if err == nil || attempt >= maxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		delay := retryBaseDelay << attempt
+		delay += time.Duration(rand.Int63n(int64(delay)))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// IsRetryable reports whether err is a serialization failure (40001) or
+// deadlock (40P01), after which the whole transaction can be retried.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// WithReadOnlyTransaction runs fn in a read-only REPEATABLE READ
+// transaction, so every query it makes sees the same snapshot even if other
+// transactions commit meanwhile. Inside an existing transaction, fn just
+// joins it.
+func (db *PostgresDB) WithReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+	return db.withTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	}, fn)
+}
+
+func (db *PostgresDB) withTx(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context) error) (err error) {
+	// The span rides in ctx, so every statement fn runs nests under it.
+	ctx, span := tracing.Tracer().Start(ctx, "db.transaction")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	tx, err := db.Pool.BeginTx(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	ctx = context.WithValue(ctx, TxKey{}, tx)
+	ctx = context.WithValue(ctx, txKey{}, tx)
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/infrastructure/tracing"
 )
 
 type RedisDB struct {
@@ -19,6 +20,7 @@ func NewRedisDB(ctx context.Context, cfg *config.RedisConfig) (*RedisDB, error)
 		Password: cfg.Password,
 		DB:       cfg.DB,
 	})
+	client.AddHook(tracing.RedisHook{})
 
 	if err := client.Ping(ctx).Err(); err != nil {
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
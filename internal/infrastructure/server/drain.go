@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestTracker counts the requests being served, so shutdown can wait for
+// them to finish and report how many it drained.
+type requestTracker struct {
+	wg       sync.WaitGroup
+	inFlight atomic.Int64
+}
+
+func (t *requestTracker) track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t.wg.Add(1)
+		t.inFlight.Add(1)
+		defer func() {
+			t.inFlight.Add(-1)
+			t.wg.Done()
+		}()
+
+		c.Next()
+	}
+}
+
+func (t *requestTracker) count() int64 {
+	return t.inFlight.Load()
+}
+
+// wait blocks until no request is in flight or ctx is done.
+func (t *requestTracker) wait(ctx context.Context) error {
+	return waitFor(ctx, t.wg.Wait)
+}
+
+// waitFor runs fn and waits for it to return, or for ctx to be done.
+func waitFor(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -13,33 +13,92 @@ import (
 	"github.com/yourusername/gobank/internal/adapter/handler"
 	"github.com/yourusername/gobank/internal/adapter/middleware"
 	"github.com/yourusername/gobank/internal/adapter/repository/redis"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
 	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
 	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/jsonlimit"
 	"github.com/yourusername/gobank/internal/pkg/token"
 )
 
 type Server struct {
-	router          *gin.Engine
-	httpServer      *http.Server
-	config          *config.Config
-	logger          *logger.Logger
-	userHandler     *handler.UserHandler
-	accountHandler  *handler.AccountHandler
-	transferHandler *handler.TransferHandler
-	healthHandler   *handler.HealthHandler
-	jwtManager      token.JWTManager
-	rateLimiter     *redis.RateLimiter
+	router             *gin.Engine
+	httpServer         *http.Server
+	config             *config.Config
+	logger             *logger.Logger
+	userHandler        *handler.UserHandler
+	accountHandler     *handler.AccountHandler
+	transferHandler    *handler.TransferHandler
+	recurringHandler   *handler.RecurringTransferHandler
+	alertHandler       *handler.BalanceAlertHandler
+	transactionHandler *handler.TransactionHandler
+	adminHandler       *handler.AdminHandler
+	adminUserHandler   *handler.AdminUserHandler
+	apiKeyHandler      *handler.APIKeyHandler
+	webhookHandler     *handler.WebhookHandler
+	auditHandler       *handler.AuditHandler
+	complianceHandler  *handler.ComplianceHandler
+	rateLimitHandler   *handler.RateLimitHandler
+	eventHandler       *handler.EventHandler
+	healthHandler      *handler.HealthHandler
+	jwksHandler        *handler.JWKSHandler
+	jwtManager         token.JWTManager
+	serviceTokens      *token.ServiceTokenManager
+	rateLimiter        *redis.RateLimiter
+	internalLimiter    *redis.RateLimiter
+	maintenance        *redis.MaintenanceMode
+	idempotency        *redis.IdempotencyStore
+	accountService     service.AccountService
+	userService        service.UserService
+	apiKeyService      service.APIKeyService
+	txManager          repository.TransactionManager
+	requests           requestTracker
+	stopWorkers        func()
+	db                 *database.PostgresDB
+	redis              *database.RedisDB
 }
 
 type ServerDeps struct {
-	Config          *config.Config
-	Logger          *logger.Logger
-	UserHandler     *handler.UserHandler
-	AccountHandler  *handler.AccountHandler
-	TransferHandler *handler.TransferHandler
-	HealthHandler   *handler.HealthHandler
-	JWTManager      token.JWTManager
-	RateLimiter     *redis.RateLimiter
+	Config              *config.Config
+	Logger              *logger.Logger
+	UserHandler         *handler.UserHandler
+	AccountHandler      *handler.AccountHandler
+	TransferHandler     *handler.TransferHandler
+	RecurringHandler    *handler.RecurringTransferHandler
+	BalanceAlertHandler *handler.BalanceAlertHandler
+	TransactionHandler  *handler.TransactionHandler
+	AdminHandler        *handler.AdminHandler
+	AdminUserHandler    *handler.AdminUserHandler
+	APIKeyHandler       *handler.APIKeyHandler
+	WebhookHandler      *handler.WebhookHandler
+	AuditHandler        *handler.AuditHandler
+	ComplianceHandler   *handler.ComplianceHandler
+	RateLimitHandler    *handler.RateLimitHandler
+	EventHandler        *handler.EventHandler
+	HealthHandler       *handler.HealthHandler
+	JWKSHandler         *handler.JWKSHandler
+	JWTManager          token.JWTManager
+	ServiceTokens       *token.ServiceTokenManager
+	RateLimiter         *redis.RateLimiter
+	InternalLimiter     *redis.RateLimiter
+	Maintenance         *redis.MaintenanceMode
+	Idempotency         *redis.IdempotencyStore
+	AccountService      service.AccountService
+	UserService         service.UserService
+	APIKeyService       service.APIKeyService
+	TxManager           repository.TransactionManager
+	// ShutdownHooks run when graceful shutdown starts, e.g. to end
+	// long-lived streams that would otherwise hold it open.
+	ShutdownHooks []func()
+	// StopWorkers stops the background jobs and returns once they have
+	// exited. It runs after in-flight requests have drained.
+	StopWorkers func()
+	// DB and Redis are closed, in that order, once requests and workers
+	// are done with them.
+	DB    *database.PostgresDB
+	Redis *database.RedisDB
 }
 
 func NewServer(deps *ServerDeps) *Server {
@@ -50,15 +109,38 @@ func NewServer(deps *ServerDeps) *Server {
 	router := gin.New()
 
 	s := &Server{
-		router:          router,
-		config:          deps.Config,
-		logger:          deps.Logger,
-		userHandler:     deps.UserHandler,
-		accountHandler:  deps.AccountHandler,
-		transferHandler: deps.TransferHandler,
-		healthHandler:   deps.HealthHandler,
-		jwtManager:      deps.JWTManager,
-		rateLimiter:     deps.RateLimiter,
+		router:             router,
+		config:             deps.Config,
+		logger:             deps.Logger,
+		userHandler:        deps.UserHandler,
+		accountHandler:     deps.AccountHandler,
+		transferHandler:    deps.TransferHandler,
+		recurringHandler:   deps.RecurringHandler,
+		alertHandler:       deps.BalanceAlertHandler,
+		transactionHandler: deps.TransactionHandler,
+		adminHandler:       deps.AdminHandler,
+		adminUserHandler:   deps.AdminUserHandler,
+		apiKeyHandler:      deps.APIKeyHandler,
+		webhookHandler:     deps.WebhookHandler,
+		auditHandler:       deps.AuditHandler,
+		complianceHandler:  deps.ComplianceHandler,
+		rateLimitHandler:   deps.RateLimitHandler,
+		eventHandler:       deps.EventHandler,
+		healthHandler:      deps.HealthHandler,
+		jwksHandler:        deps.JWKSHandler,
+		jwtManager:         deps.JWTManager,
+		serviceTokens:      deps.ServiceTokens,
+		rateLimiter:        deps.RateLimiter,
+		internalLimiter:    deps.InternalLimiter,
+		maintenance:        deps.Maintenance,
+		idempotency:        deps.Idempotency,
+		accountService:     deps.AccountService,
+		userService:        deps.UserService,
+		apiKeyService:      deps.APIKeyService,
+		txManager:          deps.TxManager,
+		stopWorkers:        deps.StopWorkers,
+		db:                 deps.DB,
+		redis:              deps.Redis,
 	}
 
 	s.setupMiddleware()
@@ -70,60 +152,272 @@ func NewServer(deps *ServerDeps) *Server {
 		ReadTimeout:  deps.Config.Server.ReadTimeout,
 		WriteTimeout: deps.Config.Server.WriteTimeout,
 	}
+	for _, hook := range deps.ShutdownHooks {
+		s.httpServer.RegisterOnShutdown(hook)
+	}
 
 	return s
 }
 
 func (s *Server) setupMiddleware() {
+	s.router.Use(s.requests.track())
 	s.router.Use(middleware.Recovery(s.logger))
 	s.router.Use(middleware.RequestID())
-	s.router.Use(middleware.Logging(s.logger))
-	s.router.Use(middleware.CORS())
+	s.router.Use(middleware.Tracing())
+	s.router.Use(middleware.AuditContext())
+	s.router.Use(middleware.Logging(s.logger, s.config.Server.LogBodies, s.config.Server.LogBodyMaxBytes))
+	s.router.Use(middleware.BodyLimit(s.config.Server.MaxBodyBytes))
+	s.router.Use(middleware.ServiceIdentity(s.serviceTokens, s.logger))
+	s.router.Use(middleware.ValidationLogging(s.logger, s.config.Server.LogValidation))
+	s.router.Use(middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins:   s.config.CORS.AllowedOrigins,
+		AllowedMethods:   s.config.CORS.AllowedMethods,
+		AllowedHeaders:   s.config.CORS.AllowedHeaders,
+		AllowCredentials: s.config.CORS.AllowCredentials,
+		MaxAge:           s.config.CORS.MaxAge,
+	}))
 	s.router.Use(middleware.SecurityHeaders())
 }
 
+// authLimit is the stricter limit on credential endpoints, on top of the
+// general one.
+func (s *Server) authLimit(route string) middleware.RouteLimit {
+	return middleware.RouteLimit{
+		Name:     route,
+		Requests: s.config.RateLimit.AuthRequests,
+		Window:   s.config.RateLimit.AuthWindow,
+	}
+}
+
 func (s *Server) setupRoutes() {
 	s.router.GET("/health", s.healthHandler.Health)
 	s.router.GET("/ready", s.healthHandler.Ready)
 	s.router.GET("/info", s.healthHandler.Info)
 	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	s.router.GET("/.well-known/jwks.json", s.jwksHandler.Keys)
+
+	authenticate := middleware.Auth(s.jwtManager, s.apiKeyService)
+	rejectSuspended := middleware.RejectSuspended(s.userService)
+	rejectRevoked := middleware.RejectRevokedSessions(s.userService)
+	maintenance := middleware.Maintenance(s.maintenance, s.config.Maintenance.AllowReads, s.config.Maintenance.RetryAfter)
+	// Reads that combine several queries run against a single snapshot.
+	snapshot := middleware.ReadSnapshot(s.txManager)
+	// Requests that move money can be retried safely with X-Idempotency-Key.
+	idempotent := middleware.Idempotency(s.idempotency, s.logger)
+	// transferLimit charges transfers, exempting those between the caller's
+	// own accounts from the general budget if configured. fromParam names
+	// the path parameter holding the source account, if any.
+	transferLimit := func(fromParam string) gin.HandlerFunc {
+		internalLimiter := s.internalLimiter
+		if !s.config.RateLimit.InternalTransferBypass {
+			internalLimiter = nil
+		}
+		return middleware.TransferRateLimit(s.rateLimiter, internalLimiter, s.accountService, fromParam)
+	}
+
+	// The event stream is long-lived, so it sits outside the concurrency
+	// limit that applies to ordinary API requests.
+	s.router.GET("/api/v1/events",
+		authenticate,
+		rejectSuspended,
+		rejectRevoked,
+		middleware.RequireScope(entity.ScopeRead),
+		middleware.RateLimit(s.rateLimiter),
+		s.eventHandler.Stream,
+	)
 
 	api := s.router.Group("/api/v1")
+	api.Use(middleware.ConcurrencyLimit(s.config.Server.MaxConcurrent))
+	api.Use(middleware.JSONLimits(jsonlimit.Limits{
+		MaxDepth:       s.config.Server.JSONMaxDepth,
+		MaxArrayLength: s.config.Server.JSONMaxArrayLen,
+	}))
 	{
 		auth := api.Group("/auth")
 		{
 			auth.Use(middleware.RateLimitByIP(s.rateLimiter))
-			auth.POST("/register", s.userHandler.Register)
-			auth.POST("/login", s.userHandler.Login)
+			// Signing in and out stays open during maintenance, so admins
+			// can still get a session to bypass it with.
+			auth.POST("/register", middleware.RateLimitByIPWithConfig(s.rateLimiter, s.authLimit("register")), maintenance, s.userHandler.Register)
+			auth.POST("/login", middleware.RateLimitByIPWithConfig(s.rateLimiter, s.authLimit("login")), s.userHandler.Login)
+			auth.POST("/2fa", middleware.RateLimitByIPWithConfig(s.rateLimiter, s.authLimit("2fa")), s.userHandler.TwoFactorLogin)
+			auth.POST("/verify-email", maintenance, s.userHandler.VerifyEmail)
+			auth.POST("/resend-verification", middleware.RateLimitByIPWithConfig(s.rateLimiter, s.authLimit("resend_verification")), maintenance, s.userHandler.ResendVerification)
+			auth.POST("/forgot-password", middleware.RateLimitByIPWithConfig(s.rateLimiter, s.authLimit("forgot_password")), maintenance, s.userHandler.ForgotPassword)
+			auth.POST("/reset-password", maintenance, s.userHandler.ResetPassword)
 			auth.POST("/refresh", s.userHandler.RefreshToken)
 			auth.POST("/logout", s.userHandler.Logout)
+			auth.POST("/logout-all", authenticate, rejectRevoked, middleware.AccessTokenOnly(), s.userHandler.LogoutAll)
 		}
 
 		users := api.Group("/users")
-		users.Use(middleware.Auth(s.jwtManager))
+		users.Use(authenticate)
+		users.Use(rejectSuspended)
+		users.Use(rejectRevoked)
+		users.Use(middleware.ScopeByMethod(entity.ScopeAdmin))
+		users.Use(maintenance)
 		users.Use(middleware.RateLimit(s.rateLimiter))
 		{
 			users.GET("/me", s.userHandler.GetMe)
 			users.PUT("/me", s.userHandler.UpdateMe)
+			users.GET("/me/rate-limit", s.rateLimitHandler.Status)
+			users.POST("/me/2fa/enable", middleware.AccessTokenOnly(), s.userHandler.EnableTwoFactor)
+			users.POST("/me/2fa/verify", middleware.AccessTokenOnly(), s.userHandler.ConfirmTwoFactor)
 		}
 
 		accounts := api.Group("/accounts")
-		accounts.Use(middleware.Auth(s.jwtManager))
+		accounts.Use(authenticate)
+		accounts.Use(rejectSuspended)
+		accounts.Use(rejectRevoked)
+		accounts.Use(middleware.ScopeByMethod(entity.ScopeAdmin))
+		accounts.Use(maintenance)
 		accounts.Use(middleware.RateLimit(s.rateLimiter))
 		{
 			accounts.POST("", s.accountHandler.Create)
-			accounts.GET("", s.accountHandler.List)
+			accounts.GET("", snapshot, s.accountHandler.List)
+			// Not under snapshot: single-account reads go through the
+			// account cache, which is skipped inside a transaction.
 			accounts.GET("/:id", s.accountHandler.GetByID)
-			accounts.GET("/:id/transactions", s.accountHandler.GetTransactions)
+			accounts.DELETE("/:id", s.accountHandler.Close)
+			accounts.PATCH("/:id/settings", s.accountHandler.UpdateSettings)
+			accounts.PATCH("/:id/limits", s.accountHandler.UpdateLimits)
+			accounts.PATCH("/:id/status", s.accountHandler.UpdateStatus)
+			accounts.POST("/:id/reactivation-request", s.accountHandler.RequestReactivation)
+			accounts.GET("/:id/transactions", snapshot, s.accountHandler.GetTransactions)
+			accounts.GET("/:id/transactions/:transactionId", snapshot, s.accountHandler.GetTransaction)
+			accounts.GET("/:id/transfers", snapshot, s.transferHandler.ListByAccount)
+			accounts.GET("/:id/statement", snapshot, s.accountHandler.Statement)
+			accounts.GET("/:id/spending-summary", snapshot, s.accountHandler.SpendingSummary)
+			accounts.GET("/:id/allowed-destinations", s.accountHandler.ListAllowedDestinations)
+			accounts.POST("/:id/allowed-destinations", s.accountHandler.AddAllowedDestination)
+			accounts.DELETE("/:id/allowed-destinations/:destinationId", s.accountHandler.RemoveAllowedDestination)
 		}
 
 		transfers := api.Group("/transfers")
-		transfers.Use(middleware.Auth(s.jwtManager))
-		transfers.Use(middleware.RateLimit(s.rateLimiter))
+		transfers.Use(authenticate)
+		transfers.Use(rejectSuspended)
+		transfers.Use(rejectRevoked)
+		transfers.Use(middleware.ScopeByMethod(entity.ScopeTransfer))
+		transfers.Use(maintenance)
+		transfers.Use(transferLimit(""))
 		{
-			transfers.POST("", s.transferHandler.Create)
-			transfers.GET("", s.transferHandler.List)
+			transfers.POST("", idempotent, s.transferHandler.Create)
+			transfers.POST("/batch", idempotent, s.transferHandler.CreateBatch)
+			transfers.GET("", snapshot, s.transferHandler.List)
+			transfers.GET("/export", s.transferHandler.Export)
 			transfers.GET("/:id", s.transferHandler.GetByID)
+			transfers.POST("/:id/refunds", idempotent, s.transferHandler.Refund)
+			transfers.POST("/:id/retry", idempotent, s.transferHandler.Retry)
+			transfers.POST("/:id/reverse", middleware.RequireRole(string(entity.RoleAdmin)), idempotent, s.transferHandler.Reverse)
+			transfers.GET("/by-reference/:ref", s.transferHandler.GetByReference)
+		}
+
+		// Moving money between the caller's accounts is a transfer, so it
+		// takes the transfer scope and limits rather than the accounts
+		// group's.
+		move := api.Group("/accounts/:id/move")
+		move.Use(authenticate)
+		move.Use(rejectSuspended)
+		move.Use(rejectRevoked)
+		move.Use(middleware.RequireScope(entity.ScopeTransfer))
+		move.Use(maintenance)
+		move.Use(transferLimit("id"))
+		{
+			move.POST("", idempotent, s.transferHandler.Move)
+		}
+
+		recurring := api.Group("/recurring-transfers")
+		recurring.Use(authenticate)
+		recurring.Use(rejectSuspended)
+		recurring.Use(rejectRevoked)
+		recurring.Use(middleware.ScopeByMethod(entity.ScopeTransfer))
+		recurring.Use(maintenance)
+		recurring.Use(middleware.RateLimit(s.rateLimiter))
+		{
+			recurring.POST("", s.recurringHandler.Create)
+			recurring.GET("", s.recurringHandler.List)
+			recurring.GET("/:id", s.recurringHandler.GetByID)
+			recurring.DELETE("/:id", s.recurringHandler.Cancel)
+		}
+
+		alerts := api.Group("/balance-alerts")
+		alerts.Use(authenticate)
+		alerts.Use(rejectSuspended)
+		alerts.Use(rejectRevoked)
+		alerts.Use(middleware.ScopeByMethod(entity.ScopeAdmin))
+		alerts.Use(maintenance)
+		alerts.Use(middleware.RateLimit(s.rateLimiter))
+		{
+			alerts.POST("", s.alertHandler.Create)
+			alerts.GET("", s.alertHandler.List)
+			alerts.GET("/:id", s.alertHandler.GetByID)
+			alerts.PATCH("/:id", s.alertHandler.Update)
+			alerts.DELETE("/:id", s.alertHandler.Delete)
+		}
+
+		transactions := api.Group("/transactions")
+		transactions.Use(authenticate)
+		transactions.Use(rejectSuspended)
+		transactions.Use(rejectRevoked)
+		transactions.Use(middleware.ScopeByMethod(entity.ScopeAdmin))
+		transactions.Use(maintenance)
+		transactions.Use(middleware.RateLimit(s.rateLimiter))
+		{
+			transactions.GET("", s.transactionHandler.List)
+			transactions.GET("/:id", s.transactionHandler.GetByID)
+		}
+
+		apiKeys := api.Group("/api-keys")
+		apiKeys.Use(authenticate)
+		apiKeys.Use(rejectSuspended)
+		apiKeys.Use(rejectRevoked)
+		apiKeys.Use(middleware.AccessTokenOnly())
+		apiKeys.Use(maintenance)
+		apiKeys.Use(middleware.RateLimit(s.rateLimiter))
+		{
+			apiKeys.POST("", s.apiKeyHandler.Create)
+			apiKeys.GET("", s.apiKeyHandler.List)
+			apiKeys.DELETE("/:id", s.apiKeyHandler.Revoke)
+		}
+
+		webhooks := api.Group("/webhooks")
+		webhooks.Use(authenticate)
+		webhooks.Use(rejectSuspended)
+		webhooks.Use(rejectRevoked)
+		webhooks.Use(middleware.AccessTokenOnly())
+		webhooks.Use(maintenance)
+		webhooks.Use(middleware.RateLimit(s.rateLimiter))
+		{
+			webhooks.POST("", s.webhookHandler.Create)
+			webhooks.GET("", s.webhookHandler.List)
+			webhooks.DELETE("/:id", s.webhookHandler.Delete)
+			webhooks.GET("/:id/deliveries", s.webhookHandler.ListDeliveries)
+		}
+
+		admin := api.Group("/admin")
+		admin.Use(authenticate)
+		admin.Use(rejectSuspended)
+		admin.Use(rejectRevoked)
+		admin.Use(middleware.RequireScope(entity.ScopeAdmin))
+		admin.Use(middleware.RequireRole(string(entity.RoleAdmin)))
+		admin.Use(middleware.RateLimit(s.rateLimiter))
+		{
+			admin.POST("/accounts/:id/transactions/import", idempotent, s.adminHandler.ImportTransactions)
+			admin.GET("/accounts", s.adminHandler.SearchAccounts)
+			admin.GET("/accounts/reactivation-requests", s.adminHandler.ListReactivationRequests)
+			admin.POST("/accounts/:id/reactivate", s.adminHandler.ApproveReactivation)
+			admin.PATCH("/accounts/:id/balance-rules", s.adminHandler.UpdateBalanceRules)
+			admin.GET("/users", s.adminUserHandler.List)
+			admin.GET("/users/:id", s.adminUserHandler.GetByID)
+			admin.PATCH("/users/:id/role", s.adminUserHandler.ChangeRole)
+			admin.DELETE("/users/:id", s.adminUserHandler.Delete)
+			admin.POST("/users/:id/suspend", s.adminHandler.SuspendUser)
+			admin.POST("/users/:id/reactivate", s.adminHandler.ReactivateUser)
+			admin.POST("/users/:id/force-logout", s.adminHandler.ForceLogout)
+			admin.GET("/stats/balances", s.adminHandler.BalanceStats)
+			admin.GET("/audit-logs", s.auditHandler.List)
+			admin.GET("/security/refresh-token-reuse", s.auditHandler.ListRefreshTokenReuse)
+			admin.GET("/compliance/export", s.complianceHandler.Export)
 		}
 	}
 }
@@ -140,14 +434,45 @@ func (s *Server) Run() error {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	s.logger.Info().Msg("Shutting down server...")
+	inFlight := s.requests.count()
+	s.logger.Info().Int64("in_flight", inFlight).Msg("Shutting down server...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), s.config.Server.ShutdownTimeout)
 	defer cancel()
 
+	return s.shutdown(ctx, inFlight)
+}
+
+// shutdown stops accepting connections, waits for in-flight requests and
+// then background workers to finish, and only then closes the database and
+// Redis pools, so nothing still running loses its connections. All of it
+// must fit in ctx.
+func (s *Server) shutdown(ctx context.Context, inFlight int64) error {
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
+	// Shutdown returns once connections are idle, but hijacked and
+	// streaming handlers may still be unwinding.
+	if err := s.requests.wait(ctx); err != nil {
+		return fmt.Errorf("timed out with %d requests in flight: %w", s.requests.count(), err)
+	}
+	s.logger.Info().Int64("drained", inFlight).Msg("In-flight requests drained")
+
+	if s.stopWorkers != nil {
+		if err := waitFor(ctx, s.stopWorkers); err != nil {
+			return fmt.Errorf("timed out waiting for background workers: %w", err)
+		}
+		s.logger.Info().Msg("Background workers stopped")
+	}
+
+	if s.db != nil {
+		s.db.Close()
+	}
+	if s.redis != nil {
+		if err := s.redis.Close(); err != nil {
+			s.logger.Error().Err(err).Msg("Failed to close Redis client")
+		}
+	}
 
 	s.logger.Info().Msg("Server exited gracefully")
 	return nil
@@ -0,0 +1,119 @@
+package accountnumber
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MaxLength is the size of the accounts.account_number column.
+const MaxLength = 20
+
+var ErrInvalid = errors.New("invalid account number")
+
+// Format describes the account numbers a deployment issues: an optional
+// upper-case letter prefix followed by Length digits. With CheckDigit set the
+// last of those digits is a Luhn check digit over the others, so the check
+// adapts to whatever length is configured.
+type Format struct {
+	Prefix     string
+	Length     int
+	CheckDigit bool
+}
+
+// NewFormat validates a format against the column size and returns it.
+func NewFormat(prefix string, length int, checkDigit bool) (*Format, error) {
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	for _, r := range prefix {
+		if r < 'A' || r > 'Z' {
+			return nil, fmt.Errorf("account number prefix %q must contain only letters", prefix)
+		}
+	}
+
+	minLength := 6
+	if checkDigit {
+		minLength++
+	}
+	if length < minLength {
+		return nil, fmt.Errorf("account number length %d is below the minimum of %d", length, minLength)
+	}
+	if len(prefix)+length > MaxLength {
+		return nil, fmt.Errorf("account number prefix and length exceed %d characters", MaxLength)
+	}
+
+	return &Format{Prefix: prefix, Length: length, CheckDigit: checkDigit}, nil
+}
+
+// Generate returns a random account number in this format.
+func (f *Format) Generate() (string, error) {
+	bodyLength := f.Length
+	if f.CheckDigit {
+		bodyLength--
+	}
+
+	var b strings.Builder
+	b.WriteString(f.Prefix)
+
+	digits := make([]byte, bodyLength)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+	b.Write(digits)
+
+	if f.CheckDigit {
+		b.WriteByte(checkDigit(string(digits)))
+	}
+
+	return b.String(), nil
+}
+
+// Validate reports whether s is a well-formed account number in this format.
+// It does not check that the account exists.
+func (f *Format) Validate(s string) error {
+	if !strings.HasPrefix(s, f.Prefix) {
+		return ErrInvalid
+	}
+
+	digits := s[len(f.Prefix):]
+	if len(digits) != f.Length {
+		return ErrInvalid
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return ErrInvalid
+		}
+	}
+
+	if f.CheckDigit {
+		body := digits[:len(digits)-1]
+		if digits[len(digits)-1] != checkDigit(body) {
+			return ErrInvalid
+		}
+	}
+
+	return nil
+}
+
+// checkDigit returns the Luhn check digit for a string of digits.
+func checkDigit(digits string) byte {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,109 @@
+package accountnumber
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewFormat(t *testing.T) {
+	tests := []struct {
+		prefix     string
+		length     int
+		checkDigit bool
+		ok         bool
+	}{
+		{"", 10, false, true},
+		{" gb ", 12, true, true},
+		{"", 6, false, true},
+		{"", 6, true, false},
+		{"", 5, false, false},
+		{"G1", 10, false, false},
+		{"GB", 18, false, true},
+		{"GB", 19, false, false},
+	}
+
+	for _, tt := range tests {
+		_, err := NewFormat(tt.prefix, tt.length, tt.checkDigit)
+		if (err == nil) != tt.ok {
+			t.Errorf("NewFormat(%q, %d, %v) = %v, want ok %v", tt.prefix, tt.length, tt.checkDigit, err, tt.ok)
+		}
+	}
+}
+
+func TestGeneratedNumbersValidate(t *testing.T) {
+	formats := []struct {
+		prefix     string
+		length     int
+		checkDigit bool
+	}{
+		{"", 10, false},
+		{"GB", 12, true},
+		{"", 7, true},
+		{"XK", 18, true},
+	}
+
+	for _, f := range formats {
+		format, err := NewFormat(f.prefix, f.length, f.checkDigit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 50; i++ {
+			number, err := format.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(number) != len(format.Prefix)+format.Length || !strings.HasPrefix(number, format.Prefix) {
+				t.Fatalf("%+v generated %q", format, number)
+			}
+			if err := format.Validate(number); err != nil {
+				t.Fatalf("%+v rejected its own number %q: %v", format, number, err)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	format, err := NewFormat("GB", 11, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 7992739871 has the Luhn check digit 3.
+	if err := format.Validate("GB79927398713"); err != nil {
+		t.Fatalf("valid number rejected: %v", err)
+	}
+
+	for _, number := range []string{
+		"GB79927398714",  // wrong check digit
+		"GB7992739871",   // too short
+		"GB799273987133", // too long
+		"US79927398713",  // wrong prefix
+		"79927398713",    // no prefix
+		"GB7992739A713",  // not all digits
+		"",
+	} {
+		if err := format.Validate(number); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Validate(%q) = %v, want ErrInvalid", number, err)
+		}
+	}
+}
+
+func TestCheckDigitCatchesSingleDigitChange(t *testing.T) {
+	format, err := NewFormat("", 12, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number, err := format.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < len(number); i++ {
+		changed := []byte(number)
+		changed[i] = '0' + (changed[i]-'0'+1)%10
+		if err := format.Validate(string(changed)); err == nil {
+			t.Fatalf("changing digit %d of %s to give %s went undetected", i, number, changed)
+		}
+	}
+}
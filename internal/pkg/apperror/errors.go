@@ -67,12 +67,48 @@ var (
 		StatusCode: http.StatusBadRequest,
 	}
 
+	ErrAccountHasBalance = &AppError{
+		Code:       "ACCOUNT_HAS_BALANCE",
+		Message:    "Account balance must be zero before it can be closed",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrJSONTooDeep = &AppError{
+		Code:       "JSON_TOO_DEEP",
+		Message:    "Request body is nested too deeply",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrJSONArrayTooLong = &AppError{
+		Code:       "JSON_ARRAY_TOO_LONG",
+		Message:    "Request body contains an array with too many elements",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrRequestTooLarge = &AppError{
+		Code:       "REQUEST_TOO_LARGE",
+		Message:    "Request body is too large",
+		StatusCode: http.StatusRequestEntityTooLarge,
+	}
+
 	ErrInternalServer = &AppError{
 		Code:       "INTERNAL_ERROR",
 		Message:    "Internal server error",
 		StatusCode: http.StatusInternalServerError,
 	}
 
+	ErrTooManyStreams = &AppError{
+		Code:       "TOO_MANY_STREAMS",
+		Message:    "Too many open event streams",
+		StatusCode: http.StatusTooManyRequests,
+	}
+
+	ErrInvalidCursor = &AppError{
+		Code:       "INVALID_CURSOR",
+		Message:    "Invalid pagination cursor",
+		StatusCode: http.StatusBadRequest,
+	}
+
 	ErrConflict = &AppError{
 		Code:       "CONFLICT",
 		Message:    "Resource conflict",
@@ -90,6 +126,18 @@ var (
 		Message:    "Too many requests",
 		StatusCode: http.StatusTooManyRequests,
 	}
+
+	ErrServiceUnavailable = &AppError{
+		Code:       "SERVICE_UNAVAILABLE",
+		Message:    "Server is too busy, please retry later",
+		StatusCode: http.StatusServiceUnavailable,
+	}
+
+	ErrMaintenance = &AppError{
+		Code:       "MAINTENANCE",
+		Message:    "Service is under maintenance",
+		StatusCode: http.StatusServiceUnavailable,
+	}
 )
 
 // User errors
@@ -100,12 +148,72 @@ var (
 		StatusCode: http.StatusNotFound,
 	}
 
+	ErrTooManySessions = &AppError{
+		Code:       "TOO_MANY_SESSIONS",
+		Message:    "Maximum number of active sessions reached; log out of another device first",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrEmailNotVerified = &AppError{
+		Code:       "EMAIL_NOT_VERIFIED",
+		Message:    "Email address has not been verified",
+		StatusCode: http.StatusForbidden,
+	}
+
 	ErrEmailAlreadyExists = &AppError{
 		Code:       "EMAIL_EXISTS",
 		Message:    "Email already registered",
 		StatusCode: http.StatusConflict,
 	}
 
+	ErrUserHasRecords = &AppError{
+		Code:       "USER_HAS_RECORDS",
+		Message:    "User has account history and cannot be deleted; suspend them instead",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrUserSuspended = &AppError{
+		Code:       "USER_SUSPENDED",
+		Message:    "User account is suspended",
+		StatusCode: http.StatusForbidden,
+	}
+
+	ErrInvalidAPIKey = &AppError{
+		Code:       "INVALID_API_KEY",
+		Message:    "Invalid, expired or revoked API key",
+		StatusCode: http.StatusUnauthorized,
+	}
+
+	ErrAPIKeyNotFound = &AppError{
+		Code:       "API_KEY_NOT_FOUND",
+		Message:    "API key not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrInsufficientScope = &AppError{
+		Code:       "INSUFFICIENT_SCOPE",
+		Message:    "API key does not have the scope required for this operation",
+		StatusCode: http.StatusForbidden,
+	}
+
+	ErrTwoFactorAlreadyEnabled = &AppError{
+		Code:       "TWO_FACTOR_ALREADY_ENABLED",
+		Message:    "Two-factor authentication is already enabled",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrTwoFactorNotPending = &AppError{
+		Code:       "TWO_FACTOR_NOT_PENDING",
+		Message:    "Two-factor authentication setup has not been started",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrInvalidTwoFactorCode = &AppError{
+		Code:       "INVALID_TWO_FACTOR_CODE",
+		Message:    "Invalid two-factor authentication code",
+		StatusCode: http.StatusUnauthorized,
+	}
+
 	ErrInvalidCredentials = &AppError{
 		Code:       "INVALID_CREDENTIALS",
 		Message:    "Invalid email or password",
@@ -123,10 +231,28 @@ var (
 		Message:    "Token has expired",
 		StatusCode: http.StatusUnauthorized,
 	}
+
+	ErrSessionRevoked = &AppError{
+		Code:       "SESSION_REVOKED",
+		Message:    "Session has been revoked; sign in again",
+		StatusCode: http.StatusUnauthorized,
+	}
+
+	ErrRefreshTokenReused = &AppError{
+		Code:       "REFRESH_TOKEN_REUSED",
+		Message:    "Refresh token was already used; all sessions from this sign-in have been revoked",
+		StatusCode: http.StatusUnauthorized,
+	}
 )
 
 // Account errors
 var (
+	ErrInvalidAccountNumber = &AppError{
+		Code:       "INVALID_ACCOUNT_NUMBER",
+		Message:    "Account number is not in a valid format",
+		StatusCode: http.StatusBadRequest,
+	}
+
 	ErrAccountNotFound = &AppError{
 		Code:       "ACCOUNT_NOT_FOUND",
 		Message:    "Account not found",
@@ -145,18 +271,72 @@ var (
 		StatusCode: http.StatusBadRequest,
 	}
 
+	ErrOverdraftLimitExceeded = &AppError{
+		Code:       "OVERDRAFT_LIMIT_EXCEEDED",
+		Message:    "Debit would exceed the account's overdraft limit",
+		StatusCode: http.StatusBadRequest,
+	}
+
 	ErrSameAccount = &AppError{
 		Code:       "SAME_ACCOUNT",
 		Message:    "Cannot transfer to the same account",
 		StatusCode: http.StatusBadRequest,
 	}
 
+	ErrOpeningDepositTooLow = &AppError{
+		Code:       "OPENING_DEPOSIT_TOO_LOW",
+		Message:    "Initial deposit is below the minimum for this account type and currency",
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+
 	ErrCurrencyMismatch = &AppError{
 		Code:       "CURRENCY_MISMATCH",
 		Message:    "Currency mismatch between accounts",
 		StatusCode: http.StatusBadRequest,
 	}
 
+	ErrCurrencyPairUnsupported = &AppError{
+		Code:       "CURRENCY_PAIR_UNSUPPORTED",
+		Message:    "No exchange rate is available for this currency pair",
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+
+	ErrUnsupportedCurrencyPair = &AppError{
+		Code:       "CURRENCY_PAIR_NOT_ALLOWED",
+		Message:    "Conversion between these currencies is not allowed",
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+
+	ErrExchangeRateUnavailable = &AppError{
+		Code:       "EXCHANGE_RATE_UNAVAILABLE",
+		Message:    "Exchange rate is temporarily unavailable",
+		StatusCode: http.StatusServiceUnavailable,
+	}
+
+	ErrInvalidStatusTransition = &AppError{
+		Code:       "INVALID_STATUS_TRANSITION",
+		Message:    "Account cannot move to the requested status",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrAccountNotDormant = &AppError{
+		Code:       "ACCOUNT_NOT_DORMANT",
+		Message:    "Account is not frozen for dormancy",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrBalanceOverflow = &AppError{
+		Code:       "BALANCE_OVERFLOW",
+		Message:    "Resulting balance exceeds the maximum supported value",
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+
+	ErrCurrencyImmutable = &AppError{
+		Code:       "CURRENCY_IMMUTABLE",
+		Message:    "Account currency cannot be changed",
+		StatusCode: http.StatusBadRequest,
+	}
+
 	ErrInvalidAmount = &AppError{
 		Code:       "INVALID_AMOUNT",
 		Message:    "Invalid amount",
@@ -172,6 +352,144 @@ var (
 		StatusCode: http.StatusNotFound,
 	}
 
+	ErrTransactionNotFound = &AppError{
+		Code:       "TRANSACTION_NOT_FOUND",
+		Message:    "Transaction not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrTransferNotRefundable = &AppError{
+		Code:       "TRANSFER_NOT_REFUNDABLE",
+		Message:    "Transfer cannot be refunded",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrRefundExceedsTransfer = &AppError{
+		Code:       "REFUND_EXCEEDS_TRANSFER",
+		Message:    "Refund amount exceeds the remaining refundable amount",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrTransferNotRetryable = &AppError{
+		Code:       "TRANSFER_NOT_RETRYABLE",
+		Message:    "Only a failed scheduled transfer can be retried",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrTransferNotReversible = &AppError{
+		Code:       "TRANSFER_NOT_REVERSIBLE",
+		Message:    "Only a completed transfer that is not itself a refund or reversal can be reversed",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrReversalInsufficientBalance = &AppError{
+		Code:       "REVERSAL_INSUFFICIENT_BALANCE",
+		Message:    "Recipient account no longer holds enough funds to reverse this transfer",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrBatchTransferScheduled = &AppError{
+		Code:       "BATCH_TRANSFER_SCHEDULED",
+		Message:    "Transfers in a batch run immediately and cannot set scheduled_at",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrBatchAborted = &AppError{
+		Code:       "BATCH_ABORTED",
+		Message:    "Not made because another transfer in the all-or-nothing batch failed",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrInvalidScheduledAt = &AppError{
+		Code:       "INVALID_SCHEDULED_AT",
+		Message:    "scheduled_at must be in the future",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrRecurringTransferNotFound = &AppError{
+		Code:       "RECURRING_TRANSFER_NOT_FOUND",
+		Message:    "Recurring transfer not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrBalanceAlertNotFound = &AppError{
+		Code:       "BALANCE_ALERT_NOT_FOUND",
+		Message:    "Balance alert not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrWebhookNotFound = &AppError{
+		Code:       "WEBHOOK_NOT_FOUND",
+		Message:    "Webhook not found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	ErrInvalidWebhookURL = &AppError{
+		Code:       "INVALID_WEBHOOK_URL",
+		Message:    "Webhook URL must be an absolute https URL to a public host",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrWebhookLimitReached = &AppError{
+		Code:       "WEBHOOK_LIMIT_REACHED",
+		Message:    "Maximum number of webhooks reached",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrInvalidSchedule = &AppError{
+		Code:       "INVALID_SCHEDULE",
+		Message:    "Schedule must start in the future and end after its first run",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrDestinationNotAllowed = &AppError{
+		Code:       "DESTINATION_NOT_ALLOWED",
+		Message:    "Destination account is not on the source account's allowlist",
+		StatusCode: http.StatusForbidden,
+	}
+
+	ErrMemoRequired = &AppError{
+		Code:       "MEMO_REQUIRED",
+		Message:    "A description is required for transfers from this account",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrMemoTooLong = &AppError{
+		Code:       "MEMO_TOO_LONG",
+		Message:    "Description exceeds the maximum length",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrMemoRejected = &AppError{
+		Code:       "MEMO_REJECTED",
+		Message:    "Description contains content that is not allowed",
+		StatusCode: http.StatusBadRequest,
+	}
+
+	ErrTransferLimitExceeded = &AppError{
+		Code:       "TRANSFER_LIMIT_EXCEEDED",
+		Message:    "Transfer would exceed the account's daily transfer limit",
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+
+	ErrTransferCooldown = &AppError{
+		Code:       "TRANSFER_COOLDOWN",
+		Message:    "A transfer between these accounts was made too recently",
+		StatusCode: http.StatusTooManyRequests,
+	}
+
+	ErrIdempotencyKeyConflict = &AppError{
+		Code:       "IDEMPOTENCY_KEY_CONFLICT",
+		Message:    "Idempotency key was already used with different parameters",
+		StatusCode: http.StatusConflict,
+	}
+
+	ErrIdempotencyRequestInProgress = &AppError{
+		Code:       "IDEMPOTENCY_REQUEST_IN_PROGRESS",
+		Message:    "A request with this idempotency key is still being processed",
+		StatusCode: http.StatusConflict,
+	}
+
 	ErrDuplicateTransfer = &AppError{
 		Code:       "DUPLICATE_TRANSFER",
 		Message:    "Duplicate transfer detected",
@@ -179,6 +497,15 @@ var (
 	}
 )
 
+// Compliance errors
+var (
+	ErrComplianceExportDisabled = &AppError{
+		Code:       "COMPLIANCE_EXPORT_DISABLED",
+		Message:    "Compliance exports are not configured",
+		StatusCode: http.StatusServiceUnavailable,
+	}
+)
+
 func IsAppError(err error) bool {
 	var appErr *AppError
 	return errors.As(err, &appErr)
@@ -192,8 +519,13 @@ func GetAppError(err error) *AppError {
 	return nil
 }
 
+// ValidationError describes one invalid field. Code is the rule that failed
+// (e.g. "required", "min") and Param its argument, if it has one, so clients
+// can build their own messages instead of showing Message.
 type ValidationError struct {
 	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
 	Message string `json:"message"`
 }
 
@@ -201,9 +533,10 @@ type ValidationErrors struct {
 	Errors []ValidationError `json:"errors"`
 }
 
-func NewValidationError(field, message string) ValidationError {
+func NewValidationError(field, code, message string) ValidationError {
 	return ValidationError{
 		Field:   field,
+		Code:    code,
 		Message: message,
 	}
 }
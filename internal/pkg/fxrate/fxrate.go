@@ -0,0 +1,131 @@
+package fxrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/pkg/money"
+)
+
+var (
+	ErrUnsupportedPair = errors.New("no exchange rate for currency pair")
+	ErrInvalidRate     = errors.New("invalid exchange rate")
+)
+
+// Static serves fixed rates from configuration. A pair that is only quoted
+// the other way round is served as the inverse of that quote.
+type Static struct {
+	rates map[string]decimal.Decimal
+}
+
+// ParseStatic reads rates written as "FROM:TO=RATE" pairs separated by commas,
+// e.g. "USD:EUR=0.92,GBP:USD=1.27". An empty string yields no rates.
+func ParseStatic(spec string) (*Static, error) {
+	rates := make(map[string]decimal.Decimal)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pair, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("fx rate %q: expected FROM:TO=RATE", entry)
+		}
+		from, to, ok := strings.Cut(pair, ":")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("fx rate %q: expected FROM:TO=RATE", entry)
+		}
+
+		rate, err := decimal.NewFromString(strings.TrimSpace(value))
+		if err != nil || !rate.IsPositive() {
+			return nil, fmt.Errorf("fx rate %q: %w", entry, ErrInvalidRate)
+		}
+		rates[pairKey(from, to)] = rate
+	}
+	return &Static{rates: rates}, nil
+}
+
+func (s *Static) Quote(_ context.Context, from, to string) (decimal.Decimal, time.Time, error) {
+	if rate, ok := s.rates[pairKey(from, to)]; ok {
+		return rate, time.Now().UTC(), nil
+	}
+	if rate, ok := s.rates[pairKey(to, from)]; ok {
+		return money.Div(decimal.NewFromInt(1), rate), time.Now().UTC(), nil
+	}
+	return decimal.Zero, time.Time{}, ErrUnsupportedPair
+}
+
+func pairKey(from, to string) string {
+	return strings.ToUpper(strings.TrimSpace(from)) + ":" + strings.ToUpper(strings.TrimSpace(to))
+}
+
+// HTTP fetches rates from an external endpoint with
+// GET <url>?from=USD&to=EUR, which must answer with a JSON body such as
+// {"rate": "0.92", "as_of": "2024-01-02T15:04:05Z"}. A missing as_of is taken
+// to mean the rate is current.
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTP(url string, timeout time.Duration) *HTTP {
+	return &HTTP{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+type httpQuote struct {
+	Rate string    `json:"rate"`
+	AsOf time.Time `json:"as_of"`
+}
+
+func (h *HTTP) Quote(ctx context.Context, from, to string) (decimal.Decimal, time.Time, error) {
+	endpoint, err := url.Parse(h.url)
+	if err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+	query := endpoint.Query()
+	query.Set("from", from)
+	query.Set("to", to)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return decimal.Zero, time.Time{}, ErrUnsupportedPair
+	case resp.StatusCode != http.StatusOK:
+		return decimal.Zero, time.Time{}, fmt.Errorf("fx provider returned status %d", resp.StatusCode)
+	}
+
+	var quote httpQuote
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+
+	rate, err := decimal.NewFromString(quote.Rate)
+	if err != nil || !rate.IsPositive() {
+		return decimal.Zero, time.Time{}, ErrInvalidRate
+	}
+
+	asOf := quote.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	return rate, asOf.UTC(), nil
+}
@@ -0,0 +1,47 @@
+package fxrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStaticQuote(t *testing.T) {
+	rates, err := ParseStatic("USD:EUR=0.92, gbp:usd=1.27")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"USD", "EUR", "0.92"},
+		{"GBP", "USD", "1.27"},
+		// Served as the inverse of the GBP:USD quote.
+		{"USD", "GBP", "0.7874015748031496"},
+		{"EUR", "USD", "1.0869565217391304"},
+	}
+	for _, tt := range tests {
+		rate, _, err := rates.Quote(ctx, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("Quote(%s, %s): %v", tt.from, tt.to, err)
+		}
+		if rate.String() != tt.want {
+			t.Errorf("Quote(%s, %s) = %s, want %s", tt.from, tt.to, rate, tt.want)
+		}
+	}
+
+	if _, _, err := rates.Quote(ctx, "USD", "JPY"); !errors.Is(err, ErrUnsupportedPair) {
+		t.Fatalf("unquoted pair = %v, want ErrUnsupportedPair", err)
+	}
+}
+
+func TestParseStaticRejectsBadRates(t *testing.T) {
+	for _, spec := range []string{"USD:EUR", "USDEUR=0.9", "USD:EUR=abc", "USD:EUR=0", "USD:EUR=-1"} {
+		if _, err := ParseStatic(spec); err == nil {
+			t.Errorf("ParseStatic(%q) accepted it", spec)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Package jsonlimit bounds the shape of JSON documents before they are
+// decoded, so a deeply nested or oversized array payload is rejected without
+// being materialized.
+package jsonlimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrTooDeep      = errors.New("JSON nesting is too deep")
+	ErrArrayTooLong = errors.New("JSON array is too long")
+)
+
+// Limits caps nesting depth and the number of elements in any one array. A
+// zero or negative field disables that limit.
+type Limits struct {
+	MaxDepth       int
+	MaxArrayLength int
+}
+
+// Enabled reports whether any limit is set.
+func (l Limits) Enabled() bool {
+	return l.MaxDepth > 0 || l.MaxArrayLength > 0
+}
+
+type frame struct {
+	array    bool
+	elements int
+}
+
+// Check scans data and returns ErrTooDeep or ErrArrayTooLong if it breaks a
+// limit. Malformed JSON is not reported here; decoding will reject it with
+// its own error.
+func (l Limits) Check(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []frame
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// io.EOF ends a well-formed document; anything else is a syntax
+			// error left for the decoder to report.
+			return nil
+		}
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		if n := len(stack); n > 0 && stack[n-1].array {
+			stack[n-1].elements++
+			if l.MaxArrayLength > 0 && stack[n-1].elements > l.MaxArrayLength {
+				return ErrArrayTooLong
+			}
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			stack = append(stack, frame{array: delim == '['})
+			if l.MaxDepth > 0 && len(stack) > l.MaxDepth {
+				return ErrTooDeep
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package jsonlimit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func nested(depth int) string {
+	return strings.Repeat(`{"a":`, depth) + "1" + strings.Repeat("}", depth)
+}
+
+func array(length int) string {
+	return "[" + strings.TrimSuffix(strings.Repeat("1,", length), ",") + "]"
+}
+
+func TestCheck(t *testing.T) {
+	limits := Limits{MaxDepth: 4, MaxArrayLength: 3}
+
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"at max depth", nested(4), nil},
+		{"over max depth", nested(5), ErrTooDeep},
+		{"arrays count toward depth", `[[[[[1]]]]]`, ErrTooDeep},
+		{"at max array length", array(3), nil},
+		{"over max array length", array(4), ErrArrayTooLong},
+		{"long nested array", `{"transfers":[{},{},{},{}]}`, ErrArrayTooLong},
+		{"object keys are not elements", `{"a":1,"b":2,"c":3,"d":4}`, nil},
+		{"sibling arrays counted apart", `[[1,2,3],[1,2,3],[1,2,3]]`, nil},
+		{"malformed is left to the decoder", `{"a":[1,2,`, nil},
+	}
+	for _, tt := range tests {
+		if err := limits.Check([]byte(tt.body)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestZeroLimitsAreDisabled(t *testing.T) {
+	var limits Limits
+	if limits.Enabled() {
+		t.Fatal("zero limits are enabled")
+	}
+	if err := limits.Check([]byte(nested(100))); err != nil {
+		t.Fatalf("disabled depth limit: %v", err)
+	}
+	if err := (Limits{MaxDepth: 2}).Check([]byte(array(1000))); err != nil {
+		t.Fatalf("disabled array limit: %v", err)
+	}
+}
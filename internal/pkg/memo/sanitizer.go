@@ -0,0 +1,144 @@
+package memo
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Mode selects what a RegexSanitizer does with a memo that matches a pattern.
+type Mode string
+
+const (
+	ModeOff    Mode = "off"
+	ModeMask   Mode = "mask"
+	ModeReject Mode = "reject"
+)
+
+// ParseMode returns the named mode, defaulting to ModeOff.
+func ParseMode(s string) Mode {
+	switch Mode(strings.ToLower(strings.TrimSpace(s))) {
+	case ModeMask:
+		return ModeMask
+	case ModeReject:
+		return ModeReject
+	default:
+		return ModeOff
+	}
+}
+
+var ErrRejected = errors.New("memo contains disallowed content")
+
+var (
+	// cardPattern matches 13 to 19 digits, optionally grouped with spaces or
+	// dashes. Matches are confirmed with a Luhn check before being treated as
+	// card numbers so that ordinary invoice numbers pass through.
+	cardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	ssnPattern  = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+)
+
+// RegexSanitizer masks or rejects memos containing card numbers, US social
+// security numbers, or any of a configured list of blocked words.
+type RegexSanitizer struct {
+	mode    Mode
+	blocked *regexp.Regexp
+}
+
+// NewRegexSanitizer builds a sanitizer for mode. Blocked words are matched
+// case-insensitively as whole words; empty entries are ignored.
+func NewRegexSanitizer(mode Mode, blockedWords []string) *RegexSanitizer {
+	s := &RegexSanitizer{mode: mode}
+
+	var quoted []string
+	for _, word := range blockedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) > 0 {
+		s.blocked = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	}
+
+	return s
+}
+
+// Sanitize returns memo unchanged in ModeOff. In ModeMask matching content is
+// replaced; in ModeReject any match returns ErrRejected.
+func (s *RegexSanitizer) Sanitize(memo string) (string, error) {
+	if s.mode == ModeOff || memo == "" {
+		return memo, nil
+	}
+
+	matched := false
+
+	memo = cardPattern.ReplaceAllStringFunc(memo, func(m string) string {
+		if !luhnValid(m) {
+			return m
+		}
+		matched = true
+		return maskDigits(m, 4)
+	})
+
+	memo = ssnPattern.ReplaceAllStringFunc(memo, func(m string) string {
+		matched = true
+		return maskDigits(m, 0)
+	})
+
+	if s.blocked != nil {
+		memo = s.blocked.ReplaceAllStringFunc(memo, func(m string) string {
+			matched = true
+			return strings.Repeat("*", len(m))
+		})
+	}
+
+	if matched && s.mode == ModeReject {
+		return "", ErrRejected
+	}
+	return memo, nil
+}
+
+// maskDigits replaces every digit in s with '*' except the last keep digits.
+// Separators are left in place.
+func maskDigits(s string, keep int) string {
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+
+	var b strings.Builder
+	seen := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			seen++
+			if seen <= digits-keep {
+				b.WriteByte('*')
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func luhnValid(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
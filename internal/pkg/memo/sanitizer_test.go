@@ -0,0 +1,77 @@
+package memo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSanitizeMasksCardNumber(t *testing.T) {
+	sanitizer := NewRegexSanitizer(ModeMask, nil)
+
+	tests := []struct {
+		memo string
+		want string
+	}{
+		{"Paid with 4111 1111 1111 1111 thanks", "Paid with **** **** **** 1111 thanks"},
+		{"card 4111-1111-1111-1111", "card ****-****-****-1111"},
+		{"card 4111111111111111", "card ************1111"},
+		{"ssn 123-45-6789", "ssn ***-**-****"},
+		// Long numbers that fail the Luhn check are not card numbers.
+		{"Invoice 1234567890123456", "Invoice 1234567890123456"},
+		{"Rent for May", "Rent for May"},
+	}
+
+	for _, tt := range tests {
+		got, err := sanitizer.Sanitize(tt.memo)
+		if err != nil {
+			t.Fatalf("Sanitize(%q): %v", tt.memo, err)
+		}
+		if got != tt.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tt.memo, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeRejectsCardNumber(t *testing.T) {
+	sanitizer := NewRegexSanitizer(ModeReject, nil)
+
+	if _, err := sanitizer.Sanitize("Paid with 4111 1111 1111 1111"); !errors.Is(err, ErrRejected) {
+		t.Fatalf("card number in reject mode = %v, want ErrRejected", err)
+	}
+	if got, err := sanitizer.Sanitize("Invoice 1042"); err != nil || got != "Invoice 1042" {
+		t.Fatalf("clean memo in reject mode = %q, %v", got, err)
+	}
+}
+
+func TestSanitizeOffLeavesMemo(t *testing.T) {
+	memo := "Paid with 4111 1111 1111 1111"
+	if got, err := NewRegexSanitizer(ModeOff, []string{"paid"}).Sanitize(memo); err != nil || got != memo {
+		t.Fatalf("Sanitize in off mode = %q, %v; want the memo unchanged", got, err)
+	}
+}
+
+func TestSanitizeBlockedWords(t *testing.T) {
+	sanitizer := NewRegexSanitizer(ModeMask, []string{"darn", " ", ""})
+
+	got, err := sanitizer.Sanitize("Darn late fee, not darning socks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "**** late fee, not darning socks" {
+		t.Fatalf("Sanitize = %q, want the whole word masked", got)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for input, want := range map[string]Mode{
+		"mask":     ModeMask,
+		" REJECT ": ModeReject,
+		"off":      ModeOff,
+		"":         ModeOff,
+		"bogus":    ModeOff,
+	} {
+		if got := ParseMode(input); got != want {
+			t.Errorf("ParseMode(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RegisterDBPool exports the connection counts of pool, read from
+// pool.Stat() at scrape time. Call it once per pool.
+func RegisterDBPool(pool *pgxpool.Pool) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gobank_db_pool_acquired_connections",
+		Help: "Number of database connections currently in use.",
+	}, func() float64 {
+		return float64(pool.Stat().AcquiredConns())
+	})
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gobank_db_pool_idle_connections",
+		Help: "Number of idle database connections in the pool.",
+	}, func() float64 {
+		return float64(pool.Stat().IdleConns())
+	})
+
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gobank_db_pool_max_connections",
+		Help: "Maximum size of the database connection pool.",
+	}, func() float64 {
+		return float64(pool.Stat().MaxConns())
+	})
+}
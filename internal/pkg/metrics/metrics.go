@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	HTTPInFlightRequests = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gobank_http_in_flight_requests",
+		Help: "Number of API requests currently being served.",
+	})
+
+	// HTTPRequestDuration is labeled by route template, not raw path, so IDs
+	// in URLs do not create a series each.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gobank_http_request_duration_seconds",
+		Help:    "Time taken to serve HTTP requests, by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	ValidationFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_validation_failures_total",
+		Help: "Number of requests rejected by input validation, by route.",
+	}, []string{"route"})
+
+	TransfersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_transfers_total",
+		Help: "Number of transfers reaching each status.",
+	}, []string{"status"})
+
+	TransferFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_transfer_failures_total",
+		Help: "Number of rejected or failed transfers, by reason.",
+	}, []string{"reason"})
+
+	LoginFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_login_failures_total",
+		Help: "Number of failed login attempts, including second factors, by reason.",
+	}, []string{"reason"})
+
+	RefreshTokenReuseTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gobank_refresh_token_reuse_total",
+		Help: "Number of replayed refresh tokens that revoked a token family.",
+	})
+
+	CleanupDeletedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_cleanup_deleted_total",
+		Help: "Number of expired rows removed by the cleanup job, by type.",
+	}, []string{"type"})
+
+	CleanupErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_cleanup_errors_total",
+		Help: "Number of failed cleanup sweeps, by type.",
+	}, []string{"type"})
+
+	EventStreamsOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gobank_event_streams_open",
+		Help: "Number of event streams currently open on this instance.",
+	})
+
+	EventStreamDroppedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gobank_event_stream_dropped_total",
+		Help: "Number of events dropped because a stream's buffer was full.",
+	})
+
+	EventStreamSlowDisconnectsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gobank_event_stream_slow_disconnects_total",
+		Help: "Number of event streams disconnected for falling behind.",
+	})
+
+	AccountCacheHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gobank_account_cache_hits_total",
+		Help: "Number of account reads served from the cache.",
+	})
+
+	AccountCacheMissesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gobank_account_cache_misses_total",
+		Help: "Number of account reads that fell through to the database, including on cache errors.",
+	})
+
+	OutboxPublishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_outbox_published_total",
+		Help: "Number of outbox events published, by event type.",
+	}, []string{"event_type"})
+
+	OutboxPublishFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_outbox_publish_failures_total",
+		Help: "Number of failed outbox publish attempts, by event type.",
+	}, []string{"event_type"})
+
+	WebhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gobank_webhook_deliveries_total",
+		Help: "Number of webhook delivery attempts, by event type and outcome (succeeded, retrying, failed).",
+	}, []string{"event_type", "outcome"})
+)
@@ -0,0 +1,95 @@
+package money
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// Precision and Scale mirror the DECIMAL(19,4) money columns.
+const (
+	Precision = 19
+	Scale     = 4
+)
+
+var ErrOverflow = errors.New("amount exceeds supported precision")
+
+// MaxValue is the largest magnitude a DECIMAL(19,4) column can hold.
+var MaxValue = decimal.New(1, Precision-Scale).Sub(decimal.New(1, -Scale))
+
+// Check returns ErrOverflow if d does not fit in a money column.
+func Check(d decimal.Decimal) error {
+	if d.Abs().GreaterThan(MaxValue) {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// Add returns a+b, or ErrOverflow if the sum does not fit in a money column.
+func Add(a, b decimal.Decimal) (decimal.Decimal, error) {
+	sum := a.Add(b)
+	if err := Check(sum); err != nil {
+		return decimal.Zero, err
+	}
+	return sum, nil
+}
+
+// DivisionPrecision is the number of decimal places kept by Div before the
+// result is rounded to a currency's scale. It is set well above Scale so that
+// chained FX calculations do not lose precision to the package default.
+const DivisionPrecision = 16
+
+type RoundingMode string
+
+const (
+	RoundHalfEven RoundingMode = "half_even"
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundDown     RoundingMode = "down"
+)
+
+// ParseRoundingMode returns the named mode, defaulting to banker's rounding.
+func ParseRoundingMode(s string) RoundingMode {
+	switch RoundingMode(s) {
+	case RoundHalfUp, RoundDown:
+		return RoundingMode(s)
+	default:
+		return RoundHalfEven
+	}
+}
+
+// Div returns a/b with DivisionPrecision decimal places.
+func Div(a, b decimal.Decimal) decimal.Decimal {
+	return a.DivRound(b, DivisionPrecision)
+}
+
+// Round rounds d to scale decimal places using mode.
+func Round(d decimal.Decimal, scale int32, mode RoundingMode) decimal.Decimal {
+	switch mode {
+	case RoundHalfUp:
+		return d.Round(scale)
+	case RoundDown:
+		return d.RoundDown(scale)
+	default:
+		return d.RoundBank(scale)
+	}
+}
+
+// Convert applies an exchange rate to amount and rounds the result to the
+// destination currency's scale.
+func Convert(amount, rate decimal.Decimal, scale int32, mode RoundingMode) decimal.Decimal {
+	return Round(amount.Mul(rate), scale, mode)
+}
+
+// DisplayScale is the number of decimal places amounts are shown with by
+// default. Storage keeps Scale places.
+const DisplayScale = 2
+
+// Format renders d with scale decimal places using banker's rounding.
+func Format(d decimal.Decimal, scale int32) string {
+	return d.RoundBank(scale).StringFixed(scale)
+}
+
+// FormatFull renders d with the full stored precision.
+func FormatFull(d decimal.Decimal) string {
+	return d.StringFixed(Scale)
+}
@@ -0,0 +1,139 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMaxValueFitsColumn(t *testing.T) {
+	if got := MaxValue.String(); got != "999999999999999.9999" {
+		t.Fatalf("MaxValue = %s, want the largest DECIMAL(19,4)", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   error
+	}{
+		{"0", nil},
+		{"999999999999999.9999", nil},
+		{"-999999999999999.9999", nil},
+		{"1000000000000000", ErrOverflow},
+		{"-1000000000000000", ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		if err := Check(decimal.RequireFromString(tt.amount)); !errors.Is(err, tt.want) {
+			t.Errorf("Check(%s) = %v, want %v", tt.amount, err, tt.want)
+		}
+	}
+}
+
+func TestAddOverflow(t *testing.T) {
+	nearMax := MaxValue.Sub(decimal.RequireFromString("0.50"))
+
+	sum, err := Add(nearMax, decimal.RequireFromString("0.50"))
+	if err != nil || !sum.Equal(MaxValue) {
+		t.Fatalf("Add up to MaxValue = %s, %v", sum, err)
+	}
+
+	if _, err := Add(nearMax, decimal.RequireFromString("0.5001")); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("Add past MaxValue = %v, want ErrOverflow", err)
+	}
+}
+
+func TestDivKeepsPrecision(t *testing.T) {
+	// The inverse of a quoted rate is kept to DivisionPrecision places, so it
+	// still converts large amounts to the right cent.
+	got := Div(decimal.NewFromInt(1), decimal.RequireFromString("1.27"))
+	if want := "0.7874015748031496"; got.String() != want {
+		t.Fatalf("Div(1, 1.27) = %s, want %s", got, want)
+	}
+
+	amount := decimal.RequireFromString("1000000")
+	if converted := Convert(amount, got, 2, RoundHalfEven); converted.String() != "787401.57" {
+		t.Fatalf("1,000,000 at the inverse of 1.27 = %s, want 787401.57", converted)
+	}
+}
+
+func TestConvertRounding(t *testing.T) {
+	tests := []struct {
+		amount string
+		rate   string
+		scale  int32
+		mode   RoundingMode
+		want   string
+	}{
+		// 10.05 * 0.5 = 5.025: a tie.
+		{"10.05", "0.5", 2, RoundHalfEven, "5.02"},
+		{"10.05", "0.5", 2, RoundHalfUp, "5.03"},
+		{"10.05", "0.5", 2, RoundDown, "5.02"},
+		// 10.15 * 0.5 = 5.075: a tie rounding to the even 5.08.
+		{"10.15", "0.5", 2, RoundHalfEven, "5.08"},
+		// 100 * 0.92345 = 92.345.
+		{"100", "0.92345", 2, RoundHalfEven, "92.34"},
+		{"100", "0.92345", 2, RoundHalfUp, "92.35"},
+		// 33.33 * 1.0999 = 36.659667.
+		{"33.33", "1.0999", 2, RoundHalfEven, "36.66"},
+		{"33.33", "1.0999", 2, RoundDown, "36.65"},
+		// A zero-decimal currency.
+		{"12.34", "149.5", 0, RoundHalfEven, "1845"},
+		{"12.34", "149.5", 0, RoundDown, "1844"},
+	}
+
+	for _, tt := range tests {
+		got := Convert(decimal.RequireFromString(tt.amount), decimal.RequireFromString(tt.rate), tt.scale, tt.mode)
+		if got.StringFixed(tt.scale) != tt.want {
+			t.Errorf("Convert(%s, %s, %d, %s) = %s, want %s", tt.amount, tt.rate, tt.scale, tt.mode, got.StringFixed(tt.scale), tt.want)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for input, want := range map[string]RoundingMode{
+		"half_up":   RoundHalfUp,
+		"down":      RoundDown,
+		"half_even": RoundHalfEven,
+		"":          RoundHalfEven,
+		"bogus":     RoundHalfEven,
+	} {
+		if got := ParseRoundingMode(input); got != want {
+			t.Errorf("ParseRoundingMode(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount string
+		scale  int32
+		want   string
+	}{
+		{"12.3456", 2, "12.35"},
+		{"12.345", 2, "12.34"},
+		{"12.355", 2, "12.36"},
+		{"12", 2, "12.00"},
+		{"12.5", 0, "12"},
+		{"-0.125", 2, "-0.12"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(decimal.RequireFromString(tt.amount), tt.scale); got != tt.want {
+			t.Errorf("Format(%s, %d) = %s, want %s", tt.amount, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFullKeepsStoredPrecision(t *testing.T) {
+	amount := decimal.RequireFromString("12.3456")
+
+	if got := FormatFull(amount); got != "12.3456" {
+		t.Fatalf("FormatFull(12.3456) = %s, want 12.3456", got)
+	}
+	if Format(amount, DisplayScale) == FormatFull(amount) {
+		t.Fatal("display and full formats agree for an amount with sub-cent precision")
+	}
+}
@@ -11,6 +11,9 @@ const (
 type Hasher interface {
 	Hash(password string) (string, error)
 	Compare(hashedPassword, password string) error
+	// NeedsRehash reports whether hashedPassword was made at a lower cost
+	// than the hasher's, so it should be replaced once the password is known.
+	NeedsRehash(hashedPassword string) bool
 }
 
 type bcryptHasher struct {
@@ -43,3 +46,11 @@ func (h *bcryptHasher) Hash(password string) (string, error) {
 func (h *bcryptHasher) Compare(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
+
+func (h *bcryptHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return false
+	}
+	return cost < h.cost
+}
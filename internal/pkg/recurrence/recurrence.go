@@ -0,0 +1,157 @@
+package recurrence
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type Interval string
+
+const (
+	Daily   Interval = "daily"
+	Weekly  Interval = "weekly"
+	Monthly Interval = "monthly"
+)
+
+var (
+	ErrInvalidInterval = errors.New("invalid recurrence interval")
+	ErrInvalidTimezone = errors.New("invalid timezone")
+)
+
+func (i Interval) Valid() bool {
+	switch i {
+	case Daily, Weekly, Monthly:
+		return true
+	}
+	return false
+}
+
+// LoadLocation resolves an IANA timezone name such as "Europe/Berlin". Empty
+// and "Local" are rejected since both would silently mean the server's zone.
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return nil, ErrInvalidTimezone
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// Schedule is a recurrence anchored at its first run. Runs keep the anchor's
+// wall-clock time in Location, so a 09:00 run stays at 09:00 local across DST
+// changes. Every run is computed from the anchor rather than the previous run,
+// so a monthly schedule starting on Jan 31 runs on Feb 28 (or 29) and then
+// Mar 31 instead of drifting to the 28th.
+type Schedule struct {
+	Interval Interval
+	Start    time.Time
+	Location *time.Location
+}
+
+func New(interval Interval, start time.Time, timezone string) (*Schedule, error) {
+	if !interval.Valid() {
+		return nil, ErrInvalidInterval
+	}
+
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schedule{Interval: interval, Start: start, Location: loc}, nil
+}
+
+// Occurrence returns the n-th run in UTC, where run 0 is Start. A wall-clock
+// time skipped by a DST jump moves forward by the size of the jump; one that
+// occurs twice resolves to the first.
+func (s *Schedule) Occurrence(n int) time.Time {
+	local := s.Start.In(s.Location)
+	year, month, day := local.Date()
+	hour, minute, sec := local.Clock()
+	nsec := local.Nanosecond()
+
+	switch s.Interval {
+	case Weekly:
+		day += 7 * n
+	case Monthly:
+		month += time.Month(n)
+		if last := daysIn(year, month); day > last {
+			day = last
+		}
+	default:
+		day += n
+	}
+
+	run := time.Date(year, month, day, hour, minute, sec, nsec, s.Location)
+
+	// time.Date leaves the choice of offset around a DST change unspecified.
+	// Push a skipped wall-clock time forward past the gap, and resolve a
+	// repeated one to its first occurrence.
+	want := time.Date(year, month, day, hour, minute, sec, nsec, time.UTC)
+	if skew := want.Sub(wallClock(run, s.Location)); skew > 0 {
+		run = run.Add(skew)
+	} else {
+		_, before := run.Add(-12 * time.Hour).Zone()
+		_, at := run.Zone()
+		earlier := run.Add(-time.Duration(before-at) * time.Second)
+		if before > at && wallClock(earlier, s.Location).Equal(want) {
+			run = earlier
+		}
+	}
+
+	return run.UTC()
+}
+
+// wallClock returns t's local date and time in loc as if it were UTC, so two
+// wall-clock readings can be compared or subtracted.
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// Next returns the first run strictly after t.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if t.Before(s.Start) {
+		return s.Occurrence(0)
+	}
+
+	n := s.estimate(t)
+	for n > 0 && !s.Occurrence(n).Before(t) {
+		n--
+	}
+	for {
+		next := s.Occurrence(n)
+		if next.After(t) {
+			return next
+		}
+		n++
+	}
+}
+
+// estimate approximates the index of the last run before t, so Next does not
+// have to walk every run since Start.
+func (s *Schedule) estimate(t time.Time) int {
+	elapsed := t.Sub(s.Start)
+
+	switch s.Interval {
+	case Weekly:
+		return int(elapsed / (7 * 24 * time.Hour))
+	case Monthly:
+		from := s.Start.In(s.Location)
+		to := t.In(s.Location)
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	default:
+		return int(elapsed / (24 * time.Hour))
+	}
+}
+
+// daysIn returns the number of days in month, which may be out of range and
+// is normalized the way time.Date does.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,122 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustSchedule(t *testing.T, interval Interval, start time.Time, timezone string) *Schedule {
+	t.Helper()
+
+	s, err := New(interval, start, timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func utc(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestMonthlyEndOfMonthRollover(t *testing.T) {
+	// Jan 31 09:00 in Berlin.
+	s := mustSchedule(t, Monthly, utc("2024-01-31T08:00:00Z"), "Europe/Berlin")
+
+	want := []string{
+		"2024-01-31T08:00:00Z",
+		"2024-02-29T08:00:00Z", // leap year
+		"2024-03-31T07:00:00Z", // back to the 31st, now in summer time
+		"2024-04-30T07:00:00Z",
+	}
+	for n, w := range want {
+		if got := s.Occurrence(n); !got.Equal(utc(w)) {
+			t.Errorf("run %d = %s, want %s", n, got.Format(time.RFC3339), w)
+		}
+	}
+
+	// Outside a leap year February is cut to the 28th.
+	s = mustSchedule(t, Monthly, utc("2023-01-31T08:00:00Z"), "Europe/Berlin")
+	if got := s.Occurrence(1); !got.Equal(utc("2023-02-28T08:00:00Z")) {
+		t.Errorf("Feb 2023 run = %s, want 2023-02-28T08:00:00Z", got.Format(time.RFC3339))
+	}
+}
+
+func TestMonthlyFollowsLocalDateNotUTC(t *testing.T) {
+	// 00:30 on the 1st in Tokyo is still the previous month in UTC.
+	s := mustSchedule(t, Monthly, utc("2024-01-31T15:30:00Z"), "Asia/Tokyo")
+
+	if got := s.Occurrence(1); !got.Equal(utc("2024-02-29T15:30:00Z")) {
+		t.Fatalf("second run = %s, want 2024-03-01 00:30 Tokyo", got.Format(time.RFC3339))
+	}
+}
+
+func TestDailyKeepsLocalTimeAcrossDST(t *testing.T) {
+	// 09:00 in New York the day before clocks go forward on 2024-03-10.
+	s := mustSchedule(t, Daily, utc("2024-03-09T14:00:00Z"), "America/New_York")
+
+	if got := s.Occurrence(1); !got.Equal(utc("2024-03-10T13:00:00Z")) {
+		t.Fatalf("run after spring forward = %s, want 09:00 EDT", got.Format(time.RFC3339))
+	}
+
+	// And back again on 2024-11-03.
+	s = mustSchedule(t, Daily, utc("2024-11-02T13:00:00Z"), "America/New_York")
+	if got := s.Occurrence(1); !got.Equal(utc("2024-11-03T14:00:00Z")) {
+		t.Fatalf("run after fall back = %s, want 09:00 EST", got.Format(time.RFC3339))
+	}
+}
+
+func TestSkippedLocalTimeMovesPastGap(t *testing.T) {
+	// 02:30 does not exist in New York on 2024-03-10.
+	s := mustSchedule(t, Daily, utc("2024-03-09T07:30:00Z"), "America/New_York")
+
+	if got := s.Occurrence(1); !got.Equal(utc("2024-03-10T07:30:00Z")) {
+		t.Fatalf("skipped run = %s, want 03:30 EDT", got.Format(time.RFC3339))
+	}
+	if got := s.Occurrence(2); !got.Equal(utc("2024-03-11T06:30:00Z")) {
+		t.Fatalf("run after gap = %s, want 02:30 EDT", got.Format(time.RFC3339))
+	}
+}
+
+func TestRepeatedLocalTimeResolvesToFirst(t *testing.T) {
+	// 01:30 happens twice in New York on 2024-11-03.
+	s := mustSchedule(t, Daily, utc("2024-11-02T05:30:00Z"), "America/New_York")
+
+	if got := s.Occurrence(1); !got.Equal(utc("2024-11-03T05:30:00Z")) {
+		t.Fatalf("repeated run = %s, want the first 01:30 (EDT)", got.Format(time.RFC3339))
+	}
+}
+
+func TestNext(t *testing.T) {
+	s := mustSchedule(t, Monthly, utc("2024-01-31T08:00:00Z"), "Europe/Berlin")
+
+	tests := map[string]string{
+		"2023-12-01T00:00:00Z": "2024-01-31T08:00:00Z", // before the start
+		"2024-01-31T08:00:00Z": "2024-02-29T08:00:00Z", // strictly after a run
+		"2024-03-01T00:00:00Z": "2024-03-31T07:00:00Z",
+		"2025-02-28T08:00:00Z": "2025-03-31T07:00:00Z",
+	}
+	for after, want := range tests {
+		if got := s.Next(utc(after)); !got.Equal(utc(want)) {
+			t.Errorf("Next(%s) = %s, want %s", after, got.Format(time.RFC3339), want)
+		}
+	}
+}
+
+func TestNewRejectsInvalidInput(t *testing.T) {
+	start := utc("2024-01-01T00:00:00Z")
+
+	for _, timezone := range []string{"", "Local", " local ", "Mars/Olympus", "+02:00"} {
+		if _, err := New(Daily, start, timezone); !errors.Is(err, ErrInvalidTimezone) {
+			t.Errorf("timezone %q: err = %v, want ErrInvalidTimezone", timezone, err)
+		}
+	}
+	if _, err := New("yearly", start, "UTC"); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("interval yearly: err = %v, want ErrInvalidInterval", err)
+	}
+}
@@ -0,0 +1,79 @@
+// Package redact masks credentials in JSON documents so they can be logged.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Mask replaces the value of every redacted field.
+const Mask = "[REDACTED]"
+
+// fields are the JSON keys whose values are never logged, matched
+// case-insensitively at any depth.
+var fields = map[string]bool{
+	"password":         true,
+	"current_password": true,
+	"new_password":     true,
+	"access_token":     true,
+	"refresh_token":    true,
+	"challenge_token":  true,
+	"token":            true,
+	"secret":           true,
+	"otpauth_uri":      true,
+	"key":              true,
+}
+
+// requestFields are redacted in request bodies only. There "code" is a
+// 2FA or verification code; in responses it names an error or validation
+// failure, which the logs need.
+var requestFields = map[string]bool{
+	"code": true,
+}
+
+// JSON returns data with the values of credential fields replaced by Mask.
+// It reports false if data is not a single JSON value, in which case
+// nothing of it is safe to log.
+func JSON(data []byte) ([]byte, bool) {
+	return redactJSON(data, nil)
+}
+
+// Request is JSON for a request body, which may also carry one-time codes.
+func Request(data []byte) ([]byte, bool) {
+	return redactJSON(data, requestFields)
+}
+
+func redactJSON(data []byte, extra map[string]bool) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		return nil, false
+	}
+
+	redacted, err := json.Marshal(walk(doc, extra))
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+func walk(v interface{}, extra map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if name := strings.ToLower(key); fields[name] || extra[name] {
+				v[key] = Mask
+				continue
+			}
+			v[key] = walk(value, extra)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = walk(value, extra)
+		}
+	}
+	return v
+}
@@ -0,0 +1,40 @@
+package redact
+
+import "testing"
+
+func TestRedaction(t *testing.T) {
+	tests := []struct {
+		name   string
+		redact func([]byte) ([]byte, bool)
+		in     string
+		want   string
+	}{
+		{"request credentials", Request,
+			`{"email":"a@example.com","Password":"hunter2"}`,
+			`{"Password":"[REDACTED]","email":"a@example.com"}`},
+		{"request 2FA code", Request,
+			`{"challenge_token":"abc","code":"123456"}`,
+			`{"challenge_token":"[REDACTED]","code":"[REDACTED]"}`},
+		{"nested response token", JSON,
+			`{"data":[{"access_token":"abc","expires_in":900}]}`,
+			`{"data":[{"access_token":"[REDACTED]","expires_in":900}]}`},
+		{"response error code", JSON,
+			`{"error":{"code":"VALIDATION_ERROR","details":[{"field":"amount","code":"required"}]}}`,
+			`{"error":{"code":"VALIDATION_ERROR","details":[{"code":"required","field":"amount"}]}}`},
+	}
+
+	for _, tt := range tests {
+		got, ok := tt.redact([]byte(tt.in))
+		if !ok || string(got) != tt.want {
+			t.Errorf("%s: got %s, %v; want %s", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestRedactionRejectsNonJSON(t *testing.T) {
+	for _, in := range []string{`password=hunter2`, `{"a":1} {"b":2}`, ``} {
+		if _, ok := JSON([]byte(in)); ok {
+			t.Errorf("JSON(%q) reported ok", in)
+		}
+	}
+}
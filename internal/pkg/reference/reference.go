@@ -0,0 +1,35 @@
+package reference
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+)
+
+// Crockford base32 alphabet; omits I, L, O and U to avoid misreading.
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Check symbols extend the alphabet to 37 characters for the mod-37 check.
+const checkAlphabet = alphabet + "*~$=U"
+
+const bodyLength = 10
+
+// New returns a random reference number of ten base32 characters followed by
+// a Crockford check character.
+func New() (string, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+
+	n := binary.BigEndian.Uint64(buf[:]) >> (64 - bodyLength*5)
+
+	var sb strings.Builder
+	sb.Grow(bodyLength + 1)
+	for i := bodyLength - 1; i >= 0; i-- {
+		sb.WriteByte(alphabet[(n>>(uint(i)*5))&0x1f])
+	}
+	sb.WriteByte(checkAlphabet[n%37])
+
+	return sb.String(), nil
+}
@@ -0,0 +1,44 @@
+package reference
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewIsWellFormed(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		ref, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ref) != bodyLength+1 {
+			t.Fatalf("%q has %d characters, want %d", ref, len(ref), bodyLength+1)
+		}
+
+		var n uint64
+		for _, c := range ref[:bodyLength] {
+			digit := strings.IndexRune(alphabet, c)
+			if digit < 0 {
+				t.Fatalf("%q contains %q, which is not in the alphabet", ref, c)
+			}
+			n = n<<5 | uint64(digit)
+		}
+		if want := checkAlphabet[n%37]; ref[bodyLength] != want {
+			t.Fatalf("%q has check character %q, want %q", ref, ref[bodyLength], want)
+		}
+	}
+}
+
+func TestNewIsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100000; i++ {
+		ref, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[ref] {
+			t.Fatalf("%q generated twice in %d references", ref, i+1)
+		}
+		seen[ref] = true
+	}
+}
@@ -2,10 +2,16 @@ package token
 
 import (
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -30,24 +36,131 @@ type JWTManager interface {
 	GenerateRefreshToken() (string, string, error)
 	ValidateAccessToken(tokenString string) (*Claims, error)
 	HashRefreshToken(token string) string
+	// JWKS returns the public keys that validate access tokens. It is empty
+	// for HS256, whose keys are secret.
+	JWKS() JWKS
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is an RSA public key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
 }
 
 type jwtManager struct {
-	secretKey          []byte
+	method             jwt.SigningMethod
+	keyID              string
+	signingKey         interface{}
+	verifyKeys         map[string]interface{}
 	accessTokenExpiry  time.Duration
 	refreshTokenExpiry time.Duration
 	issuer             string
 }
 
-func NewJWTManager(secretKey string, accessExpiry, refreshExpiry time.Duration, issuer string) JWTManager {
+// NewJWTManager returns an HS256 manager that signs with secretKey under
+// keyID and also accepts tokens signed with any of additionalKeys, keyed by
+// key ID. To rotate, move the current secret into additionalKeys under its
+// key ID and configure a new primary; drop the old one once the tokens it
+// signed have expired.
+func NewJWTManager(keyID, secretKey string, additionalKeys map[string]string, accessExpiry, refreshExpiry time.Duration, issuer string) JWTManager {
+	keys := make(map[string]interface{}, len(additionalKeys)+1)
+	for kid, secret := range additionalKeys {
+		keys[kid] = []byte(secret)
+	}
+	keys[keyID] = []byte(secretKey)
+
 	return &jwtManager{
-		secretKey:          []byte(secretKey),
+		method:             jwt.SigningMethodHS256,
+		keyID:              keyID,
+		signingKey:         []byte(secretKey),
+		verifyKeys:         keys,
 		accessTokenExpiry:  accessExpiry,
 		refreshTokenExpiry: refreshExpiry,
 		issuer:             issuer,
 	}
 }
 
+// NewRS256JWTManager returns a manager that signs with privateKey under
+// keyID and validates with its public key or any of additionalKeys. Only
+// public keys are needed to validate, so other services can verify tokens
+// from the JWKS without being able to mint them. Rotation works as for
+// NewJWTManager, keeping the old public key in additionalKeys.
+func NewRS256JWTManager(keyID string, privateKey *rsa.PrivateKey, additionalKeys map[string]*rsa.PublicKey, accessExpiry, refreshExpiry time.Duration, issuer string) JWTManager {
+	keys := make(map[string]interface{}, len(additionalKeys)+1)
+	for kid, key := range additionalKeys {
+		keys[kid] = key
+	}
+	keys[keyID] = &privateKey.PublicKey
+
+	return &jwtManager{
+		method:             jwt.SigningMethodRS256,
+		keyID:              keyID,
+		signingKey:         privateKey,
+		verifyKeys:         keys,
+		accessTokenExpiry:  accessExpiry,
+		refreshTokenExpiry: refreshExpiry,
+		issuer:             issuer,
+	}
+}
+
+// LoadRSAPrivateKey reads a PEM-encoded RSA private key (PKCS#1 or PKCS#8).
+func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(data)
+}
+
+// LoadRSAPublicKeys reads the PEM-encoded RSA public key at each path, keyed
+// by key ID.
+func LoadRSAPublicKeys(paths map[string]string) (map[string]*rsa.PublicKey, error) {
+	keys := make(map[string]*rsa.PublicKey, len(paths))
+	for kid, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", kid, err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+	return keys, nil
+}
+
+// ParseKeys parses comma-separated KID=VALUE pairs, as in
+// JWT_ADDITIONAL_KEYS.
+func ParseKeys(raw string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(pair, "=")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("invalid key %q: want KID=VALUE", kid)
+		}
+		if _, dup := keys[kid]; dup {
+			return nil, fmt.Errorf("duplicate key ID %q", kid)
+		}
+		keys[kid] = secret
+	}
+	return keys, nil
+}
+
 func (m *jwtManager) GenerateAccessToken(userID uuid.UUID, email, role string) (string, error) {
 	now := time.Now()
 	claims := &Claims{
@@ -63,8 +176,9 @@ func (m *jwtManager) GenerateAccessToken(userID uuid.UUID, email, role string) (
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secretKey)
+	token := jwt.NewWithClaims(m.method, claims)
+	token.Header["kid"] = m.keyID
+	return token.SignedString(m.signingKey)
 }
 
 func (m *jwtManager) GenerateRefreshToken() (string, string, error) {
@@ -80,12 +194,14 @@ func (m *jwtManager) GenerateRefreshToken() (string, string, error) {
 }
 
 func (m *jwtManager) ValidateAccessToken(tokenString string) (*Claims, error) {
+	// Only the configured algorithm is accepted, so an RS256 public key can
+	// never be used as an HMAC secret and vice versa.
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != m.method.Alg() {
 			return nil, ErrInvalidSignature
 		}
-		return m.secretKey, nil
-	})
+		return m.keyFor(token)
+	}, jwt.WithValidMethods([]string{m.method.Alg()}))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -102,6 +218,45 @@ func (m *jwtManager) ValidateAccessToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// keyFor returns the key named by the token's kid header. Tokens issued
+// before key IDs were introduced have none and are checked against the
+// primary key.
+func (m *jwtManager) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"]
+	if !ok {
+		return m.verifyKeys[m.keyID], nil
+	}
+	name, ok := kid.(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	key, ok := m.verifyKeys[name]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return key, nil
+}
+
+func (m *jwtManager) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for kid, key := range m.verifyKeys {
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: m.method.Alg(),
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
 func (m *jwtManager) HashRefreshToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(hash[:])
@@ -0,0 +1,78 @@
+package token
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServiceTokenRoundTrip(t *testing.T) {
+	manager := NewServiceTokenManager("secret", time.Minute)
+	now := time.Now()
+
+	tokenString, err := manager.Generate("ledger-sync", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := manager.Verify(tokenString, now.Add(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service != "ledger-sync" {
+		t.Fatalf("service = %q, want ledger-sync", service)
+	}
+}
+
+func TestServiceTokenRejected(t *testing.T) {
+	manager := NewServiceTokenManager("secret", time.Minute)
+	now := time.Now()
+
+	valid, err := manager.Generate("ledger-sync", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := NewServiceTokenManager("guessed", time.Minute).Generate("ledger-sync", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		at    time.Time
+		want  error
+	}{
+		{"other secret", forged, now, ErrInvalidSignature},
+		{"renamed service", "admin" + valid[len("ledger-sync"):], now, ErrInvalidSignature},
+		{"expired", valid, now.Add(2 * time.Minute), ErrExpiredToken},
+		{"issued in the future", valid, now.Add(-2 * time.Minute), ErrExpiredToken},
+		{"malformed", "ledger-sync", now, ErrInvalidToken},
+		{"empty service", "." + valid[len("ledger-sync."):], now, ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		if _, err := manager.Verify(tt.token, tt.at); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Verify = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestServiceTokenDisabledWithoutSecret(t *testing.T) {
+	manager := NewServiceTokenManager("", time.Minute)
+
+	if manager.Enabled() {
+		t.Fatal("manager without a secret is enabled")
+	}
+	if _, err := manager.Generate("ledger-sync", time.Now()); !errors.Is(err, ErrServiceTokenDisabled) {
+		t.Fatalf("Generate = %v, want ErrServiceTokenDisabled", err)
+	}
+	if _, err := manager.Verify("ledger-sync.0.abc", time.Now()); !errors.Is(err, ErrServiceTokenDisabled) {
+		t.Fatalf("Verify = %v, want ErrServiceTokenDisabled", err)
+	}
+}
+
+func TestServiceTokenNameCannotContainSeparator(t *testing.T) {
+	if _, err := NewServiceTokenManager("secret", time.Minute).Generate("a.b", time.Now()); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Generate(a.b) = %v, want ErrInvalidToken", err)
+	}
+}
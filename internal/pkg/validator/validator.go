@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
 	"github.com/yourusername/gobank/internal/pkg/apperror"
 )
 
@@ -27,9 +29,34 @@ func New() Validator {
 		return name
 	})
 
+	mustRegister(v, "decimalgt", decimalGreaterThan)
+	mustRegister(v, "currency", supportedCurrency)
+
 	return &customValidator{validate: v}
 }
 
+func mustRegister(v *validator.Validate, tag string, fn validator.Func) {
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		panic(err)
+	}
+}
+
+// decimalGreaterThan checks that a string field is a decimal number greater
+// than the tag's parameter, as in decimalgt=0.
+func decimalGreaterThan(fl validator.FieldLevel) bool {
+	value, err := decimal.NewFromString(fl.Field().String())
+	if err != nil {
+		return false
+	}
+	return value.GreaterThan(decimal.RequireFromString(fl.Param()))
+}
+
+// supportedCurrency checks that a field holds a currency accounts can be
+// opened in.
+func supportedCurrency(fl validator.FieldLevel) bool {
+	return entity.Currency(fl.Field().String()).IsValid()
+}
+
 func (cv *customValidator) Validate(i interface{}) []apperror.ValidationError {
 	var errors []apperror.ValidationError
 
@@ -47,8 +74,12 @@ func (cv *customValidator) Validate(i interface{}) []apperror.ValidationError {
 				message = "Value is too long (maximum: " + err.Param() + ")"
 			case "oneof":
 				message = "Value must be one of: " + err.Param()
+			case "required_without":
+				message = "This field is required when " + err.Param() + " is not given"
 			case "nefield":
 				message = "Value must be different from " + err.Param()
+			case "timezone":
+				message = "Invalid IANA timezone (e.g. Europe/London)"
 			case "uuid":
 				message = "Invalid UUID format"
 			case "gt":
@@ -59,12 +90,18 @@ func (cv *customValidator) Validate(i interface{}) []apperror.ValidationError {
 				message = "Value must be less than " + err.Param()
 			case "lte":
 				message = "Value must be less than or equal to " + err.Param()
+			case "decimalgt":
+				message = "Value must be a decimal number greater than " + err.Param()
+			case "currency":
+				message = "Unsupported currency"
 			default:
 				message = "Validation failed for " + err.Tag()
 			}
 
 			errors = append(errors, apperror.ValidationError{
 				Field:   err.Field(),
+				Code:    err.Tag(),
+				Param:   err.Param(),
 				Message: message,
 			})
 		}
@@ -0,0 +1,141 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gobank/internal/domain/service"
+)
+
+// Cache is an in-memory service.CacheService for tests that do not need
+// Redis itself. Like the Redis cache, a missing key reads as "" and values
+// other than strings are stored as JSON.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+var _ service.CacheService = (*Cache)(nil)
+
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]cacheEntry)}
+}
+
+func (c *Cache) Get(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.live(key)
+	if !ok {
+		return "", nil
+	}
+	return entry.value, nil
+}
+
+func (c *Cache) Set(_ context.Context, key string, value interface{}, ttlSeconds int) error {
+	entry, err := newCacheEntry(value, ttlSeconds)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+func (c *Cache) SetNX(_ context.Context, key string, value interface{}, ttlSeconds int) (bool, error) {
+	entry, err := newCacheEntry(value, ttlSeconds)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.live(key); ok {
+		return false, nil
+	}
+	c.entries[key] = entry
+	return true, nil
+}
+
+func (c *Cache) GetDel(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.live(key)
+	delete(c.entries, key)
+	if !ok {
+		return "", nil
+	}
+	return entry.value, nil
+}
+
+func (c *Cache) Incr(_ context.Context, key string, ttlSeconds int) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.live(key)
+	if !ok {
+		entry, _ = newCacheEntry("0", ttlSeconds)
+	}
+	count, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	count++
+	entry.value = strconv.FormatInt(count, 10)
+	c.entries[key] = entry
+	return count, nil
+}
+
+func (c *Cache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
+	value, err := c.Get(ctx, key)
+	return value != "", err
+}
+
+// live returns the entry at key unless it is missing or expired. An expired
+// entry is dropped. c.mu must be held.
+func (c *Cache) live(key string) (cacheEntry, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func newCacheEntry(value interface{}, ttlSeconds int) (cacheEntry, error) {
+	data, ok := value.(string)
+	if !ok {
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return cacheEntry{}, err
+		}
+		data = string(bytes)
+	}
+
+	entry := cacheEntry{value: data}
+	if ttlSeconds > 0 {
+		entry.expiresAt = time.Now().Add(time.Duration(ttlSeconds) * time.Second)
+	}
+	return entry, nil
+}
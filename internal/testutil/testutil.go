@@ -0,0 +1,136 @@
+// Package testutil connects tests to the Postgres and Redis services CI
+// provides. Tests that need one are skipped when its host is not configured,
+// so `go test ./...` still runs everything else on a machine without them.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+)
+
+// Postgres creates a database for the test on the server named by DB_HOST,
+// applies every migration to it and drops it when the test ends. The test is
+// skipped if DB_HOST is not set.
+func Postgres(t testing.TB) *database.PostgresDB {
+	t.Helper()
+
+	if os.Getenv("DB_HOST") == "" {
+		t.Skip("DB_HOST not set; skipping Postgres test")
+	}
+	ctx := context.Background()
+
+	cfg := config.DatabaseConfig{
+		Host:            os.Getenv("DB_HOST"),
+		Port:            env("DB_PORT", "5432"),
+		User:            env("DB_USER", "postgres"),
+		Password:        env("DB_PASSWORD", "postgres"),
+		DBName:          env("DB_NAME", "postgres"),
+		SSLMode:         env("DB_SSLMODE", "disable"),
+		MaxOpenConns:    10,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: time.Hour,
+	}
+
+	admin, err := database.NewPostgresDB(ctx, &cfg)
+	if err != nil {
+		t.Fatalf("connect to Postgres: %v", err)
+	}
+
+	name := "gobank_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := admin.Pool.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		admin.Close()
+		t.Fatalf("create test database: %v", err)
+	}
+
+	cfg.DBName = name
+	db, err := database.NewPostgresDB(ctx, &cfg)
+	if err != nil {
+		admin.Close()
+		t.Fatalf("connect to test database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+		_, _ = admin.Pool.Exec(context.Background(), "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)")
+		admin.Close()
+	})
+
+	for _, file := range migrationFiles(t) {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read migration: %v", err)
+		}
+		if _, err := db.Pool.Exec(ctx, string(sql)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(file), err)
+		}
+	}
+
+	return db
+}
+
+// Redis connects to the server named by REDIS_HOST. The test is skipped if
+// REDIS_HOST is not set. The server is shared with other tests running at
+// the same time, so tests should use keys of their own, e.g. from Key.
+func Redis(t testing.TB) *database.RedisDB {
+	t.Helper()
+
+	if os.Getenv("REDIS_HOST") == "" {
+		t.Skip("REDIS_HOST not set; skipping Redis test")
+	}
+
+	db, err := database.NewRedisDB(context.Background(), &config.RedisConfig{
+		Host:     os.Getenv("REDIS_HOST"),
+		Port:     env("REDIS_PORT", "6379"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+	})
+	if err != nil {
+		t.Fatalf("connect to Redis: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+// Key returns a key no other test uses.
+func Key(t testing.TB) string {
+	return fmt.Sprintf("test:%s:%s", t.Name(), uuid.NewString())
+}
+
+// Logger returns a logger that discards everything.
+func Logger() *logger.Logger {
+	log := zerolog.Nop()
+	return &logger.Logger{Logger: &log}
+}
+
+// migrationFiles lists the up migrations in the order they apply.
+func migrationFiles(t testing.TB) []string {
+	t.Helper()
+
+	_, file, _, _ := runtime.Caller(0)
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(file), "..", "..", "migrations", "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("find migrations: %v", err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func env(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,105 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestGetAllTransactionsAcrossAccounts(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	checking := h.account(t, userID, "USD", "0")
+	savings := h.account(t, userID, "EUR", "0")
+	someoneElses := h.account(t, h.user(t), "USD", "0")
+
+	deposit := h.transaction(t, checking.ID, entity.TransactionTypeCredit, "100", nil)
+	withdrawal := h.transaction(t, checking.ID, entity.TransactionTypeDebit, "20", nil)
+	saved := h.transaction(t, savings.ID, entity.TransactionTypeCredit, "50", nil)
+	h.transaction(t, someoneElses.ID, entity.TransactionTypeCredit, "999", nil)
+	for i, id := range []uuid.UUID{deposit.ID, withdrawal.ID, saved.ID} {
+		at := time.Now().Add(time.Duration(i-3) * time.Hour)
+		if _, err := h.db.Pool.Exec(ctx, `UPDATE transactions SET created_at = $2 WHERE id = $1`, id, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transactions, total, err := h.service.GetAllTransactions(ctx, userID, 1, 10, &entity.TransactionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(transactions) != 3 {
+		t.Fatalf("got %d of %d transactions, want 3 of 3", len(transactions), total)
+	}
+
+	// Newest first, each with the account it belongs to.
+	want := []struct {
+		id      uuid.UUID
+		account *entity.Account
+	}{
+		{saved.ID, savings},
+		{withdrawal.ID, checking},
+		{deposit.ID, checking},
+	}
+	for i, w := range want {
+		got := transactions[i]
+		if got.Transaction.ID != w.id || got.AccountNumber != w.account.AccountNumber || got.Currency != w.account.Currency {
+			t.Errorf("transaction %d = %s on %s (%s), want %s on %s (%s)", i,
+				got.Transaction.ID, got.AccountNumber, got.Currency, w.id, w.account.AccountNumber, w.account.Currency)
+		}
+	}
+
+	page, total, err := h.service.GetAllTransactions(ctx, userID, 2, 2, &entity.TransactionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(page) != 1 || page[0].Transaction.ID != deposit.ID {
+		t.Fatalf("second page of two = %d items of %d, want the oldest deposit alone", len(page), total)
+	}
+}
+
+func TestGetAllTransactionsFilters(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	checking := h.account(t, userID, "USD", "0")
+	savings := h.account(t, userID, "EUR", "0")
+
+	old := h.transaction(t, checking.ID, entity.TransactionTypeCredit, "100", nil)
+	if _, err := h.db.Pool.Exec(ctx, `UPDATE transactions SET created_at = $2 WHERE id = $1`, old.ID, time.Now().AddDate(0, -2, 0)); err != nil {
+		t.Fatal(err)
+	}
+	recent := h.transaction(t, savings.ID, entity.TransactionTypeCredit, "50", nil)
+	h.transaction(t, checking.ID, entity.TransactionTypeDebit, "20", nil)
+
+	credits := entity.TransactionTypeCredit
+	transactions, total, err := h.service.GetAllTransactions(ctx, userID, 1, 10, &entity.TransactionFilter{Type: &credits})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(transactions) != 2 {
+		t.Fatalf("credits = %d of %d, want 2 of 2", len(transactions), total)
+	}
+
+	since := time.Now().AddDate(0, -1, 0)
+	transactions, total, err = h.service.GetAllTransactions(ctx, userID, 1, 10, &entity.TransactionFilter{Type: &credits, StartDate: &since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(transactions) != 1 || transactions[0].Transaction.ID != recent.ID {
+		t.Fatalf("credits in the last month = %d of %d, want only the recent one", len(transactions), total)
+	}
+
+	until := since.AddDate(0, 0, -7)
+	if _, _, err := h.service.GetAllTransactions(ctx, userID, 1, 10, &entity.TransactionFilter{StartDate: &since, EndDate: &until}); !errors.Is(err, apperror.ErrBadRequest) {
+		t.Fatalf("start after end = %v, want ErrBadRequest", err)
+	}
+}
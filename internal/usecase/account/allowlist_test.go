@@ -0,0 +1,104 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestManageAllowedDestinations(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "100")
+	byID := h.account(t, h.user(t), "USD", "0")
+	byNumber := h.account(t, h.user(t), "USD", "0")
+
+	destinations, err := h.service.ListAllowedDestinations(ctx, userID, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destinations == nil || len(destinations) != 0 {
+		t.Fatalf("new account allowlist = %v, want empty", destinations)
+	}
+
+	if _, err := h.service.AddAllowedDestination(ctx, userID, account.ID, &entity.AddAllowedDestinationInput{AccountID: byID.ID}); err != nil {
+		t.Fatal(err)
+	}
+	destinations, err = h.service.AddAllowedDestination(ctx, userID, account.ID, &entity.AddAllowedDestinationInput{AccountNumber: byNumber.AccountNumber})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destinations) != 2 {
+		t.Fatalf("allowlist has %d entries, want 2", len(destinations))
+	}
+
+	allowed, err := h.service.allowlistRepo.IsAllowed(ctx, account.ID, byNumber.ID)
+	if err != nil || !allowed {
+		t.Fatalf("IsAllowed(added by number) = %v, %v; want true", allowed, err)
+	}
+
+	if err := h.service.RemoveAllowedDestination(ctx, userID, account.ID, byID.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.service.RemoveAllowedDestination(ctx, userID, account.ID, byID.ID); !errors.Is(err, apperror.ErrNotFound) {
+		t.Fatalf("removing an absent destination = %v, want ErrNotFound", err)
+	}
+
+	destinations, err = h.service.ListAllowedDestinations(ctx, userID, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destinations) != 1 || destinations[0].AccountID != byNumber.ID {
+		t.Fatalf("allowlist after removal = %+v, want only %s", destinations, byNumber.ID)
+	}
+}
+
+func TestAllowedDestinationsAreOwnerOnly(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	owner := h.user(t)
+	account := h.account(t, owner, "USD", "100")
+	destination := h.account(t, h.user(t), "USD", "0")
+	stranger := h.user(t)
+
+	if _, err := h.service.ListAllowedDestinations(ctx, stranger, account.ID); !errors.Is(err, apperror.ErrForbidden) {
+		t.Fatalf("list by another user = %v, want ErrForbidden", err)
+	}
+	if _, err := h.service.AddAllowedDestination(ctx, stranger, account.ID, &entity.AddAllowedDestinationInput{AccountID: destination.ID}); !errors.Is(err, apperror.ErrForbidden) {
+		t.Fatalf("add by another user = %v, want ErrForbidden", err)
+	}
+	if err := h.service.RemoveAllowedDestination(ctx, stranger, account.ID, destination.ID); !errors.Is(err, apperror.ErrForbidden) {
+		t.Fatalf("remove by another user = %v, want ErrForbidden", err)
+	}
+}
+
+func TestAddAllowedDestinationRejectsBadTargets(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "100")
+	other := h.account(t, h.user(t), "USD", "0")
+
+	tests := []struct {
+		name  string
+		input *entity.AddAllowedDestinationInput
+		want  error
+	}{
+		{"own account", &entity.AddAllowedDestinationInput{AccountID: account.ID}, apperror.ErrSameAccount},
+		{"unknown account", &entity.AddAllowedDestinationInput{AccountID: uuid.New()}, apperror.ErrAccountNotFound},
+		{"mismatched ID and number", &entity.AddAllowedDestinationInput{AccountID: uuid.New(), AccountNumber: other.AccountNumber}, apperror.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		if _, err := h.service.AddAllowedDestination(ctx, userID, account.ID, tt.input); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/reference"
+)
+
+// schedule records a transfer from one account to another due at scheduledAt.
+func (h *harness) schedule(t *testing.T, from, to *entity.Account, amount string, scheduledAt time.Time) {
+	t.Helper()
+
+	transfer := entity.NewTransfer(from.ID, to.ID, decimal.RequireFromString(amount), from.Currency, nil)
+	transfer.Status = entity.TransferStatusScheduled
+	transfer.ScheduledAt = &scheduledAt
+	ref, err := reference.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer.ReferenceNumber = ref
+	if err := h.transfers.Create(context.Background(), transfer); err != nil {
+		t.Fatalf("create scheduled transfer: %v", err)
+	}
+}
+
+func TestAvailableBalanceExcludesPendingDebits(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "100")
+	payee := h.account(t, h.user(t), "USD", "0")
+
+	// Due but not yet run by the scheduler: held against the balance.
+	h.schedule(t, account, payee, "30", time.Now().Add(-time.Minute))
+	// Not due yet: not held.
+	h.schedule(t, account, payee, "20", time.Now().Add(24*time.Hour))
+
+	got, err := h.service.GetByID(ctx, userID, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := got.ToResponse(entity.AmountDisplay)
+	if response.CurrentBalance != "100.00" || response.Balance != "100.00" {
+		t.Fatalf("current = %s, balance = %s; want 100.00", response.CurrentBalance, response.Balance)
+	}
+	if response.AvailableBalance != "70.00" {
+		t.Fatalf("available = %s, want 70.00", response.AvailableBalance)
+	}
+
+	accounts, _, err := h.service.GetByUserID(ctx, userID, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || !accounts[0].AvailableBalance().Equal(decimal.RequireFromString("70")) {
+		t.Fatalf("listed accounts = %+v, want available 70", accounts)
+	}
+}
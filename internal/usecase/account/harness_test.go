@@ -0,0 +1,130 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/adapter/repository/postgres"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/accountnumber"
+	"github.com/yourusername/gobank/internal/pkg/reference"
+	"github.com/yourusername/gobank/internal/testutil"
+	"github.com/yourusername/gobank/internal/usecase/accountcache"
+	"github.com/yourusername/gobank/internal/usecase/alert"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+	"github.com/yourusername/gobank/internal/usecase/ownership"
+)
+
+// harness is an account service over a fresh test database, with the
+// repositories it uses exposed for setting up and checking state.
+type harness struct {
+	db           *database.PostgresDB
+	service      *accountService
+	accounts     repository.AccountRepository
+	transfers    repository.TransferRepository
+	transactions repository.TransactionRepository
+	auditLogs    repository.AuditLogRepository
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	db := testutil.Postgres(t)
+
+	numbers, err := accountnumber.NewFormat("", 10, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := testutil.NewCache()
+	accountRepo := postgres.NewAccountRepository(db, numbers)
+	transferRepo := postgres.NewTransferRepository(db)
+	transactionRepo := postgres.NewTransactionRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	auditLogRepo := postgres.NewAuditLogRepository(db)
+	auditService := audit.NewAuditService(auditLogRepo, testutil.Logger())
+
+	service := NewAccountService(
+		accountRepo,
+		transactionRepo,
+		transferRepo,
+		postgres.NewAllowedDestinationRepository(db),
+		outboxRepo,
+		ownership.NewChecker(accountRepo, cache, 60),
+		accountcache.NewCache(accountRepo, cache, 0),
+		alert.NewBalanceAlertService(postgres.NewBalanceAlertRepository(db), accountRepo, outboxRepo, auditService),
+		db,
+		auditService,
+		nil,
+	).(*accountService)
+
+	return &harness{
+		db:           db,
+		service:      service,
+		accounts:     accountRepo,
+		transfers:    transferRepo,
+		transactions: transactionRepo,
+		auditLogs:    auditLogRepo,
+	}
+}
+
+// user creates a user and returns its ID.
+func (h *harness) user(t *testing.T) uuid.UUID {
+	t.Helper()
+
+	user := entity.NewUser(uuid.NewString()+"@example.com", "hash", "Test User")
+	if err := postgres.NewUserRepository(h.db).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user.ID
+}
+
+// account opens a checking account for userID holding balance.
+func (h *harness) account(t *testing.T, userID uuid.UUID, currency entity.Currency, balance string) *entity.Account {
+	t.Helper()
+
+	account := entity.NewAccount(userID, "", entity.AccountTypeChecking, currency)
+	account.Balance = decimal.RequireFromString(balance)
+	if err := h.accounts.Create(context.Background(), account); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	return account
+}
+
+// transaction records a ledger entry on accountID without touching its
+// balance.
+func (h *harness) transaction(t *testing.T, accountID uuid.UUID, txType entity.TransactionType, amount string, referenceID *uuid.UUID) *entity.Transaction {
+	t.Helper()
+
+	value := decimal.RequireFromString(amount)
+	transaction := entity.NewTransaction(accountID, txType, value, value, "test", referenceID)
+	if err := h.transactions.Create(context.Background(), transaction); err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	return transaction
+}
+
+// transfer records a completed transfer and its two ledger entries, returning
+// the transfer and the debit and credit.
+func (h *harness) transfer(t *testing.T, from, to *entity.Account, amount string) (*entity.Transfer, *entity.Transaction, *entity.Transaction) {
+	t.Helper()
+
+	transfer := entity.NewTransfer(from.ID, to.ID, decimal.RequireFromString(amount), from.Currency, nil)
+	transfer.Status = entity.TransferStatusCompleted
+	ref, err := reference.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer.ReferenceNumber = ref
+	if err := h.transfers.Create(context.Background(), transfer); err != nil {
+		t.Fatalf("create transfer: %v", err)
+	}
+
+	debit := h.transaction(t, from.ID, entity.TransactionTypeDebit, amount, &transfer.ID)
+	credit := h.transaction(t, to.ID, entity.TransactionTypeCredit, amount, &transfer.ID)
+	return transfer, debit, credit
+}
@@ -0,0 +1,126 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+func importRows(rows ...[2]string) []*entity.TransactionImportRow {
+	parsed := make([]*entity.TransactionImportRow, len(rows))
+	for i, row := range rows {
+		parsed[i] = &entity.TransactionImportRow{Line: i + 2, Type: row[0], Amount: row[1]}
+	}
+	return parsed
+}
+
+func (h *harness) ledger(t *testing.T, accountID uuid.UUID) ([]*entity.Transaction, decimal.Decimal) {
+	t.Helper()
+
+	transactions, err := h.transactions.GetByAccountID(context.Background(), accountID, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := h.accounts.GetByID(context.Background(), accountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transactions, account.Balance
+}
+
+func TestImportTransactions(t *testing.T) {
+	h := newHarness(t)
+	adminID := h.user(t)
+	account := h.account(t, h.user(t), "USD", "100")
+
+	report, err := h.service.ImportTransactions(context.Background(), adminID, account.ID,
+		importRows([2]string{"credit", "25.50"}, [2]string{"DEBIT", "10"}), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 2 || report.Failed != 0 || report.BalanceAfter != "115.50" {
+		t.Fatalf("report = %+v, want 2 imported with balance 115.50", report)
+	}
+	for _, result := range report.Results {
+		if result.Status != entity.ImportRowImported || result.TransactionID == nil {
+			t.Fatalf("row %d = %+v, want imported", result.Line, result)
+		}
+	}
+
+	transactions, balance := h.ledger(t, account.ID)
+	if len(transactions) != 2 {
+		t.Fatalf("%d transactions written, want 2", len(transactions))
+	}
+	if !balance.Equal(decimal.RequireFromString("115.50")) {
+		t.Fatalf("balance = %s, want 115.50", balance)
+	}
+
+	logs, err := h.auditLogs.GetByEntityID(context.Background(), "account", account.ID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].Action != "account.import_transactions" || logs[0].UserID == nil || *logs[0].UserID != adminID {
+		t.Fatalf("audit logs = %+v, want one import by the admin", logs)
+	}
+}
+
+func TestImportTransactionsMalformedRow(t *testing.T) {
+	h := newHarness(t)
+	account := h.account(t, h.user(t), "USD", "100")
+
+	report, err := h.service.ImportTransactions(context.Background(), h.user(t), account.ID, importRows(
+		[2]string{"credit", "5"},
+		[2]string{"refund", "5"},
+		[2]string{"credit", "five"},
+		[2]string{"debit", "500"},
+	), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 0 || report.Failed != 3 {
+		t.Fatalf("report = %+v, want 3 failed and nothing imported", report)
+	}
+	if report.Results[0].Status != entity.ImportRowValid {
+		t.Fatalf("good row = %+v, want valid", report.Results[0])
+	}
+	for _, result := range report.Results[1:] {
+		if result.Status != entity.ImportRowError || result.Error == "" {
+			t.Fatalf("row %d = %+v, want an error", result.Line, result)
+		}
+	}
+
+	transactions, balance := h.ledger(t, account.ID)
+	if len(transactions) != 0 || !balance.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("a file with bad rows changed the account: %d transactions, balance %s", len(transactions), balance)
+	}
+}
+
+func TestImportTransactionsDryRun(t *testing.T) {
+	h := newHarness(t)
+	account := h.account(t, h.user(t), "USD", "100")
+
+	report, err := h.service.ImportTransactions(context.Background(), h.user(t), account.ID,
+		importRows([2]string{"credit", "25"}, [2]string{"debit", "5"}), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.DryRun || report.Imported != 0 || report.Failed != 0 || report.BalanceAfter != "120.00" {
+		t.Fatalf("report = %+v, want a clean dry run ending at 120.00", report)
+	}
+	for _, result := range report.Results {
+		if result.Status != entity.ImportRowValid {
+			t.Fatalf("row %d = %+v, want valid", result.Line, result)
+		}
+	}
+
+	transactions, balance := h.ledger(t, account.ID)
+	if len(transactions) != 0 || !balance.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("dry run changed the account: %d transactions, balance %s", len(transactions), balance)
+	}
+	if count, err := h.auditLogs.CountByEntityID(context.Background(), "account", account.ID); err != nil || count != 0 {
+		t.Fatalf("dry run audit logs = %d, %v; want none", count, err)
+	}
+}
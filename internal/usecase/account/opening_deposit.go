@@ -0,0 +1,55 @@
+package account
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/money"
+)
+
+// OpeningDepositMinimums holds the smallest initial deposit each account
+// product may be opened with, keyed by account type and currency. Products
+// without an entry can be opened empty.
+type OpeningDepositMinimums map[string]decimal.Decimal
+
+// ParseOpeningDepositMinimums reads minimums written as "TYPE:CURRENCY=AMOUNT"
+// pairs separated by commas, e.g. "checking:USD=25,savings:EUR=100".
+func ParseOpeningDepositMinimums(spec string) (OpeningDepositMinimums, error) {
+	minimums := make(OpeningDepositMinimums)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		product, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("opening deposit %q: expected TYPE:CURRENCY=AMOUNT", entry)
+		}
+		accountType, currency, ok := strings.Cut(product, ":")
+		if !ok || accountType == "" || currency == "" {
+			return nil, fmt.Errorf("opening deposit %q: expected TYPE:CURRENCY=AMOUNT", entry)
+		}
+
+		amount, err := decimal.NewFromString(strings.TrimSpace(value))
+		if err != nil || amount.IsNegative() || money.Check(amount) != nil {
+			return nil, fmt.Errorf("opening deposit %q: invalid amount", entry)
+		}
+		minimums[productKey(entity.AccountType(accountType), entity.Currency(currency))] = amount
+	}
+	return minimums, nil
+}
+
+// For returns the minimum opening deposit for the product, or zero.
+func (m OpeningDepositMinimums) For(accountType entity.AccountType, currency entity.Currency) decimal.Decimal {
+	if minimum, ok := m[productKey(accountType, currency)]; ok {
+		return minimum
+	}
+	return decimal.Zero
+}
+
+func productKey(accountType entity.AccountType, currency entity.Currency) string {
+	return strings.ToLower(strings.TrimSpace(string(accountType))) + ":" + strings.ToUpper(strings.TrimSpace(string(currency)))
+}
@@ -0,0 +1,134 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestParseOpeningDepositMinimums(t *testing.T) {
+	minimums, err := ParseOpeningDepositMinimums(" checking:USD=25, savings:eur=100.50 ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		accountType entity.AccountType
+		currency    entity.Currency
+		want        string
+	}{
+		{entity.AccountTypeChecking, "USD", "25"},
+		{entity.AccountTypeSavings, "EUR", "100.50"},
+		{entity.AccountTypeSavings, "USD", "0"},
+		{entity.AccountTypeChecking, "EUR", "0"},
+	}
+	for _, tt := range tests {
+		if got := minimums.For(tt.accountType, tt.currency); !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("For(%s, %s) = %s, want %s", tt.accountType, tt.currency, got, tt.want)
+		}
+	}
+
+	empty, err := ParseOpeningDepositMinimums("")
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("empty spec = %v, %v; want no minimums", empty, err)
+	}
+}
+
+func TestParseOpeningDepositMinimumsRejectsBadEntries(t *testing.T) {
+	for _, spec := range []string{"checking=25", "checking:USD", ":USD=25", "checking:USD=abc", "checking:USD=-5"} {
+		if _, err := ParseOpeningDepositMinimums(spec); err == nil {
+			t.Errorf("%q parsed without error", spec)
+		}
+	}
+}
+
+func TestCreateWithOpeningDeposit(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	var err error
+	h.service.openingDeposits, err = ParseOpeningDepositMinimums("checking:USD=25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID := h.user(t)
+
+	account, err := h.service.Create(ctx, userID, &entity.CreateAccountInput{
+		AccountType:    entity.AccountTypeChecking,
+		Currency:       "USD",
+		InitialDeposit: "40",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !account.Balance.Equal(decimal.RequireFromString("40")) {
+		t.Fatalf("opening balance = %s, want 40", account.Balance)
+	}
+
+	transactions, err := h.transactions.GetByAccountID(ctx, account.ID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 1 || transactions[0].Type != entity.TransactionTypeCredit || !transactions[0].Amount.Equal(decimal.RequireFromString("40")) {
+		t.Fatalf("opening transactions = %+v, want one credit of 40", transactions)
+	}
+}
+
+func TestCreateBelowMinimumOpeningDeposit(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	var err error
+	h.service.openingDeposits, err = ParseOpeningDepositMinimums("checking:USD=25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID := h.user(t)
+
+	for _, deposit := range []string{"", "0", "24.99"} {
+		_, err := h.service.Create(ctx, userID, &entity.CreateAccountInput{
+			AccountType:    entity.AccountTypeChecking,
+			Currency:       "USD",
+			InitialDeposit: deposit,
+		})
+		if !errors.Is(err, apperror.ErrOpeningDepositTooLow) {
+			t.Errorf("deposit %q: err = %v, want ErrOpeningDepositTooLow", deposit, err)
+		}
+	}
+
+	accounts, err := h.accounts.GetByUserID(ctx, userID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 0 {
+		t.Fatalf("refused openings left %d accounts behind", len(accounts))
+	}
+
+	// Products without a minimum can still be opened empty.
+	if _, err := h.service.Create(ctx, userID, &entity.CreateAccountInput{
+		AccountType: entity.AccountTypeSavings,
+		Currency:    "USD",
+	}); err != nil {
+		t.Fatalf("savings account without a minimum: %v", err)
+	}
+}
+
+func TestCreateRejectsInvalidOpeningDeposit(t *testing.T) {
+	h := newHarness(t)
+	userID := h.user(t)
+
+	for _, deposit := range []string{"abc", "-10"} {
+		_, err := h.service.Create(context.Background(), userID, &entity.CreateAccountInput{
+			AccountType:    entity.AccountTypeChecking,
+			Currency:       "USD",
+			InitialDeposit: deposit,
+		})
+		if !errors.Is(err, apperror.ErrInvalidAmount) {
+			t.Errorf("deposit %q: err = %v, want ErrInvalidAmount", deposit, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestDormantAccountReactivation(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "100")
+
+	if _, err := h.service.RequestReactivation(ctx, userID, account.ID); !errors.Is(err, apperror.ErrAccountNotDormant) {
+		t.Fatalf("reactivation request for an active account = %v, want ErrAccountNotDormant", err)
+	}
+
+	account.Status = entity.AccountStatusFrozen
+	account.StatusReason = entity.AccountStatusReasonDormant
+	if err := h.accounts.Update(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+
+	// The owner cannot lift a dormancy freeze themselves.
+	stored, err := h.accounts.GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.CanTransitionTo(entity.AccountStatusActive) {
+		t.Fatal("owner may reactivate a dormant account")
+	}
+
+	requested, err := h.service.RequestReactivation(ctx, userID, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested.ReactivationRequestedAt == nil {
+		t.Fatal("reactivation request not recorded")
+	}
+	again, err := h.service.RequestReactivation(ctx, userID, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.ReactivationRequestedAt.Equal(*requested.ReactivationRequestedAt) {
+		t.Fatal("a repeated request moved the account in the queue")
+	}
+
+	adminID := h.user(t)
+	approved, err := h.service.ApproveReactivation(ctx, adminID, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != entity.AccountStatusActive || approved.StatusReason != "" || approved.ReactivationRequestedAt != nil {
+		t.Fatalf("approved account = %s %q %v, want active with no reason or request", approved.Status, approved.StatusReason, approved.ReactivationRequestedAt)
+	}
+
+	logs, err := h.auditLogs.GetByAction(ctx, "account.approve_reactivation", 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].UserID == nil || *logs[0].UserID != adminID || logs[0].EntityID == nil || *logs[0].EntityID != account.ID {
+		t.Fatalf("audit logs = %+v, want the admin's approval of the account", logs)
+	}
+}
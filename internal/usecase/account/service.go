@@ -2,34 +2,104 @@ package account
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/yourusername/gobank/internal/domain/entity"
 	"github.com/yourusername/gobank/internal/domain/repository"
 	"github.com/yourusername/gobank/internal/domain/service"
 	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/money"
+	"github.com/yourusername/gobank/internal/usecase/accountcache"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+	"github.com/yourusername/gobank/internal/usecase/ownership"
 )
 
 type accountService struct {
 	accountRepo     repository.AccountRepository
 	transactionRepo repository.TransactionRepository
+	transferRepo    repository.TransferRepository
+	allowlistRepo   repository.AllowedDestinationRepository
+	outboxRepo      repository.OutboxRepository
+	ownership       *ownership.Checker
+	accountCache    *accountcache.Cache
+	alerts          service.BalanceAlertEvaluator
+	txManager       repository.TransactionManager
+	audit           service.AuditService
+	openingDeposits OpeningDepositMinimums
 }
 
 func NewAccountService(
 	accountRepo repository.AccountRepository,
 	transactionRepo repository.TransactionRepository,
+	transferRepo repository.TransferRepository,
+	allowlistRepo repository.AllowedDestinationRepository,
+	outboxRepo repository.OutboxRepository,
+	ownershipChecker *ownership.Checker,
+	accountCache *accountcache.Cache,
+	alerts service.BalanceAlertEvaluator,
+	txManager repository.TransactionManager,
+	auditService service.AuditService,
+	openingDeposits OpeningDepositMinimums,
 ) service.AccountService {
 	return &accountService{
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
+		transferRepo:    transferRepo,
+		allowlistRepo:   allowlistRepo,
+		outboxRepo:      outboxRepo,
+		ownership:       ownershipChecker,
+		accountCache:    accountCache,
+		alerts:          alerts,
+		txManager:       txManager,
+		audit:           auditService,
+		openingDeposits: openingDeposits,
 	}
 }
 
+// Create opens an account. An initial deposit is credited in the same
+// transaction, so the account never exists below its product's minimum.
 func (s *accountService) Create(ctx context.Context, userID uuid.UUID, input *entity.CreateAccountInput) (*entity.Account, error) {
+	deposit := decimal.Zero
+	if input.InitialDeposit != "" {
+		parsed, err := decimal.NewFromString(input.InitialDeposit)
+		if err != nil || parsed.IsNegative() || money.Check(parsed) != nil {
+			return nil, apperror.ErrInvalidAmount
+		}
+		deposit = parsed
+	}
+
+	if minimum := s.openingDeposits.For(input.AccountType, input.Currency); deposit.LessThan(minimum) {
+		return nil, apperror.ErrOpeningDepositTooLow
+	}
+
 	account := entity.NewAccount(userID, "", input.AccountType, input.Currency)
 
-	if err := s.accountRepo.Create(ctx, account); err != nil {
-		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create account", 500)
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.accountRepo.Create(txCtx, account); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create account", 500)
+		}
+
+		if !deposit.IsPositive() {
+			return nil
+		}
+
+		transaction := entity.NewTransaction(account.ID, entity.TransactionTypeCredit, deposit, deposit, "Opening deposit", nil)
+		if err := s.transactionRepo.Create(txCtx, transaction); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create transaction", 500)
+		}
+
+		if err := s.accountRepo.UpdateBalance(txCtx, account, deposit); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update account balance", 500)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	createdAccount, err := s.accountRepo.GetByID(ctx, account.ID)
@@ -37,11 +107,34 @@ func (s *accountService) Create(ctx context.Context, userID uuid.UUID, input *en
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get created account", 500)
 	}
 
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "account.create", "account", &account.ID, nil, map[string]interface{}{
+		"account_number":  createdAccount.AccountNumber,
+		"account_type":    createdAccount.AccountType,
+		"currency":        createdAccount.Currency,
+		"initial_deposit": deposit.String(),
+	}, info.IPAddress, info.UserAgent)
+
 	return createdAccount, nil
 }
 
+// GetByID returns one of the user's accounts, possibly from the account
+// cache. Callers that go on to modify the account use getForUpdate instead.
 func (s *accountService) GetByID(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error) {
+	account, err := s.accountCache.Get(ctx, accountID)
+	return s.owned(ctx, userID, account, err)
+}
+
+// getForUpdate is GetByID reading straight from the database, so changes are
+// never made to a stale copy.
+func (s *accountService) getForUpdate(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error) {
 	account, err := s.accountRepo.GetByID(ctx, accountID)
+	return s.owned(ctx, userID, account, err)
+}
+
+// owned checks the result of an account lookup belongs to userID and fills
+// in its pending debits.
+func (s *accountService) owned(ctx context.Context, userID uuid.UUID, account *entity.Account, err error) (*entity.Account, error) {
 	if err != nil {
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
 	}
@@ -53,9 +146,200 @@ func (s *accountService) GetByID(ctx context.Context, userID, accountID uuid.UUI
 		return nil, apperror.ErrForbidden
 	}
 
+	if err := s.loadPendingDebits(ctx, account); err != nil {
+		return nil, err
+	}
+
 	return account, nil
 }
 
+// loadPendingDebits fills in PendingDebits so responses can report the
+// available balance alongside the ledger balance.
+func (s *accountService) loadPendingDebits(ctx context.Context, accounts ...*entity.Account) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(accounts))
+	for i, account := range accounts {
+		ids[i] = account.ID
+	}
+
+	pending, err := s.transferRepo.SumPendingDebits(ctx, ids, time.Now().UTC())
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get pending debits", 500)
+	}
+
+	for _, account := range accounts {
+		account.PendingDebits = pending[account.ID]
+	}
+	return nil
+}
+
+func (s *accountService) UpdateSettings(ctx context.Context, userID, accountID uuid.UUID, input *entity.UpdateAccountSettingsInput) (*entity.Account, error) {
+	account, err := s.getForUpdate(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := map[string]interface{}{"require_memo": account.RequireMemo}
+
+	if input.RequireMemo != nil {
+		account.RequireMemo = *input.RequireMemo
+	}
+
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		return nil, updateFailed(err)
+	}
+	s.accountCache.Invalidate(ctx, account.ID)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "account.update_settings", "account", &account.ID, oldValues,
+		map[string]interface{}{"require_memo": account.RequireMemo}, info.IPAddress, info.UserAgent)
+
+	return account, nil
+}
+
+// UpdateLimits sets or clears the account's daily outbound transfer limit.
+func (s *accountService) UpdateLimits(ctx context.Context, userID, accountID uuid.UUID, input *entity.UpdateAccountLimitsInput) (*entity.Account, error) {
+	var limit *decimal.Decimal
+	if input.DailyTransferLimit != nil {
+		parsed, err := decimal.NewFromString(*input.DailyTransferLimit)
+		if err != nil || parsed.LessThanOrEqual(decimal.Zero) || money.Check(parsed) != nil {
+			return nil, apperror.ErrInvalidAmount
+		}
+		limit = &parsed
+	}
+
+	account, err := s.getForUpdate(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := map[string]interface{}{"daily_transfer_limit": account.DailyTransferLimit}
+
+	account.DailyTransferLimit = limit
+
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		return nil, updateFailed(err)
+	}
+	s.accountCache.Invalidate(ctx, account.ID)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "account.update_limits", "account", &account.ID, oldValues,
+		map[string]interface{}{"daily_transfer_limit": account.DailyTransferLimit}, info.IPAddress, info.UserAgent)
+
+	return account, nil
+}
+
+// UpdateStatus moves one of the user's accounts to status if the transition is
+// allowed. Requesting the current status is a no-op.
+func (s *accountService) UpdateStatus(ctx context.Context, userID, accountID uuid.UUID, status entity.AccountStatus) (*entity.Account, error) {
+	account, err := s.getForUpdate(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status == status {
+		return account, nil
+	}
+	if !account.CanTransitionTo(status) {
+		return nil, apperror.ErrInvalidStatusTransition
+	}
+
+	oldStatus := account.Status
+	account.Status = status
+	account.StatusReason = ""
+
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.accountRepo.Update(txCtx, account); err != nil {
+			return updateFailed(err)
+		}
+		return s.recordStatusEvent(txCtx, account, oldStatus)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.accountCache.Invalidate(ctx, account.ID)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "account.update_status", "account", &account.ID,
+		map[string]interface{}{"status": oldStatus},
+		map[string]interface{}{"status": account.Status}, info.IPAddress, info.UserAgent)
+
+	return account, nil
+}
+
+// updateFailed wraps an error from accountRepo.Update. Losing a race with a
+// concurrent write, or trying to change the currency, is reported as such
+// rather than as an internal error.
+func updateFailed(err error) error {
+	if errors.Is(err, apperror.ErrConflict) || errors.Is(err, apperror.ErrCurrencyImmutable) {
+		return err
+	}
+	return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update account", 500)
+}
+
+// recordStatusEvent writes an account.status_changed event in the
+// transaction that changed the status.
+func (s *accountService) recordStatusEvent(txCtx context.Context, account *entity.Account, oldStatus entity.AccountStatus) error {
+	event, err := entity.NewOutboxEvent(entity.EventAccountStatusChanged, "account", account.ID, map[string]interface{}{
+		"account_id":      account.ID,
+		"account_number":  account.AccountNumber,
+		"user_id":         account.UserID,
+		"previous_status": oldStatus,
+		"status":          account.Status,
+	})
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to encode account event", 500)
+	}
+	if err := s.outboxRepo.Create(txCtx, event); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to record account event", 500)
+	}
+	return nil
+}
+
+// Close soft-deletes one of the user's accounts. Its history is kept, but it
+// no longer appears in lookups and cannot send or receive transfers. Only an
+// account with a zero balance can be closed.
+func (s *accountService) Close(ctx context.Context, userID, accountID uuid.UUID) error {
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		account, err := s.accountRepo.GetByIDForUpdate(txCtx, accountID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+		}
+		if account == nil {
+			return apperror.ErrAccountNotFound
+		}
+		if account.UserID != userID {
+			return apperror.ErrForbidden
+		}
+		if !account.Balance.IsZero() {
+			return apperror.ErrAccountHasBalance
+		}
+
+		closed, err := s.accountRepo.Close(txCtx, account.ID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to close account", 500)
+		}
+		if !closed {
+			return apperror.ErrAccountNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = s.ownership.Invalidate(ctx, accountID)
+	s.accountCache.Invalidate(ctx, accountID)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "account.close", "account", &accountID, nil,
+		map[string]interface{}{"status_reason": entity.AccountStatusReasonClosed}, info.IPAddress, info.UserAgent)
+
+	return nil
+}
+
 func (s *accountService) GetByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Account, int64, error) {
 	if page < 1 {
 		page = 1
@@ -75,19 +359,23 @@ func (s *accountService) GetByUserID(ctx context.Context, userID uuid.UUID, page
 		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count accounts", 500)
 	}
 
+	if err := s.loadPendingDebits(ctx, accounts...); err != nil {
+		return nil, 0, err
+	}
+
 	return accounts, total, nil
 }
 
-func (s *accountService) GetTransactions(ctx context.Context, userID, accountID uuid.UUID, page, pageSize int) ([]*entity.Transaction, int64, error) {
-	account, err := s.accountRepo.GetByID(ctx, accountID)
+func (s *accountService) GetTransactions(ctx context.Context, userID, accountID uuid.UUID, filter *entity.TransactionFilter, page, pageSize int) ([]*entity.Transaction, int64, error) {
+	ownerID, found, err := s.ownership.OwnerOf(ctx, accountID)
 	if err != nil {
 		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
 	}
-	if account == nil {
+	if !found {
 		return nil, 0, apperror.ErrAccountNotFound
 	}
 
-	if account.UserID != userID {
+	if ownerID != userID {
 		return nil, 0, apperror.ErrForbidden
 	}
 
@@ -99,15 +387,560 @@ func (s *accountService) GetTransactions(ctx context.Context, userID, accountID
 	}
 	offset := (page - 1) * pageSize
 
-	transactions, err := s.transactionRepo.GetByAccountID(ctx, accountID, pageSize, offset)
+	if filter.HasDateRange() {
+		if startDate, endDate := filter.DateRange(); startDate.After(endDate) {
+			return nil, 0, apperror.ErrBadRequest
+		}
+	}
+
+	transactions, err := s.transactionRepo.GetByAccountIDFiltered(ctx, accountID, filter, pageSize, offset)
 	if err != nil {
 		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transactions", 500)
 	}
 
-	total, err := s.transactionRepo.CountByAccountID(ctx, accountID)
+	total, err := s.transactionRepo.CountByAccountIDFiltered(ctx, accountID, filter)
 	if err != nil {
 		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count transactions", 500)
 	}
 
 	return transactions, total, nil
 }
+
+// GetAllTransactions lists transactions across every account the user owns.
+func (s *accountService) GetAllTransactions(ctx context.Context, userID uuid.UUID, page, pageSize int, filter *entity.TransactionFilter) ([]*entity.AccountTransaction, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	if filter.HasDateRange() {
+		if startDate, endDate := filter.DateRange(); startDate.After(endDate) {
+			return nil, 0, apperror.ErrBadRequest
+		}
+	}
+
+	transactions, err := s.transactionRepo.GetByUserID(ctx, userID, filter, pageSize, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transactions", 500)
+	}
+
+	total, err := s.transactionRepo.CountByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count transactions", 500)
+	}
+
+	return transactions, total, nil
+}
+
+// GetTransactionsCursor returns one page of the account's transactions
+// matching filter after cursor, newest first, and the cursor of the next
+// page. An empty cursor starts from the newest transaction; an empty next
+// cursor means this is the last page.
+func (s *accountService) GetTransactionsCursor(ctx context.Context, userID, accountID uuid.UUID, filter *entity.TransactionFilter, cursor string, pageSize int) ([]*entity.Transaction, string, error) {
+	ownerID, found, err := s.ownership.OwnerOf(ctx, accountID)
+	if err != nil {
+		return nil, "", apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+	}
+	if !found {
+		return nil, "", apperror.ErrAccountNotFound
+	}
+
+	if ownerID != userID {
+		return nil, "", apperror.ErrForbidden
+	}
+
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	if filter.HasDateRange() {
+		if startDate, endDate := filter.DateRange(); startDate.After(endDate) {
+			return nil, "", apperror.ErrBadRequest
+		}
+	}
+
+	after := entity.TransactionCursor{CreatedAt: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)}
+	if cursor != "" {
+		if after, err = entity.ParseTransactionCursor(cursor); err != nil {
+			return nil, "", apperror.ErrInvalidCursor
+		}
+	}
+
+	// One extra row tells us whether another page follows.
+	transactions, err := s.transactionRepo.GetByAccountIDAfter(ctx, accountID, filter, after.CreatedAt, after.ID, pageSize+1)
+	if err != nil {
+		return nil, "", apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transactions", 500)
+	}
+
+	var next string
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		next = transactions[pageSize-1].Cursor().Encode()
+	}
+
+	return transactions, next, nil
+}
+
+func (s *accountService) GetTransaction(ctx context.Context, userID, transactionID uuid.UUID) (*entity.TransactionDetail, error) {
+	transaction, err := s.ownedTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transactionDetail(ctx, transaction)
+}
+
+// GetAccountTransaction is GetTransaction limited to one account: a
+// transaction on any other account, including one on an account the user
+// does not own, is not found.
+func (s *accountService) GetAccountTransaction(ctx context.Context, userID, accountID, transactionID uuid.UUID) (*entity.TransactionDetail, error) {
+	transaction, err := s.ownedTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.AccountID != accountID {
+		return nil, apperror.ErrTransactionNotFound
+	}
+	return s.transactionDetail(ctx, transaction)
+}
+
+// ownedTransaction returns the transaction if it is on one of the user's
+// accounts. Other users' transactions are not found, so their IDs reveal
+// nothing.
+func (s *accountService) ownedTransaction(ctx context.Context, userID, transactionID uuid.UUID) (*entity.Transaction, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transaction", 500)
+	}
+	if transaction == nil {
+		return nil, apperror.ErrTransactionNotFound
+	}
+
+	owned, err := s.ownership.IsOwner(ctx, userID, transaction.AccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+	}
+	if !owned {
+		return nil, apperror.ErrTransactionNotFound
+	}
+	return transaction, nil
+}
+
+// transactionDetail adds to transaction the transfer it belongs to and the
+// account on the other side of it.
+func (s *accountService) transactionDetail(ctx context.Context, transaction *entity.Transaction) (*entity.TransactionDetail, error) {
+	detail := &entity.TransactionDetail{Transaction: transaction}
+	if transaction.ReferenceID == nil {
+		return detail, nil
+	}
+
+	transfer, err := s.transferRepo.GetByID(ctx, *transaction.ReferenceID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transfer", 500)
+	}
+	if transfer == nil {
+		return detail, nil
+	}
+	detail.Transfer = transfer
+
+	counterpartyID := transfer.FromAccountID
+	if transfer.FromAccountID == transaction.AccountID {
+		counterpartyID = transfer.ToAccountID
+	}
+
+	counterparty, err := s.accountRepo.GetByID(ctx, counterpartyID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get counterparty account", 500)
+	}
+	detail.CounterpartyAccount = counterparty
+
+	return detail, nil
+}
+
+// errImportAborted rolls back an import that is a dry run or has invalid rows.
+var errImportAborted = errors.New("import aborted")
+
+func (s *accountService) ImportTransactions(ctx context.Context, adminID, accountID uuid.UUID, rows []*entity.TransactionImportRow, dryRun bool) (*entity.TransactionImportReport, error) {
+	report := &entity.TransactionImportReport{
+		AccountID: accountID,
+		DryRun:    dryRun,
+		Total:     len(rows),
+		Results:   make([]*entity.TransactionImportResult, 0, len(rows)),
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		account, err := s.accountRepo.GetByIDForUpdate(txCtx, accountID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+		}
+		if account == nil {
+			return apperror.ErrAccountNotFound
+		}
+
+		balance := account.Balance
+		transactions := make([]*entity.Transaction, 0, len(rows))
+
+		for _, row := range rows {
+			result := &entity.TransactionImportResult{Line: row.Line}
+			report.Results = append(report.Results, result)
+
+			transaction, err := buildAdjustment(account.ID, balance, account.BalanceFloor(), row)
+			if err != nil {
+				result.Status = entity.ImportRowError
+				result.Error = err.Error()
+				report.Failed++
+				continue
+			}
+
+			balance = transaction.BalanceAfter
+			result.Status = entity.ImportRowValid
+			result.TransactionID = &transaction.ID
+			transactions = append(transactions, transaction)
+		}
+
+		report.BalanceAfter = money.Format(balance, account.Currency.DisplayScale())
+
+		if dryRun || report.Failed > 0 {
+			return errImportAborted
+		}
+
+		for _, transaction := range transactions {
+			if err := s.transactionRepo.Create(txCtx, transaction); err != nil {
+				return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create transaction", 500)
+			}
+		}
+
+		if err := s.accountRepo.UpdateBalance(txCtx, account, balance); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update account balance", 500)
+		}
+
+		return s.alerts.Evaluate(txCtx, entity.BalanceChange{Account: account, Previous: account.Balance, Current: balance})
+	})
+
+	if err != nil && !errors.Is(err, errImportAborted) {
+		return nil, err
+	}
+
+	if err == nil {
+		s.accountCache.Invalidate(ctx, accountID)
+		for _, result := range report.Results {
+			result.Status = entity.ImportRowImported
+		}
+		report.Imported = len(report.Results)
+
+		info := audit.RequestInfoFrom(ctx)
+		s.audit.Record(ctx, &adminID, "account.import_transactions", "account", &accountID, nil,
+			map[string]interface{}{
+				"imported":      report.Imported,
+				"balance_after": report.BalanceAfter,
+			}, info.IPAddress, info.UserAgent)
+	}
+
+	return report, nil
+}
+
+// buildAdjustment applies row to balance, refusing a debit that would take
+// it below floor.
+func buildAdjustment(accountID uuid.UUID, balance, floor decimal.Decimal, row *entity.TransactionImportRow) (*entity.Transaction, error) {
+	txType := entity.TransactionType(strings.ToLower(strings.TrimSpace(row.Type)))
+	if txType != entity.TransactionTypeCredit && txType != entity.TransactionTypeDebit {
+		return nil, fmt.Errorf("type must be credit or debit")
+	}
+
+	amount, err := decimal.NewFromString(strings.TrimSpace(row.Amount))
+	if err != nil || amount.LessThanOrEqual(decimal.Zero) || money.Check(amount) != nil {
+		return nil, fmt.Errorf("invalid amount")
+	}
+
+	var newBalance decimal.Decimal
+	if txType == entity.TransactionTypeCredit {
+		newBalance, err = money.Add(balance, amount)
+		if err != nil {
+			return nil, fmt.Errorf("resulting balance overflows")
+		}
+	} else {
+		newBalance = balance.Sub(amount)
+		if newBalance.LessThan(floor) {
+			return nil, fmt.Errorf("insufficient balance")
+		}
+	}
+
+	description := strings.TrimSpace(row.Description)
+	if description == "" {
+		description = "Reconciliation adjustment"
+	}
+
+	return entity.NewTransaction(accountID, txType, amount, newBalance, description, nil), nil
+}
+
+func (s *accountService) OwnsAccounts(ctx context.Context, userID uuid.UUID, accountIDs ...uuid.UUID) (bool, error) {
+	for _, accountID := range accountIDs {
+		owned, err := s.ownership.IsOwner(ctx, userID, accountID)
+		if err != nil {
+			return false, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+		}
+		if !owned {
+			return false, nil
+		}
+	}
+	return len(accountIDs) > 0, nil
+}
+
+// RequestReactivation records the owner's request to lift a dormancy freeze.
+// Repeated requests keep the original timestamp so the admin queue order is
+// stable.
+func (s *accountService) RequestReactivation(ctx context.Context, userID, accountID uuid.UUID) (*entity.Account, error) {
+	account, err := s.getForUpdate(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsDormant() {
+		return nil, apperror.ErrAccountNotDormant
+	}
+	if account.ReactivationRequestedAt != nil {
+		return account, nil
+	}
+
+	now := time.Now().UTC()
+	account.ReactivationRequestedAt = &now
+
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		return nil, updateFailed(err)
+	}
+	s.accountCache.Invalidate(ctx, account.ID)
+
+	return account, nil
+}
+
+func (s *accountService) ApproveReactivation(ctx context.Context, adminID, accountID uuid.UUID) (*entity.Account, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+	}
+	if account == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if !account.IsDormant() {
+		return nil, apperror.ErrAccountNotDormant
+	}
+
+	oldStatus := account.Status
+	account.Status = entity.AccountStatusActive
+	account.StatusReason = ""
+	account.ReactivationRequestedAt = nil
+
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.accountRepo.Update(txCtx, account); err != nil {
+			return updateFailed(err)
+		}
+		return s.recordStatusEvent(txCtx, account, oldStatus)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.accountCache.Invalidate(ctx, account.ID)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &adminID, "account.approve_reactivation", "account", &account.ID,
+		map[string]interface{}{"status": oldStatus},
+		map[string]interface{}{"status": account.Status}, info.IPAddress, info.UserAgent)
+
+	if err := s.loadPendingDebits(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// UpdateBalanceRules sets the account's minimum balance, which only applies
+// to savings accounts, and its overdraft limit. A rule that would put the
+// current balance below its floor is still set; it only affects later debits.
+func (s *accountService) UpdateBalanceRules(ctx context.Context, adminID, accountID uuid.UUID, input *entity.UpdateBalanceRulesInput) (*entity.Account, error) {
+	minBalance, err := parseBalanceRule(input.MinBalance)
+	if err != nil {
+		return nil, err
+	}
+	overdraftLimit, err := parseBalanceRule(input.OverdraftLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+	}
+	if account == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+
+	oldValues := map[string]interface{}{
+		"min_balance":     account.MinBalance,
+		"overdraft_limit": account.OverdraftLimit,
+	}
+
+	if minBalance != nil {
+		account.MinBalance = *minBalance
+	}
+	if overdraftLimit != nil {
+		account.OverdraftLimit = *overdraftLimit
+	}
+
+	if err := s.accountRepo.Update(ctx, account); err != nil {
+		return nil, updateFailed(err)
+	}
+	s.accountCache.Invalidate(ctx, account.ID)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &adminID, "account.update_balance_rules", "account", &account.ID, oldValues,
+		map[string]interface{}{
+			"min_balance":     account.MinBalance,
+			"overdraft_limit": account.OverdraftLimit,
+		}, info.IPAddress, info.UserAgent)
+
+	if err := s.loadPendingDebits(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// parseBalanceRule parses an optional non-negative amount.
+func parseBalanceRule(value *string) (*decimal.Decimal, error) {
+	if value == nil {
+		return nil, nil
+	}
+	parsed, err := decimal.NewFromString(*value)
+	if err != nil || parsed.IsNegative() || money.Check(parsed) != nil {
+		return nil, apperror.ErrInvalidAmount
+	}
+	return &parsed, nil
+}
+
+func (s *accountService) GetPendingReactivations(ctx context.Context, page, pageSize int) ([]*entity.Account, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	accounts, err := s.accountRepo.GetPendingReactivations(ctx, pageSize, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get reactivation requests", 500)
+	}
+
+	total, err := s.accountRepo.CountPendingReactivations(ctx)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count reactivation requests", 500)
+	}
+
+	if err := s.loadPendingDebits(ctx, accounts...); err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, total, nil
+}
+
+// Search lists accounts across all users for admins, closed ones included.
+func (s *accountService) Search(ctx context.Context, filter *entity.AccountSearchFilter, page, pageSize int) ([]*entity.Account, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	accounts, err := s.accountRepo.Search(ctx, filter, pageSize, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to search accounts", 500)
+	}
+
+	total, err := s.accountRepo.CountSearch(ctx, filter)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count accounts", 500)
+	}
+
+	if err := s.loadPendingDebits(ctx, accounts...); err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, total, nil
+}
+
+func (s *accountService) ListAllowedDestinations(ctx context.Context, userID, accountID uuid.UUID) ([]*entity.AllowedDestination, error) {
+	if _, err := s.GetByID(ctx, userID, accountID); err != nil {
+		return nil, err
+	}
+
+	destinations, err := s.allowlistRepo.GetByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get allowed destinations", 500)
+	}
+	if destinations == nil {
+		destinations = []*entity.AllowedDestination{}
+	}
+	return destinations, nil
+}
+
+// AddAllowedDestination adds an account, by ID or number, to the allowlist
+// and returns the updated list. Once the list is non-empty, transfers from
+// the account may only go to accounts on it.
+func (s *accountService) AddAllowedDestination(ctx context.Context, userID, accountID uuid.UUID, input *entity.AddAllowedDestinationInput) ([]*entity.AllowedDestination, error) {
+	if _, err := s.GetByID(ctx, userID, accountID); err != nil {
+		return nil, err
+	}
+
+	var destination *entity.Account
+	var err error
+	if input.AccountNumber != "" {
+		destination, err = s.accountRepo.GetByAccountNumber(ctx, input.AccountNumber)
+	} else {
+		destination, err = s.accountRepo.GetByID(ctx, input.AccountID)
+	}
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get destination account", 500)
+	}
+	if destination == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if input.AccountNumber != "" && input.AccountID != uuid.Nil && input.AccountID != destination.ID {
+		return nil, apperror.ErrBadRequest
+	}
+	if destination.ID == accountID {
+		return nil, apperror.ErrSameAccount
+	}
+
+	if err := s.allowlistRepo.Add(ctx, accountID, destination.ID); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to add allowed destination", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "account.allowlist_add", "account", &accountID, nil,
+		map[string]interface{}{"destination_account_id": destination.ID}, info.IPAddress, info.UserAgent)
+
+	return s.ListAllowedDestinations(ctx, userID, accountID)
+}
+
+func (s *accountService) RemoveAllowedDestination(ctx context.Context, userID, accountID, destinationAccountID uuid.UUID) error {
+	if _, err := s.GetByID(ctx, userID, accountID); err != nil {
+		return err
+	}
+
+	removed, err := s.allowlistRepo.Remove(ctx, accountID, destinationAccountID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to remove allowed destination", 500)
+	}
+	if !removed {
+		return apperror.ErrNotFound
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "account.allowlist_remove", "account", &accountID,
+		map[string]interface{}{"destination_account_id": destinationAccountID}, nil, info.IPAddress, info.UserAgent)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+func TestUpdateSettingsRequireMemo(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "0")
+
+	enabled := true
+	updated, err := h.service.UpdateSettings(ctx, userID, account.ID, &entity.UpdateAccountSettingsInput{RequireMemo: &enabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.RequireMemo || !updated.ToResponse(entity.AmountDisplay).RequireMemo {
+		t.Fatal("require_memo not set on the updated account")
+	}
+
+	// Leaving the field out keeps the current setting.
+	updated, err = h.service.UpdateSettings(ctx, userID, account.ID, &entity.UpdateAccountSettingsInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.RequireMemo {
+		t.Fatal("an empty update cleared require_memo")
+	}
+
+	stored, err := h.accounts.GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.RequireMemo {
+		t.Fatal("require_memo not stored")
+	}
+}
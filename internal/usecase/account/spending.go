@@ -0,0 +1,47 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// SpendingSummary totals the debits on one of the user's accounts between
+// start and end, grouped by category. As with statements, an end in the
+// future is clamped to now.
+func (s *accountService) SpendingSummary(ctx context.Context, userID, accountID uuid.UUID, start, end time.Time) (*entity.SpendingSummary, error) {
+	if now := time.Now().UTC(); end.After(now) {
+		end = now
+	}
+	if start.After(end) {
+		return nil, apperror.ErrBadRequest
+	}
+
+	account, err := s.GetByID(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.transactionRepo.SpendingByCategory(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to summarize spending", 500)
+	}
+
+	total := decimal.Zero
+	for _, c := range categories {
+		total = total.Add(c.Total)
+	}
+
+	return &entity.SpendingSummary{
+		AccountID:  account.ID,
+		Currency:   account.Currency,
+		StartDate:  start,
+		EndDate:    end,
+		Total:      total,
+		Categories: categories,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// statementPageSize is how many transactions a statement reads at a time.
+const statementPageSize = 500
+
+// GetStatement returns the summary of one of the user's accounts between
+// start and end. An end in the future is clamped to now so the closing
+// balance is the balance as of the statement.
+func (s *accountService) GetStatement(ctx context.Context, userID, accountID uuid.UUID, start, end time.Time) (*entity.Statement, error) {
+	if now := time.Now().UTC(); end.After(now) {
+		end = now
+	}
+	if start.After(end) {
+		return nil, apperror.ErrBadRequest
+	}
+
+	account, err := s.GetByID(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opening, err := s.transactionRepo.GetBalanceBefore(ctx, account.ID, start)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get opening balance", 500)
+	}
+	// Timestamps are stored with microsecond precision, so this includes
+	// everything up to and including end.
+	closing, err := s.transactionRepo.GetBalanceBefore(ctx, account.ID, end.Add(time.Microsecond))
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get closing balance", 500)
+	}
+
+	count, err := s.transactionRepo.CountByAccountIDAndDateRange(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count transactions", 500)
+	}
+
+	return &entity.Statement{
+		AccountID:        account.ID,
+		AccountNumber:    account.AccountNumber,
+		Currency:         account.Currency,
+		StartDate:        start,
+		EndDate:          end,
+		OpeningBalance:   opening,
+		ClosingBalance:   closing,
+		TransactionCount: count,
+	}, nil
+}
+
+// StreamStatement calls fn for each transaction in the statement, oldest
+// first. Transactions are read a page at a time, so a long statement is never
+// held in memory.
+func (s *accountService) StreamStatement(ctx context.Context, statement *entity.Statement, fn func(*entity.Transaction) error) error {
+	// Pages come newest first, so walk them from the end of the range and
+	// emit each one in reverse.
+	remaining := int(statement.TransactionCount)
+	for remaining > 0 {
+		limit := statementPageSize
+		if remaining < limit {
+			limit = remaining
+		}
+		offset := remaining - limit
+
+		transactions, err := s.transactionRepo.GetByAccountIDAndDateRange(ctx, statement.AccountID, statement.StartDate, statement.EndDate, limit, offset)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transactions", 500)
+		}
+		for i := len(transactions) - 1; i >= 0; i-- {
+			if err := fn(transactions[i]); err != nil {
+				return err
+			}
+		}
+
+		remaining = offset
+	}
+	return nil
+}
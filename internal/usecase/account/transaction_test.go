@@ -0,0 +1,85 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestGetTransactionWithTransfer(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	sender, recipient := h.user(t), h.user(t)
+	from := h.account(t, sender, "USD", "100")
+	to := h.account(t, recipient, "USD", "0")
+	transfer, debit, credit := h.transfer(t, from, to, "25")
+
+	detail, err := h.service.GetTransaction(ctx, sender, debit.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Transfer == nil || detail.Transfer.ID != transfer.ID {
+		t.Fatalf("debit detail has transfer %v, want %s", detail.Transfer, transfer.ID)
+	}
+	if detail.CounterpartyAccount == nil || detail.CounterpartyAccount.ID != to.ID {
+		t.Fatal("debit detail does not name the destination as counterparty")
+	}
+
+	response := detail.ToResponse(entity.AmountDisplay)
+	if got := response.Transfer.CounterpartyAccount; got != entity.MaskAccountNumber(to.AccountNumber) || !strings.HasPrefix(got, "*") {
+		t.Fatalf("counterparty account = %q, want the masked destination number", got)
+	}
+
+	detail, err = h.service.GetTransaction(ctx, recipient, credit.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.CounterpartyAccount == nil || detail.CounterpartyAccount.ID != from.ID {
+		t.Fatal("credit detail does not name the source as counterparty")
+	}
+}
+
+func TestGetTransactionStandaloneDeposit(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "0")
+	deposit := h.transaction(t, account.ID, entity.TransactionTypeCredit, "40", nil)
+
+	detail, err := h.service.GetTransaction(ctx, userID, deposit.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Transaction.ID != deposit.ID {
+		t.Fatalf("got transaction %s, want %s", detail.Transaction.ID, deposit.ID)
+	}
+	if detail.Transfer != nil || detail.CounterpartyAccount != nil {
+		t.Fatal("a deposit has no transfer context")
+	}
+	if response := detail.ToResponse(entity.AmountDisplay); response.Transfer != nil {
+		t.Fatal("deposit response includes a transfer")
+	}
+}
+
+func TestGetTransactionNotOwned(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	owner, stranger := h.user(t), h.user(t)
+	account := h.account(t, owner, "USD", "0")
+	deposit := h.transaction(t, account.ID, entity.TransactionTypeCredit, "40", nil)
+
+	if _, err := h.service.GetTransaction(ctx, stranger, deposit.ID); !errors.Is(err, apperror.ErrTransactionNotFound) {
+		t.Fatalf("another user's transaction = %v, want ErrTransactionNotFound", err)
+	}
+	if _, err := h.service.GetTransaction(ctx, owner, uuid.New()); !errors.Is(err, apperror.ErrTransactionNotFound) {
+		t.Fatalf("unknown transaction = %v, want ErrTransactionNotFound", err)
+	}
+}
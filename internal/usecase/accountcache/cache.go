@@ -0,0 +1,78 @@
+package accountcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// Cache reads accounts through a short-lived cache keyed by account ID.
+// Anything that changes an account must call Invalidate once its change has
+// committed. The TTL bounds how stale an entry can get when that is not
+// possible, e.g. for bulk updates, or when an invalidation races a read that
+// is repopulating the entry. Any cache failure falls back to the database.
+type Cache struct {
+	accountRepo repository.AccountRepository
+	cache       service.CacheService
+	ttlSeconds  int
+}
+
+// NewCache creates an account cache. A ttlSeconds of zero or less disables
+// caching, so every Get reads the database.
+func NewCache(accountRepo repository.AccountRepository, cache service.CacheService, ttlSeconds int) *Cache {
+	return &Cache{
+		accountRepo: accountRepo,
+		cache:       cache,
+		ttlSeconds:  ttlSeconds,
+	}
+}
+
+// Get returns the account with the given ID, or nil if it does not exist.
+// Missing accounts are not cached. Inside a transaction the cache is skipped
+// so the account is read from the transaction's snapshot.
+func (c *Cache) Get(ctx context.Context, accountID uuid.UUID) (*entity.Account, error) {
+	if _, inTx := database.TxFromContext(ctx); inTx || c.ttlSeconds <= 0 {
+		return c.accountRepo.GetByID(ctx, accountID)
+	}
+
+	key := cacheKey(accountID)
+
+	if cached, err := c.cache.Get(ctx, key); err == nil && cached != "" {
+		account := &entity.Account{}
+		if err := json.Unmarshal([]byte(cached), account); err == nil {
+			metrics.AccountCacheHitsTotal.Inc()
+			return account, nil
+		}
+	}
+	metrics.AccountCacheMissesTotal.Inc()
+
+	account, err := c.accountRepo.GetByID(ctx, accountID)
+	if err != nil || account == nil {
+		return account, err
+	}
+
+	_ = c.cache.Set(ctx, key, account, c.ttlSeconds)
+
+	return account, nil
+}
+
+// Invalidate drops the cached copies of the given accounts.
+func (c *Cache) Invalidate(ctx context.Context, accountIDs ...uuid.UUID) {
+	if c.ttlSeconds <= 0 {
+		return
+	}
+	for _, id := range accountIDs {
+		_ = c.cache.Delete(ctx, cacheKey(id))
+	}
+}
+
+func cacheKey(accountID uuid.UUID) string {
+	return fmt.Sprintf("account:%s", accountID)
+}
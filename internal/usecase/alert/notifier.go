@@ -0,0 +1,75 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+)
+
+type emailNotifier struct {
+	userRepo repository.UserRepository
+	mailer   service.Mailer
+}
+
+// NewEmailNotifier returns a publisher that emails the owner of each
+// triggered balance alert that asked for email. It ignores other events.
+func NewEmailNotifier(userRepo repository.UserRepository, mailer service.Mailer) service.EventPublisher {
+	return &emailNotifier{userRepo: userRepo, mailer: mailer}
+}
+
+func (n *emailNotifier) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	if event.EventType != entity.EventBalanceAlertTriggered {
+		return nil
+	}
+
+	var payload struct {
+		Alert           entity.BalanceAlertResponse `json:"alert"`
+		UserID          uuid.UUID                   `json:"user_id"`
+		AccountNumber   string                      `json:"account_number"`
+		PreviousBalance string                      `json:"previous_balance"`
+		Balance         string                      `json:"balance"`
+		NotifyEmail     bool                        `json:"notify_email"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("decode balance alert event: %w", err)
+	}
+	if !payload.NotifyEmail {
+		return nil
+	}
+
+	user, err := n.userRepo.GetByID(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	subject := fmt.Sprintf("Balance alert for account %s", payload.AccountNumber)
+	body := fmt.Sprintf(
+		"The balance of account %s moved from %s to %s %s, which is %s your alert threshold of %s %s.",
+		payload.AccountNumber,
+		payload.PreviousBalance,
+		payload.Balance,
+		payload.Alert.Currency,
+		describe(payload.Alert.Condition),
+		payload.Alert.Threshold,
+		payload.Alert.Currency,
+	)
+	return n.mailer.Send(ctx, user.Email, subject, body)
+}
+
+func describe(condition entity.BalanceAlertCondition) string {
+	switch condition {
+	case entity.BalanceAlertAbove:
+		return "above"
+	case entity.BalanceAlertBelow:
+		return "below"
+	}
+	return "across"
+}
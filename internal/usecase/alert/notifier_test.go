@@ -0,0 +1,105 @@
+package alert
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+)
+
+// users serves GetByID from a fixed user. Other methods are not implemented.
+type users struct {
+	repository.UserRepository
+	user *entity.User
+}
+
+func (u users) GetByID(_ context.Context, id uuid.UUID) (*entity.User, error) {
+	if u.user != nil && u.user.ID == id {
+		return u.user, nil
+	}
+	return nil, nil
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type mailbox struct {
+	sent []sentMail
+}
+
+func (m *mailbox) Send(_ context.Context, to, subject, body string) error {
+	m.sent = append(m.sent, sentMail{to, subject, body})
+	return nil
+}
+
+func alertEvent(t *testing.T, userID uuid.UUID, notifyEmail bool) *entity.OutboxEvent {
+	t.Helper()
+
+	alert := &entity.BalanceAlert{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Condition: entity.BalanceAlertBelow,
+		Threshold: decimal.RequireFromString("50"),
+		Currency:  entity.CurrencyUSD,
+	}
+	event, err := entity.NewOutboxEvent(entity.EventBalanceAlertTriggered, "balance_alert", alert.ID, map[string]interface{}{
+		"alert":            alert.ToResponse(entity.AmountFull),
+		"user_id":          userID,
+		"account_number":   "1234567890",
+		"previous_balance": "60.00",
+		"balance":          "40.00",
+		"notify_email":     notifyEmail,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestEmailNotifierRespectsPreference(t *testing.T) {
+	user := entity.NewUser("owner@example.com", "hash", "Owner")
+	mail := &mailbox{}
+	notifier := NewEmailNotifier(users{user: user}, mail)
+
+	if err := notifier.Publish(context.Background(), alertEvent(t, user.ID, false)); err != nil {
+		t.Fatal(err)
+	}
+	if len(mail.sent) != 0 {
+		t.Fatalf("sent %d emails for an alert without email notification", len(mail.sent))
+	}
+
+	if err := notifier.Publish(context.Background(), alertEvent(t, user.ID, true)); err != nil {
+		t.Fatal(err)
+	}
+	if len(mail.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(mail.sent))
+	}
+	sent := mail.sent[0]
+	if sent.to != "owner@example.com" || !strings.Contains(sent.subject, "1234567890") {
+		t.Fatalf("sent %+v", sent)
+	}
+	if !strings.Contains(sent.body, "from 60.00 to 40.00 USD") || !strings.Contains(sent.body, "below your alert threshold of 50") {
+		t.Fatalf("body = %q", sent.body)
+	}
+}
+
+func TestEmailNotifierIgnoresOtherEvents(t *testing.T) {
+	mail := &mailbox{}
+	notifier := NewEmailNotifier(users{}, mail)
+
+	event, err := entity.NewOutboxEvent(entity.EventTransferCompleted, "transfer", uuid.New(), map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if len(mail.sent) != 0 {
+		t.Fatalf("sent %d emails for a transfer event", len(mail.sent))
+	}
+}
@@ -0,0 +1,241 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/money"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+type balanceAlertService struct {
+	alertRepo   repository.BalanceAlertRepository
+	accountRepo repository.AccountRepository
+	outboxRepo  repository.OutboxRepository
+	audit       service.AuditService
+}
+
+func NewBalanceAlertService(
+	alertRepo repository.BalanceAlertRepository,
+	accountRepo repository.AccountRepository,
+	outboxRepo repository.OutboxRepository,
+	auditService service.AuditService,
+) service.BalanceAlertService {
+	return &balanceAlertService{
+		alertRepo:   alertRepo,
+		accountRepo: accountRepo,
+		outboxRepo:  outboxRepo,
+		audit:       auditService,
+	}
+}
+
+func (s *balanceAlertService) Create(ctx context.Context, userID uuid.UUID, input *entity.CreateBalanceAlertInput) (*entity.BalanceAlert, error) {
+	threshold, err := parseThreshold(input.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+	}
+	if account == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if account.UserID != userID {
+		return nil, apperror.ErrForbidden
+	}
+
+	now := time.Now().UTC()
+	alert := &entity.BalanceAlert{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccountID:   account.ID,
+		Condition:   entity.BalanceAlertCondition(input.Condition),
+		Threshold:   threshold,
+		Currency:    account.Currency,
+		Recurring:   input.Recurring,
+		NotifyEmail: input.NotifyEmail,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create balance alert", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "balance_alert.create", "balance_alert", &alert.ID, nil, map[string]interface{}{
+		"account_id": alert.AccountID,
+		"condition":  alert.Condition,
+		"threshold":  alert.Threshold.String(),
+		"recurring":  alert.Recurring,
+	}, info.IPAddress, info.UserAgent)
+
+	return alert, nil
+}
+
+func (s *balanceAlertService) GetByID(ctx context.Context, userID, id uuid.UUID) (*entity.BalanceAlert, error) {
+	alert, err := s.alertRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get balance alert", 500)
+	}
+	if alert == nil || alert.UserID != userID {
+		return nil, apperror.ErrBalanceAlertNotFound
+	}
+	return alert, nil
+}
+
+func (s *balanceAlertService) List(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.BalanceAlert, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	alerts, err := s.alertRepo.GetByUserID(ctx, userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get balance alerts", 500)
+	}
+
+	total, err := s.alertRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count balance alerts", 500)
+	}
+
+	return alerts, total, nil
+}
+
+// Update changes an alert's threshold and delivery. Setting Active re-arms
+// a one-shot alert that has already fired.
+func (s *balanceAlertService) Update(ctx context.Context, userID, id uuid.UUID, input *entity.UpdateBalanceAlertInput) (*entity.BalanceAlert, error) {
+	alert, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := map[string]interface{}{
+		"threshold":    alert.Threshold.String(),
+		"recurring":    alert.Recurring,
+		"notify_email": alert.NotifyEmail,
+		"active":       alert.Active,
+	}
+
+	if input.Threshold != nil {
+		threshold, err := parseThreshold(*input.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		alert.Threshold = threshold
+	}
+	if input.Recurring != nil {
+		alert.Recurring = *input.Recurring
+	}
+	if input.NotifyEmail != nil {
+		alert.NotifyEmail = *input.NotifyEmail
+	}
+	if input.Active != nil {
+		alert.Active = *input.Active
+	}
+	alert.UpdatedAt = time.Now().UTC()
+
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update balance alert", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "balance_alert.update", "balance_alert", &alert.ID, oldValues, map[string]interface{}{
+		"threshold":    alert.Threshold.String(),
+		"recurring":    alert.Recurring,
+		"notify_email": alert.NotifyEmail,
+		"active":       alert.Active,
+	}, info.IPAddress, info.UserAgent)
+
+	return alert, nil
+}
+
+func (s *balanceAlertService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.alertRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to delete balance alert", 500)
+	}
+	if !deleted {
+		return apperror.ErrBalanceAlertNotFound
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "balance_alert.delete", "balance_alert", &id, nil, nil, info.IPAddress, info.UserAgent)
+
+	return nil
+}
+
+// Evaluate fires the active alerts on the changed accounts whose condition
+// the change satisfies. It must run in the transaction that changed the
+// balances, so an alert fires if and only if the change commits.
+func (s *balanceAlertService) Evaluate(txCtx context.Context, changes ...entity.BalanceChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	byAccount := make(map[uuid.UUID]entity.BalanceChange, len(changes))
+	accountIDs := make([]uuid.UUID, 0, len(changes))
+	for _, change := range changes {
+		if _, ok := byAccount[change.Account.ID]; !ok {
+			accountIDs = append(accountIDs, change.Account.ID)
+		}
+		byAccount[change.Account.ID] = change
+	}
+
+	alerts, err := s.alertRepo.GetActiveByAccountIDs(txCtx, accountIDs)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get balance alerts", 500)
+	}
+
+	now := time.Now().UTC()
+	for _, alert := range alerts {
+		change := byAccount[alert.AccountID]
+		if !alert.Triggered(change.Previous, change.Current) {
+			continue
+		}
+
+		if err := s.alertRepo.MarkTriggered(txCtx, alert.ID, now); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update balance alert", 500)
+		}
+		alert.LastTriggeredAt = &now
+		alert.Active = alert.Recurring
+
+		scale := change.Account.Currency.DisplayScale()
+		event, err := entity.NewOutboxEvent(entity.EventBalanceAlertTriggered, "balance_alert", alert.ID, map[string]interface{}{
+			"alert":            alert.ToResponse(entity.AmountFull),
+			"user_id":          alert.UserID,
+			"account_id":       change.Account.ID,
+			"account_number":   change.Account.AccountNumber,
+			"previous_balance": money.Format(change.Previous, scale),
+			"balance":          money.Format(change.Current, scale),
+			"notify_email":     alert.NotifyEmail,
+		})
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to encode balance alert event", 500)
+		}
+		if err := s.outboxRepo.Create(txCtx, event); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to record balance alert event", 500)
+		}
+	}
+	return nil
+}
+
+func parseThreshold(value string) (decimal.Decimal, error) {
+	threshold, err := decimal.NewFromString(value)
+	if err != nil || threshold.IsNegative() || money.Check(threshold) != nil {
+		return decimal.Zero, apperror.ErrInvalidAmount
+	}
+	return threshold, nil
+}
@@ -0,0 +1,154 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+const (
+	keyPrefix       = "gbk_"
+	displayedPrefix = len(keyPrefix) + 8
+)
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   repository.UserRepository
+	audit      service.AuditService
+}
+
+func NewAPIKeyService(
+	apiKeyRepo repository.APIKeyRepository,
+	userRepo repository.UserRepository,
+	auditService service.AuditService,
+) service.APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		audit:      auditService,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, userID uuid.UUID, input *entity.CreateAPIKeyInput) (*entity.CreatedAPIKey, error) {
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, apperror.New("INVALID_EXPIRY", "Expiry must be in the future", 400)
+	}
+
+	plaintext, err := generateKey()
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate API key", 500)
+	}
+
+	key := &entity.APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(input.Name),
+		KeyPrefix: plaintext[:displayedPrefix],
+		KeyHash:   hashKey(plaintext),
+		Scopes:    dedupeScopes(input.Scopes),
+		ExpiresAt: input.ExpiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create API key", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "api_key.create", "api_key", &key.ID, nil, map[string]interface{}{
+		"name":   key.Name,
+		"scopes": key.Scopes,
+	}, info.IPAddress, info.UserAgent)
+
+	return &entity.CreatedAPIKey{APIKey: key, Key: plaintext}, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, userID uuid.UUID) ([]*entity.APIKey, error) {
+	keys, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get API keys", 500)
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, userID, keyID uuid.UUID) error {
+	revoked, err := s.apiKeyRepo.Revoke(ctx, keyID, userID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke API key", 500)
+	}
+	if !revoked {
+		return apperror.ErrAPIKeyNotFound
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "api_key.revoke", "api_key", &keyID, nil, nil, info.IPAddress, info.UserAgent)
+
+	return nil
+}
+
+// Authenticate resolves a plaintext key to the key record and its owner.
+// Unknown, revoked and expired keys are indistinguishable to the caller.
+func (s *apiKeyService) Authenticate(ctx context.Context, plaintext string) (*entity.APIKey, *entity.User, error) {
+	if !strings.HasPrefix(plaintext, keyPrefix) {
+		return nil, nil, apperror.ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashKey(plaintext))
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to validate API key", 500)
+	}
+	if key == nil || !key.IsActive(time.Now()) {
+		return nil, nil, apperror.ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil {
+		return nil, nil, apperror.ErrInvalidAPIKey
+	}
+	if user.Status == entity.UserStatusSuspended {
+		return nil, nil, apperror.ErrUserSuspended
+	}
+
+	_ = s.apiKeyRepo.TouchLastUsed(ctx, key.ID)
+
+	return key, user, nil
+}
+
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+func dedupeScopes(scopes []entity.APIKeyScope) []entity.APIKeyScope {
+	seen := make(map[entity.APIKeyScope]bool, len(scopes))
+	out := make([]entity.APIKeyScope, 0, len(scopes))
+	for _, s := range scopes {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+package audit
+
+import "context"
+
+type requestInfoKey struct{}
+
+// RequestInfo identifies the client behind the request being audited.
+type RequestInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the client details stored by WithRequestInfo, or
+// empty values for work that did not start from an HTTP request.
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
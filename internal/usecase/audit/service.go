@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+type auditService struct {
+	auditLogRepo repository.AuditLogRepository
+	logger       *logger.Logger
+}
+
+func NewAuditService(auditLogRepo repository.AuditLogRepository, log *logger.Logger) service.AuditService {
+	return &auditService{
+		auditLogRepo: auditLogRepo,
+		logger:       log,
+	}
+}
+
+// Record writes an audit entry. It is best-effort: a failed write is logged
+// rather than returned so auditing never fails an operation that has already
+// been committed.
+func (s *auditService) Record(
+	ctx context.Context,
+	userID *uuid.UUID,
+	action string,
+	entityType string,
+	entityID *uuid.UUID,
+	oldValues map[string]interface{},
+	newValues map[string]interface{},
+	ipAddress string,
+	userAgent string,
+) {
+	log := &entity.AuditLog{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		OldValues:  oldValues,
+		NewValues:  newValues,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := s.auditLogRepo.Create(ctx, log); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("action", action).
+			Str("entity_type", entityType).
+			Msg("Failed to write audit log")
+	}
+}
+
+func (s *auditService) GetByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.AuditLog, int64, error) {
+	limit, offset := pageBounds(page, pageSize)
+
+	logs, err := s.auditLogRepo.GetByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get audit logs", 500)
+	}
+
+	total, err := s.auditLogRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count audit logs", 500)
+	}
+
+	return logs, total, nil
+}
+
+func (s *auditService) GetByEntity(ctx context.Context, entityType string, entityID uuid.UUID, page, pageSize int) ([]*entity.AuditLog, int64, error) {
+	limit, offset := pageBounds(page, pageSize)
+
+	logs, err := s.auditLogRepo.GetByEntityID(ctx, entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get audit logs", 500)
+	}
+
+	total, err := s.auditLogRepo.CountByEntityID(ctx, entityType, entityID)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count audit logs", 500)
+	}
+
+	return logs, total, nil
+}
+
+func (s *auditService) GetByAction(ctx context.Context, action string, page, pageSize int) ([]*entity.AuditLog, int64, error) {
+	limit, offset := pageBounds(page, pageSize)
+
+	logs, err := s.auditLogRepo.GetByAction(ctx, action, limit, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get audit logs", 500)
+	}
+
+	total, err := s.auditLogRepo.CountByAction(ctx, action)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count audit logs", 500)
+	}
+
+	return logs, total, nil
+}
+
+func pageBounds(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	return pageSize, (page - 1) * pageSize
+}
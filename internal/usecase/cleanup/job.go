@@ -0,0 +1,66 @@
+package cleanup
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// Sweeper removes or retires rows of a single type that are older than now
+// minus Retention.
+type Sweeper struct {
+	Name      string
+	Retention time.Duration
+	Sweep     func(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Job struct {
+	interval time.Duration
+	sweepers []Sweeper
+	logger   *logger.Logger
+}
+
+func NewJob(interval time.Duration, log *logger.Logger, sweepers ...Sweeper) *Job {
+	return &Job{
+		interval: interval,
+		sweepers: sweepers,
+		logger:   log,
+	}
+}
+
+// Start runs a sweep on every tick until ctx is cancelled.
+func (j *Job) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce runs every sweeper once. A failing sweeper is logged and counted but
+// does not prevent the remaining ones from running.
+func (j *Job) RunOnce(ctx context.Context) {
+	now := time.Now().UTC()
+
+	for _, sweeper := range j.sweepers {
+		deleted, err := sweeper.Sweep(ctx, now.Add(-sweeper.Retention))
+		if err != nil {
+			metrics.CleanupErrorsTotal.WithLabelValues(sweeper.Name).Inc()
+			j.logger.Error().Err(err).Str("type", sweeper.Name).Msg("Cleanup sweep failed")
+			continue
+		}
+
+		metrics.CleanupDeletedTotal.WithLabelValues(sweeper.Name).Add(float64(deleted))
+		if deleted > 0 {
+			j.logger.Info().Str("type", sweeper.Name).Int64("deleted", deleted).Msg("Cleanup sweep completed")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cleanup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+)
+
+// table is a sweepable set of rows keyed by the time they expire.
+type table struct {
+	rows []time.Time
+}
+
+func (t *table) sweep(_ context.Context, before time.Time) (int64, error) {
+	var kept []time.Time
+	for _, expiresAt := range t.rows {
+		if !expiresAt.Before(before) {
+			kept = append(kept, expiresAt)
+		}
+	}
+	deleted := int64(len(t.rows) - len(kept))
+	t.rows = kept
+	return deleted, nil
+}
+
+func newTestLogger(buf *bytes.Buffer) *logger.Logger {
+	log := zerolog.New(buf)
+	return &logger.Logger{Logger: &log}
+}
+
+func TestRunOnceSweepsEveryTypeWithItsRetention(t *testing.T) {
+	now := time.Now().UTC()
+
+	refreshTokens := &table{rows: []time.Time{now.Add(-2 * time.Hour), now.Add(time.Hour)}}
+	resetTokens := &table{rows: []time.Time{now.Add(-2 * time.Hour), now.Add(-30 * time.Minute)}}
+	idempotencyKeys := &table{rows: []time.Time{now.Add(-48 * time.Hour), now.Add(-2 * time.Hour)}}
+
+	var buf bytes.Buffer
+	job := NewJob(time.Minute, newTestLogger(&buf),
+		Sweeper{Name: "refresh_token", Sweep: refreshTokens.sweep},
+		Sweeper{Name: "password_reset_token", Retention: time.Hour, Sweep: resetTokens.sweep},
+		Sweeper{Name: "idempotency_key", Retention: 24 * time.Hour, Sweep: idempotencyKeys.sweep},
+	)
+	job.RunOnce(context.Background())
+
+	for name, tbl := range map[string]*table{
+		"refresh_token":        refreshTokens,
+		"password_reset_token": resetTokens,
+		"idempotency_key":      idempotencyKeys,
+	} {
+		if len(tbl.rows) != 1 {
+			t.Errorf("%s: %d rows left after the sweep, want 1", name, len(tbl.rows))
+		}
+	}
+}
+
+func TestRunOnceContinuesPastFailingSweeper(t *testing.T) {
+	now := time.Now().UTC()
+	resetTokens := &table{rows: []time.Time{now.Add(-time.Hour)}}
+
+	var buf bytes.Buffer
+	job := NewJob(time.Minute, newTestLogger(&buf),
+		Sweeper{Name: "refresh_token", Sweep: func(context.Context, time.Time) (int64, error) {
+			return 0, errors.New("connection refused")
+		}},
+		Sweeper{Name: "password_reset_token", Sweep: resetTokens.sweep},
+	)
+	job.RunOnce(context.Background())
+
+	if len(resetTokens.rows) != 0 {
+		t.Fatal("a failing sweeper stopped the ones after it")
+	}
+	if !strings.Contains(buf.String(), `"type":"refresh_token"`) || !strings.Contains(buf.String(), "connection refused") {
+		t.Fatalf("failure not logged with its type: %s", buf.String())
+	}
+}
+
+func TestStartSweepsOnEachTickUntilCancelled(t *testing.T) {
+	sweeps := make(chan struct{}, 10)
+
+	var buf bytes.Buffer
+	job := NewJob(time.Millisecond, newTestLogger(&buf), Sweeper{
+		Name: "refresh_token",
+		Sweep: func(context.Context, time.Time) (int64, error) {
+			select {
+			case sweeps <- struct{}{}:
+			default:
+			}
+			return 0, nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		job.Start(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-sweeps:
+		case <-time.After(time.Second):
+			t.Fatal("no sweep within a second")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after cancel")
+	}
+}
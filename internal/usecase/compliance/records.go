@@ -0,0 +1,241 @@
+package compliance
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/money"
+)
+
+const (
+	recordAccount   = "account"
+	recordTransfer  = "transfer"
+	recordSignature = "signature"
+)
+
+// recordWriter encodes export records in one output format.
+type recordWriter interface {
+	header() error
+	account(*entity.Account) error
+	transfer(*entity.ComplianceTransferRow) error
+	signature(string) error
+	flush() error
+}
+
+func newRecordWriter(format entity.ComplianceExportFormat, w io.Writer) recordWriter {
+	if format == entity.ComplianceExportJSONL {
+		return &jsonlWriter{enc: json.NewEncoder(w)}
+	}
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// csvColumns is shared by every row; a row leaves blank the columns that do
+// not apply to its record_type. The signature row carries the algorithm in
+// the status column and the signature in the id column.
+var csvColumns = []string{
+	"record_type",
+	"id",
+	"reference_number",
+	"user_id",
+	"account_number",
+	"account_type",
+	"from_account_number",
+	"to_account_number",
+	"amount",
+	"balance",
+	"fee",
+	"currency",
+	"exchange_rate",
+	"converted_amount",
+	"converted_currency",
+	"status",
+	"status_reason",
+	"created_at",
+	"completed_at",
+	"closed_at",
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) header() error {
+	return c.w.Write(csvColumns)
+}
+
+func (c *csvWriter) row(values map[string]string) error {
+	record := make([]string, len(csvColumns))
+	for i, column := range csvColumns {
+		record[i] = values[column]
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) account(a *entity.Account) error {
+	return c.row(map[string]string{
+		"record_type":    recordAccount,
+		"id":             a.ID.String(),
+		"user_id":        a.UserID.String(),
+		"account_number": a.AccountNumber,
+		"account_type":   string(a.AccountType),
+		"balance":        money.FormatFull(a.Balance),
+		"currency":       string(a.Currency),
+		"status":         string(a.Status),
+		"status_reason":  a.StatusReason,
+		"created_at":     formatTime(&a.CreatedAt),
+		"closed_at":      formatTime(a.DeletedAt),
+	})
+}
+
+func (c *csvWriter) transfer(t *entity.ComplianceTransferRow) error {
+	convertedCurrency := ""
+	if t.ConvertedCurrency != nil {
+		convertedCurrency = string(*t.ConvertedCurrency)
+	}
+	exchangeRate := ""
+	if t.ExchangeRate != nil {
+		exchangeRate = t.ExchangeRate.String()
+	}
+	return c.row(map[string]string{
+		"record_type":         recordTransfer,
+		"id":                  t.ID.String(),
+		"reference_number":    t.ReferenceNumber,
+		"from_account_number": t.FromAccountNumber,
+		"to_account_number":   t.ToAccountNumber,
+		"amount":              money.FormatFull(t.Amount),
+		"fee":                 money.FormatFull(t.Fee),
+		"currency":            string(t.Currency),
+		"exchange_rate":       exchangeRate,
+		"converted_amount":    formatAmount(t.ConvertedAmount),
+		"converted_currency":  convertedCurrency,
+		"status":              string(t.Status),
+		"created_at":          formatTime(&t.CreatedAt),
+		"completed_at":        formatTime(t.CompletedAt),
+	})
+}
+
+func (c *csvWriter) signature(signature string) error {
+	if err := c.row(map[string]string{
+		"record_type": recordSignature,
+		"id":          signature,
+		"status":      SignatureAlgorithm,
+	}); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+type accountRecord struct {
+	RecordType    string               `json:"record_type"`
+	ID            string               `json:"id"`
+	UserID        string               `json:"user_id"`
+	AccountNumber string               `json:"account_number"`
+	AccountType   entity.AccountType   `json:"account_type"`
+	Balance       string               `json:"balance"`
+	Currency      entity.Currency      `json:"currency"`
+	Status        entity.AccountStatus `json:"status"`
+	StatusReason  string               `json:"status_reason,omitempty"`
+	CreatedAt     string               `json:"created_at"`
+	ClosedAt      string               `json:"closed_at,omitempty"`
+}
+
+type transferRecord struct {
+	RecordType        string                `json:"record_type"`
+	ID                string                `json:"id"`
+	ReferenceNumber   string                `json:"reference_number"`
+	FromAccountNumber string                `json:"from_account_number"`
+	ToAccountNumber   string                `json:"to_account_number"`
+	Amount            string                `json:"amount"`
+	Fee               string                `json:"fee"`
+	Currency          entity.Currency       `json:"currency"`
+	ExchangeRate      *decimal.Decimal      `json:"exchange_rate,omitempty"`
+	ConvertedAmount   string                `json:"converted_amount,omitempty"`
+	ConvertedCurrency *entity.Currency      `json:"converted_currency,omitempty"`
+	Status            entity.TransferStatus `json:"status"`
+	CreatedAt         string                `json:"created_at"`
+	CompletedAt       string                `json:"completed_at,omitempty"`
+}
+
+type signatureRecord struct {
+	RecordType string `json:"record_type"`
+	Algorithm  string `json:"algorithm"`
+	Signature  string `json:"signature"`
+}
+
+func (j *jsonlWriter) header() error {
+	return nil
+}
+
+func (j *jsonlWriter) account(a *entity.Account) error {
+	return j.enc.Encode(&accountRecord{
+		RecordType:    recordAccount,
+		ID:            a.ID.String(),
+		UserID:        a.UserID.String(),
+		AccountNumber: a.AccountNumber,
+		AccountType:   a.AccountType,
+		Balance:       money.FormatFull(a.Balance),
+		Currency:      a.Currency,
+		Status:        a.Status,
+		StatusReason:  a.StatusReason,
+		CreatedAt:     formatTime(&a.CreatedAt),
+		ClosedAt:      formatTime(a.DeletedAt),
+	})
+}
+
+func (j *jsonlWriter) transfer(t *entity.ComplianceTransferRow) error {
+	return j.enc.Encode(&transferRecord{
+		RecordType:        recordTransfer,
+		ID:                t.ID.String(),
+		ReferenceNumber:   t.ReferenceNumber,
+		FromAccountNumber: t.FromAccountNumber,
+		ToAccountNumber:   t.ToAccountNumber,
+		Amount:            money.FormatFull(t.Amount),
+		Fee:               money.FormatFull(t.Fee),
+		Currency:          t.Currency,
+		ExchangeRate:      t.ExchangeRate,
+		ConvertedAmount:   formatAmount(t.ConvertedAmount),
+		ConvertedCurrency: t.ConvertedCurrency,
+		Status:            t.Status,
+		CreatedAt:         formatTime(&t.CreatedAt),
+		CompletedAt:       formatTime(t.CompletedAt),
+	})
+}
+
+func (j *jsonlWriter) signature(signature string) error {
+	return j.enc.Encode(&signatureRecord{
+		RecordType: recordSignature,
+		Algorithm:  SignatureAlgorithm,
+		Signature:  signature,
+	})
+}
+
+func (j *jsonlWriter) flush() error {
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatAmount(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+	return money.FormatFull(*d)
+}
@@ -0,0 +1,110 @@
+package compliance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+// SignatureAlgorithm names how export signatures are computed.
+const SignatureAlgorithm = "HMAC-SHA256"
+
+type complianceService struct {
+	accountRepo  repository.AccountRepository
+	transferRepo repository.TransferRepository
+	audit        service.AuditService
+	signingKey   []byte
+}
+
+// NewComplianceService creates the service behind regulatory exports. An
+// empty signing key disables exports.
+func NewComplianceService(accountRepo repository.AccountRepository, transferRepo repository.TransferRepository, auditService service.AuditService, signingKey string) service.ComplianceService {
+	return &complianceService{
+		accountRepo:  accountRepo,
+		transferRepo: transferRepo,
+		audit:        auditService,
+		signingKey:   []byte(signingKey),
+	}
+}
+
+func (s *complianceService) Enabled() bool {
+	return len(s.signingKey) > 0
+}
+
+// Export streams every account opened before to, and every transfer created
+// in [from, to), to w in the given format. Records are written as they are
+// read. The output ends with a signature record holding the hex
+// HMAC-SHA256 of everything before it, which is also returned. Every
+// export, finished or not, is audited against the actor.
+func (s *complianceService) Export(ctx context.Context, actorID uuid.UUID, format entity.ComplianceExportFormat, from, to time.Time, w io.Writer) (*entity.ComplianceExportSummary, error) {
+	if !s.Enabled() {
+		return nil, apperror.ErrComplianceExportDisabled
+	}
+	if !format.IsValid() || !from.Before(to) {
+		return nil, apperror.ErrBadRequest
+	}
+
+	mac := hmac.New(sha256.New, s.signingKey)
+	records := newRecordWriter(format, io.MultiWriter(w, mac))
+	summary := &entity.ComplianceExportSummary{}
+
+	err := s.export(ctx, records, from, to, summary)
+	if err == nil {
+		// The signature covers the records, so they must all have reached
+		// the MAC before it is taken.
+		if err = records.flush(); err == nil {
+			summary.Signature = hex.EncodeToString(mac.Sum(nil))
+			err = newRecordWriter(format, w).signature(summary.Signature)
+		}
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &actorID, "compliance.export", "compliance_export", nil, nil,
+		map[string]interface{}{
+			"format":    format,
+			"from":      from,
+			"to":        to,
+			"accounts":  summary.Accounts,
+			"transfers": summary.Transfers,
+			"signature": summary.Signature,
+			"completed": err == nil,
+		}, info.IPAddress, info.UserAgent)
+
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (s *complianceService) export(ctx context.Context, records recordWriter, from, to time.Time, summary *entity.ComplianceExportSummary) error {
+	if err := records.header(); err != nil {
+		return err
+	}
+
+	err := s.accountRepo.ExportOpenedBefore(ctx, to, func(account *entity.Account) error {
+		summary.Accounts++
+		return records.account(account)
+	})
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to export accounts", 500)
+	}
+
+	err = s.transferRepo.ExportForCompliance(ctx, from, to, func(row *entity.ComplianceTransferRow) error {
+		summary.Transfers++
+		return records.transfer(row)
+	})
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to export transfers", 500)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package compliance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+const testSigningKey = "test-signing-key"
+
+var (
+	rangeFrom = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rangeTo   = time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// exportAccounts serves ExportOpenedBefore from a fixed list. Other methods
+// are not implemented.
+type exportAccounts struct {
+	repository.AccountRepository
+	accounts []*entity.Account
+	before   time.Time
+}
+
+func (r *exportAccounts) ExportOpenedBefore(_ context.Context, before time.Time, fn func(*entity.Account) error) error {
+	r.before = before
+	for _, account := range r.accounts {
+		if err := fn(account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportTransfers serves ExportForCompliance from a fixed list, calling
+// onRow before each row is handed over. Other methods are not implemented.
+type exportTransfers struct {
+	repository.TransferRepository
+	rows     []*entity.ComplianceTransferRow
+	from, to time.Time
+	onRow    func()
+}
+
+func (r *exportTransfers) ExportForCompliance(_ context.Context, from, to time.Time, fn func(*entity.ComplianceTransferRow) error) error {
+	r.from, r.to = from, to
+	for _, row := range r.rows {
+		if r.onRow != nil {
+			r.onRow()
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordedAudit keeps every audit entry. Other methods are not implemented.
+type recordedAudit struct {
+	service.AuditService
+	actions []string
+	values  []map[string]interface{}
+}
+
+func (a *recordedAudit) Record(_ context.Context, _ *uuid.UUID, action, _ string, _ *uuid.UUID, _, newValues map[string]interface{}, _, _ string) {
+	a.actions = append(a.actions, action)
+	a.values = append(a.values, newValues)
+}
+
+type fixture struct {
+	service   *complianceService
+	accounts  *exportAccounts
+	transfers *exportTransfers
+	audit     *recordedAudit
+	account   *entity.Account
+	transfer  *entity.ComplianceTransferRow
+}
+
+func newFixture(signingKey string) *fixture {
+	account := entity.NewAccount(uuid.New(), "", entity.AccountTypeChecking, entity.CurrencyUSD)
+	account.AccountNumber = "1234567890"
+	account.Balance = decimal.RequireFromString("100.1234")
+	account.CreatedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	completedAt := time.Date(2024, 6, 15, 12, 0, 1, 0, time.UTC)
+	transfer := &entity.ComplianceTransferRow{
+		ID:                uuid.New(),
+		ReferenceNumber:   "TRF-1",
+		FromAccountNumber: "1234567890",
+		ToAccountNumber:   "0987654321",
+		Amount:            decimal.RequireFromString("25.5"),
+		Currency:          entity.CurrencyUSD,
+		Fee:               decimal.Zero,
+		Status:            entity.TransferStatusCompleted,
+		CreatedAt:         time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC),
+		CompletedAt:       &completedAt,
+	}
+
+	f := &fixture{
+		accounts:  &exportAccounts{accounts: []*entity.Account{account}},
+		transfers: &exportTransfers{rows: []*entity.ComplianceTransferRow{transfer}},
+		audit:     &recordedAudit{},
+		account:   account,
+		transfer:  transfer,
+	}
+	f.service = NewComplianceService(f.accounts, f.transfers, f.audit, signingKey).(*complianceService)
+	return f
+}
+
+// verify checks that signature is the HMAC of signed under testSigningKey.
+func verify(t *testing.T, signed []byte, signature string) {
+	t.Helper()
+
+	mac := hmac.New(sha256.New, []byte(testSigningKey))
+	mac.Write(signed)
+	if want := hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Fatalf("signature = %s, want %s", signature, want)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	f := newFixture(testSigningKey)
+	var out bytes.Buffer
+
+	summary, err := f.service.Export(context.Background(), uuid.New(), entity.ComplianceExportCSV, rangeFrom, rangeTo, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Accounts != 1 || summary.Transfers != 1 {
+		t.Fatalf("summary = %+v, want 1 account and 1 transfer", summary)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(out.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want header, account, transfer and signature", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(csvColumns, ",") {
+		t.Fatalf("header = %v", rows[0])
+	}
+
+	column := func(row []string, name string) string {
+		for i, c := range csvColumns {
+			if c == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("no column %s", name)
+		return ""
+	}
+
+	account, transfer, signature := rows[1], rows[2], rows[3]
+	if column(account, "record_type") != "account" || column(account, "account_number") != "1234567890" ||
+		column(account, "balance") != "100.1234" || column(account, "created_at") != "2024-01-02T03:04:05Z" {
+		t.Fatalf("account row = %v", account)
+	}
+	if column(transfer, "record_type") != "transfer" || column(transfer, "to_account_number") != "0987654321" ||
+		column(transfer, "amount") != "25.5000" || column(transfer, "completed_at") != "2024-06-15T12:00:01Z" {
+		t.Fatalf("transfer row = %v", transfer)
+	}
+	if column(signature, "record_type") != "signature" || column(signature, "status") != SignatureAlgorithm {
+		t.Fatalf("signature row = %v", signature)
+	}
+
+	// The signature covers every byte before its own row.
+	signed := out.Bytes()[:bytes.LastIndex(out.Bytes(), []byte("signature,"))]
+	verify(t, signed, column(signature, "id"))
+	if summary.Signature != column(signature, "id") {
+		t.Fatalf("returned signature %s differs from the written one", summary.Signature)
+	}
+}
+
+func TestExportJSONL(t *testing.T) {
+	f := newFixture(testSigningKey)
+	var out bytes.Buffer
+
+	summary, err := f.service.Export(context.Background(), uuid.New(), entity.ComplianceExportJSONL, rangeFrom, rangeTo, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(out.Bytes()))
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want account, transfer and signature", len(lines))
+	}
+
+	if lines[0]["record_type"] != "account" || lines[0]["id"] != f.account.ID.String() || lines[0]["balance"] != "100.1234" {
+		t.Fatalf("account line = %v", lines[0])
+	}
+	if _, ok := lines[0]["closed_at"]; ok {
+		t.Fatalf("open account has closed_at: %v", lines[0])
+	}
+	if lines[1]["record_type"] != "transfer" || lines[1]["reference_number"] != "TRF-1" || lines[1]["amount"] != "25.5000" {
+		t.Fatalf("transfer line = %v", lines[1])
+	}
+	if lines[2]["record_type"] != "signature" || lines[2]["algorithm"] != SignatureAlgorithm || lines[2]["signature"] != summary.Signature {
+		t.Fatalf("signature line = %v", lines[2])
+	}
+
+	signed := out.Bytes()[:bytes.LastIndex(out.Bytes(), []byte(`{"record_type":"signature"`))]
+	verify(t, signed, summary.Signature)
+}
+
+func TestExportStreamsRecords(t *testing.T) {
+	f := newFixture(testSigningKey)
+	var out bytes.Buffer
+
+	// The account is written out before the first transfer is read.
+	var writtenBeforeTransfers string
+	f.transfers.onRow = func() { writtenBeforeTransfers = out.String() }
+
+	if _, err := f.service.Export(context.Background(), uuid.New(), entity.ComplianceExportJSONL, rangeFrom, rangeTo, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(writtenBeforeTransfers, f.account.ID.String()) {
+		t.Fatalf("account not yet written when transfers were read: %q", writtenBeforeTransfers)
+	}
+}
+
+func TestExportDateRange(t *testing.T) {
+	f := newFixture(testSigningKey)
+
+	if _, err := f.service.Export(context.Background(), uuid.New(), entity.ComplianceExportCSV, rangeFrom, rangeTo, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if !f.transfers.from.Equal(rangeFrom) || !f.transfers.to.Equal(rangeTo) {
+		t.Fatalf("transfers read for [%v, %v), want [%v, %v)", f.transfers.from, f.transfers.to, rangeFrom, rangeTo)
+	}
+	if !f.accounts.before.Equal(rangeTo) {
+		t.Fatalf("accounts read opened before %v, want %v", f.accounts.before, rangeTo)
+	}
+
+	for _, bad := range [][2]time.Time{{rangeTo, rangeFrom}, {rangeFrom, rangeFrom}} {
+		if _, err := f.service.Export(context.Background(), uuid.New(), entity.ComplianceExportCSV, bad[0], bad[1], &bytes.Buffer{}); !errors.Is(err, apperror.ErrBadRequest) {
+			t.Errorf("range [%v, %v): err = %v, want ErrBadRequest", bad[0], bad[1], err)
+		}
+	}
+	if _, err := f.service.Export(context.Background(), uuid.New(), "xml", rangeFrom, rangeTo, &bytes.Buffer{}); !errors.Is(err, apperror.ErrBadRequest) {
+		t.Errorf("format xml: err = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestExportIsAudited(t *testing.T) {
+	f := newFixture(testSigningKey)
+
+	summary, err := f.service.Export(context.Background(), uuid.New(), entity.ComplianceExportCSV, rangeFrom, rangeTo, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.audit.actions) != 1 || f.audit.actions[0] != "compliance.export" {
+		t.Fatalf("audited %v, want one compliance.export", f.audit.actions)
+	}
+	values := f.audit.values[0]
+	if values["completed"] != true || values["signature"] != summary.Signature || values["transfers"] != int64(1) {
+		t.Fatalf("audit values = %v", values)
+	}
+}
+
+func TestExportDisabledWithoutSigningKey(t *testing.T) {
+	f := newFixture("")
+
+	if _, err := f.service.Export(context.Background(), uuid.New(), entity.ComplianceExportCSV, rangeFrom, rangeTo, &bytes.Buffer{}); !errors.Is(err, apperror.ErrComplianceExportDisabled) {
+		t.Fatalf("err = %v, want ErrComplianceExportDisabled", err)
+	}
+}
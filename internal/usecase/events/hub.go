@@ -0,0 +1,171 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// SlowConsumerPolicy decides what happens when a stream's buffer is full.
+type SlowConsumerPolicy string
+
+const (
+	// PolicyDropOldest discards the oldest buffered event to make room.
+	PolicyDropOldest SlowConsumerPolicy = "drop_oldest"
+	// PolicyDisconnect closes the stream; the client is expected to reconnect.
+	PolicyDisconnect SlowConsumerPolicy = "disconnect"
+)
+
+// ParsePolicy returns the named policy, defaulting to PolicyDropOldest.
+func ParsePolicy(s string) SlowConsumerPolicy {
+	if SlowConsumerPolicy(s) == PolicyDisconnect {
+		return PolicyDisconnect
+	}
+	return PolicyDropOldest
+}
+
+// Hub fans events out to the event streams open on this instance. Dispatch
+// never blocks on a client: each stream has a bounded buffer and the
+// configured policy applies when it is full.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[*subscription]struct{}
+	bufferSize  int
+	maxPerUser  int
+	policy      SlowConsumerPolicy
+	logger      *logger.Logger
+}
+
+func NewHub(bufferSize, maxPerUser int, policy SlowConsumerPolicy, log *logger.Logger) *Hub {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Hub{
+		subscribers: make(map[uuid.UUID]map[*subscription]struct{}),
+		bufferSize:  bufferSize,
+		maxPerUser:  maxPerUser,
+		policy:      policy,
+		logger:      log,
+	}
+}
+
+type subscription struct {
+	hub    *Hub
+	userID uuid.UUID
+	events chan *entity.OutboxEvent
+}
+
+func (s *subscription) Events() <-chan *entity.OutboxEvent {
+	return s.events
+}
+
+// Close detaches the stream from the hub. It is safe to call more than once
+// and after the hub has disconnected the stream itself.
+func (s *subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Subscribe opens a stream for the user's events. A user may hold at most
+// maxPerUser streams at once; a non-positive limit disables the cap.
+func (h *Hub) Subscribe(userID uuid.UUID) (service.EventSubscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.subscribers[userID]
+	if h.maxPerUser > 0 && len(subs) >= h.maxPerUser {
+		return nil, apperror.ErrTooManyStreams
+	}
+	if subs == nil {
+		subs = make(map[*subscription]struct{})
+		h.subscribers[userID] = subs
+	}
+
+	sub := &subscription{
+		hub:    h,
+		userID: userID,
+		events: make(chan *entity.OutboxEvent, h.bufferSize),
+	}
+	subs[sub] = struct{}{}
+	metrics.EventStreamsOpen.Inc()
+
+	return sub, nil
+}
+
+// Dispatch delivers event to every open stream of the users it concerns.
+func (h *Hub) Dispatch(event *entity.OutboxEvent) {
+	recipients := event.Recipients()
+	if len(recipients) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userID := range recipients {
+		for sub := range h.subscribers[userID] {
+			h.deliver(sub, event)
+		}
+	}
+}
+
+// Close disconnects every stream, e.g. on shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// deliver must be called with h.mu held. Only the hub sends on a stream's
+// channel, so after dropping one buffered event the retry cannot block.
+func (h *Hub) deliver(sub *subscription, event *entity.OutboxEvent) {
+	select {
+	case sub.events <- event:
+		return
+	default:
+	}
+
+	if h.policy == PolicyDisconnect {
+		metrics.EventStreamSlowDisconnectsTotal.Inc()
+		h.logger.Warn().Str("user_id", sub.userID.String()).Msg("Disconnecting slow event stream")
+		h.remove(sub)
+		return
+	}
+
+	select {
+	case <-sub.events:
+		metrics.EventStreamDroppedTotal.Inc()
+	default:
+	}
+	select {
+	case sub.events <- event:
+	default:
+		metrics.EventStreamDroppedTotal.Inc()
+	}
+}
+
+// remove must be called with h.mu held.
+func (h *Hub) remove(sub *subscription) {
+	subs := h.subscribers[sub.userID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+	close(sub.events)
+	metrics.EventStreamsOpen.Dec()
+}
@@ -0,0 +1,186 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+// counter reads an unlabelled counter from the default registry.
+func counter(t *testing.T, name string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func userEvent(t *testing.T, userID uuid.UUID) *entity.OutboxEvent {
+	t.Helper()
+
+	event, err := entity.NewOutboxEvent(entity.EventBalanceAlertTriggered, "account", uuid.New(), map[string]uuid.UUID{"user_id": userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// drain reads the events buffered on a stream and reports whether the
+// stream has been closed.
+func drain(sub <-chan *entity.OutboxEvent) ([]*entity.OutboxEvent, bool) {
+	var events []*entity.OutboxEvent
+	for {
+		select {
+		case event, ok := <-sub:
+			if !ok {
+				return events, true
+			}
+			events = append(events, event)
+		default:
+			return events, false
+		}
+	}
+}
+
+func TestSlowReaderDropsOldest(t *testing.T) {
+	hub := NewHub(3, 0, PolicyDropOldest, testutil.Logger())
+	userID := uuid.New()
+	sub, err := hub.Subscribe(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	before := counter(t, "gobank_event_stream_dropped_total")
+
+	// Nobody reads while five events arrive.
+	var sent []*entity.OutboxEvent
+	for i := 0; i < 5; i++ {
+		event := userEvent(t, userID)
+		sent = append(sent, event)
+		hub.Dispatch(event)
+	}
+
+	received, closed := drain(sub.Events())
+	if closed {
+		t.Fatal("stream closed under the drop-oldest policy")
+	}
+	if len(received) != 3 {
+		t.Fatalf("received %d events, want the buffer's 3", len(received))
+	}
+	for i, event := range received {
+		if event.ID != sent[i+2].ID {
+			t.Fatalf("event %d = %s, want the newest events in order", i, event.ID)
+		}
+	}
+	if got := counter(t, "gobank_event_stream_dropped_total") - before; got != 2 {
+		t.Fatalf("dropped counter went up by %v, want 2", got)
+	}
+}
+
+func TestSlowReaderDisconnected(t *testing.T) {
+	hub := NewHub(2, 0, PolicyDisconnect, testutil.Logger())
+	userID := uuid.New()
+	sub, err := hub.Subscribe(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := counter(t, "gobank_event_stream_slow_disconnects_total")
+	for i := 0; i < 3; i++ {
+		hub.Dispatch(userEvent(t, userID))
+	}
+
+	received, closed := drain(sub.Events())
+	if !closed {
+		t.Fatal("slow stream left open under the disconnect policy")
+	}
+	if len(received) != 2 {
+		t.Fatalf("received %d events before the disconnect, want 2", len(received))
+	}
+	if got := counter(t, "gobank_event_stream_slow_disconnects_total") - before; got != 1 {
+		t.Fatalf("disconnect counter went up by %v, want 1", got)
+	}
+
+	// Closing a stream the hub already dropped is harmless, and later events
+	// are not sent to it.
+	sub.Close()
+	hub.Dispatch(userEvent(t, userID))
+}
+
+func TestSubscribeCapsStreamsPerUser(t *testing.T) {
+	hub := NewHub(1, 2, PolicyDropOldest, testutil.Logger())
+	userID := uuid.New()
+
+	first, err := hub.Subscribe(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hub.Subscribe(userID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hub.Subscribe(userID); !errors.Is(err, apperror.ErrTooManyStreams) {
+		t.Fatalf("third stream = %v, want ErrTooManyStreams", err)
+	}
+	if _, err := hub.Subscribe(uuid.New()); err != nil {
+		t.Fatalf("another user's stream: %v", err)
+	}
+
+	first.Close()
+	if _, err := hub.Subscribe(userID); err != nil {
+		t.Fatalf("stream after closing one: %v", err)
+	}
+	hub.Close()
+}
+
+func TestDispatchOnlyReachesRecipients(t *testing.T) {
+	hub := NewHub(4, 0, PolicyDropOldest, testutil.Logger())
+	alice, bob := uuid.New(), uuid.New()
+	aliceSub, err := hub.Subscribe(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobSub, err := hub.Subscribe(bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hub.Dispatch(userEvent(t, alice))
+
+	if received, _ := drain(aliceSub.Events()); len(received) != 1 {
+		t.Fatalf("recipient received %d events, want 1", len(received))
+	}
+	if received, _ := drain(bobSub.Events()); len(received) != 0 {
+		t.Fatalf("other user received %d events, want 0", len(received))
+	}
+
+	hub.Close()
+	if _, closed := drain(aliceSub.Events()); !closed {
+		t.Fatal("stream left open after the hub closed")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for input, want := range map[string]SlowConsumerPolicy{
+		"disconnect":  PolicyDisconnect,
+		"drop_oldest": PolicyDropOldest,
+		"":            PolicyDropOldest,
+		"bogus":       PolicyDropOldest,
+	} {
+		if got := ParsePolicy(input); got != want {
+			t.Errorf("ParsePolicy(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package fee
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/money"
+)
+
+// Rule charges Flat plus Percent of the amount on each transfer.
+type Rule struct {
+	Flat    decimal.Decimal
+	Percent decimal.Decimal
+}
+
+// Schedule maps an account type to the rule charged on transfers out of
+// accounts of that type. Types without a rule are not charged.
+type Schedule map[entity.AccountType]Rule
+
+// ParseSchedule reads rules written as "TYPE=FLAT+PERCENT%" pairs separated
+// by commas, e.g. "checking=0.25+0.1%,savings=1.00". Either part of a rule may
+// be left out.
+func ParseSchedule(spec string) (Schedule, error) {
+	schedule := make(Schedule)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		accountType, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(accountType) == "" {
+			return nil, fmt.Errorf("transfer fee %q: expected TYPE=FLAT+PERCENT%%", entry)
+		}
+
+		var rule Rule
+		for _, part := range strings.Split(value, "+") {
+			part = strings.TrimSpace(part)
+			target := &rule.Flat
+			if strings.HasSuffix(part, "%") {
+				part = strings.TrimSuffix(part, "%")
+				target = &rule.Percent
+			}
+			amount, err := decimal.NewFromString(part)
+			if err != nil || amount.IsNegative() {
+				return nil, fmt.Errorf("transfer fee %q: invalid amount %q", entry, part)
+			}
+			*target = amount
+		}
+		schedule[entity.AccountType(strings.ToLower(strings.TrimSpace(accountType)))] = rule
+	}
+	return schedule, nil
+}
+
+type feeService struct {
+	schedule Schedule
+}
+
+func NewFeeService(schedule Schedule) service.FeeService {
+	return &feeService{schedule: schedule}
+}
+
+// Calculate returns the fee for sending amount from an account of the given
+// type, rounded to the currency's display precision.
+func (s *feeService) Calculate(accountType entity.AccountType, currency entity.Currency, amount decimal.Decimal) decimal.Decimal {
+	rule, ok := s.schedule[accountType]
+	if !ok {
+		return decimal.Zero
+	}
+
+	fee := rule.Flat.Add(amount.Mul(rule.Percent).Div(decimal.NewFromInt(100)))
+	return money.Round(fee, currency.DisplayScale(), money.RoundHalfEven)
+}
@@ -0,0 +1,42 @@
+package fx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+// Pairs is the set of currency conversions a deployment allows. An empty set
+// allows every pair the rate provider can quote.
+type Pairs map[string]struct{}
+
+// ParsePairs reads a comma-separated list of FROM:TO pairs, e.g.
+// "USD:EUR,EUR:USD". Pairs are directional: allowing USD:EUR does not allow
+// EUR:USD.
+func ParsePairs(spec string) (Pairs, error) {
+	pairs := make(Pairs)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		from, to, ok := strings.Cut(entry, ":")
+		from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+		if !ok || from == "" || to == "" || from == to {
+			return nil, fmt.Errorf("fx pair %q: expected FROM:TO", entry)
+		}
+		pairs[from+":"+to] = struct{}{}
+	}
+	return pairs, nil
+}
+
+// Allows reports whether converting from one currency to another is allowed.
+func (p Pairs) Allows(from, to entity.Currency) bool {
+	if len(p) == 0 || from == to {
+		return true
+	}
+	_, ok := p[string(from)+":"+string(to)]
+	return ok
+}
@@ -0,0 +1,48 @@
+package fx
+
+import (
+	"testing"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+func TestParsePairs(t *testing.T) {
+	pairs, err := ParsePairs(" usd:eur , GBP:USD,")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		from, to entity.Currency
+		want     bool
+	}{
+		{"USD", "EUR", true},
+		{"GBP", "USD", true},
+		{"EUR", "USD", false}, // pairs are directional
+		{"USD", "GBP", false},
+		{"USD", "USD", true},
+	}
+	for _, tt := range tests {
+		if got := pairs.Allows(tt.from, tt.to); got != tt.want {
+			t.Errorf("Allows(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyPairsAllowEverything(t *testing.T) {
+	pairs, err := ParsePairs("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pairs.Allows("USD", "EUR") || !Pairs(nil).Allows("EUR", "GBP") {
+		t.Fatal("an empty pair list restricts conversions")
+	}
+}
+
+func TestParsePairsRejectsBadEntries(t *testing.T) {
+	for _, spec := range []string{"USD", "USD:", ":EUR", "USD:USD", "USD-EUR"} {
+		if _, err := ParsePairs(spec); err == nil {
+			t.Errorf("%q parsed without error", spec)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/fxrate"
+)
+
+const rateCacheKeyPrefix = "fx_rate:"
+
+type fxService struct {
+	provider service.FXRateProvider
+	cache    service.CacheService
+	ttl      time.Duration
+	allowed  Pairs
+}
+
+// NewFXService returns an FXService that caches provider quotes in Redis. A
+// quote older than ttl, whether cached or fresh from the provider, is never
+// used. Only conversions in allowed are supported, or any if it is empty.
+func NewFXService(provider service.FXRateProvider, cache service.CacheService, ttl time.Duration, allowed Pairs) service.FXService {
+	return &fxService{
+		provider: provider,
+		cache:    cache,
+		ttl:      ttl,
+		allowed:  allowed,
+	}
+}
+
+func (s *fxService) Supports(from, to entity.Currency) bool {
+	return s.allowed.Allows(from, to)
+}
+
+func (s *fxService) Rate(ctx context.Context, from, to entity.Currency) (decimal.Decimal, error) {
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	key := rateCacheKeyPrefix + string(from) + ":" + string(to)
+
+	if cached, err := s.cache.Get(ctx, key); err == nil && cached != "" {
+		var quote entity.ExchangeRate
+		if err := json.Unmarshal([]byte(cached), &quote); err == nil && s.fresh(quote.AsOf) {
+			return quote.Rate, nil
+		}
+	}
+
+	rate, asOf, err := s.provider.Quote(ctx, string(from), string(to))
+	if errors.Is(err, fxrate.ErrUnsupportedPair) {
+		return decimal.Zero, apperror.ErrCurrencyPairUnsupported
+	}
+	if err != nil {
+		return decimal.Zero, apperror.Wrap(err, "EXCHANGE_RATE_UNAVAILABLE", "Exchange rate is temporarily unavailable", 503)
+	}
+	if !s.fresh(asOf) {
+		return decimal.Zero, apperror.ErrExchangeRateUnavailable
+	}
+
+	// Cache only for the rest of the quote's lifetime, so a cached rate is
+	// never older than ttl.
+	if remaining := int(s.ttl.Seconds() - time.Since(asOf).Seconds()); remaining > 0 {
+		_ = s.cache.Set(ctx, key, entity.ExchangeRate{From: from, To: to, Rate: rate, AsOf: asOf}, remaining)
+	}
+
+	return rate, nil
+}
+
+func (s *fxService) fresh(asOf time.Time) bool {
+	return time.Since(asOf) <= s.ttl
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+)
+
+type logPublisher struct {
+	logger *logger.Logger
+}
+
+// NewLogPublisher returns a publisher that writes each event to the log. It is
+// the default until a real transport is configured.
+func NewLogPublisher(log *logger.Logger) service.EventPublisher {
+	return &logPublisher{logger: log}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	p.logger.Info().
+		Str("event_id", event.ID.String()).
+		Str("event_type", event.EventType).
+		Str("aggregate_type", event.AggregateType).
+		Str("aggregate_id", event.AggregateID.String()).
+		RawJSON("payload", event.Payload).
+		Msg("Event published")
+	return nil
+}
+
+type fanoutPublisher struct {
+	publishers []service.EventPublisher
+}
+
+// NewFanoutPublisher publishes each event to every publisher in turn. It
+// stops at the first error so the relay retries the event; publishers that
+// already succeeded will see it again.
+func NewFanoutPublisher(publishers ...service.EventPublisher) service.EventPublisher {
+	return &fanoutPublisher{publishers: publishers}
+}
+
+func (p *fanoutPublisher) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+func TestFanoutStopsAtFirstFailure(t *testing.T) {
+	first, failing, last := &recorder{}, &recorder{failing: true}, &recorder{}
+	event := &entity.OutboxEvent{ID: uuid.New(), EventType: entity.EventTransferCompleted}
+
+	if err := NewFanoutPublisher(first, failing, last).Publish(context.Background(), event); err == nil {
+		t.Fatal("fanout hid a publisher's failure")
+	}
+	if len(first.events) != 1 {
+		t.Fatalf("first publisher saw %d events, want 1", len(first.events))
+	}
+	if len(last.events) != 0 {
+		t.Fatalf("publisher after the failure saw %d events, want 0", len(last.events))
+	}
+}
+
+func TestFanoutPublishesToAll(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	event := &entity.OutboxEvent{ID: uuid.New(), EventType: entity.EventTransferCompleted}
+
+	if err := NewFanoutPublisher(a, b).Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.events) != 1 || len(b.events) != 1 {
+		t.Fatalf("publishers saw %d and %d events, want 1 each", len(a.events), len(b.events))
+	}
+}
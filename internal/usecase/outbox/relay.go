@@ -0,0 +1,99 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// Relay publishes outbox events written by committed transactions. Each batch
+// is claimed, published and marked inside one database transaction, so a
+// crash before commit leaves the events unpublished and they are delivered
+// again on the next run. Delivery is therefore at least once; publishers must
+// tolerate duplicates.
+type Relay struct {
+	outboxRepo repository.OutboxRepository
+	db         *database.PostgresDB
+	publisher  service.EventPublisher
+	interval   time.Duration
+	batchSize  int
+	logger     *logger.Logger
+}
+
+func NewRelay(
+	outboxRepo repository.OutboxRepository,
+	db *database.PostgresDB,
+	publisher service.EventPublisher,
+	interval time.Duration,
+	batchSize int,
+	log *logger.Logger,
+) *Relay {
+	return &Relay{
+		outboxRepo: outboxRepo,
+		db:         db,
+		publisher:  publisher,
+		interval:   interval,
+		batchSize:  batchSize,
+		logger:     log,
+	}
+}
+
+// Start relays pending events on every tick until ctx is cancelled.
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RunOnce(ctx); err != nil {
+				r.logger.Error().Err(err).Msg("Outbox relay failed")
+			}
+		}
+	}
+}
+
+// RunOnce publishes up to one batch of pending events and returns how many
+// were published. An event whose publish fails stays pending and is retried on
+// a later run.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	published := 0
+
+	err := r.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		events, err := r.outboxRepo.GetUnpublishedForUpdate(txCtx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := r.publisher.Publish(ctx, event); err != nil {
+				metrics.OutboxPublishFailuresTotal.WithLabelValues(event.EventType).Inc()
+				r.logger.Warn().Err(err).
+					Str("event_id", event.ID.String()).
+					Str("event_type", event.EventType).
+					Int("attempts", event.Attempts+1).
+					Msg("Outbox event publish failed")
+				if err := r.outboxRepo.MarkFailed(txCtx, event.ID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := r.outboxRepo.MarkPublished(txCtx, event.ID); err != nil {
+				return err
+			}
+			metrics.OutboxPublishedTotal.WithLabelValues(event.EventType).Inc()
+			published++
+		}
+		return nil
+	})
+
+	return published, err
+}
@@ -0,0 +1,135 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/repository/postgres"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+// recorder collects published events. While failing is set, Publish returns
+// an error instead.
+type recorder struct {
+	events  []*entity.OutboxEvent
+	failing bool
+}
+
+func (r *recorder) Publish(_ context.Context, event *entity.OutboxEvent) error {
+	if r.failing {
+		return errors.New("broker unavailable")
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+// commitEvent writes an event the way services do, inside a committed
+// transaction, and returns it.
+func commitEvent(t *testing.T, db *database.PostgresDB, outboxRepo repository.OutboxRepository) *entity.OutboxEvent {
+	t.Helper()
+
+	event, err := entity.NewOutboxEvent(entity.EventTransferCompleted, "transfer", uuid.New(), map[string]string{"amount": "10.00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.WithTransaction(context.Background(), func(txCtx context.Context) error {
+		return outboxRepo.Create(txCtx, event)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestRelayDeliversEventsLeftByCrash(t *testing.T) {
+	db := testutil.Postgres(t)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	ctx := context.Background()
+
+	// The transaction commits and the process stops before any relay runs.
+	event := commitEvent(t, db, outboxRepo)
+
+	// A relay started afterwards finds and delivers it.
+	publisher := &recorder{}
+	relay := NewRelay(outboxRepo, db, publisher, time.Second, 10, testutil.Logger())
+
+	published, err := relay.RunOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if published != 1 || len(publisher.events) != 1 || publisher.events[0].ID != event.ID {
+		t.Fatalf("relay published %d events %v, want the committed event", published, publisher.events)
+	}
+
+	// Once marked, it is not delivered again.
+	if published, err := relay.RunOnce(ctx); err != nil || published != 0 {
+		t.Fatalf("second run published %d, %v; want 0", published, err)
+	}
+}
+
+func TestRelayRetriesFailedPublish(t *testing.T) {
+	db := testutil.Postgres(t)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	ctx := context.Background()
+
+	event := commitEvent(t, db, outboxRepo)
+	publisher := &recorder{failing: true}
+	relay := NewRelay(outboxRepo, db, publisher, time.Second, 10, testutil.Logger())
+
+	if published, err := relay.RunOnce(ctx); err != nil || published != 0 {
+		t.Fatalf("run with a failing publisher published %d, %v; want 0", published, err)
+	}
+
+	var pending []*entity.OutboxEvent
+	err := db.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		pending, err = outboxRepo.GetUnpublishedForUpdate(txCtx, 10)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Attempts != 1 || pending[0].LastError == nil {
+		t.Fatalf("pending after a failed publish = %+v, want one event with an attempt and error recorded", pending)
+	}
+
+	publisher.failing = false
+	if published, err := relay.RunOnce(ctx); err != nil || published != 1 {
+		t.Fatalf("retry published %d, %v; want 1", published, err)
+	}
+	if publisher.events[0].ID != event.ID {
+		t.Fatalf("retry published %s, want %s", publisher.events[0].ID, event.ID)
+	}
+}
+
+func TestRelaySkipsRolledBackEvents(t *testing.T) {
+	db := testutil.Postgres(t)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	ctx := context.Background()
+
+	event, err := entity.NewOutboxEvent(entity.EventTransferCompleted, "transfer", uuid.New(), map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rollback := errors.New("transfer failed")
+	err = db.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := outboxRepo.Create(txCtx, event); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("transaction = %v, want the rollback error", err)
+	}
+
+	publisher := &recorder{}
+	if published, err := NewRelay(outboxRepo, db, publisher, time.Second, 10, testutil.Logger()).RunOnce(ctx); err != nil || published != 0 {
+		t.Fatalf("relay published %d, %v for a rolled-back transaction; want 0", published, err)
+	}
+}
@@ -0,0 +1,70 @@
+package ownership
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+)
+
+// Checker resolves the owner of an account, caching the account-to-owner
+// mapping for a short TTL. Only existing accounts are cached, and any cache
+// failure falls back to the database, so a cache outage never grants access.
+type Checker struct {
+	accountRepo repository.AccountRepository
+	cache       service.CacheService
+	ttlSeconds  int
+}
+
+func NewChecker(accountRepo repository.AccountRepository, cache service.CacheService, ttlSeconds int) *Checker {
+	return &Checker{
+		accountRepo: accountRepo,
+		cache:       cache,
+		ttlSeconds:  ttlSeconds,
+	}
+}
+
+// OwnerOf returns the owner of accountID. The boolean is false when the
+// account does not exist.
+func (c *Checker) OwnerOf(ctx context.Context, accountID uuid.UUID) (uuid.UUID, bool, error) {
+	key := cacheKey(accountID)
+
+	if cached, err := c.cache.Get(ctx, key); err == nil && cached != "" {
+		if ownerID, err := uuid.Parse(cached); err == nil {
+			return ownerID, true, nil
+		}
+	}
+
+	account, err := c.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	if account == nil {
+		return uuid.Nil, false, nil
+	}
+
+	_ = c.cache.Set(ctx, key, account.UserID.String(), c.ttlSeconds)
+
+	return account.UserID, true, nil
+}
+
+// IsOwner reports whether userID owns accountID.
+func (c *Checker) IsOwner(ctx context.Context, userID, accountID uuid.UUID) (bool, error) {
+	ownerID, found, err := c.OwnerOf(ctx, accountID)
+	if err != nil {
+		return false, err
+	}
+	return found && ownerID == userID, nil
+}
+
+// Invalidate drops the cached owner of accountID. It must be called whenever
+// an account changes hands or is removed.
+func (c *Checker) Invalidate(ctx context.Context, accountID uuid.UUID) error {
+	return c.cache.Delete(ctx, cacheKey(accountID))
+}
+
+func cacheKey(accountID uuid.UUID) string {
+	return fmt.Sprintf("account_owner:%s", accountID)
+}
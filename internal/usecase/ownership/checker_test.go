@@ -0,0 +1,141 @@
+package ownership
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+// accountStore serves GetByID from a map and counts the lookups.
+type accountStore struct {
+	repository.AccountRepository
+	accounts map[uuid.UUID]*entity.Account
+	lookups  int
+}
+
+func (s *accountStore) GetByID(_ context.Context, id uuid.UUID) (*entity.Account, error) {
+	s.lookups++
+	return s.accounts[id], nil
+}
+
+func TestCheckerCachesOwner(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	account := entity.NewAccount(owner, "1234567890", entity.AccountTypeChecking, "USD")
+	store := &accountStore{accounts: map[uuid.UUID]*entity.Account{account.ID: account}}
+	checker := NewChecker(store, testutil.NewCache(), 60)
+
+	for i := 0; i < 3; i++ {
+		owned, err := checker.IsOwner(ctx, owner, account.ID)
+		if err != nil || !owned {
+			t.Fatalf("IsOwner = %v, %v; want true", owned, err)
+		}
+	}
+	if store.lookups != 1 {
+		t.Fatalf("%d database lookups for three checks, want 1", store.lookups)
+	}
+
+	if owned, _ := checker.IsOwner(ctx, uuid.New(), account.ID); owned {
+		t.Fatal("a cached owner was granted to another user")
+	}
+}
+
+func TestCheckerDoesNotCacheMissingAccount(t *testing.T) {
+	ctx := context.Background()
+	store := &accountStore{accounts: map[uuid.UUID]*entity.Account{}}
+	checker := NewChecker(store, testutil.NewCache(), 60)
+
+	missing := uuid.New()
+	for i := 0; i < 2; i++ {
+		_, found, err := checker.OwnerOf(ctx, missing)
+		if err != nil || found {
+			t.Fatalf("OwnerOf a missing account = %v, %v", found, err)
+		}
+	}
+	if store.lookups != 2 {
+		t.Fatalf("%d database lookups for a missing account, want 2", store.lookups)
+	}
+
+	// Once the account exists it is found straight away.
+	account := entity.NewAccount(uuid.New(), "1234567890", entity.AccountTypeChecking, "USD")
+	account.ID = missing
+	store.accounts[missing] = account
+	if _, found, _ := checker.OwnerOf(ctx, missing); !found {
+		t.Fatal("account created after a miss is not found")
+	}
+}
+
+func TestCheckerInvalidateOnOwnershipChange(t *testing.T) {
+	ctx := context.Background()
+	previous, next := uuid.New(), uuid.New()
+	account := entity.NewAccount(previous, "1234567890", entity.AccountTypeChecking, "USD")
+	store := &accountStore{accounts: map[uuid.UUID]*entity.Account{account.ID: account}}
+	checker := NewChecker(store, testutil.NewCache(), 60)
+
+	if owned, _ := checker.IsOwner(ctx, previous, account.ID); !owned {
+		t.Fatal("IsOwner = false for the owner")
+	}
+
+	account.UserID = next
+	if err := checker.Invalidate(ctx, account.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if owned, _ := checker.IsOwner(ctx, previous, account.ID); owned {
+		t.Fatal("previous owner still passes after invalidation")
+	}
+	if owned, _ := checker.IsOwner(ctx, next, account.ID); !owned {
+		t.Fatal("new owner refused after invalidation")
+	}
+}
+
+// brokenCache fails every call, like an unreachable Redis.
+type brokenCache struct{}
+
+func (brokenCache) Get(context.Context, string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func (brokenCache) Set(context.Context, string, interface{}, int) error {
+	return errors.New("connection refused")
+}
+
+func (brokenCache) Delete(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func (brokenCache) Exists(context.Context, string) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (brokenCache) SetNX(context.Context, string, interface{}, int) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func (brokenCache) GetDel(context.Context, string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func (brokenCache) Incr(context.Context, string, int) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestCheckerFallsBackToDatabase(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	account := entity.NewAccount(owner, "1234567890", entity.AccountTypeChecking, "USD")
+	store := &accountStore{accounts: map[uuid.UUID]*entity.Account{account.ID: account}}
+	checker := NewChecker(store, brokenCache{}, 60)
+
+	if owned, err := checker.IsOwner(ctx, owner, account.ID); err != nil || !owned {
+		t.Fatalf("IsOwner with the cache down = %v, %v; want true", owned, err)
+	}
+	if owned, err := checker.IsOwner(ctx, uuid.New(), account.ID); err != nil || owned {
+		t.Fatalf("IsOwner for another user with the cache down = %v, %v; want false", owned, err)
+	}
+}
@@ -0,0 +1,47 @@
+package recurring
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+)
+
+// Scheduler periodically materializes the due runs of standing orders.
+type Scheduler struct {
+	recurringService service.RecurringTransferService
+	interval         time.Duration
+	batchSize        int
+	logger           *logger.Logger
+}
+
+func NewScheduler(recurringService service.RecurringTransferService, interval time.Duration, batchSize int, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		recurringService: recurringService,
+		interval:         interval,
+		batchSize:        batchSize,
+		logger:           log,
+	}
+}
+
+// Start materializes due runs on every tick until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processed, err := s.recurringService.RunDue(ctx, time.Now().UTC(), s.batchSize)
+			if err != nil {
+				s.logger.Error().Err(err).Msg("Recurring transfer run failed")
+			}
+			if processed > 0 {
+				s.logger.Info().Int("processed", processed).Msg("Materialized recurring transfers")
+			}
+		}
+	}
+}
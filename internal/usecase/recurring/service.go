@@ -0,0 +1,289 @@
+package recurring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/money"
+	"github.com/yourusername/gobank/internal/pkg/recurrence"
+	"github.com/yourusername/gobank/internal/pkg/reference"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+type recurringTransferService struct {
+	recurringRepo repository.RecurringTransferRepository
+	accountRepo   repository.AccountRepository
+	transferRepo  repository.TransferRepository
+	allowlistRepo repository.AllowedDestinationRepository
+	db            *database.PostgresDB
+	audit         service.AuditService
+	memoSanitizer service.MemoSanitizer
+	config        *config.Config
+}
+
+func NewRecurringTransferService(
+	recurringRepo repository.RecurringTransferRepository,
+	accountRepo repository.AccountRepository,
+	transferRepo repository.TransferRepository,
+	allowlistRepo repository.AllowedDestinationRepository,
+	db *database.PostgresDB,
+	auditService service.AuditService,
+	memoSanitizer service.MemoSanitizer,
+	cfg *config.Config,
+) service.RecurringTransferService {
+	return &recurringTransferService{
+		recurringRepo: recurringRepo,
+		accountRepo:   accountRepo,
+		transferRepo:  transferRepo,
+		allowlistRepo: allowlistRepo,
+		db:            db,
+		audit:         auditService,
+		memoSanitizer: memoSanitizer,
+		config:        cfg,
+	}
+}
+
+// Create sets up a standing order whose first run is at input.StartAt. Later
+// runs keep that run's local time in input.Timezone.
+func (s *recurringTransferService) Create(ctx context.Context, userID uuid.UUID, input *entity.CreateRecurringTransferInput) (*entity.RecurringTransfer, error) {
+	amount, err := decimal.NewFromString(input.Amount)
+	if err != nil || amount.LessThanOrEqual(decimal.Zero) || money.Check(amount) != nil {
+		return nil, apperror.ErrInvalidAmount
+	}
+
+	schedule, err := recurrence.New(recurrence.Interval(input.Interval), input.StartAt, input.Timezone)
+	if err != nil {
+		return nil, apperror.ErrInvalidSchedule
+	}
+	firstRun := schedule.Occurrence(0)
+	if !firstRun.After(time.Now()) {
+		return nil, apperror.ErrInvalidSchedule
+	}
+	if input.EndDate != nil && input.EndDate.Before(firstRun) {
+		return nil, apperror.ErrInvalidSchedule
+	}
+
+	description, err := s.sanitizeMemo(input.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := s.accountRepo.GetByID(ctx, input.FromAccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get source account", 500)
+	}
+	if fromAccount == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if fromAccount.UserID != userID {
+		return nil, apperror.ErrForbidden
+	}
+	if fromAccount.RequireMemo && description == "" {
+		return nil, apperror.ErrMemoRequired
+	}
+	if !fromAccount.IsActive() {
+		return nil, apperror.ErrAccountInactive
+	}
+
+	toAccount, err := s.accountRepo.GetByID(ctx, input.ToAccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get destination account", 500)
+	}
+	if toAccount == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if fromAccount.Currency != toAccount.Currency {
+		return nil, apperror.ErrCurrencyMismatch
+	}
+
+	allowed, err := s.allowlistRepo.IsAllowed(ctx, fromAccount.ID, toAccount.ID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to check destination allowlist", 500)
+	}
+	if !allowed {
+		return nil, apperror.ErrDestinationNotAllowed
+	}
+
+	now := time.Now().UTC()
+	recurring := &entity.RecurringTransfer{
+		ID:            uuid.New(),
+		UserID:        userID,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        amount,
+		Currency:      fromAccount.Currency,
+		Description:   description,
+		Interval:      schedule.Interval,
+		Timezone:      schedule.Location.String(),
+		StartAt:       firstRun,
+		EndDate:       input.EndDate,
+		NextRunAt:     &firstRun,
+		Status:        entity.RecurringTransferStatusActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.recurringRepo.Create(ctx, recurring); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create recurring transfer", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "recurring_transfer.create", "recurring_transfer", &recurring.ID, nil, map[string]interface{}{
+		"from_account_id": recurring.FromAccountID,
+		"to_account_id":   recurring.ToAccountID,
+		"amount":          recurring.Amount.String(),
+		"interval":        recurring.Interval,
+		"timezone":        recurring.Timezone,
+	}, info.IPAddress, info.UserAgent)
+
+	return recurring, nil
+}
+
+// memoColumnLength is the size of recurring_transfers.description.
+const memoColumnLength = 255
+
+// sanitizeMemo applies the same length limit and filter as one-off transfers,
+// so a standing order cannot carry a memo a transfer would reject.
+func (s *recurringTransferService) sanitizeMemo(memo string) (string, error) {
+	memo = strings.TrimSpace(memo)
+
+	maxLength := s.config.Transfer.MemoMaxLength
+	if maxLength <= 0 || maxLength > memoColumnLength {
+		maxLength = memoColumnLength
+	}
+	if utf8.RuneCountInString(memo) > maxLength {
+		return "", apperror.ErrMemoTooLong
+	}
+
+	sanitized, err := s.memoSanitizer.Sanitize(memo)
+	if err != nil {
+		return "", apperror.ErrMemoRejected
+	}
+	return sanitized, nil
+}
+
+func (s *recurringTransferService) GetByID(ctx context.Context, userID, id uuid.UUID) (*entity.RecurringTransfer, error) {
+	recurring, err := s.recurringRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get recurring transfer", 500)
+	}
+	if recurring == nil || recurring.UserID != userID {
+		return nil, apperror.ErrRecurringTransferNotFound
+	}
+	return recurring, nil
+}
+
+func (s *recurringTransferService) List(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.RecurringTransfer, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	recurrings, err := s.recurringRepo.GetByUserID(ctx, userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get recurring transfers", 500)
+	}
+
+	total, err := s.recurringRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count recurring transfers", 500)
+	}
+
+	return recurrings, total, nil
+}
+
+// Cancel stops future runs. Transfers already materialized are unaffected.
+// Cancelling an order that is no longer active returns it unchanged.
+func (s *recurringTransferService) Cancel(ctx context.Context, userID, id uuid.UUID) (*entity.RecurringTransfer, error) {
+	cancelled, err := s.recurringRepo.Cancel(ctx, id, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to cancel recurring transfer", 500)
+	}
+
+	recurring, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cancelled {
+		info := audit.RequestInfoFrom(ctx)
+		s.audit.Record(ctx, &userID, "recurring_transfer.cancel", "recurring_transfer", &recurring.ID,
+			map[string]interface{}{"status": entity.RecurringTransferStatusActive},
+			map[string]interface{}{"status": recurring.Status},
+			info.IPAddress, info.UserAgent)
+	}
+
+	return recurring, nil
+}
+
+// RunDue materializes the next run of up to limit standing orders due at now
+// as scheduled transfers, which the transfer scheduler then settles. Each
+// order advances by one run per call, so missed runs are caught up over
+// successive calls. The idempotency key ties a transfer to its run, so a run
+// can never be materialized twice.
+func (s *recurringTransferService) RunDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	processed := 0
+
+	err := s.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		due, err := s.recurringRepo.GetDueForUpdate(txCtx, now, limit)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get due recurring transfers", 500)
+		}
+
+		for _, recurring := range due {
+			schedule, err := recurring.Schedule()
+			if err != nil {
+				return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to load recurring transfer schedule", 500)
+			}
+
+			referenceNumber, err := reference.New()
+			if err != nil {
+				return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate reference number", 500)
+			}
+
+			idempotencyKey := fmt.Sprintf("recurring:%s:%d", recurring.ID, recurring.RunCount)
+			scheduledAt := *recurring.NextRunAt
+			transfer := entity.NewTransfer(
+				recurring.FromAccountID,
+				recurring.ToAccountID,
+				recurring.Amount,
+				recurring.Currency,
+				&idempotencyKey,
+			)
+			transfer.Status = entity.TransferStatusScheduled
+			transfer.ScheduledAt = &scheduledAt
+			transfer.Description = recurring.Description
+			transfer.ReferenceNumber = referenceNumber
+
+			if err := s.transferRepo.Create(txCtx, transfer); err != nil {
+				return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create transfer", 500)
+			}
+
+			recurring.Advance(schedule)
+			if err := s.recurringRepo.UpdateSchedule(txCtx, recurring); err != nil {
+				return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update recurring transfer", 500)
+			}
+			processed++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return processed, nil
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+const balancesCacheKey = "stats:balances"
+
+type statsService struct {
+	accountRepo repository.AccountRepository
+	cache       service.CacheService
+	ttlSeconds  int
+}
+
+func NewStatsService(accountRepo repository.AccountRepository, cache service.CacheService, ttlSeconds int) service.StatsService {
+	return &statsService{
+		accountRepo: accountRepo,
+		cache:       cache,
+		ttlSeconds:  ttlSeconds,
+	}
+}
+
+// BalancesByCurrency returns holdings grouped by currency and account type.
+// Results are cached briefly since the aggregate scans every account.
+func (s *statsService) BalancesByCurrency(ctx context.Context) ([]*entity.BalanceAggregate, error) {
+	if cached, err := s.cache.Get(ctx, balancesCacheKey); err == nil && cached != "" {
+		var aggregates []*entity.BalanceAggregate
+		if err := json.Unmarshal([]byte(cached), &aggregates); err == nil {
+			return aggregates, nil
+		}
+	}
+
+	aggregates, err := s.accountRepo.AggregateBalances(ctx)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to aggregate balances", 500)
+	}
+	if aggregates == nil {
+		aggregates = []*entity.BalanceAggregate{}
+	}
+
+	_ = s.cache.Set(ctx, balancesCacheKey, aggregates, s.ttlSeconds)
+
+	return aggregates, nil
+}
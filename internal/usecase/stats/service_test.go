@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/testutil"
+)
+
+// aggregateStore returns fixed aggregates and counts how often it is asked.
+// Other methods are not implemented.
+type aggregateStore struct {
+	repository.AccountRepository
+	aggregates []*entity.BalanceAggregate
+	err        error
+	calls      int
+}
+
+func (s *aggregateStore) AggregateBalances(context.Context) ([]*entity.BalanceAggregate, error) {
+	s.calls++
+	return s.aggregates, s.err
+}
+
+func TestBalancesByCurrencyIsCached(t *testing.T) {
+	ctx := context.Background()
+	store := &aggregateStore{aggregates: []*entity.BalanceAggregate{
+		{Currency: "EUR", AccountType: entity.AccountTypeChecking, AccountCount: 1, TotalBalance: decimal.RequireFromString("10.5")},
+		{Currency: "USD", AccountType: entity.AccountTypeSavings, AccountCount: 3, TotalBalance: decimal.RequireFromString("1000.1234")},
+	}}
+	service := NewStatsService(store, testutil.NewCache(), 30)
+
+	first, err := service.BalancesByCurrency(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.BalancesByCurrency(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if store.calls != 1 {
+		t.Fatalf("aggregate query ran %d times, want 1", store.calls)
+	}
+	if len(second) != len(first) {
+		t.Fatalf("cached result has %d aggregates, want %d", len(second), len(first))
+	}
+	for i := range first {
+		if second[i].Currency != first[i].Currency || second[i].AccountCount != first[i].AccountCount ||
+			!second[i].TotalBalance.Equal(first[i].TotalBalance) {
+			t.Errorf("cached aggregate %d = %+v, want %+v", i, second[i], first[i])
+		}
+	}
+}
+
+func TestBalancesByCurrencyEmpty(t *testing.T) {
+	aggregates, err := NewStatsService(&aggregateStore{}, testutil.NewCache(), 30).BalancesByCurrency(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aggregates == nil || len(aggregates) != 0 {
+		t.Fatalf("aggregates with no accounts = %#v, want an empty slice", aggregates)
+	}
+}
+
+func TestBalancesByCurrencyError(t *testing.T) {
+	store := &aggregateStore{err: errors.New("connection reset")}
+	if _, err := NewStatsService(store, testutil.NewCache(), 30).BalancesByCurrency(context.Background()); err == nil {
+		t.Fatal("query failure not reported")
+	}
+}
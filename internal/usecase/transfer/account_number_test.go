@@ -0,0 +1,54 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestTransferByConfiguredAccountNumber(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Account.NumberPrefix = "GB"
+		cfg.Account.NumberLength = 12
+		cfg.Account.NumberCheckDigit = true
+	})
+	ctx := context.Background()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, h.user(t), "USD", "0")
+	if !strings.HasPrefix(to.AccountNumber, "GB") || len(to.AccountNumber) != 14 {
+		t.Fatalf("account number %q is not in the configured format", to.AccountNumber)
+	}
+
+	create := func(number string) (*entity.Transfer, error) {
+		return h.service.Create(ctx, userID, &entity.CreateTransferInput{
+			FromAccountID:   from.ID,
+			ToAccountNumber: number,
+			Amount:          "1",
+		})
+	}
+
+	transfer, err := create(to.AccountNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transfer.ToAccountID != to.ID {
+		t.Fatalf("transfer went to %s, want %s", transfer.ToAccountID, to.ID)
+	}
+
+	// Flip the check digit.
+	last := to.AccountNumber[len(to.AccountNumber)-1]
+	mistyped := to.AccountNumber[:len(to.AccountNumber)-1] + string('0'+(last-'0'+1)%10)
+
+	for _, number := range []string{mistyped, to.AccountNumber[2:], "GB" + to.AccountNumber[3:]} {
+		if _, err := create(number); !errors.Is(err, apperror.ErrInvalidAccountNumber) {
+			t.Errorf("transfer to %q = %v, want ErrInvalidAccountNumber", number, err)
+		}
+	}
+}
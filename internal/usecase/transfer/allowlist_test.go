@@ -0,0 +1,85 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/adapter/repository/postgres"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestEmptyAllowlistIsUnrestricted(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, h.user(t), "USD", "0")
+
+	h.transfer(t, userID, from, to, "10")
+}
+
+func TestAllowlistRestrictsDestinations(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	allowed := h.account(t, h.user(t), "USD", "0")
+	blocked := h.account(t, h.user(t), "USD", "0")
+
+	if err := postgres.NewAllowedDestinationRepository(h.db).Add(ctx, from.ID, allowed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	h.transfer(t, userID, from, allowed, "10")
+
+	_, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   blocked.ID,
+		Amount:        "10",
+	})
+	if !errors.Is(err, apperror.ErrDestinationNotAllowed) {
+		t.Fatalf("transfer to a destination not on the allowlist = %v, want ErrDestinationNotAllowed", err)
+	}
+
+	if got := h.balance(t, from.ID); !got.Equal(decimal.RequireFromString("90")) {
+		t.Fatalf("source balance = %s, want 90", got)
+	}
+	if got := h.balance(t, blocked.ID); !got.IsZero() {
+		t.Fatalf("blocked destination balance = %s, want 0", got)
+	}
+}
+
+func TestAllowlistMatchesByAccountNumber(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	allowed := h.account(t, h.user(t), "USD", "0")
+	blocked := h.account(t, h.user(t), "USD", "0")
+
+	if err := postgres.NewAllowedDestinationRepository(h.db).Add(ctx, from.ID, allowed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID:   from.ID,
+		ToAccountNumber: allowed.AccountNumber,
+		Amount:          "10",
+	}); err != nil {
+		t.Fatalf("transfer to an allowlisted account number: %v", err)
+	}
+
+	_, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID:   from.ID,
+		ToAccountNumber: blocked.AccountNumber,
+		Amount:          "10",
+	})
+	if !errors.Is(err, apperror.ErrDestinationNotAllowed) {
+		t.Fatalf("transfer to a blocked account number = %v, want ErrDestinationNotAllowed", err)
+	}
+}
@@ -0,0 +1,124 @@
+package transfer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+// alertEvents counts the pending balance alert events for alertID.
+func alertEvents(t *testing.T, h *harness, alertID uuid.UUID) int {
+	t.Helper()
+
+	var count int
+	for _, event := range pendingEvents(t, h) {
+		if event.EventType == entity.EventBalanceAlertTriggered && event.AggregateID == alertID {
+			count++
+		}
+	}
+	return count
+}
+
+func TestOneShotBelowAlertFiresOnce(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "100")
+	other := h.account(t, userID, "USD", "0")
+
+	alert, err := h.alerts.Create(ctx, userID, &entity.CreateBalanceAlertInput{
+		AccountID:   account.ID,
+		Condition:   string(entity.BalanceAlertBelow),
+		Threshold:   "50",
+		NotifyEmail: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.transfer(t, userID, account, other, "30") // 70: still above
+	if n := alertEvents(t, h, alert.ID); n != 0 {
+		t.Fatalf("alert fired %d times above the threshold", n)
+	}
+
+	h.transfer(t, userID, account, other, "30") // 40: drops below
+	if n := alertEvents(t, h, alert.ID); n != 1 {
+		t.Fatalf("alert fired %d times on dropping below, want 1", n)
+	}
+
+	h.transfer(t, userID, account, other, "10") // 30: stays below
+	h.transfer(t, userID, other, account, "50") // 80: back above
+	h.transfer(t, userID, account, other, "40") // 40: below again
+	if n := alertEvents(t, h, alert.ID); n != 1 {
+		t.Fatalf("one-shot alert fired %d times, want exactly 1", n)
+	}
+
+	stored, err := h.alerts.GetByID(ctx, userID, alert.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Active || stored.LastTriggeredAt == nil {
+		t.Fatalf("fired one-shot alert stored as active=%v last_triggered_at=%v", stored.Active, stored.LastTriggeredAt)
+	}
+}
+
+func TestRecurringBelowAlertFiresOnEachCrossing(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "100")
+	other := h.account(t, userID, "USD", "0")
+
+	alert, err := h.alerts.Create(ctx, userID, &entity.CreateBalanceAlertInput{
+		AccountID: account.ID,
+		Condition: string(entity.BalanceAlertBelow),
+		Threshold: "50",
+		Recurring: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.transfer(t, userID, account, other, "60") // 40: below
+	h.transfer(t, userID, account, other, "10") // 30: stays below
+	h.transfer(t, userID, other, account, "50") // 80: back above
+	h.transfer(t, userID, account, other, "40") // 40: below again
+	if n := alertEvents(t, h, alert.ID); n != 2 {
+		t.Fatalf("recurring alert fired %d times, want 2", n)
+	}
+}
+
+func TestRearmedOneShotAlertFiresAgain(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	account := h.account(t, userID, "USD", "100")
+	other := h.account(t, userID, "USD", "0")
+
+	alert, err := h.alerts.Create(ctx, userID, &entity.CreateBalanceAlertInput{
+		AccountID: account.ID,
+		Condition: string(entity.BalanceAlertBelow),
+		Threshold: "50",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.transfer(t, userID, account, other, "60") // 40: fires
+	h.transfer(t, userID, other, account, "60") // 100: back above
+
+	active := true
+	if _, err := h.alerts.Update(ctx, userID, alert.ID, &entity.UpdateBalanceAlertInput{Active: &active}); err != nil {
+		t.Fatal(err)
+	}
+
+	h.transfer(t, userID, account, other, "60") // 40: fires again
+	if n := alertEvents(t, h, alert.ID); n != 2 {
+		t.Fatalf("re-armed alert fired %d times in total, want 2", n)
+	}
+}
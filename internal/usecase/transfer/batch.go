@@ -0,0 +1,117 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// CreateBatch makes each transfer in input and returns one result per
+// transfer, in order. By default the transfers are independent, so one that
+// fails does not stop the rest. With AllOrNothing, every transfer is checked
+// before any is made and all of them are made in one transaction, so either
+// all succeed or none does.
+func (s *transferService) CreateBatch(ctx context.Context, userID uuid.UUID, input *entity.CreateTransferBatchInput) []entity.TransferBatchResult {
+	items := make([]*entity.CreateTransferInput, len(input.Transfers))
+	for i := range input.Transfers {
+		item := input.Transfers[i]
+		item.IdempotencyKey = input.ItemIdempotencyKey(i)
+		items[i] = &item
+	}
+
+	if input.AllOrNothing {
+		return s.createAllOrNothing(ctx, userID, items)
+	}
+
+	results := make([]entity.TransferBatchResult, len(items))
+	for i, item := range items {
+		if item.ScheduledAt != nil {
+			results[i].Err = apperror.ErrBatchTransferScheduled
+			continue
+		}
+		results[i].Transfer, results[i].Err = s.Create(ctx, userID, item)
+	}
+	return results
+}
+
+func (s *transferService) createAllOrNothing(ctx context.Context, userID uuid.UUID, items []*entity.CreateTransferInput) []entity.TransferBatchResult {
+	results := make([]entity.TransferBatchResult, len(items))
+
+	// A transfer already made under its idempotency key is replayed and
+	// left out of the transaction.
+	reqs := make([]*transferRequest, len(items))
+	for i, item := range items {
+		if item.ScheduledAt != nil {
+			return abortBatch(results, i, apperror.ErrBatchTransferScheduled)
+		}
+		req, existingTransfer, err := s.prepare(ctx, userID, item, false)
+		if err != nil {
+			return abortBatch(results, i, err)
+		}
+		if existingTransfer != nil {
+			results[i].Transfer = existingTransfer
+			continue
+		}
+		reqs[i] = req
+	}
+
+	var failed int
+	err := s.db.WithTransactionRetry(ctx, s.config.Transfer.TxMaxRetries, func(txCtx context.Context) error {
+		failed = -1
+		for i, req := range reqs {
+			if req == nil {
+				continue
+			}
+			transfer, err := s.execute(txCtx, userID, req)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results[i].Transfer = transfer
+		}
+		return nil
+	})
+	if err != nil {
+		for i, req := range reqs {
+			if req != nil {
+				results[i].Transfer = nil
+			}
+		}
+		if failed >= 0 {
+			return abortBatch(results, failed, err)
+		}
+		// The commit itself failed, so it failed every transfer in it.
+		metrics.TransferFailuresTotal.WithLabelValues(failureReason(err)).Inc()
+		for i, req := range reqs {
+			if req != nil {
+				results[i].Err = err
+			}
+		}
+		return results
+	}
+
+	for i, req := range reqs {
+		if req != nil {
+			s.completed(ctx, userID, results[i].Transfer)
+		}
+	}
+	return results
+}
+
+// abortBatch records err against the transfer at index and marks every other
+// transfer not already made as aborted.
+func abortBatch(results []entity.TransferBatchResult, index int, err error) []entity.TransferBatchResult {
+	metrics.TransferFailuresTotal.WithLabelValues(failureReason(err)).Inc()
+	for i := range results {
+		switch {
+		case i == index:
+			results[i].Err = err
+		case results[i].Transfer == nil:
+			results[i].Err = apperror.ErrBatchAborted
+		}
+	}
+	return results
+}
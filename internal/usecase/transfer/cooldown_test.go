@@ -0,0 +1,79 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestTransferPairCooldown(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Transfer.PairCooldown = time.Hour
+	})
+	ctx := context.Background()
+
+	userID := h.user(t)
+	checking := h.account(t, userID, "USD", "100")
+	savings := h.account(t, userID, "USD", "100")
+	other := h.account(t, userID, "USD", "0")
+
+	first := h.transfer(t, userID, checking, savings, "1")
+
+	create := func(from, to *entity.Account) error {
+		_, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        "1",
+		})
+		return err
+	}
+
+	if err := create(checking, savings); !errors.Is(err, apperror.ErrTransferCooldown) {
+		t.Fatalf("same pair within the cooldown = %v, want ErrTransferCooldown", err)
+	}
+
+	// The cooldown is per ordered pair.
+	if err := create(checking, other); err != nil {
+		t.Fatalf("another destination within the cooldown: %v", err)
+	}
+	if err := create(savings, checking); err != nil {
+		t.Fatalf("the opposite direction within the cooldown: %v", err)
+	}
+
+	// Just inside the window the pair is still held back.
+	setCreatedAt(t, h, first.ID, time.Now().Add(-time.Hour+time.Minute))
+	if err := create(checking, savings); !errors.Is(err, apperror.ErrTransferCooldown) {
+		t.Fatalf("same pair a minute before the cooldown ends = %v, want ErrTransferCooldown", err)
+	}
+
+	setCreatedAt(t, h, first.ID, time.Now().Add(-time.Hour-time.Second))
+	if err := create(checking, savings); err != nil {
+		t.Fatalf("same pair past the cooldown: %v", err)
+	}
+}
+
+func TestTransferPairCooldownOffByDefault(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	checking := h.account(t, userID, "USD", "100")
+	savings := h.account(t, userID, "USD", "0")
+
+	for i := 0; i < 3; i++ {
+		h.transfer(t, userID, checking, savings, "1")
+	}
+}
+
+func setCreatedAt(t *testing.T, h *harness, transferID uuid.UUID, createdAt time.Time) {
+	t.Helper()
+
+	if _, err := h.db.Pool.Exec(context.Background(), `UPDATE transfers SET created_at = $2 WHERE id = $1`, transferID, createdAt); err != nil {
+		t.Fatal(err)
+	}
+}
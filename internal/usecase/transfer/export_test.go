@@ -0,0 +1,58 @@
+package transfer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+)
+
+func TestExportMasksCounterpartyAndSetsDirection(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	otherID := h.user(t)
+	checking := h.account(t, userID, "USD", "100")
+	savings := h.account(t, userID, "USD", "0")
+	theirs := h.account(t, otherID, "USD", "100")
+
+	h.transfer(t, userID, checking, savings, "10")
+	h.transfer(t, userID, checking, theirs, "20")
+	h.transfer(t, otherID, theirs, checking, "30")
+
+	rows := map[entity.TransferDirection]*entity.TransferExportRow{}
+	err := h.service.Export(context.Background(), userID, time.Unix(0, 0), time.Now().Add(time.Hour), func(row *entity.TransferExportRow) error {
+		rows[row.Direction] = row
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		direction    entity.TransferDirection
+		amount       string
+		counterparty *entity.Account
+	}{
+		{entity.TransferDirectionInternal, "10", savings},
+		{entity.TransferDirectionOutgoing, "20", theirs},
+		{entity.TransferDirectionIncoming, "30", theirs},
+	}
+	if len(rows) != len(tests) {
+		t.Fatalf("exported directions %v, want one of each", rows)
+	}
+	for _, tt := range tests {
+		row := rows[tt.direction]
+		if row == nil {
+			t.Fatalf("no %s transfer exported", tt.direction)
+		}
+		if !row.Amount.Equal(decimal.RequireFromString(tt.amount)) {
+			t.Errorf("%s amount = %s, want %s", tt.direction, row.Amount, tt.amount)
+		}
+		if want := entity.MaskAccountNumber(tt.counterparty.AccountNumber); row.CounterpartyAccount != want {
+			t.Errorf("%s counterparty = %q, want %q", tt.direction, row.CounterpartyAccount, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestConvertedTransferRoundsToDestinationScale(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		// 10.05 USD at 0.5 is 5.025 EUR, a tie.
+		{"half_even", "5.02"},
+		{"half_up", "5.03"},
+		{"down", "5.02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			h := newHarness(t, func(cfg *config.Config) {
+				cfg.FX.StaticRates = "USD:EUR=0.5"
+				cfg.Transfer.FXRoundingMode = tt.mode
+			})
+
+			userID := h.user(t)
+			from := h.account(t, userID, "USD", "100")
+			to := h.account(t, userID, "EUR", "0")
+
+			transfer, err := h.service.Create(context.Background(), userID, &entity.CreateTransferInput{
+				FromAccountID:   from.ID,
+				ToAccountID:     to.ID,
+				Amount:          "10.05",
+				AllowConversion: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := decimal.RequireFromString(tt.want)
+			if transfer.ConvertedAmount == nil || !transfer.ConvertedAmount.Equal(want) {
+				t.Fatalf("converted amount = %v, want %s", transfer.ConvertedAmount, want)
+			}
+			if got := h.balance(t, to.ID); !got.Equal(want) {
+				t.Fatalf("destination balance = %s, want %s", got, want)
+			}
+			if got := h.balance(t, from.ID); !got.Equal(decimal.RequireFromString("89.95")) {
+				t.Fatalf("source balance = %s, want 89.95", got)
+			}
+		})
+	}
+}
+
+func TestCurrencyPairRestrictions(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.FX.StaticRates = "USD:EUR=0.5,EUR:USD=2"
+		cfg.FX.AllowedPairs = "USD:EUR"
+	})
+	ctx := context.Background()
+
+	userID := h.user(t)
+	usd := h.account(t, userID, "USD", "100")
+	eur := h.account(t, userID, "EUR", "100")
+
+	if _, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID:   usd.ID,
+		ToAccountID:     eur.ID,
+		Amount:          "10",
+		AllowConversion: true,
+	}); err != nil {
+		t.Fatalf("allowed pair USD:EUR: %v", err)
+	}
+
+	// EUR:USD has a rate but is not an allowed pair.
+	_, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID:   eur.ID,
+		ToAccountID:     usd.ID,
+		Amount:          "10",
+		AllowConversion: true,
+	})
+	if !errors.Is(err, apperror.ErrUnsupportedCurrencyPair) {
+		t.Fatalf("disallowed pair EUR:USD = %v, want ErrUnsupportedCurrencyPair", err)
+	}
+	if got := h.balance(t, eur.ID); !got.Equal(decimal.RequireFromString("105")) {
+		t.Fatalf("EUR balance = %s, want 105", got)
+	}
+
+	// Same-currency transfers are never restricted.
+	other := h.account(t, userID, "EUR", "0")
+	h.transfer(t, userID, eur, other, "5")
+}
@@ -0,0 +1,166 @@
+package transfer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/adapter/repository/postgres"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/accountnumber"
+	"github.com/yourusername/gobank/internal/pkg/fxrate"
+	"github.com/yourusername/gobank/internal/pkg/memo"
+	"github.com/yourusername/gobank/internal/testutil"
+	"github.com/yourusername/gobank/internal/usecase/accountcache"
+	"github.com/yourusername/gobank/internal/usecase/alert"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+	"github.com/yourusername/gobank/internal/usecase/fee"
+	"github.com/yourusername/gobank/internal/usecase/fx"
+	"github.com/yourusername/gobank/internal/usecase/ownership"
+)
+
+// harness is a transfer service over a fresh test database, with the
+// repositories it uses exposed for setting up and checking state.
+type harness struct {
+	db           *database.PostgresDB
+	service      *transferService
+	accounts     repository.AccountRepository
+	transfers    repository.TransferRepository
+	transactions repository.TransactionRepository
+	alerts       service.BalanceAlertService
+}
+
+// testConfig is the part of the configuration the transfer service reads,
+// at its defaults.
+func testConfig() *config.Config {
+	return &config.Config{
+		Account: config.AccountConfig{
+			NumberLength: 10,
+		},
+		Transfer: config.TransferConfig{
+			FXRoundingMode: "half_even",
+			MemoMaxLength:  255,
+			TxMaxRetries:   3,
+		},
+	}
+}
+
+// newHarness starts a transfer service on a test database. configure, if
+// not nil, adjusts the configuration before the service is built; fees,
+// rates, allowed FX pairs and the account number format are read from it too.
+func newHarness(t testing.TB, configure func(*config.Config)) *harness {
+	t.Helper()
+
+	db := testutil.Postgres(t)
+	cfg := testConfig()
+	if configure != nil {
+		configure(cfg)
+	}
+
+	numbers, err := accountnumber.NewFormat(cfg.Account.NumberPrefix, cfg.Account.NumberLength, cfg.Account.NumberCheckDigit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := fee.ParseSchedule(cfg.Transfer.Fees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rates, err := fxrate.ParseStatic(cfg.FX.StaticRates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := fx.ParsePairs(cfg.FX.AllowedPairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := testutil.NewCache()
+	accountRepo := postgres.NewAccountRepository(db, numbers)
+	transferRepo := postgres.NewTransferRepository(db)
+	transactionRepo := postgres.NewTransactionRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	auditService := audit.NewAuditService(postgres.NewAuditLogRepository(db), testutil.Logger())
+	alerts := alert.NewBalanceAlertService(postgres.NewBalanceAlertRepository(db), accountRepo, outboxRepo, auditService)
+
+	service := NewTransferService(
+		accountRepo,
+		transferRepo,
+		transactionRepo,
+		outboxRepo,
+		postgres.NewAllowedDestinationRepository(db),
+		fx.NewFXService(rates, cache, time.Minute, pairs),
+		fee.NewFeeService(schedule),
+		db,
+		ownership.NewChecker(accountRepo, cache, 60),
+		accountcache.NewCache(accountRepo, cache, 0),
+		alerts,
+		auditService,
+		memo.NewRegexSanitizer(memo.ParseMode(cfg.Transfer.MemoFilterMode), cfg.Transfer.MemoBlocklist),
+		numbers,
+		cfg,
+	).(*transferService)
+
+	return &harness{
+		db:           db,
+		service:      service,
+		accounts:     accountRepo,
+		transfers:    transferRepo,
+		transactions: transactionRepo,
+		alerts:       alerts,
+	}
+}
+
+// user creates a user and returns its ID.
+func (h *harness) user(t testing.TB) uuid.UUID {
+	t.Helper()
+
+	user := entity.NewUser(uuid.NewString()+"@example.com", "hash", "Test User")
+	if err := postgres.NewUserRepository(h.db).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user.ID
+}
+
+// account opens a checking account for userID holding balance.
+func (h *harness) account(t testing.TB, userID uuid.UUID, currency entity.Currency, balance string) *entity.Account {
+	t.Helper()
+
+	account := entity.NewAccount(userID, "", entity.AccountTypeChecking, currency)
+	account.Balance = decimal.RequireFromString(balance)
+	if err := h.accounts.Create(context.Background(), account); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	return account
+}
+
+// balance reads the current balance of an account.
+func (h *harness) balance(t testing.TB, accountID uuid.UUID) decimal.Decimal {
+	t.Helper()
+
+	account, err := h.accounts.GetByID(context.Background(), accountID)
+	if err != nil || account == nil {
+		t.Fatalf("get account: %v", err)
+	}
+	return account.Balance
+}
+
+// transfer makes an immediate transfer and fails the test if it is refused.
+func (h *harness) transfer(t testing.TB, userID uuid.UUID, from, to *entity.Account, amount string) *entity.Transfer {
+	t.Helper()
+
+	transfer, err := h.service.Create(context.Background(), userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        amount,
+	})
+	if err != nil {
+		t.Fatalf("transfer %s: %v", amount, err)
+	}
+	return transfer
+}
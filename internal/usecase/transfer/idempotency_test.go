@@ -0,0 +1,57 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestIdempotencyKeyReplayAndConflict(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, userID, "USD", "0")
+	other := h.account(t, userID, "USD", "0")
+
+	create := func(to *entity.Account, amount string) (*entity.Transfer, error) {
+		return h.service.Create(ctx, userID, &entity.CreateTransferInput{
+			FromAccountID:  from.ID,
+			ToAccountID:    to.ID,
+			Amount:         amount,
+			IdempotencyKey: "order-1042",
+		})
+	}
+
+	original, err := create(to, "25")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same request again is answered with the original transfer.
+	replayed, err := create(to, "25.00")
+	if err != nil {
+		t.Fatalf("same-payload replay: %v", err)
+	}
+	if replayed.ID != original.ID {
+		t.Fatalf("replay returned transfer %s, want %s", replayed.ID, original.ID)
+	}
+	if got := h.balance(t, from.ID); !got.Equal(decimal.RequireFromString("75")) {
+		t.Fatalf("source balance = %s after a replay, want 75", got)
+	}
+
+	if _, err := create(to, "30"); !errors.Is(err, apperror.ErrIdempotencyKeyConflict) {
+		t.Fatalf("reuse with a different amount = %v, want ErrIdempotencyKeyConflict", err)
+	}
+	if _, err := create(other, "25"); !errors.Is(err, apperror.ErrIdempotencyKeyConflict) {
+		t.Fatalf("reuse with a different destination = %v, want ErrIdempotencyKeyConflict", err)
+	}
+	if got := h.balance(t, from.ID); !got.Equal(decimal.RequireFromString("75")) {
+		t.Fatalf("source balance = %s after conflicting reuse, want 75", got)
+	}
+}
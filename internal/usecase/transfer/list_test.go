@@ -0,0 +1,53 @@
+package transfer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTransferListTotalIsStableAcrossPages(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, userID, "USD", "0")
+	otherID := h.user(t)
+	other := h.account(t, otherID, "USD", "100")
+
+	for i := 0; i < 6; i++ {
+		h.transfer(t, userID, from, to, "1")
+	}
+	h.transfer(t, userID, from, other, "1")
+	// Transfers into the user's accounts from elsewhere count too.
+	h.transfer(t, otherID, other, to, "1")
+
+	seen := map[string]bool{}
+	for page, want := range []int{3, 3, 2, 0} {
+		transfers, total, err := h.service.GetByUserID(ctx, userID, page+1, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 8 {
+			t.Fatalf("page %d reports total %d, want 8", page+1, total)
+		}
+		if len(transfers) != want {
+			t.Fatalf("page %d has %d transfers, want %d", page+1, len(transfers), want)
+		}
+		for _, transfer := range transfers {
+			if seen[transfer.ID.String()] {
+				t.Fatalf("transfer %s listed on two pages", transfer.ID)
+			}
+			seen[transfer.ID.String()] = true
+		}
+	}
+
+	// The other user's total only counts the two transfers touching them.
+	_, total, err := h.service.GetByUserID(ctx, otherID, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("other user reports total %d, want 2", total)
+	}
+}
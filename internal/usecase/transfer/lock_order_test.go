@@ -0,0 +1,94 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+)
+
+// lockRecorder records the order GetByIDForUpdate is called in. Other
+// methods are not implemented.
+type lockRecorder struct {
+	repository.AccountRepository
+	locked []uuid.UUID
+}
+
+func (r *lockRecorder) GetByIDForUpdate(_ context.Context, id uuid.UUID) (*entity.Account, error) {
+	r.locked = append(r.locked, id)
+	return &entity.Account{ID: id}, nil
+}
+
+func TestLockAccountsInIDOrder(t *testing.T) {
+	low, high := uuid.New(), uuid.New()
+	if bytes.Compare(high[:], low[:]) < 0 {
+		low, high = high, low
+	}
+
+	for _, tt := range []struct{ from, to uuid.UUID }{{low, high}, {high, low}} {
+		accounts := &lockRecorder{}
+		s := &transferService{accountRepo: accounts}
+
+		from, to, err := s.lockAccounts(context.Background(), tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(accounts.locked) != 2 || accounts.locked[0] != low || accounts.locked[1] != high {
+			t.Fatalf("transfer %s -> %s locked %v, want the lower ID first", tt.from, tt.to, accounts.locked)
+		}
+		if from.ID != tt.from || to.ID != tt.to {
+			t.Fatalf("lockAccounts returned %s -> %s, want %s -> %s", from.ID, to.ID, tt.from, tt.to)
+		}
+	}
+}
+
+func TestReciprocalTransfersDoNotDeadlock(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		// A deadlock would otherwise be retried and go unnoticed.
+		cfg.Transfer.TxMaxRetries = 0
+	})
+
+	aliceID, bobID := h.user(t), h.user(t)
+	alice := h.account(t, aliceID, "USD", "1000")
+	bob := h.account(t, bobID, "USD", "1000")
+
+	const rounds = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*rounds)
+	send := func(userID uuid.UUID, from, to *entity.Account, amount string) {
+		defer wg.Done()
+		_, err := h.service.Create(context.Background(), userID, &entity.CreateTransferInput{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+		})
+		errs <- err
+	}
+
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		go send(aliceID, alice, bob, "3")
+		go send(bobID, bob, alice, "1")
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("reciprocal transfer failed: %v", err)
+		}
+	}
+
+	if got, want := h.balance(t, alice.ID), decimal.NewFromInt(1000-rounds*2); !got.Equal(want) {
+		t.Fatalf("alice balance = %s, want %s", got, want)
+	}
+	if got, want := h.balance(t, bob.ID), decimal.NewFromInt(1000+rounds*2); !got.Equal(want) {
+		t.Fatalf("bob balance = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestRequireMemo(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	business := h.account(t, userID, "USD", "100")
+	personal := h.account(t, userID, "USD", "100")
+	business.RequireMemo = true
+	if err := h.accounts.Update(ctx, business); err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(from, to *entity.Account, description string, scheduledAt *time.Time) error {
+		_, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        "1",
+			Description:   description,
+			ScheduledAt:   scheduledAt,
+		})
+		return err
+	}
+
+	if err := create(business, personal, "", nil); !errors.Is(err, apperror.ErrMemoRequired) {
+		t.Fatalf("transfer without a memo from an enforcing account = %v, want ErrMemoRequired", err)
+	}
+	if err := create(business, personal, "   ", nil); !errors.Is(err, apperror.ErrMemoRequired) {
+		t.Fatalf("transfer with a blank memo from an enforcing account = %v, want ErrMemoRequired", err)
+	}
+	tomorrow := time.Now().Add(24 * time.Hour)
+	if err := create(business, personal, "", &tomorrow); !errors.Is(err, apperror.ErrMemoRequired) {
+		t.Fatalf("scheduled transfer without a memo from an enforcing account = %v, want ErrMemoRequired", err)
+	}
+
+	if err := create(business, personal, "Invoice 1042", nil); err != nil {
+		t.Fatalf("transfer with a memo from an enforcing account: %v", err)
+	}
+	// The flag only covers money leaving the account.
+	if err := create(personal, business, "", nil); err != nil {
+		t.Fatalf("transfer without a memo from a non-enforcing account: %v", err)
+	}
+}
+
+// memoTransfer makes a transfer of 1 with memo between two new accounts.
+func memoTransfer(t *testing.T, h *harness, memo string) (*entity.Transfer, error) {
+	t.Helper()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, userID, "USD", "0")
+	return h.service.Create(context.Background(), userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        "1",
+		Description:   memo,
+	})
+}
+
+func TestMemoCardNumberMasked(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Transfer.MemoFilterMode = "mask"
+	})
+
+	transfer, err := memoTransfer(t, h, "Refund to 4111 1111 1111 1111")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := h.transfers.GetByID(context.Background(), transfer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Description != "Refund to **** **** **** 1111" {
+		t.Fatalf("stored memo = %q, want the card number masked", stored.Description)
+	}
+}
+
+func TestMemoCardNumberRejected(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Transfer.MemoFilterMode = "reject"
+	})
+
+	if _, err := memoTransfer(t, h, "Refund to 4111 1111 1111 1111"); !errors.Is(err, apperror.ErrMemoRejected) {
+		t.Fatalf("memo with a card number = %v, want ErrMemoRejected", err)
+	}
+	if _, err := memoTransfer(t, h, "Invoice 1042"); err != nil {
+		t.Fatalf("memo without sensitive content: %v", err)
+	}
+}
+
+func TestMemoMaxLength(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Transfer.MemoMaxLength = 10
+	})
+
+	if _, err := memoTransfer(t, h, "Rent: May"); err != nil {
+		t.Fatalf("memo within the limit: %v", err)
+	}
+	if _, err := memoTransfer(t, h, "Rent for May"); !errors.Is(err, apperror.ErrMemoTooLong) {
+		t.Fatalf("memo over the limit = %v, want ErrMemoTooLong", err)
+	}
+}
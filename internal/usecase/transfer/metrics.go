@@ -0,0 +1,50 @@
+package transfer
+
+import (
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// failureReasons maps known rejections to metric labels. Anything else is
+// reported as "internal" or "other" so label cardinality stays bounded.
+var failureReasons = map[*apperror.AppError]string{
+	apperror.ErrInvalidAmount:           "invalid_amount",
+	apperror.ErrSameAccount:             "same_account",
+	apperror.ErrAccountNotFound:         "account_not_found",
+	apperror.ErrInvalidAccountNumber:    "invalid_account_number",
+	apperror.ErrForbidden:               "forbidden",
+	apperror.ErrCurrencyMismatch:        "currency_mismatch",
+	apperror.ErrInsufficientBalance:     "insufficient_balance",
+	apperror.ErrOverdraftLimitExceeded:  "overdraft_limit_exceeded",
+	apperror.ErrAccountInactive:         "account_inactive",
+	apperror.ErrBalanceOverflow:         "balance_overflow",
+	apperror.ErrMemoRequired:            "memo_required",
+	apperror.ErrMemoTooLong:             "memo_too_long",
+	apperror.ErrMemoRejected:            "memo_rejected",
+	apperror.ErrTransferCooldown:        "cooldown",
+	apperror.ErrIdempotencyKeyConflict:  "idempotency_conflict",
+	apperror.ErrDestinationNotAllowed:   "destination_not_allowed",
+	apperror.ErrTransferLimitExceeded:   "limit_exceeded",
+	apperror.ErrUnsupportedCurrencyPair: "currency_pair_not_allowed",
+}
+
+func failureReason(err error) string {
+	appErr := apperror.GetAppError(err)
+	if appErr == nil {
+		return "other"
+	}
+	if reason, ok := failureReasons[appErr]; ok {
+		return reason
+	}
+	if appErr.StatusCode >= 500 {
+		return "internal"
+	}
+	return "other"
+}
+
+// recordStatus counts a transfer reaching status. Call it only once the
+// change has committed.
+func recordStatus(status entity.TransferStatus) {
+	metrics.TransfersTotal.WithLabelValues(string(status)).Inc()
+}
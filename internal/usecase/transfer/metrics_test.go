@@ -0,0 +1,107 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// failures reads gobank_transfer_failures_total for reason.
+func failures(t *testing.T, reason string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "gobank_transfer_failures_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "reason" && label.GetValue() == reason {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{apperror.ErrInsufficientBalance, "insufficient_balance"},
+		{apperror.ErrCurrencyMismatch, "currency_mismatch"},
+		{apperror.ErrAccountInactive, "account_inactive"},
+		{apperror.ErrForbidden, "forbidden"},
+		{apperror.ErrSameAccount, "same_account"},
+		{apperror.Wrap(errors.New("connection reset"), "INTERNAL_ERROR", "Failed to create transfer", 500), "internal"},
+		{apperror.New("SOMETHING_NEW", "Unlisted rejection", 400), "other"},
+		{errors.New("plain error"), "other"},
+	}
+
+	for _, tt := range tests {
+		if got := failureReason(tt.err); got != tt.want {
+			t.Errorf("failureReason(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCreateCountsFailureByReason(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	strangerID := h.user(t)
+	usd := h.account(t, userID, "USD", "10")
+	other := h.account(t, userID, "USD", "0")
+	eur := h.account(t, userID, "EUR", "0")
+	inactive := h.account(t, userID, "USD", "0")
+	inactive.Status = entity.AccountStatusInactive
+	if err := h.accounts.Update(ctx, inactive); err != nil {
+		t.Fatal(err)
+	}
+	stranger := h.account(t, strangerID, "USD", "100")
+
+	tests := []struct {
+		reason string
+		from   *entity.Account
+		to     *entity.Account
+		amount string
+	}{
+		{"insufficient_balance", usd, other, "50"},
+		{"currency_mismatch", usd, eur, "1"},
+		{"account_inactive", usd, inactive, "1"},
+		{"forbidden", stranger, other, "1"},
+		{"same_account", usd, usd, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			before := failures(t, tt.reason)
+
+			_, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+				FromAccountID: tt.from.ID,
+				ToAccountID:   tt.to.ID,
+				Amount:        tt.amount,
+			})
+			if err == nil {
+				t.Fatal("transfer succeeded, want it refused")
+			}
+			if got := failureReason(err); got != tt.reason {
+				t.Fatalf("refused with %v (%s), want %s", err, got, tt.reason)
+			}
+			if got := failures(t, tt.reason) - before; got != 1 {
+				t.Fatalf("%s failures went up by %v, want 1", tt.reason, got)
+			}
+		})
+	}
+}
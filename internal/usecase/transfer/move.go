@@ -0,0 +1,50 @@
+package transfer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// Move transfers money from one of the user's accounts to another. Unlike
+// Create it refuses a destination the user does not own, and never converts
+// between currencies; otherwise the transfer is checked and made as Create
+// makes it.
+func (s *transferService) Move(ctx context.Context, userID, fromAccountID uuid.UUID, input *entity.MoveFundsInput) (*entity.Transfer, error) {
+	if err := s.checkOwnAccounts(ctx, userID, fromAccountID, input.ToAccountID); err != nil {
+		metrics.TransferFailuresTotal.WithLabelValues(failureReason(err)).Inc()
+		return nil, err
+	}
+
+	return s.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID:  fromAccountID,
+		ToAccountID:    input.ToAccountID,
+		Amount:         input.Amount,
+		IdempotencyKey: input.IdempotencyKey,
+		Description:    input.Description,
+	})
+}
+
+// checkOwnAccounts checks that both accounts exist and belong to the user.
+func (s *transferService) checkOwnAccounts(ctx context.Context, userID, fromAccountID, toAccountID uuid.UUID) error {
+	if fromAccountID == toAccountID {
+		return apperror.ErrSameAccount
+	}
+
+	for _, accountID := range []uuid.UUID{fromAccountID, toAccountID} {
+		ownerID, found, err := s.ownership.OwnerOf(ctx, accountID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+		}
+		if !found {
+			return apperror.ErrAccountNotFound
+		}
+		if ownerID != userID {
+			return apperror.ErrForbidden
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/gobank/internal/adapter/repository/postgres"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// pendingEvents returns the outbox events not yet published.
+func pendingEvents(t *testing.T, h *harness) []*entity.OutboxEvent {
+	t.Helper()
+
+	var events []*entity.OutboxEvent
+	err := h.db.WithTransaction(context.Background(), func(txCtx context.Context) error {
+		var err error
+		events, err = postgres.NewOutboxRepository(h.db).GetUnpublishedForUpdate(txCtx, 100)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestTransferWritesOutboxEvent(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, userID, "USD", "0")
+
+	// A refused transfer leaves nothing to publish.
+	_, err := h.service.Create(context.Background(), userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        "500",
+	})
+	if !errors.Is(err, apperror.ErrInsufficientBalance) {
+		t.Fatalf("overdrawing transfer = %v, want ErrInsufficientBalance", err)
+	}
+	if events := pendingEvents(t, h); len(events) != 0 {
+		t.Fatalf("refused transfer left %d outbox events", len(events))
+	}
+
+	transfer := h.transfer(t, userID, from, to, "10")
+
+	var completed []*entity.OutboxEvent
+	for _, event := range pendingEvents(t, h) {
+		if event.EventType == entity.EventTransferCompleted {
+			completed = append(completed, event)
+		}
+	}
+	if len(completed) != 1 || completed[0].AggregateID != transfer.ID {
+		t.Fatalf("completed events = %+v, want one for transfer %s", completed, transfer.ID)
+	}
+}
@@ -0,0 +1,53 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/money"
+)
+
+func TestTransferRefusesCreditThatWouldOverflow(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, userID, "USD", money.MaxValue.Sub(decimal.RequireFromString("0.5")).String())
+
+	_, err := h.service.Create(context.Background(), userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        "1",
+	})
+	if !errors.Is(err, apperror.ErrBalanceOverflow) {
+		t.Fatalf("credit past the column maximum = %v, want ErrBalanceOverflow", err)
+	}
+
+	if got := h.balance(t, from.ID); !got.Equal(from.Balance) {
+		t.Fatalf("source balance = %s after a refused transfer, want %s", got, from.Balance)
+	}
+	if got := h.balance(t, to.ID); !got.Equal(to.Balance) {
+		t.Fatalf("destination balance = %s after a refused transfer, want %s", got, to.Balance)
+	}
+}
+
+func TestTransferRefusesAmountBeyondColumn(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, userID, "USD", "0")
+
+	_, err := h.service.Create(context.Background(), userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        "1000000000000000",
+	})
+	if !errors.Is(err, apperror.ErrInvalidAmount) {
+		t.Fatalf("amount past the column maximum = %v, want ErrInvalidAmount", err)
+	}
+}
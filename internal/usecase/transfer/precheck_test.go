@@ -0,0 +1,107 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// lockRow holds a FOR UPDATE lock on an account until the returned function
+// is called, as a concurrent transfer would.
+func lockRow(t testing.TB, h *harness, accountID uuid.UUID) func() {
+	t.Helper()
+
+	tx, err := h.db.Pool.BeginTx(context.Background(), pgx.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(context.Background(), `SELECT id FROM accounts WHERE id = $1 FOR UPDATE`, accountID); err != nil {
+		_ = tx.Rollback(context.Background())
+		t.Fatal(err)
+	}
+	return func() { _ = tx.Rollback(context.Background()) }
+}
+
+func TestUnderfundedTransferFailsWithoutWaitingForLock(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "10")
+	to := h.account(t, h.user(t), "USD", "0")
+
+	unlock := lockRow(t, h, from.ID)
+	defer unlock()
+
+	// Were the request to queue for the row lock, it would hit the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        "50",
+	})
+	if !errors.Is(err, apperror.ErrInsufficientBalance) {
+		t.Fatalf("underfunded transfer while the source is locked = %v, want ErrInsufficientBalance", err)
+	}
+}
+
+func TestPrecheckLeavesFundedTransfersToLockedCheck(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "50")
+	to := h.account(t, h.user(t), "USD", "0")
+
+	// Exactly the balance passes the precheck and the locked check.
+	h.transfer(t, userID, from, to, "50")
+	if got := h.balance(t, to.ID); !got.Equal(decimal.RequireFromString("50")) {
+		t.Fatalf("destination balance = %s, want 50", got)
+	}
+
+	// Another user's account is not prechecked; the locked path refuses it.
+	_, err := h.service.Create(context.Background(), h.user(t), &entity.CreateTransferInput{
+		FromAccountID: to.ID,
+		ToAccountID:   from.ID,
+		Amount:        "1000",
+	})
+	if !errors.Is(err, apperror.ErrForbidden) {
+		t.Fatalf("transfer from another user's account = %v, want ErrForbidden", err)
+	}
+}
+
+// BenchmarkUnderfundedTransferWhileSourceLocked measures requests the source
+// cannot fund while a concurrent transfer holds its row. The precheck lets
+// them fail without queueing behind the lock.
+func BenchmarkUnderfundedTransferWhileSourceLocked(b *testing.B) {
+	h := newHarness(b, nil)
+
+	userID := h.user(b)
+	from := h.account(b, userID, "USD", "10")
+	to := h.account(b, h.user(b), "USD", "0")
+	input := &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        "50",
+	}
+
+	unlock := lockRow(b, h, from.ID)
+	defer unlock()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := h.service.Create(context.Background(), userID, input); !errors.Is(err, apperror.ErrInsufficientBalance) {
+				b.Errorf("err = %v, want ErrInsufficientBalance", err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestGetByReferenceNumber(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	sender, recipient, stranger := h.user(t), h.user(t), h.user(t)
+	from := h.account(t, sender, "USD", "100")
+	to := h.account(t, recipient, "USD", "0")
+
+	transfer := h.transfer(t, sender, from, to, "10")
+	if transfer.ReferenceNumber == "" {
+		t.Fatal("transfer created without a reference number")
+	}
+	other := h.transfer(t, sender, from, to, "10")
+	if other.ReferenceNumber == transfer.ReferenceNumber {
+		t.Fatalf("two transfers share reference number %s", transfer.ReferenceNumber)
+	}
+
+	for name, userID := range map[string]uuid.UUID{"sender": sender, "recipient": recipient} {
+		got, err := h.service.GetByReferenceNumber(ctx, userID, strings.ToLower(transfer.ReferenceNumber))
+		if err != nil {
+			t.Fatalf("%s lookup: %v", name, err)
+		}
+		if got.ID != transfer.ID {
+			t.Fatalf("%s lookup found transfer %s, want %s", name, got.ID, transfer.ID)
+		}
+	}
+
+	if _, err := h.service.GetByReferenceNumber(ctx, stranger, transfer.ReferenceNumber); !errors.Is(err, apperror.ErrForbidden) {
+		t.Fatalf("lookup by a stranger = %v, want ErrForbidden", err)
+	}
+	if _, err := h.service.GetByReferenceNumber(ctx, sender, "0000000000*"); !errors.Is(err, apperror.ErrTransferNotFound) {
+		t.Fatalf("lookup of an unknown reference = %v, want ErrTransferNotFound", err)
+	}
+}
@@ -0,0 +1,69 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestPartialRefundsUpToOriginal(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	sender, recipient := h.user(t), h.user(t)
+	from := h.account(t, sender, "USD", "100")
+	to := h.account(t, recipient, "USD", "0")
+	original := h.transfer(t, sender, from, to, "100")
+
+	for _, amount := range []string{"30", "50"} {
+		if _, err := h.service.PartialRefund(ctx, recipient, original.ID, decimal.RequireFromString(amount)); err != nil {
+			t.Fatalf("refund of %s: %v", amount, err)
+		}
+	}
+
+	if _, err := h.service.PartialRefund(ctx, recipient, original.ID, decimal.RequireFromString("20.01")); !errors.Is(err, apperror.ErrRefundExceedsTransfer) {
+		t.Fatalf("refund past the remaining 20 = %v, want ErrRefundExceedsTransfer", err)
+	}
+
+	refund, err := h.service.PartialRefund(ctx, recipient, original.ID, decimal.RequireFromString("20"))
+	if err != nil {
+		t.Fatalf("refund of the remaining 20: %v", err)
+	}
+	if refund.RefundOf == nil || *refund.RefundOf != original.ID {
+		t.Fatal("refund does not point at the original transfer")
+	}
+
+	if _, err := h.service.PartialRefund(ctx, recipient, original.ID, decimal.RequireFromString("0.01")); !errors.Is(err, apperror.ErrRefundExceedsTransfer) {
+		t.Fatalf("refund of a fully refunded transfer = %v, want ErrRefundExceedsTransfer", err)
+	}
+
+	stored, err := h.transfers.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.RefundedAmount.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("refunded amount = %s, want 100", stored.RefundedAmount)
+	}
+	if got := h.balance(t, from.ID); !got.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("sender balance = %s, want 100 back", got)
+	}
+	if got := h.balance(t, to.ID); !got.IsZero() {
+		t.Fatalf("recipient balance = %s, want 0", got)
+	}
+}
+
+func TestPartialRefundOnlyByRecipient(t *testing.T) {
+	h := newHarness(t, nil)
+
+	sender, recipient := h.user(t), h.user(t)
+	from := h.account(t, sender, "USD", "100")
+	to := h.account(t, recipient, "USD", "0")
+	original := h.transfer(t, sender, from, to, "100")
+
+	if _, err := h.service.PartialRefund(context.Background(), sender, original.ID, decimal.NewFromInt(10)); !errors.Is(err, apperror.ErrForbidden) {
+		t.Fatalf("refund by the sender = %v, want ErrForbidden", err)
+	}
+}
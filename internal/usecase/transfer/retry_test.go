@@ -0,0 +1,120 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+// failedScheduled schedules amount from one account to another and runs it
+// while the source cannot cover it, returning the failed transfer.
+func (h *harness) failedScheduled(t *testing.T, userID uuid.UUID, from, to *entity.Account, amount string) *entity.Transfer {
+	t.Helper()
+	ctx := context.Background()
+
+	runAt := time.Now().Add(time.Hour)
+	scheduled, err := h.service.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        amount,
+		ScheduledAt:   &runAt,
+	})
+	if err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if _, err := h.service.RunScheduled(ctx, runAt.Add(time.Minute), 10); err != nil {
+		t.Fatalf("run scheduled: %v", err)
+	}
+
+	failed, err := h.transfers.GetByID(ctx, scheduled.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed.Status != entity.TransferStatusFailed {
+		t.Fatalf("scheduled transfer ran as %s, want failed", failed.Status)
+	}
+	return failed
+}
+
+func TestRetryAfterFailureSucceeds(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "10")
+	savings := h.account(t, userID, "USD", "100")
+	to := h.account(t, h.user(t), "USD", "0")
+
+	failed := h.failedScheduled(t, userID, from, to, "50")
+
+	// Still short of funds: the retry is refused and the original stays failed.
+	if _, err := h.service.Retry(ctx, userID, failed.ID); !errors.Is(err, apperror.ErrInsufficientBalance) {
+		t.Fatalf("retry without funds = %v, want ErrInsufficientBalance", err)
+	}
+
+	h.transfer(t, userID, savings, from, "40")
+
+	retry, err := h.service.Retry(ctx, userID, failed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.ID == failed.ID || retry.Status != entity.TransferStatusCompleted {
+		t.Fatalf("retry = %s (%s), want a new completed transfer", retry.ID, retry.Status)
+	}
+	if got := h.balance(t, from.ID); !got.IsZero() {
+		t.Fatalf("source balance = %s, want 0", got)
+	}
+	if got := h.balance(t, to.ID); !got.Equal(decimal.RequireFromString("50")) {
+		t.Fatalf("destination balance = %s, want 50", got)
+	}
+
+	// Retrying again returns the same transfer instead of paying twice.
+	again, err := h.service.Retry(ctx, userID, failed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != retry.ID {
+		t.Fatalf("second retry made transfer %s, want %s", again.ID, retry.ID)
+	}
+	if got := h.balance(t, to.ID); !got.Equal(decimal.RequireFromString("50")) {
+		t.Fatalf("destination balance after a second retry = %s, want 50", got)
+	}
+}
+
+func TestRetryOfCompletedTransferRejected(t *testing.T) {
+	h := newHarness(t, nil)
+	ctx := context.Background()
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "100")
+	to := h.account(t, h.user(t), "USD", "0")
+
+	completed := h.transfer(t, userID, from, to, "10")
+
+	if _, err := h.service.Retry(ctx, userID, completed.ID); !errors.Is(err, apperror.ErrTransferNotRetryable) {
+		t.Fatalf("retry of a completed transfer = %v, want ErrTransferNotRetryable", err)
+	}
+	if got := h.balance(t, to.ID); !got.Equal(decimal.RequireFromString("10")) {
+		t.Fatalf("destination balance = %s, want 10", got)
+	}
+}
+
+func TestRetryIsOwnerOnly(t *testing.T) {
+	h := newHarness(t, nil)
+
+	userID := h.user(t)
+	from := h.account(t, userID, "USD", "10")
+	to := h.account(t, h.user(t), "USD", "0")
+
+	failed := h.failedScheduled(t, userID, from, to, "50")
+
+	if _, err := h.service.Retry(context.Background(), h.user(t), failed.ID); err == nil {
+		t.Fatal("another user retried the transfer")
+	}
+}
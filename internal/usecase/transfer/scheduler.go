@@ -0,0 +1,47 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+)
+
+// Scheduler periodically runs scheduled transfers that have come due.
+type Scheduler struct {
+	transferService service.TransferService
+	interval        time.Duration
+	batchSize       int
+	logger          *logger.Logger
+}
+
+func NewScheduler(transferService service.TransferService, interval time.Duration, batchSize int, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		transferService: transferService,
+		interval:        interval,
+		batchSize:       batchSize,
+		logger:          log,
+	}
+}
+
+// Start runs due transfers on every tick until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processed, err := s.transferService.RunScheduled(ctx, time.Now().UTC(), s.batchSize)
+			if err != nil {
+				s.logger.Error().Err(err).Msg("Scheduled transfer run failed")
+			}
+			if processed > 0 {
+				s.logger.Info().Int("processed", processed).Msg("Ran scheduled transfers")
+			}
+		}
+	}
+}
@@ -1,155 +1,967 @@
 package transfer
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/yourusername/gobank/internal/domain/entity"
 	"github.com/yourusername/gobank/internal/domain/repository"
 	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
 	"github.com/yourusername/gobank/internal/infrastructure/database"
+	"github.com/yourusername/gobank/internal/pkg/accountnumber"
 	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+	"github.com/yourusername/gobank/internal/pkg/money"
+	"github.com/yourusername/gobank/internal/pkg/reference"
+	"github.com/yourusername/gobank/internal/usecase/accountcache"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+	"github.com/yourusername/gobank/internal/usecase/ownership"
 )
 
 type transferService struct {
 	accountRepo     repository.AccountRepository
 	transferRepo    repository.TransferRepository
 	transactionRepo repository.TransactionRepository
+	outboxRepo      repository.OutboxRepository
+	allowlistRepo   repository.AllowedDestinationRepository
+	fx              service.FXService
+	fees            service.FeeService
 	db              *database.PostgresDB
+	ownership       *ownership.Checker
+	accountCache    *accountcache.Cache
+	alerts          service.BalanceAlertEvaluator
+	audit           service.AuditService
+	memoSanitizer   service.MemoSanitizer
+	accountNumbers  *accountnumber.Format
+	config          *config.Config
 }
 
 func NewTransferService(
 	accountRepo repository.AccountRepository,
 	transferRepo repository.TransferRepository,
 	transactionRepo repository.TransactionRepository,
+	outboxRepo repository.OutboxRepository,
+	allowlistRepo repository.AllowedDestinationRepository,
+	fxService service.FXService,
+	feeService service.FeeService,
 	db *database.PostgresDB,
+	ownershipChecker *ownership.Checker,
+	accountCache *accountcache.Cache,
+	alerts service.BalanceAlertEvaluator,
+	auditService service.AuditService,
+	memoSanitizer service.MemoSanitizer,
+	accountNumbers *accountnumber.Format,
+	cfg *config.Config,
 ) service.TransferService {
 	return &transferService{
 		accountRepo:     accountRepo,
 		transferRepo:    transferRepo,
 		transactionRepo: transactionRepo,
+		outboxRepo:      outboxRepo,
+		allowlistRepo:   allowlistRepo,
+		fx:              fxService,
+		fees:            feeService,
 		db:              db,
+		ownership:       ownershipChecker,
+		accountCache:    accountCache,
+		alerts:          alerts,
+		audit:           auditService,
+		memoSanitizer:   memoSanitizer,
+		accountNumbers:  accountNumbers,
+		config:          cfg,
 	}
 }
 
 func (s *transferService) Create(ctx context.Context, userID uuid.UUID, input *entity.CreateTransferInput) (*entity.Transfer, error) {
+	transfer, err := s.create(ctx, userID, input)
+	if err != nil {
+		metrics.TransferFailuresTotal.WithLabelValues(failureReason(err)).Inc()
+	}
+	return transfer, err
+}
+
+// replay returns the transfer already created with idempotencyKey, or nil if
+// there is none. A key first used for a different request is a conflict.
+func (s *transferService) replay(ctx context.Context, idempotencyKey, requestHash string) (*entity.Transfer, error) {
+	existingTransfer, err := s.transferRepo.GetByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to check idempotency key", 500)
+	}
+	if existingTransfer == nil {
+		return nil, nil
+	}
+	// Transfers stored before request hashes existed cannot be compared and
+	// are replayed as before.
+	if existingTransfer.RequestHash != "" && existingTransfer.RequestHash != requestHash {
+		return nil, apperror.ErrIdempotencyKeyConflict
+	}
+	return existingTransfer, nil
+}
+
+// replayDuplicate resolves a transfer that lost the race to store its
+// idempotency key: the request that won is replayed if it matches.
+func (s *transferService) replayDuplicate(ctx context.Context, idempotencyKey, requestHash string) (*entity.Transfer, error) {
+	existingTransfer, err := s.replay(ctx, idempotencyKey, requestHash)
+	if err != nil {
+		return nil, err
+	}
+	if existingTransfer == nil {
+		return nil, apperror.ErrDuplicateTransfer
+	}
+	return existingTransfer, nil
+}
+
+func (s *transferService) create(ctx context.Context, userID uuid.UUID, input *entity.CreateTransferInput) (*entity.Transfer, error) {
+	req, existingTransfer, err := s.prepare(ctx, userID, input, true)
+	if err != nil || existingTransfer != nil {
+		return existingTransfer, err
+	}
+
+	if input.ScheduledAt != nil {
+		return s.schedule(ctx, userID, input, req.amount, req.description, req.requestHash)
+	}
+
+	var transfer *entity.Transfer
+
+	err = s.db.WithTransactionRetry(ctx, s.config.Transfer.TxMaxRetries, func(txCtx context.Context) error {
+		var err error
+		transfer, err = s.execute(txCtx, userID, req)
+		return err
+	})
+
+	if errors.Is(err, apperror.ErrDuplicateTransfer) {
+		return s.replayDuplicate(ctx, input.IdempotencyKey, req.requestHash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.completed(ctx, userID, transfer)
+
+	return transfer, nil
+}
+
+// transferRequest is a transfer request that has passed every check that
+// can be made without locking its accounts.
+type transferRequest struct {
+	input       *entity.CreateTransferInput
+	amount      decimal.Decimal
+	description string
+	requestHash string
+	rate        *decimal.Decimal
+}
+
+// prepare parses and checks input. If its idempotency key was already used
+// for the same request, the transfer made then is returned instead. With
+// precheck, an immediate transfer the source account plainly cannot fund is
+// rejected here; callers that settle several transfers in one transaction,
+// whose earlier transfers may fund later ones, pass false.
+func (s *transferService) prepare(ctx context.Context, userID uuid.UUID, input *entity.CreateTransferInput, precheck bool) (*transferRequest, *entity.Transfer, error) {
+	amount, err := decimal.NewFromString(input.Amount)
+	if err != nil {
+		return nil, nil, apperror.ErrInvalidAmount
+	}
+
+	if input.ToAccountNumber != "" {
+		if err := s.resolveDestination(ctx, input); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	requestHash := input.RequestHash(userID, amount)
+
 	if input.IdempotencyKey != "" {
-		existingTransfer, err := s.transferRepo.GetByIdempotencyKey(ctx, input.IdempotencyKey)
+		existingTransfer, err := s.replay(ctx, input.IdempotencyKey, requestHash)
+		if err != nil || existingTransfer != nil {
+			return nil, existingTransfer, err
+		}
+	}
+
+	if amount.LessThanOrEqual(decimal.Zero) || money.Check(amount) != nil {
+		return nil, nil, apperror.ErrInvalidAmount
+	}
+
+	if input.FromAccountID == input.ToAccountID {
+		return nil, nil, apperror.ErrSameAccount
+	}
+
+	description, err := s.sanitizeMemo(input.Description)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &transferRequest{
+		input:       input,
+		amount:      amount,
+		description: description,
+		requestHash: requestHash,
+	}
+
+	if input.ScheduledAt != nil {
+		return req, nil, nil
+	}
+
+	if precheck {
+		if err := s.precheckBalance(ctx, userID, input.FromAccountID, amount); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// The rate is fetched before any rows are locked, since it may take a
+	// call to the rate provider. Scheduled transfers get theirs when they run.
+	if input.AllowConversion {
+		if req.rate, err = s.conversionRate(ctx, input); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return req, nil, nil
+}
+
+// precheckBalance rejects a transfer the source account plainly cannot fund,
+// reading it without a lock so that hopeless requests do not queue for the
+// row behind funded ones. It is only an early exit: execute checks again
+// under the lock. Accounts the user does not own, or that are not active,
+// are left for execute to reject with the proper error.
+func (s *transferService) precheckBalance(ctx context.Context, userID, fromAccountID uuid.UUID, amount decimal.Decimal) error {
+	fromAccount, err := s.accountRepo.GetByID(ctx, fromAccountID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get source account", 500)
+	}
+	if fromAccount == nil || fromAccount.UserID != userID || !fromAccount.IsActive() {
+		return nil
+	}
+	if err := s.loadPendingDebits(ctx, fromAccount); err != nil {
+		return err
+	}
+
+	fee := s.fees.Calculate(fromAccount.AccountType, fromAccount.Currency, amount)
+	if !fromAccount.CanDebit(amount.Add(fee)) {
+		return debitRefused(fromAccount)
+	}
+	return nil
+}
+
+// loadPendingDebits fills in the account's pending debits, so that CanDebit
+// leaves room for the scheduled transfers that are already due. Scheduled
+// transfers themselves run against the ledger balance, as they are the
+// pending debits.
+func (s *transferService) loadPendingDebits(ctx context.Context, account *entity.Account) error {
+	pending, err := s.transferRepo.SumPendingDebits(ctx, []uuid.UUID{account.ID}, time.Now().UTC())
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get pending debits", 500)
+	}
+	account.PendingDebits = pending[account.ID]
+	return nil
+}
+
+// debitRefused is the error for a debit that CanDebit refused on an active
+// account: the overdraft limit if the account has one, otherwise a short
+// balance.
+func debitRefused(account *entity.Account) *apperror.AppError {
+	if account.HasOverdraft() {
+		return apperror.ErrOverdraftLimitExceeded
+	}
+	return apperror.ErrInsufficientBalance
+}
+
+// execute locks the accounts of a prepared request, checks them and settles
+// the transfer, all in the caller's transaction.
+func (s *transferService) execute(txCtx context.Context, userID uuid.UUID, req *transferRequest) (*entity.Transfer, error) {
+	input := req.input
+	amount := req.amount
+	rate := req.rate
+
+	fromAccount, toAccount, err := s.lockAccounts(txCtx, input.FromAccountID, input.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+
+	if fromAccount.UserID != userID {
+		return nil, apperror.ErrForbidden
+	}
+
+	if fromAccount.RequireMemo && req.description == "" {
+		return nil, apperror.ErrMemoRequired
+	}
+
+	if toAccount == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+
+	if fromAccount.Currency != toAccount.Currency && rate == nil {
+		return nil, apperror.ErrCurrencyMismatch
+	}
+
+	if err := s.checkAllowed(txCtx, fromAccount.ID, toAccount.ID); err != nil {
+		return nil, err
+	}
+
+	if !fromAccount.IsActive() {
+		return nil, apperror.ErrAccountInactive
+	}
+
+	if err := s.loadPendingDebits(txCtx, fromAccount); err != nil {
+		return nil, err
+	}
+	fee := s.fees.Calculate(fromAccount.AccountType, fromAccount.Currency, amount)
+	if !fromAccount.CanDebit(amount.Add(fee)) {
+		return nil, debitRefused(fromAccount)
+	}
+
+	if !toAccount.CanCredit() {
+		return nil, apperror.ErrAccountInactive
+	}
+
+	if err := s.checkDailyLimit(txCtx, fromAccount, amount); err != nil {
+		return nil, err
+	}
+
+	if cooldown := s.config.Transfer.PairCooldown; cooldown > 0 {
+		lastAt, err := s.transferRepo.GetLastTransferTime(txCtx, fromAccount.ID, toAccount.ID)
 		if err != nil {
-			return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to check idempotency key", 500)
+			return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to check transfer cooldown", 500)
 		}
-		if existingTransfer != nil {
-			return existingTransfer, nil
+		if lastAt != nil && time.Since(*lastAt) < cooldown {
+			return nil, apperror.ErrTransferCooldown
 		}
 	}
 
-	amount, err := decimal.NewFromString(input.Amount)
+	var idempotencyKey *string
+	if input.IdempotencyKey != "" {
+		idempotencyKey = &input.IdempotencyKey
+	}
+
+	transfer := entity.NewTransfer(
+		input.FromAccountID,
+		input.ToAccountID,
+		amount,
+		fromAccount.Currency,
+		idempotencyKey,
+	)
+	transfer.Description = req.description
+	transfer.RequestHash = req.requestHash
+	transfer.Category = entity.NormalizeCategory(input.Category)
+	transfer.Tags = entity.NormalizeTags(input.Tags)
+	transfer.Fee = fee
+
+	debitDescription := fmt.Sprintf("Transfer to account %s", toAccount.AccountNumber)
+	creditDescription := fmt.Sprintf("Transfer from account %s", fromAccount.AccountNumber)
+
+	if rate != nil {
+		if err := s.convert(transfer, *rate, toAccount.Currency); err != nil {
+			return nil, err
+		}
+		fxNote := fmt.Sprintf(" (%s %s = %s %s at %s)",
+			transfer.Amount.StringFixed(transfer.Currency.DisplayScale()), transfer.Currency,
+			transfer.ConvertedAmount.StringFixed(toAccount.Currency.DisplayScale()), toAccount.Currency,
+			rate.String())
+		debitDescription += fxNote
+		creditDescription += fxNote
+	}
+
+	if err := s.settle(txCtx, transfer, fromAccount, toAccount, debitDescription, creditDescription); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// lockAccounts locks both accounts of a transfer, in the order of their IDs
+// rather than the direction of the transfer, so that transfers in opposite
+// directions between the same accounts cannot deadlock. Either account is
+// nil if it does not exist.
+func (s *transferService) lockAccounts(txCtx context.Context, fromAccountID, toAccountID uuid.UUID) (*entity.Account, *entity.Account, error) {
+	firstID, secondID := fromAccountID, toAccountID
+	if bytes.Compare(secondID[:], firstID[:]) < 0 {
+		firstID, secondID = secondID, firstID
+	}
+
+	first, err := s.accountRepo.GetByIDForUpdate(txCtx, firstID)
 	if err != nil {
-		return nil, apperror.ErrInvalidAmount
+		return nil, nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to lock accounts", 500)
 	}
-	if amount.LessThanOrEqual(decimal.Zero) {
-		return nil, apperror.ErrInvalidAmount
+	second, err := s.accountRepo.GetByIDForUpdate(txCtx, secondID)
+	if err != nil {
+		return nil, nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to lock accounts", 500)
 	}
 
-	if input.FromAccountID == input.ToAccountID {
-		return nil, apperror.ErrSameAccount
+	if firstID == fromAccountID {
+		return first, second, nil
 	}
+	return second, first, nil
+}
 
-	var transfer *entity.Transfer
+// completed does the work that follows a committed transfer.
+func (s *transferService) completed(ctx context.Context, userID uuid.UUID, transfer *entity.Transfer) {
+	s.accountCache.Invalidate(ctx, transfer.FromAccountID, transfer.ToAccountID)
+	recordStatus(transfer.Status)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "transfer.create", "transfer", &transfer.ID, nil, transferAuditValues(transfer), info.IPAddress, info.UserAgent)
+}
+
+// schedule stores a transfer to run at input.ScheduledAt. The accounts are
+// checked now so obvious mistakes fail early, but the balance is only checked
+// when the transfer runs.
+func (s *transferService) schedule(
+	ctx context.Context,
+	userID uuid.UUID,
+	input *entity.CreateTransferInput,
+	amount decimal.Decimal,
+	description, requestHash string,
+) (*entity.Transfer, error) {
+	if !input.ScheduledAt.After(time.Now()) {
+		return nil, apperror.ErrInvalidScheduledAt
+	}
 
-	err = s.db.WithTransaction(ctx, func(txCtx context.Context) error {
-		fromAccount, err := s.accountRepo.GetByIDForUpdate(txCtx, input.FromAccountID)
+	fromAccount, err := s.accountRepo.GetByID(ctx, input.FromAccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get source account", 500)
+	}
+	if fromAccount == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if fromAccount.UserID != userID {
+		return nil, apperror.ErrForbidden
+	}
+	if fromAccount.RequireMemo && description == "" {
+		return nil, apperror.ErrMemoRequired
+	}
+	if !fromAccount.IsActive() {
+		return nil, apperror.ErrAccountInactive
+	}
+
+	toAccount, err := s.accountRepo.GetByID(ctx, input.ToAccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get destination account", 500)
+	}
+	if toAccount == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if fromAccount.Currency != toAccount.Currency {
+		return nil, apperror.ErrCurrencyMismatch
+	}
+	if err := s.checkAllowed(ctx, fromAccount.ID, toAccount.ID); err != nil {
+		return nil, err
+	}
+
+	var idempotencyKey *string
+	if input.IdempotencyKey != "" {
+		idempotencyKey = &input.IdempotencyKey
+	}
+
+	scheduledAt := input.ScheduledAt.UTC()
+	transfer := entity.NewTransfer(
+		input.FromAccountID,
+		input.ToAccountID,
+		amount,
+		fromAccount.Currency,
+		idempotencyKey,
+	)
+	transfer.Status = entity.TransferStatusScheduled
+	transfer.ScheduledAt = &scheduledAt
+	transfer.Description = description
+	transfer.RequestHash = requestHash
+	transfer.Category = entity.NormalizeCategory(input.Category)
+	transfer.Tags = entity.NormalizeTags(input.Tags)
+
+	referenceNumber, err := reference.New()
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate reference number", 500)
+	}
+	transfer.ReferenceNumber = referenceNumber
+
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		if errors.Is(err, apperror.ErrDuplicateTransfer) {
+			return s.replayDuplicate(ctx, input.IdempotencyKey, requestHash)
+		}
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create transfer", 500)
+	}
+	recordStatus(transfer.Status)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "transfer.schedule", "transfer", &transfer.ID, nil, transferAuditValues(transfer), info.IPAddress, info.UserAgent)
+
+	return transfer, nil
+}
+
+// RunScheduled settles up to limit scheduled transfers that are due at now
+// and returns how many were processed. A transfer that can no longer be made,
+// e.g. for lack of funds, is marked failed. One that hits an internal error
+// stays scheduled and is retried on the next run.
+func (s *transferService) RunScheduled(ctx context.Context, now time.Time, limit int) (int, error) {
+	due, err := s.transferRepo.GetDueScheduled(ctx, now, limit)
+	if err != nil {
+		return 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get scheduled transfers", 500)
+	}
+
+	processed := 0
+	var firstErr error
+	for _, transfer := range due {
+		if err := s.runScheduled(ctx, transfer.ID); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		processed++
+	}
+
+	return processed, firstErr
+}
+
+func (s *transferService) runScheduled(ctx context.Context, transferID uuid.UUID) error {
+	var (
+		transfer *entity.Transfer
+		ownerID  *uuid.UUID
+		failure  *apperror.AppError
+	)
+
+	err := s.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		transfer, err = s.transferRepo.GetByIDForUpdate(txCtx, transferID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transfer", 500)
+		}
+		// Another instance may have run it since it was listed.
+		if transfer == nil || transfer.Status != entity.TransferStatusScheduled {
+			transfer = nil
+			return nil
+		}
+
+		fromAccount, toAccount, err := s.lockAccounts(txCtx, transfer.FromAccountID, transfer.ToAccountID)
 		if err != nil {
-			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get source account", 500)
+			return err
+		}
+		if fromAccount != nil {
+			ownerID = &fromAccount.UserID
+			// The fee is set by the source account's type when the money
+			// moves, not when the transfer was scheduled.
+			transfer.Fee = s.fees.Calculate(fromAccount.AccountType, fromAccount.Currency, transfer.Amount)
+		}
+
+		failure = checkScheduled(transfer, fromAccount, toAccount)
+		if failure == nil {
+			switch err := s.checkAllowed(txCtx, transfer.FromAccountID, transfer.ToAccountID); err {
+			case nil:
+			case apperror.ErrDestinationNotAllowed:
+				failure = apperror.ErrDestinationNotAllowed
+			default:
+				return err
+			}
+		}
+		if failure == nil {
+			switch err := s.checkDailyLimit(txCtx, fromAccount, transfer.Amount); err {
+			case nil:
+			case apperror.ErrTransferLimitExceeded:
+				failure = apperror.ErrTransferLimitExceeded
+			default:
+				return err
+			}
+		}
+		if failure != nil {
+			if err := s.transferRepo.UpdateStatus(txCtx, transfer.ID, entity.TransferStatusFailed, nil); err != nil {
+				return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update transfer status", 500)
+			}
+			transfer.Status = entity.TransferStatusFailed
+			return nil
+		}
+
+		if transfer.Fee.IsPositive() {
+			if err := s.transferRepo.SetFee(txCtx, transfer.ID, transfer.Fee); err != nil {
+				return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to record transfer fee", 500)
+			}
+		}
+
+		return s.post(
+			txCtx,
+			transfer,
+			fromAccount,
+			toAccount,
+			fmt.Sprintf("Scheduled transfer to account %s", toAccount.AccountNumber),
+			fmt.Sprintf("Scheduled transfer from account %s", fromAccount.AccountNumber),
+		)
+	})
+
+	if err != nil || transfer == nil {
+		return err
+	}
+	if failure == nil {
+		s.accountCache.Invalidate(ctx, transfer.FromAccountID, transfer.ToAccountID)
+	}
+	recordStatus(transfer.Status)
+
+	values := transferAuditValues(transfer)
+	action := "transfer.scheduled_run"
+	if failure != nil {
+		action = "transfer.scheduled_fail"
+		values["failure_reason"] = failure.Code
+		metrics.TransferFailuresTotal.WithLabelValues(failureReason(failure)).Inc()
+	}
+	s.audit.Record(ctx, ownerID, action, "transfer", &transfer.ID, nil, values, "", "")
+
+	return nil
+}
+
+// Retry re-attempts a scheduled transfer that failed when it ran, e.g. for
+// lack of funds, as a new immediate transfer made through the normal creation
+// path. The new transfer's idempotency key is derived from the failed one, so
+// however often the owner retries, the money moves at most once; repeating a
+// successful retry returns the same transfer.
+func (s *transferService) Retry(ctx context.Context, userID, transferID uuid.UUID) (*entity.Transfer, error) {
+	original, err := s.GetByID(ctx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if original.Status != entity.TransferStatusFailed || original.ScheduledAt == nil {
+		return nil, apperror.ErrTransferNotRetryable
+	}
+
+	retry, err := s.Create(ctx, userID, &entity.CreateTransferInput{
+		FromAccountID:  original.FromAccountID,
+		ToAccountID:    original.ToAccountID,
+		Amount:         original.Amount.String(),
+		IdempotencyKey: "retry:" + original.ID.String(),
+		Description:    original.Description,
+		Category:       stringValue(original.Category),
+		Tags:           original.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "transfer.retry", "transfer", &original.ID, nil,
+		map[string]interface{}{"retry_transfer_id": retry.ID}, info.IPAddress, info.UserAgent)
+
+	return retry, nil
+}
+
+// checkScheduled repeats the checks Create makes, against the accounts as
+// they are when a scheduled transfer runs.
+func checkScheduled(transfer *entity.Transfer, fromAccount, toAccount *entity.Account) *apperror.AppError {
+	switch {
+	case fromAccount == nil || toAccount == nil:
+		return apperror.ErrAccountNotFound
+	case fromAccount.Currency != toAccount.Currency:
+		return apperror.ErrCurrencyMismatch
+	case !fromAccount.IsActive():
+		return apperror.ErrAccountInactive
+	case !fromAccount.CanDebit(transfer.DebitAmount()):
+		return debitRefused(fromAccount)
+	case !toAccount.CanCredit():
+		return apperror.ErrAccountInactive
+	}
+	if _, err := money.Add(toAccount.Balance, transfer.Amount); err != nil {
+		return apperror.ErrBalanceOverflow
+	}
+	return nil
+}
+
+// conversionRate returns the rate for converting from the source account's
+// currency to the destination's, or nil if they share a currency.
+func (s *transferService) conversionRate(ctx context.Context, input *entity.CreateTransferInput) (*decimal.Decimal, error) {
+	fromAccount, err := s.accountRepo.GetByID(ctx, input.FromAccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get source account", 500)
+	}
+	toAccount, err := s.accountRepo.GetByID(ctx, input.ToAccountID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get destination account", 500)
+	}
+	if fromAccount == nil || toAccount == nil {
+		return nil, apperror.ErrAccountNotFound
+	}
+	if fromAccount.Currency == toAccount.Currency {
+		return nil, nil
+	}
+	if !s.fx.Supports(fromAccount.Currency, toAccount.Currency) {
+		return nil, apperror.ErrUnsupportedCurrencyPair
+	}
+
+	rate, err := s.fx.Rate(ctx, fromAccount.Currency, toAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// convert records on transfer the amount credited in currency at rate,
+// rounded to the currency's precision with the configured rounding mode.
+func (s *transferService) convert(transfer *entity.Transfer, rate decimal.Decimal, currency entity.Currency) error {
+	converted := money.Convert(transfer.Amount, rate, currency.DisplayScale(), money.ParseRoundingMode(s.config.Transfer.FXRoundingMode))
+	if !converted.IsPositive() || money.Check(converted) != nil {
+		return apperror.ErrInvalidAmount
+	}
+
+	transfer.ExchangeRate = &rate
+	transfer.ConvertedAmount = &converted
+	transfer.ConvertedCurrency = &currency
+	return nil
+}
+
+// checkAllowed enforces the source account's destination allowlist. Refunds
+// and reversals only return money to the original payer and skip it.
+func (s *transferService) checkAllowed(ctx context.Context, fromAccountID, toAccountID uuid.UUID) error {
+	allowed, err := s.allowlistRepo.IsAllowed(ctx, fromAccountID, toAccountID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to check destination allowlist", 500)
+	}
+	if !allowed {
+		return apperror.ErrDestinationNotAllowed
+	}
+	return nil
+}
+
+// checkDailyLimit rejects amount if it would take the account's completed
+// outbound transfers for the current UTC day past its daily limit. It must run
+// with the account row locked so concurrent transfers are counted.
+func (s *transferService) checkDailyLimit(ctx context.Context, account *entity.Account, amount decimal.Decimal) error {
+	if account.DailyTransferLimit == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	sent, err := s.transferRepo.SumOutboundSince(ctx, account.ID, startOfDay)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to check daily transfer limit", 500)
+	}
+	if !account.WithinDailyLimit(sent, amount) {
+		return apperror.ErrTransferLimitExceeded
+	}
+	return nil
+}
+
+// resolveDestination fills in ToAccountID from ToAccountNumber. If both are
+// given they must name the same account.
+func (s *transferService) resolveDestination(ctx context.Context, input *entity.CreateTransferInput) error {
+	if err := s.accountNumbers.Validate(input.ToAccountNumber); err != nil {
+		return apperror.ErrInvalidAccountNumber
+	}
+
+	toAccount, err := s.accountRepo.GetByAccountNumber(ctx, input.ToAccountNumber)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get destination account", 500)
+	}
+	if toAccount == nil {
+		return apperror.ErrAccountNotFound
+	}
+	if input.ToAccountID != uuid.Nil && input.ToAccountID != toAccount.ID {
+		return apperror.ErrBadRequest
+	}
+
+	input.ToAccountID = toAccount.ID
+	return nil
+}
+
+// memoColumnLength is the size of transfers.description.
+const memoColumnLength = 255
+
+// sanitizeMemo trims the memo, enforces the configured maximum length and runs
+// it through the memo sanitizer.
+func (s *transferService) sanitizeMemo(memo string) (string, error) {
+	memo = strings.TrimSpace(memo)
+
+	maxLength := s.config.Transfer.MemoMaxLength
+	if maxLength <= 0 || maxLength > memoColumnLength {
+		maxLength = memoColumnLength
+	}
+	if utf8.RuneCountInString(memo) > maxLength {
+		return "", apperror.ErrMemoTooLong
+	}
+
+	sanitized, err := s.memoSanitizer.Sanitize(memo)
+	if err != nil {
+		return "", apperror.ErrMemoRejected
+	}
+	return sanitized, nil
+}
+
+func (s *transferService) PartialRefund(ctx context.Context, userID, transferID uuid.UUID, amount decimal.Decimal) (*entity.Transfer, error) {
+	if amount.LessThanOrEqual(decimal.Zero) || money.Check(amount) != nil {
+		return nil, apperror.ErrInvalidAmount
+	}
+
+	var refund *entity.Transfer
+
+	err := s.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		original, err := s.transferRepo.GetByIDForUpdate(txCtx, transferID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transfer", 500)
+		}
+		if original == nil {
+			return apperror.ErrTransferNotFound
+		}
+		// A partial refund of a converted transfer would need a fresh rate;
+		// those are undone with a reversal instead.
+		if original.Status != entity.TransferStatusCompleted || original.IsCompensating() || original.IsConverted() {
+			return apperror.ErrTransferNotRefundable
+		}
+
+		if amount.GreaterThan(original.RefundableAmount()) {
+			return apperror.ErrRefundExceedsTransfer
+		}
+
+		// The refund flows back from the original recipient, who must own it.
+		fromAccount, toAccount, err := s.lockAccounts(txCtx, original.ToAccountID, original.FromAccountID)
+		if err != nil {
+			return err
 		}
 		if fromAccount == nil {
 			return apperror.ErrAccountNotFound
 		}
-
 		if fromAccount.UserID != userID {
 			return apperror.ErrForbidden
 		}
 
-		toAccount, err := s.accountRepo.GetByIDForUpdate(txCtx, input.ToAccountID)
-		if err != nil {
-			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get destination account", 500)
-		}
 		if toAccount == nil {
 			return apperror.ErrAccountNotFound
 		}
 
-		if fromAccount.Currency != toAccount.Currency {
-			return apperror.ErrCurrencyMismatch
+		if !fromAccount.IsActive() {
+			return apperror.ErrAccountInactive
 		}
-
 		if !fromAccount.CanDebit(amount) {
-			return apperror.ErrInsufficientBalance
+			return debitRefused(fromAccount)
 		}
-
 		if !toAccount.CanCredit() {
 			return apperror.ErrAccountInactive
 		}
 
-		var idempotencyKey *string
-		if input.IdempotencyKey != "" {
-			idempotencyKey = &input.IdempotencyKey
-		}
-
-		transfer = entity.NewTransfer(
-			input.FromAccountID,
-			input.ToAccountID,
+		refund = entity.NewTransfer(
+			fromAccount.ID,
+			toAccount.ID,
 			amount,
-			fromAccount.Currency,
-			idempotencyKey,
+			original.Currency,
+			nil,
 		)
+		refund.RefundOf = &original.ID
 
-		if err := s.transferRepo.Create(txCtx, transfer); err != nil {
-			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create transfer", 500)
+		if err := s.settle(
+			txCtx,
+			refund,
+			fromAccount,
+			toAccount,
+			fmt.Sprintf("Refund of transfer %s", original.ReferenceNumber),
+			fmt.Sprintf("Refund of transfer %s", original.ReferenceNumber),
+		); err != nil {
+			return err
 		}
 
-		newFromBalance := fromAccount.Balance.Sub(amount)
-		if err := s.accountRepo.UpdateBalance(txCtx, fromAccount.ID, newFromBalance); err != nil {
-			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update source account balance", 500)
+		if err := s.transferRepo.AddRefundedAmount(txCtx, original.ID, amount); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update refunded amount", 500)
 		}
 
-		newToBalance := toAccount.Balance.Add(amount)
-		if err := s.accountRepo.UpdateBalance(txCtx, toAccount.ID, newToBalance); err != nil {
-			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update destination account balance", 500)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	s.accountCache.Invalidate(ctx, refund.FromAccountID, refund.ToAccountID)
+	recordStatus(refund.Status)
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "transfer.refund", "transfer", &refund.ID, nil, transferAuditValues(refund), info.IPAddress, info.UserAgent)
+
+	return refund, nil
+}
+
+// Reverse undoes a completed transfer on an operator's behalf by moving its
+// unrefunded amount back to the sender. The original is marked reversed and
+// the ledger entries on both sides reference it, so they read as a pair.
+func (s *transferService) Reverse(ctx context.Context, userID, transferID uuid.UUID, reason string) (*entity.Transfer, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperror.ErrBadRequest
+	}
+
+	var reversal *entity.Transfer
+
+	err := s.db.WithTransaction(ctx, func(txCtx context.Context) error {
+		original, err := s.transferRepo.GetByIDForUpdate(txCtx, transferID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transfer", 500)
+		}
+		if original == nil {
+			return apperror.ErrTransferNotFound
+		}
+		if original.Status != entity.TransferStatusCompleted || original.IsCompensating() {
+			return apperror.ErrTransferNotReversible
 		}
 
-		debitTx := entity.NewTransaction(
-			fromAccount.ID,
-			entity.TransactionTypeDebit,
-			amount,
-			newFromBalance,
-			fmt.Sprintf("Transfer to account %s", toAccount.AccountNumber),
-			&transfer.ID,
-		)
-		if err := s.transactionRepo.Create(txCtx, debitTx); err != nil {
-			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create debit transaction", 500)
+		amount := original.RefundableAmount()
+		if !amount.IsPositive() {
+			return apperror.ErrTransferNotReversible
+		}
+
+		fromAccount, toAccount, err := s.lockAccounts(txCtx, original.ToAccountID, original.FromAccountID)
+		if err != nil {
+			return err
+		}
+		if fromAccount == nil {
+			return apperror.ErrAccountNotFound
+		}
+		if toAccount == nil {
+			return apperror.ErrAccountNotFound
 		}
 
-		creditTx := entity.NewTransaction(
+		if original.IsConverted() {
+			amount = *original.ConvertedAmount
+		}
+		if !fromAccount.CanDebit(amount) {
+			return apperror.ErrReversalInsufficientBalance
+		}
+		if !toAccount.CanCredit() {
+			return apperror.ErrAccountInactive
+		}
+
+		reversal = entity.NewTransfer(
+			fromAccount.ID,
 			toAccount.ID,
-			entity.TransactionTypeCredit,
 			amount,
-			newToBalance,
-			fmt.Sprintf("Transfer from account %s", fromAccount.AccountNumber),
-			&transfer.ID,
+			original.Currency,
+			nil,
 		)
-		if err := s.transactionRepo.Create(txCtx, creditTx); err != nil {
-			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create credit transaction", 500)
+		// A converted transfer is unwound at its original rate: the recipient
+		// gives back what they received and the sender gets back what they paid.
+		if original.IsConverted() {
+			inverse := money.Div(decimal.NewFromInt(1), *original.ExchangeRate)
+			reversal.Amount = *original.ConvertedAmount
+			reversal.Currency = *original.ConvertedCurrency
+			reversal.ExchangeRate = &inverse
+			reversal.ConvertedAmount = &original.Amount
+			reversal.ConvertedCurrency = &original.Currency
+		}
+		reversal.ReversalOf = &original.ID
+		reversal.Description = reason
+
+		if err := s.settle(
+			txCtx,
+			reversal,
+			fromAccount,
+			toAccount,
+			fmt.Sprintf("Reversal of transfer %s: %s", original.ReferenceNumber, reason),
+			fmt.Sprintf("Reversal of transfer %s: %s", original.ReferenceNumber, reason),
+		); err != nil {
+			return err
 		}
 
-		completedAt := time.Now().UTC()
-		if err := s.transferRepo.UpdateStatus(txCtx, transfer.ID, entity.TransferStatusCompleted, &completedAt); err != nil {
+		if err := s.transferRepo.UpdateStatus(txCtx, original.ID, entity.TransferStatusReversed, original.CompletedAt); err != nil {
 			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update transfer status", 500)
 		}
-		transfer.Status = entity.TransferStatusCompleted
-		transfer.CompletedAt = &completedAt
 
 		return nil
 	})
@@ -157,8 +969,185 @@ func (s *transferService) Create(ctx context.Context, userID uuid.UUID, input *e
 	if err != nil {
 		return nil, err
 	}
+	s.accountCache.Invalidate(ctx, reversal.FromAccountID, reversal.ToAccountID)
+	recordStatus(reversal.Status)
+	recordStatus(entity.TransferStatusReversed)
 
-	return transfer, nil
+	values := transferAuditValues(reversal)
+	values["reason"] = reason
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "transfer.reverse", "transfer", &reversal.ID, nil, values, info.IPAddress, info.UserAgent)
+
+	return reversal, nil
+}
+
+func transferAuditValues(transfer *entity.Transfer) map[string]interface{} {
+	values := map[string]interface{}{
+		"reference_number": transfer.ReferenceNumber,
+		"from_account_id":  transfer.FromAccountID,
+		"to_account_id":    transfer.ToAccountID,
+		"amount":           transfer.Amount.String(),
+		"currency":         transfer.Currency,
+		"status":           transfer.Status,
+	}
+	if transfer.RefundOf != nil {
+		values["refund_of"] = *transfer.RefundOf
+	}
+	if transfer.ReversalOf != nil {
+		values["reversal_of"] = *transfer.ReversalOf
+	}
+	if transfer.Fee.IsPositive() {
+		values["fee"] = transfer.Fee.String()
+	}
+	if transfer.IsConverted() {
+		values["exchange_rate"] = transfer.ExchangeRate.String()
+		values["converted_amount"] = transfer.ConvertedAmount.String()
+		values["converted_currency"] = *transfer.ConvertedCurrency
+	}
+	return values
+}
+
+// settle persists transfer and posts it. Both accounts must already be
+// locked by the surrounding transaction and checked for eligibility.
+func (s *transferService) settle(
+	txCtx context.Context,
+	transfer *entity.Transfer,
+	fromAccount, toAccount *entity.Account,
+	debitDescription, creditDescription string,
+) error {
+	referenceNumber, err := reference.New()
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate reference number", 500)
+	}
+	transfer.ReferenceNumber = referenceNumber
+
+	if err := s.transferRepo.Create(txCtx, transfer); err != nil {
+		if errors.Is(err, apperror.ErrDuplicateTransfer) {
+			return err
+		}
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create transfer", 500)
+	}
+
+	return s.post(txCtx, transfer, fromAccount, toAccount, debitDescription, creditDescription)
+}
+
+// post moves an already stored transfer's amount from fromAccount to
+// toAccount, recording a ledger entry on each side, and marks it completed.
+// A fee is taken from fromAccount as a separate debit.
+// The ledger entries of a reversal reference the transfer it reverses.
+func (s *transferService) post(
+	txCtx context.Context,
+	transfer *entity.Transfer,
+	fromAccount, toAccount *entity.Account,
+	debitDescription, creditDescription string,
+) error {
+	amount := transfer.Amount
+	credit := transfer.CreditAmount()
+
+	ledgerRef := &transfer.ID
+	if transfer.ReversalOf != nil {
+		ledgerRef = transfer.ReversalOf
+	}
+
+	newToBalance, err := money.Add(toAccount.Balance, credit)
+	if err != nil {
+		return apperror.ErrBalanceOverflow
+	}
+
+	newFromBalance := fromAccount.Balance.Sub(transfer.DebitAmount())
+	if err := s.accountRepo.UpdateBalance(txCtx, fromAccount, newFromBalance); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update source account balance", 500)
+	}
+
+	if err := s.accountRepo.UpdateBalance(txCtx, toAccount, newToBalance); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update destination account balance", 500)
+	}
+
+	debitTx := entity.NewTransaction(
+		fromAccount.ID,
+		entity.TransactionTypeDebit,
+		amount,
+		fromAccount.Balance.Sub(amount),
+		debitDescription,
+		ledgerRef,
+	)
+	debitTx.Category = transfer.Category
+	debitTx.Tags = transfer.Tags
+	if err := s.transactionRepo.Create(txCtx, debitTx); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create debit transaction", 500)
+	}
+
+	if transfer.Fee.IsPositive() {
+		feeTx := entity.NewTransaction(
+			fromAccount.ID,
+			entity.TransactionTypeDebit,
+			transfer.Fee,
+			newFromBalance,
+			"Transfer fee",
+			ledgerRef,
+		)
+		if err := s.transactionRepo.Create(txCtx, feeTx); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create fee transaction", 500)
+		}
+	}
+
+	creditTx := entity.NewTransaction(
+		toAccount.ID,
+		entity.TransactionTypeCredit,
+		credit,
+		newToBalance,
+		creditDescription,
+		ledgerRef,
+	)
+	if err := s.transactionRepo.Create(txCtx, creditTx); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create credit transaction", 500)
+	}
+
+	completedAt := time.Now().UTC()
+	if err := s.transferRepo.UpdateStatus(txCtx, transfer.ID, entity.TransferStatusCompleted, &completedAt); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update transfer status", 500)
+	}
+	transfer.Status = entity.TransferStatusCompleted
+	transfer.CompletedAt = &completedAt
+
+	changes := []entity.BalanceChange{
+		{Account: fromAccount, Previous: fromAccount.Balance, Current: newFromBalance},
+		{Account: toAccount, Previous: toAccount.Balance, Current: newToBalance},
+	}
+	fromAccount.Balance = newFromBalance
+	toAccount.Balance = newToBalance
+
+	if err := s.recordEvent(txCtx, transfer, fromAccount, toAccount); err != nil {
+		return err
+	}
+	return s.alerts.Evaluate(txCtx, changes...)
+}
+
+// recordEvent writes the transfer's outbox event in the settling transaction,
+// so the event is published if and only if the transfer commits.
+func (s *transferService) recordEvent(txCtx context.Context, transfer *entity.Transfer, fromAccount, toAccount *entity.Account) error {
+	eventType := entity.EventTransferCompleted
+	switch {
+	case transfer.RefundOf != nil:
+		eventType = entity.EventTransferRefunded
+	case transfer.ReversalOf != nil:
+		eventType = entity.EventTransferReversed
+	}
+
+	event, err := entity.NewOutboxEvent(eventType, "transfer", transfer.ID, map[string]interface{}{
+		"transfer":     transfer.ToResponse(entity.AmountFull),
+		"from_user_id": fromAccount.UserID,
+		"to_user_id":   toAccount.UserID,
+	})
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to encode transfer event", 500)
+	}
+
+	if err := s.outboxRepo.Create(txCtx, event); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to record transfer event", 500)
+	}
+	return nil
 }
 
 func (s *transferService) GetByID(ctx context.Context, userID uuid.UUID, transferID uuid.UUID) (*entity.Transfer, error) {
@@ -170,21 +1159,41 @@ func (s *transferService) GetByID(ctx context.Context, userID uuid.UUID, transfe
 		return nil, apperror.ErrTransferNotFound
 	}
 
-	fromAccount, err := s.accountRepo.GetByID(ctx, transfer.FromAccountID)
-	if err != nil {
-		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+	if err := s.checkParticipant(ctx, userID, transfer); err != nil {
+		return nil, err
 	}
 
-	toAccount, err := s.accountRepo.GetByID(ctx, transfer.ToAccountID)
+	return transfer, nil
+}
+
+func (s *transferService) GetByReferenceNumber(ctx context.Context, userID uuid.UUID, referenceNumber string) (*entity.Transfer, error) {
+	transfer, err := s.transferRepo.GetByReferenceNumber(ctx, strings.ToUpper(referenceNumber))
 	if err != nil {
-		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transfer", 500)
+	}
+	if transfer == nil {
+		return nil, apperror.ErrTransferNotFound
 	}
 
-	if (fromAccount != nil && fromAccount.UserID == userID) || (toAccount != nil && toAccount.UserID == userID) {
-		return transfer, nil
+	if err := s.checkParticipant(ctx, userID, transfer); err != nil {
+		return nil, err
 	}
 
-	return nil, apperror.ErrForbidden
+	return transfer, nil
+}
+
+func (s *transferService) checkParticipant(ctx context.Context, userID uuid.UUID, transfer *entity.Transfer) error {
+	for _, accountID := range []uuid.UUID{transfer.FromAccountID, transfer.ToAccountID} {
+		owned, err := s.ownership.IsOwner(ctx, userID, accountID)
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+		}
+		if owned {
+			return nil
+		}
+	}
+
+	return apperror.ErrForbidden
 }
 
 func (s *transferService) GetByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]*entity.Transfer, int64, error) {
@@ -201,5 +1210,64 @@ func (s *transferService) GetByUserID(ctx context.Context, userID uuid.UUID, pag
 		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transfers", 500)
 	}
 
-	return transfers, int64(len(transfers)), nil
+	total, err := s.transferRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count transfers", 500)
+	}
+
+	return transfers, total, nil
+}
+
+// GetByAccountID lists the transfers on one of the user's accounts, limited
+// to those into or out of it unless direction is all.
+func (s *transferService) GetByAccountID(ctx context.Context, userID, accountID uuid.UUID, direction entity.AccountTransferDirection, page, pageSize int) ([]*entity.Transfer, int64, error) {
+	ownerID, found, err := s.ownership.OwnerOf(ctx, accountID)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get account", 500)
+	}
+	if !found {
+		return nil, 0, apperror.ErrAccountNotFound
+	}
+	if ownerID != userID {
+		return nil, 0, apperror.ErrForbidden
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	transfers, err := s.transferRepo.GetByAccountID(ctx, accountID, direction, pageSize, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get transfers", 500)
+	}
+
+	total, err := s.transferRepo.CountByAccountID(ctx, accountID, direction)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count transfers", 500)
+	}
+
+	return transfers, total, nil
+}
+
+func (s *transferService) Export(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(*entity.TransferExportRow) error) error {
+	err := s.transferRepo.ExportByUserID(ctx, userID, from, to, func(row *entity.TransferExportRow) error {
+		row.CounterpartyAccount = entity.MaskAccountNumber(row.CounterpartyAccount)
+		return fn(row)
+	})
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to export transfers", 500)
+	}
+	return nil
+}
+
+// stringValue returns *s, or "" if s is nil.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
 }
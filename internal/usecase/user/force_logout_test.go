@@ -0,0 +1,102 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestForceLogoutRevokesSessions(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	admin := h.register(t)
+	user := h.register(t)
+
+	phone, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	laptop, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedBefore := time.Now()
+
+	count, err := h.service.ForceLogout(ctx, admin.ID, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("sessions terminated = %d, want 2", count)
+	}
+
+	for _, tokens := range []string{phone.RefreshToken, laptop.RefreshToken} {
+		if _, err := h.service.RefreshToken(ctx, tokens); err == nil {
+			t.Fatal("refresh token issued before the force-logout still works")
+		}
+	}
+	if revoked, err := h.service.IsTokenRevoked(ctx, user.ID, issuedBefore); err != nil || !revoked {
+		t.Fatalf("access token issued before the force-logout: revoked = %v, %v; want true", revoked, err)
+	}
+
+	// Logging in again starts a session that is not revoked.
+	if _, err := h.login(user); err != nil {
+		t.Fatalf("login after force-logout: %v", err)
+	}
+	if revoked, err := h.service.IsTokenRevoked(ctx, user.ID, time.Now().Add(time.Second)); err != nil || revoked {
+		t.Fatalf("access token issued after the force-logout: revoked = %v, %v; want false", revoked, err)
+	}
+
+	logs, err := h.auditLogs.GetByEntityID(ctx, "user", user.ID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recorded bool
+	for _, log := range logs {
+		if log.Action == "user.force_logout" && log.UserID != nil && *log.UserID == admin.ID {
+			recorded = true
+		}
+	}
+	if !recorded {
+		t.Fatalf("no user.force_logout audit entry by the admin in %d entries", len(logs))
+	}
+}
+
+func TestForceLogoutLeavesOtherUsersSignedIn(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	admin := h.register(t)
+	target := h.register(t)
+	other := h.register(t)
+
+	tokens, err := h.login(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.service.ForceLogout(ctx, admin.ID, target.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.service.RefreshToken(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("another user's refresh: %v", err)
+	}
+	if revoked, err := h.service.IsTokenRevoked(ctx, other.ID, time.Now()); err != nil || revoked {
+		t.Fatalf("another user's access token: revoked = %v, %v; want false", revoked, err)
+	}
+}
+
+func TestForceLogoutUnknownUser(t *testing.T) {
+	h := newHarness(t)
+	admin := h.register(t)
+
+	if _, err := h.service.ForceLogout(context.Background(), admin.ID, uuid.New()); !errors.Is(err, apperror.ErrUserNotFound) {
+		t.Fatalf("err = %v, want ErrUserNotFound", err)
+	}
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/adapter/mailer"
+	"github.com/yourusername/gobank/internal/adapter/repository/postgres"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/pkg/password"
+	"github.com/yourusername/gobank/internal/pkg/secretbox"
+	"github.com/yourusername/gobank/internal/pkg/token"
+	"github.com/yourusername/gobank/internal/testutil"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testPassword is the password of every user the harness registers.
+const testPassword = "correct horse battery"
+
+// harness is a user service over a fresh test database, with the
+// repositories it uses exposed for setting up and checking state.
+type harness struct {
+	service       *userService
+	users         repository.UserRepository
+	refreshTokens repository.RefreshTokenRepository
+	auditLogs     repository.AuditLogRepository
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	db := testutil.Postgres(t)
+	cfg := &config.Config{
+		JWT: config.JWTConfig{
+			AccessTokenExpiry:  15 * time.Minute,
+			RefreshTokenExpiry: 24 * time.Hour,
+			Issuer:             "gobank-test",
+		},
+		Auth: config.AuthConfig{
+			PasswordBcryptCost:    bcrypt.MinCost,
+			TwoFactorChallengeTTL: 5 * time.Minute,
+		},
+	}
+
+	totpSecrets, err := secretbox.New("test-totp-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userRepo := postgres.NewUserRepository(db)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	auditLogRepo := postgres.NewAuditLogRepository(db)
+	service := NewUserService(
+		userRepo,
+		refreshTokenRepo,
+		postgres.NewPasswordResetTokenRepository(db),
+		audit.NewAuditService(auditLogRepo, testutil.Logger()),
+		testutil.NewCache(),
+		mailer.NewLogMailer(testutil.Logger()),
+		password.NewHasherWithCost(cfg.Auth.PasswordBcryptCost),
+		token.NewJWTManager("test", "test-secret", nil, cfg.JWT.AccessTokenExpiry, cfg.JWT.RefreshTokenExpiry, cfg.JWT.Issuer),
+		totpSecrets,
+		cfg,
+		testutil.Logger(),
+	).(*userService)
+
+	return &harness{
+		service:       service,
+		users:         userRepo,
+		refreshTokens: refreshTokenRepo,
+		auditLogs:     auditLogRepo,
+	}
+}
+
+// register creates a user with testPassword.
+func (h *harness) register(t *testing.T) *entity.User {
+	t.Helper()
+
+	user, err := h.service.Register(context.Background(), &entity.CreateUserInput{
+		Email:    uuid.NewString() + "@example.com",
+		Password: testPassword,
+		FullName: "Test User",
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	return user
+}
+
+// login signs user in with testPassword.
+func (h *harness) login(user *entity.User) (*entity.AuthTokens, error) {
+	return h.service.Login(context.Background(), &entity.LoginInput{
+		Email:    user.Email,
+		Password: testPassword,
+	})
+}
@@ -0,0 +1,51 @@
+package user
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLogoutTwice(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	user := h.register(t)
+	tokens, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.service.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("first logout: %v", err)
+	}
+	if err := h.service.Logout(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("second logout: %v", err)
+	}
+
+	if _, err := h.service.RefreshToken(ctx, tokens.RefreshToken); err == nil {
+		t.Fatal("refresh token still works after logout")
+	}
+}
+
+func TestLogoutOfInvalidatedTokenSucceeds(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	user := h.register(t)
+	tokens, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.service.RefreshToken(ctx, tokens.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, token := range map[string]string{
+		"rotated":      tokens.RefreshToken,
+		"never issued": "never-issued",
+	} {
+		if err := h.service.Logout(ctx, token); err != nil {
+			t.Errorf("logout with a %s token: %v", name, err)
+		}
+	}
+}
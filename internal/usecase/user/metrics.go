@@ -0,0 +1,35 @@
+package user
+
+import (
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+// loginFailureReasons maps known login rejections to metric labels. Anything
+// else is reported as "internal" or "other" so label cardinality stays
+// bounded.
+var loginFailureReasons = map[*apperror.AppError]string{
+	apperror.ErrInvalidCredentials: "invalid_credentials",
+	apperror.ErrUserSuspended:      "suspended",
+	apperror.ErrEmailNotVerified:   "email_not_verified",
+	apperror.ErrInvalidToken:       "invalid_two_factor",
+	apperror.ErrTooManySessions:    "too_many_sessions",
+}
+
+func recordLoginFailure(err error) {
+	metrics.LoginFailuresTotal.WithLabelValues(loginFailureReason(err)).Inc()
+}
+
+func loginFailureReason(err error) string {
+	appErr := apperror.GetAppError(err)
+	if appErr == nil {
+		return "other"
+	}
+	if reason, ok := loginFailureReasons[appErr]; ok {
+		return reason
+	}
+	if appErr.StatusCode >= 500 {
+		return "internal"
+	}
+	return "other"
+}
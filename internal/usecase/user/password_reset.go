@@ -0,0 +1,112 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+const passwordResetSentKeyPrefix = "password_reset_sent:"
+
+// ForgotPassword emails a password reset token to the user. It succeeds for
+// unknown emails, for users sent a reset email too recently and when the
+// email cannot be sent, so callers cannot use it to find registered
+// addresses.
+func (s *userService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil {
+		return nil
+	}
+
+	if interval := s.config.Auth.PasswordResetInterval; interval > 0 {
+		first, err := s.cache.SetNX(ctx, passwordResetSentKeyPrefix+user.ID.String(), "1", int(interval.Seconds()))
+		if err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to check password reset interval", 500)
+		}
+		if !first {
+			return nil
+		}
+	}
+
+	rawToken, tokenHash, err := s.jwtManager.GenerateRefreshToken()
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate reset token", 500)
+	}
+
+	now := time.Now().UTC()
+	ttl := s.config.Auth.PasswordResetTTL
+	resetToken := &entity.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	// Only the most recent reset email works.
+	if err := s.resetTokenRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to invalidate reset tokens", 500)
+	}
+	if err := s.resetTokenRepo.Create(ctx, resetToken); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to store reset token", 500)
+	}
+
+	body := fmt.Sprintf("Use this code to reset your password: %s\n\nThe code expires in %s. If you did not ask to reset your password, you can ignore this email.", rawToken, ttl)
+	if err := s.mailer.Send(ctx, user.Email, "Reset your password", body); err != nil {
+		s.logger.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send password reset email")
+		return nil
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &user.ID, "user.forgot_password", "user", &user.ID, nil, nil, info.IPAddress, info.UserAgent)
+
+	return nil
+}
+
+// ResetPassword redeems a reset token and sets a new password. Every session
+// of the user is ended: refresh tokens are revoked and access tokens issued
+// before the reset are rejected.
+func (s *userService) ResetPassword(ctx context.Context, input *entity.ResetPasswordInput) error {
+	resetToken, err := s.resetTokenRepo.Consume(ctx, s.jwtManager.HashRefreshToken(input.Token))
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to redeem reset token", 500)
+	}
+	if resetToken == nil {
+		return apperror.ErrInvalidToken
+	}
+
+	passwordHash, err := s.passwordHasher.Hash(input.Password)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to hash password", 500)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, resetToken.UserID, passwordHash); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update password", 500)
+	}
+
+	count, err := s.refreshTokenRepo.DeleteByUserID(ctx, resetToken.UserID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke refresh tokens", 500)
+	}
+
+	ttl := int(s.config.JWT.AccessTokenExpiry.Seconds())
+	revokedAt := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := s.cache.Set(ctx, sessionsRevokedKeyPrefix+resetToken.UserID.String(), revokedAt, ttl); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke access tokens", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &resetToken.UserID, "user.reset_password", "user", &resetToken.UserID, nil,
+		map[string]interface{}{"sessions_terminated": count}, info.IPAddress, info.UserAgent)
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// sentMail records emails instead of sending them, failing each send with
+// err if it is set.
+type sentMail struct {
+	to  []string
+	err error
+}
+
+func (m *sentMail) Send(_ context.Context, to, _, _ string) error {
+	m.to = append(m.to, to)
+	return m.err
+}
+
+func TestForgotPasswordSucceedsWhenMailFails(t *testing.T) {
+	h := newHarness(t)
+	user := h.register(t)
+	mail := &sentMail{err: errors.New("smtp unavailable")}
+	h.service.mailer = mail
+
+	if err := h.service.ForgotPassword(context.Background(), user.Email); err != nil {
+		t.Fatalf("ForgotPassword with the mailer down = %v, want nil", err)
+	}
+	if len(mail.to) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(mail.to))
+	}
+}
+
+func TestForgotPasswordInterval(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+	h.service.config.Auth.PasswordResetInterval = time.Minute
+	user := h.register(t)
+	mail := &sentMail{}
+	h.service.mailer = mail
+
+	for i := 0; i < 3; i++ {
+		if err := h.service.ForgotPassword(ctx, user.Email); err != nil {
+			t.Fatalf("ForgotPassword call %d: %v", i+1, err)
+		}
+	}
+	if len(mail.to) != 1 {
+		t.Fatalf("sent %d reset emails within the interval, want 1", len(mail.to))
+	}
+}
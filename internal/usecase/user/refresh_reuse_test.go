@@ -0,0 +1,89 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+// reuses reads gobank_refresh_token_reuse_total.
+func reuses(t *testing.T) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() == "gobank_refresh_token_reuse_total" {
+			return family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestRefreshTokenReuseIsCountedAndListed(t *testing.T) {
+	h := newHarness(t)
+	ctx := audit.WithRequestInfo(context.Background(), audit.RequestInfo{IPAddress: "203.0.113.7", UserAgent: "replayer"})
+
+	user := h.register(t)
+	tokens, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := h.service.RefreshToken(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := reuses(t)
+
+	if _, err := h.service.RefreshToken(ctx, tokens.RefreshToken); !errors.Is(err, apperror.ErrRefreshTokenReused) {
+		t.Fatalf("replayed refresh token = %v, want ErrRefreshTokenReused", err)
+	}
+	if got := reuses(t) - before; got != 1 {
+		t.Fatalf("reuse counter moved by %v, want 1", got)
+	}
+
+	// The whole family is revoked, including the token that replaced it.
+	if _, err := h.service.RefreshToken(ctx, rotated.RefreshToken); err == nil {
+		t.Fatal("rotated refresh token still works after a reuse")
+	}
+
+	logs, err := h.auditLogs.GetByAction(ctx, entity.AuditActionRefreshTokenReuse, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event *entity.AuditLog
+	for _, log := range logs {
+		if log.UserID != nil && *log.UserID == user.ID {
+			event = log
+		}
+	}
+	if event == nil {
+		t.Fatal("reuse is not in the listing")
+	}
+	if event.IPAddress != "203.0.113.7" || event.CreatedAt.IsZero() {
+		t.Fatalf("reuse recorded as %+v, want the client IP and a timestamp", event)
+	}
+	if event.NewValues["family_id"] == nil {
+		t.Fatalf("reuse recorded without its token family: %v", event.NewValues)
+	}
+}
+
+func TestUnknownRefreshTokenIsNotReuse(t *testing.T) {
+	h := newHarness(t)
+	before := reuses(t)
+
+	if _, err := h.service.RefreshToken(context.Background(), "never-issued"); !errors.Is(err, apperror.ErrInvalidToken) {
+		t.Fatalf("unknown refresh token = %v, want ErrInvalidToken", err)
+	}
+	if got := reuses(t) - before; got != 0 {
+		t.Fatalf("reuse counter moved by %v for an unknown token", got)
+	}
+}
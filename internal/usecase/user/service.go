@@ -2,6 +2,8 @@ package user
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,32 +11,59 @@ import (
 	"github.com/yourusername/gobank/internal/domain/repository"
 	"github.com/yourusername/gobank/internal/domain/service"
 	"github.com/yourusername/gobank/internal/infrastructure/config"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
 	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
 	"github.com/yourusername/gobank/internal/pkg/password"
+	"github.com/yourusername/gobank/internal/pkg/secretbox"
 	"github.com/yourusername/gobank/internal/pkg/token"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+const (
+	suspendedKeyPrefix       = "user_suspended:"
+	sessionsRevokedKeyPrefix = "user_sessions_revoked:"
 )
 
 type userService struct {
 	userRepo         repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	resetTokenRepo   repository.PasswordResetTokenRepository
+	audit            service.AuditService
+	cache            service.CacheService
+	mailer           service.Mailer
 	passwordHasher   password.Hasher
 	jwtManager       token.JWTManager
+	totpSecrets      *secretbox.Box
 	config           *config.Config
+	logger           *logger.Logger
 }
 
 func NewUserService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	auditService service.AuditService,
+	cache service.CacheService,
+	mailer service.Mailer,
 	passwordHasher password.Hasher,
 	jwtManager token.JWTManager,
+	totpSecrets *secretbox.Box,
 	cfg *config.Config,
+	log *logger.Logger,
 ) service.UserService {
 	return &userService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		resetTokenRepo:   resetTokenRepo,
+		audit:            auditService,
+		cache:            cache,
+		mailer:           mailer,
 		passwordHasher:   passwordHasher,
 		jwtManager:       jwtManager,
+		totpSecrets:      totpSecrets,
 		config:           cfg,
+		logger:           log,
 	}
 }
 
@@ -58,10 +87,21 @@ func (s *userService) Register(ctx context.Context, input *entity.CreateUserInpu
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create user", 500)
 	}
 
+	// The account exists either way; a lost email can be resent.
+	_ = s.sendVerification(ctx, user)
+
 	return user, nil
 }
 
 func (s *userService) Login(ctx context.Context, input *entity.LoginInput) (*entity.AuthTokens, error) {
+	tokens, err := s.login(ctx, input)
+	if err != nil {
+		recordLoginFailure(err)
+	}
+	return tokens, err
+}
+
+func (s *userService) login(ctx context.Context, input *entity.LoginInput) (*entity.AuthTokens, error) {
 	user, err := s.userRepo.GetByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
@@ -73,7 +113,42 @@ func (s *userService) Login(ctx context.Context, input *entity.LoginInput) (*ent
 	if err := s.passwordHasher.Compare(user.PasswordHash, input.Password); err != nil {
 		return nil, apperror.ErrInvalidCredentials
 	}
+	s.rehashPassword(ctx, user, input.Password)
+
+	if user.Status == entity.UserStatusSuspended {
+		return nil, apperror.ErrUserSuspended
+	}
+
+	if s.config.Auth.RequireEmailVerification && !user.EmailVerified {
+		return nil, apperror.ErrEmailNotVerified
+	}
+
+	if user.TwoFactorEnabled {
+		return s.startTwoFactorChallenge(ctx, user)
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// rehashPassword upgrades a hash made at a lower cost than the configured
+// one, now that the plaintext is known. It is best effort: the login goes
+// ahead on failure and the upgrade is tried again next time.
+func (s *userService) rehashPassword(ctx context.Context, user *entity.User, plaintext string) {
+	if !s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+	hashed, err := s.passwordHasher.Hash(plaintext)
+	if err != nil {
+		return
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashed); err != nil {
+		return
+	}
+	user.PasswordHash = hashed
+}
 
+// issueTokens starts a new session for a user who has fully authenticated.
+func (s *userService) issueTokens(ctx context.Context, user *entity.User) (*entity.AuthTokens, error) {
 	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, user.Email, string(user.Role))
 	if err != nil {
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate access token", 500)
@@ -84,18 +159,27 @@ func (s *userService) Login(ctx context.Context, input *entity.LoginInput) (*ent
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate refresh token", 500)
 	}
 
+	if err := s.enforceSessionLimit(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	tokenID := uuid.New()
 	refreshTokenEntity := &entity.RefreshToken{
-		ID:        uuid.New(),
+		ID:        tokenID,
 		UserID:    user.ID,
+		FamilyID:  tokenID,
 		TokenHash: refreshTokenHash,
-		ExpiresAt: time.Now().Add(s.config.JWT.RefreshTokenExpiry),
-		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().UTC().Add(s.config.JWT.RefreshTokenExpiry),
+		CreatedAt: time.Now().UTC(),
 	}
 
 	if err := s.refreshTokenRepo.Create(ctx, refreshTokenEntity); err != nil {
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to store refresh token", 500)
 	}
 
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &user.ID, "user.login", "user", &user.ID, nil, nil, info.IPAddress, info.UserAgent)
+
 	return &entity.AuthTokens{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -112,7 +196,7 @@ func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*e
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to validate refresh token", 500)
 	}
 	if storedToken == nil {
-		return nil, apperror.ErrInvalidToken
+		return nil, s.checkRefreshTokenReuse(ctx, tokenHash)
 	}
 
 	if storedToken.ExpiresAt.Before(time.Now()) {
@@ -128,8 +212,18 @@ func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*e
 		return nil, apperror.ErrUserNotFound
 	}
 
-	if err := s.refreshTokenRepo.DeleteByTokenHash(ctx, tokenHash); err != nil {
-		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to delete old refresh token", 500)
+	if user.Status == entity.UserStatusSuspended {
+		_ = s.refreshTokenRepo.DeleteByTokenHash(ctx, tokenHash)
+		return nil, apperror.ErrUserSuspended
+	}
+
+	rotated, err := s.refreshTokenRepo.MarkUsed(ctx, tokenHash)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to rotate refresh token", 500)
+	}
+	if !rotated {
+		// Another request rotated this token between the lookup and now.
+		return nil, s.checkRefreshTokenReuse(ctx, tokenHash)
 	}
 
 	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, user.Email, string(user.Role))
@@ -145,9 +239,10 @@ func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*e
 	refreshTokenEntity := &entity.RefreshToken{
 		ID:        uuid.New(),
 		UserID:    user.ID,
+		FamilyID:  storedToken.FamilyID,
 		TokenHash: newRefreshTokenHash,
-		ExpiresAt: time.Now().Add(s.config.JWT.RefreshTokenExpiry),
-		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().UTC().Add(s.config.JWT.RefreshTokenExpiry),
+		CreatedAt: time.Now().UTC(),
 	}
 
 	if err := s.refreshTokenRepo.Create(ctx, refreshTokenEntity); err != nil {
@@ -162,9 +257,58 @@ func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*e
 	}, nil
 }
 
+// checkRefreshTokenReuse is called for a refresh token that is not live. If it
+// was rotated earlier, someone is replaying it, so every token in its family
+// is revoked. Otherwise the token is simply invalid.
+func (s *userService) checkRefreshTokenReuse(ctx context.Context, tokenHash string) error {
+	usedToken, err := s.refreshTokenRepo.GetUsedByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to validate refresh token", 500)
+	}
+	if usedToken == nil {
+		return apperror.ErrInvalidToken
+	}
+
+	revoked, err := s.refreshTokenRepo.DeleteByFamilyID(ctx, usedToken.FamilyID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke refresh tokens", 500)
+	}
+
+	metrics.RefreshTokenReuseTotal.Inc()
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &usedToken.UserID, entity.AuditActionRefreshTokenReuse, "user", &usedToken.UserID, nil,
+		map[string]interface{}{"family_id": usedToken.FamilyID, "sessions_terminated": revoked},
+		info.IPAddress, info.UserAgent)
+
+	return apperror.ErrRefreshTokenReused
+}
+
+// Logout revokes the given refresh token. It is idempotent: a token that is
+// unknown, expired, already rotated or already logged out is treated as
+// revoked and succeeds, so clients can safely retry a logout.
 func (s *userService) Logout(ctx context.Context, refreshToken string) error {
 	tokenHash := s.jwtManager.HashRefreshToken(refreshToken)
-	return s.refreshTokenRepo.DeleteByTokenHash(ctx, tokenHash)
+	if err := s.refreshTokenRepo.DeleteByTokenHash(ctx, tokenHash); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke refresh token", 500)
+	}
+	return nil
+}
+
+// LogoutAll revokes every refresh token belonging to the user and returns how
+// many sessions were terminated. Access tokens already issued remain valid
+// until they expire.
+func (s *userService) LogoutAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := s.refreshTokenRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke refresh tokens", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "user.logout_all", "user", &userID, nil,
+		map[string]interface{}{"sessions_terminated": count}, info.IPAddress, info.UserAgent)
+
+	return count, nil
 }
 
 func (s *userService) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
@@ -187,7 +331,12 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, input *entity.Up
 		return nil, apperror.ErrUserNotFound
 	}
 
-	if input.FullName != "" {
+	oldValues := map[string]interface{}{}
+	newValues := map[string]interface{}{}
+
+	if input.FullName != "" && input.FullName != user.FullName {
+		oldValues["full_name"] = user.FullName
+		newValues["full_name"] = input.FullName
 		user.FullName = input.FullName
 	}
 
@@ -199,12 +348,269 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, input *entity.Up
 		if exists {
 			return nil, apperror.ErrEmailAlreadyExists
 		}
+		oldValues["email"] = user.Email
+		newValues["email"] = input.Email
 		user.Email = input.Email
+		user.EmailVerified = false
+		user.EmailVerifiedAt = nil
 	}
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update user", 500)
 	}
 
+	if _, changed := newValues["email"]; changed {
+		_ = s.sendVerification(ctx, user)
+	}
+
+	if len(newValues) > 0 {
+		info := audit.RequestInfoFrom(ctx)
+		s.audit.Record(ctx, &user.ID, "user.update", "user", &user.ID, oldValues, newValues, info.IPAddress, info.UserAgent)
+	}
+
+	return user, nil
+}
+
+// Suspend blocks a user from authenticating without touching their data.
+// Refresh tokens are revoked, and a marker is cached for the lifetime of an
+// access token so the auth middleware rejects tokens issued before the
+// suspension.
+func (s *userService) Suspend(ctx context.Context, actorID, userID uuid.UUID, reason string) (*entity.User, error) {
+	if actorID == userID {
+		return nil, apperror.New("CANNOT_SUSPEND_SELF", "Admins cannot suspend their own account", 400)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	if user.Status == entity.UserStatusSuspended {
+		return user, nil
+	}
+
+	if err := s.setStatus(ctx, user, entity.UserStatusSuspended); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.refreshTokenRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke refresh tokens", 500)
+	}
+
+	ttl := int(s.config.JWT.AccessTokenExpiry.Seconds())
+	if err := s.cache.Set(ctx, suspendedKeyPrefix+user.ID.String(), "1", ttl); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke access tokens", 500)
+	}
+
+	newValues := map[string]interface{}{"status": entity.UserStatusSuspended}
+	if reason != "" {
+		newValues["reason"] = reason
+	}
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &actorID, "user.suspend", "user", &user.ID,
+		map[string]interface{}{"status": entity.UserStatusActive}, newValues,
+		info.IPAddress, info.UserAgent)
+
+	return user, nil
+}
+
+func (s *userService) Reactivate(ctx context.Context, actorID, userID uuid.UUID) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	if user.Status == entity.UserStatusActive {
+		return user, nil
+	}
+
+	if err := s.setStatus(ctx, user, entity.UserStatusActive); err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, suspendedKeyPrefix+user.ID.String()); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to clear suspension", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &actorID, "user.reactivate", "user", &user.ID,
+		map[string]interface{}{"status": entity.UserStatusSuspended},
+		map[string]interface{}{"status": entity.UserStatusActive},
+		info.IPAddress, info.UserAgent)
+
 	return user, nil
 }
+
+// IsSuspended reports whether access tokens for the user must be rejected.
+// It only consults the cache, so it is cheap enough to run on every request.
+func (s *userService) IsSuspended(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return s.cache.Exists(ctx, suspendedKeyPrefix+userID.String())
+}
+
+// ForceLogout ends every session of a user on an admin's behalf. Refresh
+// tokens are revoked, and the revocation time is cached for the lifetime of an
+// access token so tokens issued up to now are rejected. API keys are not
+// sessions and stay valid.
+func (s *userService) ForceLogout(ctx context.Context, actorID, userID uuid.UUID) (int64, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil {
+		return 0, apperror.ErrUserNotFound
+	}
+
+	count, err := s.revokeSessions(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &actorID, "user.force_logout", "user", &user.ID, nil,
+		map[string]interface{}{"sessions_terminated": count}, info.IPAddress, info.UserAgent)
+
+	return count, nil
+}
+
+// revokeSessions deletes the user's refresh tokens and caches the revocation
+// time for the lifetime of an access token, so tokens issued up to now are
+// rejected and the next one is minted from the current user record.
+func (s *userService) revokeSessions(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := s.refreshTokenRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke refresh tokens", 500)
+	}
+
+	ttl := int(s.config.JWT.AccessTokenExpiry.Seconds())
+	revokedAt := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := s.cache.Set(ctx, sessionsRevokedKeyPrefix+userID.String(), revokedAt, ttl); err != nil {
+		return 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to revoke access tokens", 500)
+	}
+	return count, nil
+}
+
+// List returns a page of users for admins, optionally narrowed to those whose
+// email or full name contains search.
+func (s *userService) List(ctx context.Context, page, pageSize int, search string) ([]*entity.User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	users, err := s.userRepo.List(ctx, search, pageSize, offset)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to list users", 500)
+	}
+
+	total, err := s.userRepo.CountAll(ctx, search)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count users", 500)
+	}
+
+	return users, total, nil
+}
+
+// ChangeRole sets a user's role. Their sessions are revoked, since access
+// and refresh tokens carry the old role; it takes effect when they next log
+// in.
+func (s *userService) ChangeRole(ctx context.Context, actorID, userID uuid.UUID, role entity.UserRole) (*entity.User, error) {
+	if actorID == userID {
+		return nil, apperror.New("CANNOT_CHANGE_OWN_ROLE", "Admins cannot change their own role", 400)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil {
+		return nil, apperror.ErrUserNotFound
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	oldRole := user.Role
+	user.Role = role
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update user role", 500)
+	}
+
+	if _, err := s.revokeSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &actorID, "user.change_role", "user", &user.ID,
+		map[string]interface{}{"role": oldRole},
+		map[string]interface{}{"role": role},
+		info.IPAddress, info.UserAgent)
+
+	return user, nil
+}
+
+// Delete removes a user who has no account history. Users with history must
+// be suspended instead so the ledger stays intact.
+func (s *userService) Delete(ctx context.Context, actorID, userID uuid.UUID) error {
+	if actorID == userID {
+		return apperror.New("CANNOT_DELETE_SELF", "Admins cannot delete their own account", 400)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil {
+		return apperror.ErrUserNotFound
+	}
+
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		if errors.Is(err, apperror.ErrUserHasRecords) {
+			return err
+		}
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to delete user", 500)
+	}
+
+	// Refresh tokens went with the user; access tokens already issued must
+	// stop working too.
+	if _, err := s.revokeSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &actorID, "user.delete", "user", &user.ID,
+		map[string]interface{}{"email": user.Email, "role": user.Role}, nil,
+		info.IPAddress, info.UserAgent)
+
+	return nil
+}
+
+// IsTokenRevoked reports whether an access token issued at issuedAt predates
+// the user's last force-logout. Token timestamps have one-second precision, so
+// a token issued in the same second as the logout is treated as revoked.
+func (s *userService) IsTokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	value, err := s.cache.Get(ctx, sessionsRevokedKeyPrefix+userID.String())
+	if err != nil || value == "" {
+		return false, err
+	}
+
+	revokedAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedAt.Unix() <= revokedAt, nil
+}
+
+func (s *userService) setStatus(ctx context.Context, user *entity.User, status entity.UserStatus) error {
+	user.Status = status
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update user status", 500)
+	}
+	return nil
+}
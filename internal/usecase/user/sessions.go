@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+// SessionLimitPolicy decides what a login does when the user already has
+// the maximum number of sessions.
+type SessionLimitPolicy string
+
+const (
+	// SessionPolicyBlock rejects the login.
+	SessionPolicyBlock SessionLimitPolicy = "block"
+	// SessionPolicyEvictOldest ends the least recently used session to make
+	// room.
+	SessionPolicyEvictOldest SessionLimitPolicy = "evict_oldest"
+)
+
+// ParseSessionLimitPolicy returns the named policy, defaulting to
+// SessionPolicyBlock.
+func ParseSessionLimitPolicy(s string) SessionLimitPolicy {
+	if SessionLimitPolicy(s) == SessionPolicyEvictOldest {
+		return SessionPolicyEvictOldest
+	}
+	return SessionPolicyBlock
+}
+
+// enforceSessionLimit makes room for a new session under the configured
+// limit. A session is a live refresh token; rotation replaces its token, so
+// the token's creation time is when the session was last used. Evicted
+// sessions can no longer refresh, but access tokens already issued to them
+// stay valid until they expire.
+func (s *userService) enforceSessionLimit(ctx context.Context, userID uuid.UUID) error {
+	limit := s.config.Auth.MaxSessions
+	if limit <= 0 {
+		return nil
+	}
+
+	sessions, err := s.refreshTokenRepo.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to list sessions", 500)
+	}
+	if len(sessions) < limit {
+		return nil
+	}
+
+	if ParseSessionLimitPolicy(s.config.Auth.SessionLimitPolicy) != SessionPolicyEvictOldest {
+		return apperror.ErrTooManySessions
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	for _, session := range sessions[:len(sessions)-limit+1] {
+		if _, err := s.refreshTokenRepo.DeleteByFamilyID(ctx, session.FamilyID); err != nil {
+			return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to end session", 500)
+		}
+		s.audit.Record(ctx, &userID, "user.session_evicted", "user", &userID, nil,
+			map[string]interface{}{"family_id": session.FamilyID, "last_used_at": session.CreatedAt},
+			info.IPAddress, info.UserAgent)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestParseSessionLimitPolicy(t *testing.T) {
+	tests := map[string]SessionLimitPolicy{
+		"evict_oldest": SessionPolicyEvictOldest,
+		"block":        SessionPolicyBlock,
+		"":             SessionPolicyBlock,
+		"unknown":      SessionPolicyBlock,
+	}
+	for input, want := range tests {
+		if got := ParseSessionLimitPolicy(input); got != want {
+			t.Errorf("ParseSessionLimitPolicy(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestSessionLimitBlocksLogin(t *testing.T) {
+	h := newHarness(t)
+	h.service.config.Auth.MaxSessions = 2
+	h.service.config.Auth.SessionLimitPolicy = string(SessionPolicyBlock)
+	ctx := context.Background()
+
+	user := h.register(t)
+	first, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.login(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.login(user); !errors.Is(err, apperror.ErrTooManySessions) {
+		t.Fatalf("login at the limit = %v, want ErrTooManySessions", err)
+	}
+
+	// Existing sessions are untouched, and logging one out makes room.
+	if err := h.service.Logout(ctx, first.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.login(user); err != nil {
+		t.Fatalf("login after freeing a session: %v", err)
+	}
+}
+
+func TestSessionLimitEvictsLeastRecentlyUsed(t *testing.T) {
+	h := newHarness(t)
+	h.service.config.Auth.MaxSessions = 2
+	h.service.config.Auth.SessionLimitPolicy = string(SessionPolicyEvictOldest)
+	ctx := context.Background()
+
+	user := h.register(t)
+	phone, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	laptop, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Using the phone session makes the laptop the least recently used.
+	phone, err = h.service.RefreshToken(ctx, phone.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tablet, err := h.login(user)
+	if err != nil {
+		t.Fatalf("login at the limit: %v", err)
+	}
+
+	if _, err := h.service.RefreshToken(ctx, laptop.RefreshToken); err == nil {
+		t.Fatal("evicted session can still refresh")
+	}
+	for name, tokens := range map[string]string{"phone": phone.RefreshToken, "tablet": tablet.RefreshToken} {
+		if _, err := h.service.RefreshToken(ctx, tokens); err != nil {
+			t.Fatalf("%s session: %v", name, err)
+		}
+	}
+
+	sessions, err := h.refreshTokens.ListActiveByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("user has %d sessions, want 2", len(sessions))
+	}
+
+	logs, err := h.auditLogs.GetByAction(ctx, "user.session_evicted", 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var evictions int
+	for _, log := range logs {
+		if log.UserID != nil && *log.UserID == user.ID {
+			evictions++
+		}
+	}
+	if evictions != 1 {
+		t.Fatalf("recorded %d evictions, want 1", evictions)
+	}
+}
+
+func TestNoSessionLimitByDefault(t *testing.T) {
+	h := newHarness(t)
+
+	user := h.register(t)
+	for i := 0; i < 5; i++ {
+		if _, err := h.login(user); err != nil {
+			t.Fatalf("login %d: %v", i+1, err)
+		}
+	}
+}
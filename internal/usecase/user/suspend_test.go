@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestSuspendAndReactivate(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	admin := h.register(t)
+	user := h.register(t)
+
+	tokens, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.service.Suspend(ctx, admin.ID, user.ID, "fraud review"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.login(user); !errors.Is(err, apperror.ErrUserSuspended) {
+		t.Fatalf("login while suspended = %v, want ErrUserSuspended", err)
+	}
+	if _, err := h.service.RefreshToken(ctx, tokens.RefreshToken); err == nil {
+		t.Fatal("refresh token issued before the suspension still works")
+	}
+	if suspended, err := h.service.IsSuspended(ctx, user.ID); err != nil || !suspended {
+		t.Fatalf("IsSuspended = %v, %v; want true", suspended, err)
+	}
+
+	// The user's data is kept.
+	stored, err := h.users.GetByID(ctx, user.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("get suspended user: %v", err)
+	}
+	if stored.Status != entity.UserStatusSuspended || stored.Email != user.Email {
+		t.Fatalf("suspended user stored as %+v", stored)
+	}
+
+	if _, err := h.service.Reactivate(ctx, admin.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if suspended, err := h.service.IsSuspended(ctx, user.ID); err != nil || suspended {
+		t.Fatalf("IsSuspended after reactivation = %v, %v; want false", suspended, err)
+	}
+	tokens, err = h.login(user)
+	if err != nil {
+		t.Fatalf("login after reactivation: %v", err)
+	}
+	if _, err := h.service.RefreshToken(ctx, tokens.RefreshToken); err != nil {
+		t.Fatalf("refresh after reactivation: %v", err)
+	}
+}
+
+func TestSuspendSelfRefused(t *testing.T) {
+	h := newHarness(t)
+
+	admin := h.register(t)
+	if _, err := h.service.Suspend(context.Background(), admin.ID, admin.ID, ""); err == nil {
+		t.Fatal("admin suspended their own account")
+	}
+	if _, err := h.login(admin); err != nil {
+		t.Fatalf("login after refused self-suspension: %v", err)
+	}
+}
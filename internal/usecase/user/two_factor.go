@@ -0,0 +1,214 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/pkg/totp"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+const (
+	twoFactorChallengeKeyPrefix = "2fa_challenge:"
+	twoFactorAttemptsKeyPrefix  = "2fa_attempts:"
+	twoFactorLastStepKeyPrefix  = "2fa_last_step:"
+	twoFactorUsedStepKeyPrefix  = "2fa_used_step:"
+
+	// maxTwoFactorAttempts is how many wrong codes a login challenge
+	// tolerates before it is discarded and the user must log in again.
+	maxTwoFactorAttempts = 5
+)
+
+type twoFactorChallenge struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// EnableTwoFactor starts 2FA enrollment with a new secret. The secret is not
+// used for login until ConfirmTwoFactor accepts a code generated from it, so
+// restarting enrollment simply replaces it.
+func (s *userService) EnableTwoFactor(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorEnrollment, error) {
+	user, err := s.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, apperror.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate 2FA secret", 500)
+	}
+	sealed, err := s.totpSecrets.Seal(secret)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to encrypt 2FA secret", 500)
+	}
+
+	user.TOTPSecret = &sealed
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update user", 500)
+	}
+
+	return &entity.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.config.JWT.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor activates 2FA once the user proves their authenticator
+// produces valid codes for the pending secret.
+func (s *userService) ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) (*entity.User, error) {
+	user, err := s.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, apperror.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == nil {
+		return nil, apperror.ErrTwoFactorNotPending
+	}
+
+	if err := s.checkTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	user.TwoFactorEnabled = true
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to update user", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &user.ID, "user.enable_2fa", "user", &user.ID,
+		map[string]interface{}{"two_factor_enabled": false},
+		map[string]interface{}{"two_factor_enabled": true}, info.IPAddress, info.UserAgent)
+
+	return user, nil
+}
+
+// startTwoFactorChallenge is the first step of a login for a 2FA user. The
+// returned challenge token stands in for the password on the second step.
+func (s *userService) startTwoFactorChallenge(ctx context.Context, user *entity.User) (*entity.AuthTokens, error) {
+	challengeToken, challengeHash, err := s.jwtManager.GenerateRefreshToken()
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate 2FA challenge", 500)
+	}
+
+	ttl := s.config.Auth.TwoFactorChallengeTTL
+	challenge := twoFactorChallenge{UserID: user.ID}
+	if err := s.cache.Set(ctx, twoFactorChallengeKeyPrefix+challengeHash, challenge, int(ttl.Seconds())); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to store 2FA challenge", 500)
+	}
+
+	return &entity.AuthTokens{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+		ExpiresIn:         int64(ttl.Seconds()),
+	}, nil
+}
+
+// CompleteTwoFactorLogin finishes a login started by Login for a 2FA user.
+func (s *userService) CompleteTwoFactorLogin(ctx context.Context, input *entity.TwoFactorLoginInput) (*entity.AuthTokens, error) {
+	tokens, err := s.completeTwoFactorLogin(ctx, input)
+	if err != nil {
+		recordLoginFailure(err)
+	}
+	return tokens, err
+}
+
+func (s *userService) completeTwoFactorLogin(ctx context.Context, input *entity.TwoFactorLoginInput) (*entity.AuthTokens, error) {
+	challengeHash := s.jwtManager.HashRefreshToken(input.ChallengeToken)
+	key := twoFactorChallengeKeyPrefix + challengeHash
+	attemptsKey := twoFactorAttemptsKeyPrefix + challengeHash
+
+	cached, err := s.cache.Get(ctx, key)
+	if err != nil || cached == "" {
+		return nil, apperror.ErrInvalidToken
+	}
+	var challenge twoFactorChallenge
+	if err := json.Unmarshal([]byte(cached), &challenge); err != nil {
+		return nil, apperror.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get user", 500)
+	}
+	if user == nil || !user.TwoFactorEnabled {
+		_ = s.cache.Delete(ctx, key)
+		return nil, apperror.ErrInvalidToken
+	}
+	if user.Status == entity.UserStatusSuspended {
+		_ = s.cache.Delete(ctx, key)
+		return nil, apperror.ErrUserSuspended
+	}
+
+	// Every attempt is counted before the code is checked, so concurrent
+	// guesses cannot get past the limit between a read and a write.
+	attempts, err := s.cache.Incr(ctx, attemptsKey, int(s.config.Auth.TwoFactorChallengeTTL.Seconds()))
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count 2FA attempts", 500)
+	}
+	if attempts > maxTwoFactorAttempts {
+		_ = s.cache.Delete(ctx, key)
+		return nil, apperror.ErrInvalidToken
+	}
+
+	if err := s.checkTOTP(ctx, user, input.Code); err != nil {
+		if attempts == maxTwoFactorAttempts {
+			_ = s.cache.Delete(ctx, key)
+		}
+		return nil, err
+	}
+
+	// Only one request can consume the challenge.
+	if consumed, err := s.cache.GetDel(ctx, key); err != nil || consumed == "" {
+		return nil, apperror.ErrInvalidToken
+	}
+	_ = s.cache.Delete(ctx, attemptsKey)
+	return s.issueTokens(ctx, user)
+}
+
+// checkTOTP validates code against the user's secret. A code's time step can
+// be used only once, so an intercepted code cannot be replayed.
+func (s *userService) checkTOTP(ctx context.Context, user *entity.User, code string) error {
+	secret, err := s.totpSecrets.Open(*user.TOTPSecret)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to decrypt 2FA secret", 500)
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return apperror.ErrInvalidTwoFactorCode
+	}
+
+	lastStepKey := twoFactorLastStepKeyPrefix + user.ID.String()
+	if last, err := s.cache.Get(ctx, lastStepKey); err == nil && last != "" {
+		if lastStep, err := strconv.ParseInt(last, 10, 64); err == nil && step <= lastStep {
+			return apperror.ErrInvalidTwoFactorCode
+		}
+	}
+
+	// Long enough to outlive every step the code could still match.
+	ttl := int(totp.Period.Seconds()) * (2*totp.Skew + 1)
+
+	// The last step above only turns away codes older than one already
+	// used; claiming the step itself keeps two concurrent requests from
+	// both using the same code.
+	usedStepKey := twoFactorUsedStepKeyPrefix + user.ID.String() + ":" + strconv.FormatInt(step, 10)
+	claimed, err := s.cache.SetNX(ctx, usedStepKey, "1", ttl)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to record 2FA code use", 500)
+	}
+	if !claimed {
+		return apperror.ErrInvalidTwoFactorCode
+	}
+	_ = s.cache.Set(ctx, lastStepKey, strconv.FormatInt(step, 10), ttl)
+
+	return nil
+}
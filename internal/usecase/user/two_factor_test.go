@@ -0,0 +1,74 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+)
+
+func TestTwoFactorAttemptLimitHoldsUnderConcurrency(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	user := h.register(t)
+	if _, err := h.service.EnableTwoFactor(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := h.users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.TwoFactorEnabled = true
+	if err := h.users.Update(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := h.login(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tokens.TwoFactorRequired {
+		t.Fatal("login did not ask for a 2FA code")
+	}
+
+	// A code of the wrong length never validates, so every guess is wrong.
+	const guesses = 4 * maxTwoFactorAttempts
+	errs := make([]error, guesses)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = h.service.CompleteTwoFactorLogin(ctx, &entity.TwoFactorLoginInput{
+				ChallengeToken: tokens.ChallengeToken,
+				Code:           "0",
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	checked := 0
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, apperror.ErrInvalidTwoFactorCode):
+			checked++
+		case errors.Is(err, apperror.ErrInvalidToken):
+		default:
+			t.Fatalf("wrong code = %v, want ErrInvalidTwoFactorCode or ErrInvalidToken", err)
+		}
+	}
+	if checked != maxTwoFactorAttempts {
+		t.Fatalf("%d of %d concurrent guesses were checked, want %d", checked, guesses, maxTwoFactorAttempts)
+	}
+
+	if _, err := h.service.CompleteTwoFactorLogin(ctx, &entity.TwoFactorLoginInput{
+		ChallengeToken: tokens.ChallengeToken,
+		Code:           "0",
+	}); !errors.Is(err, apperror.ErrInvalidToken) {
+		t.Fatalf("guess after the limit = %v, want ErrInvalidToken", err)
+	}
+}
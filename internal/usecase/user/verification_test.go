@@ -0,0 +1,24 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestResendVerificationWithinIntervalIsSilent(t *testing.T) {
+	h := newHarness(t)
+	h.service.config.Auth.VerificationResendInterval = time.Minute
+
+	// Registering sends the first verification email.
+	user := h.register(t)
+	mail := &sentMail{}
+	h.service.mailer = mail
+
+	if err := h.service.ResendVerification(context.Background(), user.Email); err != nil {
+		t.Fatalf("ResendVerification within the interval = %v, want nil", err)
+	}
+	if len(mail.to) != 0 {
+		t.Fatalf("sent %d verification emails within the interval, want 0", len(mail.to))
+	}
+}
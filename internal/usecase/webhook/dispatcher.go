@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/infrastructure/logger"
+	"github.com/yourusername/gobank/internal/pkg/metrics"
+)
+
+const (
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of
+	// the request body, keyed with the webhook's secret.
+	SignatureHeader = "X-Signature"
+	EventHeader     = "X-Webhook-Event"
+	// DeliveryHeader identifies the delivery. It is the same on every retry,
+	// so receivers can discard duplicates.
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	// leaseMargin is added to the request timeout to give how long a claimed
+	// delivery is held before another dispatcher may retry it.
+	leaseMargin = time.Minute
+	// maxResponseRead bounds how much of a response body is drained so the
+	// connection can be reused.
+	maxResponseRead = 64 << 10
+)
+
+// DispatcherConfig controls how deliveries are sent and retried.
+type DispatcherConfig struct {
+	Interval        time.Duration
+	BatchSize       int
+	Timeout         time.Duration
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// AllowPrivateTargets lets deliveries reach loopback and private
+	// addresses, for local development.
+	AllowPrivateTargets bool
+}
+
+// Dispatcher sends queued webhook deliveries. A delivery that does not get a
+// 2xx response is retried with exponential backoff until MaxAttempts is
+// reached, after which it is marked failed.
+type Dispatcher struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	client       *http.Client
+	config       DispatcherConfig
+	wake         chan struct{}
+	logger       *logger.Logger
+}
+
+func NewDispatcher(
+	webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	config DispatcherConfig,
+	log *logger.Logger,
+) *Dispatcher {
+	return &Dispatcher{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		client:       newClient(config.Timeout, config.AllowPrivateTargets),
+		config:       config,
+		wake:         make(chan struct{}, 1),
+		logger:       log,
+	}
+}
+
+// Wake makes the dispatcher run now instead of at its next tick. It never
+// blocks.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start sends due deliveries on every tick, or when woken, until ctx is
+// cancelled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		// Keep going while full batches come back so a backlog drains
+		// without waiting a tick per batch.
+		for ctx.Err() == nil {
+			sent, err := d.RunOnce(ctx)
+			if err != nil {
+				d.logger.Error().Err(err).Msg("Webhook dispatch failed")
+				break
+			}
+			if sent < d.config.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// RunOnce claims up to one batch of due deliveries, sends them concurrently
+// and records each outcome. It returns how many were attempted.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	deliveries, err := d.deliveryRepo.ClaimDue(ctx, now, now.Add(d.config.Timeout+leaseMargin), d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := make(map[uuid.UUID]*entity.Webhook)
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = d.webhookRepo.GetByID(ctx, delivery.WebhookID)
+			if err != nil {
+				wg.Wait()
+				return 0, err
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+		// Deleting a webhook deletes its deliveries, so there is nothing
+		// left to record.
+		if webhook == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(webhook *entity.Webhook, delivery *entity.WebhookDelivery) {
+			defer wg.Done()
+			d.attempt(ctx, webhook, delivery)
+		}(webhook, delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// attempt sends one delivery and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) {
+	statusCode, err := d.send(ctx, webhook, delivery)
+	if err != nil && ctx.Err() != nil {
+		// Cut short by shutdown, which is not the endpoint's fault. The
+		// delivery is retried once its lease expires.
+		return
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	if statusCode != 0 {
+		delivery.ResponseStatus = &statusCode
+	}
+
+	outcome := "succeeded"
+	switch {
+	case err == nil:
+		delivery.Status = entity.WebhookDeliverySucceeded
+		delivery.LastError = nil
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.config.MaxAttempts:
+		outcome = "failed"
+		msg := err.Error()
+		delivery.Status = entity.WebhookDeliveryFailed
+		delivery.LastError = &msg
+	default:
+		outcome = "retrying"
+		msg := err.Error()
+		delivery.LastError = &msg
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+	}
+	metrics.WebhookDeliveriesTotal.WithLabelValues(delivery.EventType, outcome).Inc()
+
+	if err != nil {
+		d.logger.Warn().Err(err).
+			Str("delivery_id", delivery.ID.String()).
+			Str("webhook_id", delivery.WebhookID.String()).
+			Str("event_type", delivery.EventType).
+			Int("attempts", delivery.Attempts).
+			Str("outcome", outcome).
+			Msg("Webhook delivery failed")
+	}
+
+	// Record the outcome even if shutdown has begun since; otherwise the
+	// lease expires and the delivery is sent again.
+	if err := d.deliveryRepo.RecordAttempt(context.WithoutCancel(ctx), delivery); err != nil {
+		d.logger.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("Failed to record webhook delivery")
+	}
+}
+
+// send POSTs the delivery's payload, signed with the webhook's secret, and
+// returns the response status. Anything but a 2xx response is an error.
+func (d *Dispatcher) send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, delivery.Payload))
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseRead))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait before the attempt after the given number of
+// attempts: RetryBackoff doubled for each earlier retry, capped at
+// MaxRetryBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.RetryBackoff
+	for i := 1; i < attempts && delay < d.config.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.config.MaxRetryBackoff {
+		delay = d.config.MaxRetryBackoff
+	}
+	return delay
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret, as sent in
+// SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,217 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/gobank/internal/domain/entity"
+	"github.com/yourusername/gobank/internal/domain/repository"
+	"github.com/yourusername/gobank/internal/domain/service"
+	"github.com/yourusername/gobank/internal/pkg/apperror"
+	"github.com/yourusername/gobank/internal/usecase/audit"
+)
+
+const secretPrefix = "whsec_"
+
+type webhookService struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	dispatcher   *Dispatcher
+	audit        service.AuditService
+	maxPerUser   int
+	allowHTTP    bool
+	allowPrivate bool
+}
+
+// NewWebhookService returns the webhook service. Deliveries it queues wake
+// dispatcher, so they go out without waiting for its next tick.
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	dispatcher *Dispatcher,
+	auditService service.AuditService,
+	maxPerUser int,
+	allowHTTP bool,
+	allowPrivate bool,
+) service.WebhookService {
+	return &webhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		dispatcher:   dispatcher,
+		audit:        auditService,
+		maxPerUser:   maxPerUser,
+		allowHTTP:    allowHTTP,
+		allowPrivate: allowPrivate,
+	}
+}
+
+func (s *webhookService) Create(ctx context.Context, userID uuid.UUID, input *entity.CreateWebhookInput) (*entity.CreatedWebhook, error) {
+	if !s.validURL(input.URL) {
+		return nil, apperror.ErrInvalidWebhookURL
+	}
+
+	if s.maxPerUser > 0 {
+		count, err := s.webhookRepo.CountByUserID(ctx, userID)
+		if err != nil {
+			return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count webhooks", 500)
+		}
+		if count >= int64(s.maxPerUser) {
+			return nil, apperror.ErrWebhookLimitReached
+		}
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to generate webhook secret", 500)
+	}
+
+	webhook := &entity.Webhook{
+		ID:         uuid.New(),
+		UserID:     userID,
+		URL:        input.URL,
+		Secret:     secret,
+		EventTypes: dedupeEventTypes(input.EventTypes),
+		Active:     true,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to create webhook", 500)
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "webhook.create", "webhook", &webhook.ID, nil, map[string]interface{}{
+		"url":         webhook.URL,
+		"event_types": webhook.EventTypes,
+	}, info.IPAddress, info.UserAgent)
+
+	return &entity.CreatedWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+func (s *webhookService) List(ctx context.Context, userID uuid.UUID) ([]*entity.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get webhooks", 500)
+	}
+	return webhooks, nil
+}
+
+// Delete removes the webhook along with its delivery history. Deliveries
+// already being sent may still arrive.
+func (s *webhookService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := s.webhookRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return apperror.Wrap(err, "INTERNAL_ERROR", "Failed to delete webhook", 500)
+	}
+	if !deleted {
+		return apperror.ErrWebhookNotFound
+	}
+
+	info := audit.RequestInfoFrom(ctx)
+	s.audit.Record(ctx, &userID, "webhook.delete", "webhook", &id, nil, nil, info.IPAddress, info.UserAgent)
+
+	return nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, userID, id uuid.UUID, page, pageSize int) ([]*entity.WebhookDelivery, int64, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get webhook", 500)
+	}
+	if webhook == nil || webhook.UserID != userID {
+		return nil, 0, apperror.ErrWebhookNotFound
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	deliveries, err := s.deliveryRepo.GetByWebhookID(ctx, id, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to get webhook deliveries", 500)
+	}
+
+	total, err := s.deliveryRepo.CountByWebhookID(ctx, id)
+	if err != nil {
+		return nil, 0, apperror.Wrap(err, "INTERNAL_ERROR", "Failed to count webhook deliveries", 500)
+	}
+
+	return deliveries, total, nil
+}
+
+// Publish queues a delivery of event to each webhook of the users it
+// concerns that subscribes to its type. A republished event is not queued
+// twice for the same webhook.
+func (s *webhookService) Publish(ctx context.Context, event *entity.OutboxEvent) error {
+	recipients := event.Recipients()
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	webhooks, err := s.webhookRepo.GetActiveByUserIDs(ctx, recipients)
+	if err != nil {
+		return err
+	}
+
+	queued := 0
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event.EventType) {
+			continue
+		}
+		delivery, err := entity.NewWebhookDelivery(webhook, event)
+		if err != nil {
+			return err
+		}
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			return err
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		s.dispatcher.Wake()
+	}
+	return nil
+}
+
+// validURL rejects what can be ruled out up front. Names that resolve to
+// private addresses are refused by the dispatcher when it connects.
+func (s *webhookService) validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || u.User != nil {
+		return false
+	}
+	if !s.allowPrivate && blockedHost(u.Hostname()) {
+		return false
+	}
+	return u.Scheme == "https" || (s.allowHTTP && u.Scheme == "http")
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// dedupeEventTypes never returns nil, as an empty list is stored as
+// subscribing to everything.
+func dedupeEventTypes(eventTypes []string) []string {
+	seen := make(map[string]bool, len(eventTypes))
+	out := make([]string, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errRedirect is returned for a redirect response. Redirects are not
+// followed, as the new location has not been checked.
+var errRedirect = errors.New("redirects are not followed")
+
+// sharedAddressSpace is the carrier-grade NAT range, which IsPrivate does not
+// cover.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// blockedAddr reports whether addr is not a public unicast address, e.g.
+// loopback, RFC 1918, link-local (which includes cloud metadata endpoints)
+// or unspecified.
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsGlobalUnicast() ||
+		addr.IsPrivate() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// blockedHost reports whether a URL host is known not to be public without
+// resolving it: localhost or a literal non-public IP.
+func blockedHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && blockedAddr(addr)
+}
+
+// dialControl refuses connections to non-public addresses. It runs after DNS
+// resolution, so a public name that resolves to a private address is
+// refused too.
+func dialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if blockedAddr(addrPort.Addr()) {
+		return fmt.Errorf("webhook target %s is not a public address", addrPort.Addr())
+	}
+	return nil
+}
+
+// newClient returns the client deliveries are sent with. Unless
+// allowPrivate is set it only connects to public addresses. Redirects are
+// never followed.
+func newClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = dialControl
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return errRedirect
+		},
+	}
+}
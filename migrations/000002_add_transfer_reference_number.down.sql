@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transfers_reference_number;
+
+ALTER TABLE transfers DROP COLUMN IF EXISTS reference_number;
@@ -0,0 +1,10 @@
+-- Human-friendly confirmation number for transfers
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS reference_number VARCHAR(16);
+
+UPDATE transfers
+SET reference_number = UPPER(SUBSTRING(REPLACE(id::text, '-', '') FROM 1 FOR 11))
+WHERE reference_number IS NULL;
+
+ALTER TABLE transfers ALTER COLUMN reference_number SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfers_reference_number ON transfers(reference_number);
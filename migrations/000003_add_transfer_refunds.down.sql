@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transfers_refund_of;
+
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS refunded_within_amount;
+ALTER TABLE transfers DROP COLUMN IF EXISTS refund_of;
+ALTER TABLE transfers DROP COLUMN IF EXISTS refunded_amount;
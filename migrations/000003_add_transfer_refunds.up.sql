@@ -0,0 +1,7 @@
+-- Partial refunds: refunds are transfers that point back at the original
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS refunded_amount DECIMAL(19,4) NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0);
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS refund_of UUID REFERENCES transfers(id);
+
+ALTER TABLE transfers ADD CONSTRAINT refunded_within_amount CHECK (refunded_amount <= amount);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_refund_of ON transfers(refund_of);
@@ -0,0 +1,3 @@
+ALTER TABLE accounts DROP COLUMN IF EXISTS require_memo;
+
+ALTER TABLE transfers DROP COLUMN IF EXISTS description;
@@ -0,0 +1,4 @@
+-- Memo on transfers, optionally mandatory per source account
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS description VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS require_memo BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Suspended users keep their data but cannot authenticate
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'suspended'));
@@ -0,0 +1,2 @@
+-- Fingerprint of the request that first used an idempotency key
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS request_hash VARCHAR(64) NOT NULL DEFAULT '';
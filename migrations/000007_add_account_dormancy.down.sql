@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_account_created;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS reactivation_requested_at;
+ALTER TABLE accounts DROP COLUMN IF EXISTS status_reason;
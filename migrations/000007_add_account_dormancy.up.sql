@@ -0,0 +1,5 @@
+-- Why an account was frozen, and pending owner requests to lift a dormancy freeze
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status_reason VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS reactivation_requested_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_created ON transactions(account_id, created_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transfers_reversal_of;
+
+ALTER TABLE transfers DROP COLUMN IF EXISTS reversal_of;
+
+UPDATE transfers SET status = 'completed' WHERE status = 'reversed';
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_status_check;
+ALTER TABLE transfers ADD CONSTRAINT transfers_status_check CHECK (status IN ('pending', 'completed', 'failed'));
@@ -0,0 +1,7 @@
+-- Reversals: compensating transfers that undo a completed transfer
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_status_check;
+ALTER TABLE transfers ADD CONSTRAINT transfers_status_check CHECK (status IN ('pending', 'completed', 'failed', 'reversed'));
+
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS reversal_of UUID REFERENCES transfers(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfers_reversal_of ON transfers(reversal_of);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transfers_scheduled_due;
+
+ALTER TABLE transfers DROP COLUMN IF EXISTS scheduled_at;
+
+UPDATE transfers SET status = 'failed' WHERE status = 'scheduled';
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_status_check;
+ALTER TABLE transfers ADD CONSTRAINT transfers_status_check CHECK (status IN ('pending', 'completed', 'failed', 'reversed'));
@@ -0,0 +1,7 @@
+-- Scheduled transfers: stored ahead of time and settled when scheduled_at passes
+ALTER TABLE transfers DROP CONSTRAINT IF EXISTS transfers_status_check;
+ALTER TABLE transfers ADD CONSTRAINT transfers_status_check CHECK (status IN ('pending', 'completed', 'failed', 'reversed', 'scheduled'));
+
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_transfers_scheduled_due ON transfers(scheduled_at) WHERE status = 'scheduled';
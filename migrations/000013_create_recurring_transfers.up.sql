@@ -0,0 +1,23 @@
+-- Standing orders: each run materializes a scheduled transfer
+CREATE TABLE IF NOT EXISTS recurring_transfers (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    from_account_id UUID NOT NULL REFERENCES accounts(id),
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(19,4) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+    timezone VARCHAR(64) NOT NULL,
+    start_at TIMESTAMPTZ NOT NULL,
+    end_date TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ,
+    run_count INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled', 'completed')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_transfers_user_id ON recurring_transfers(user_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_transfers_due ON recurring_transfers(next_run_at) WHERE status = 'active';
@@ -0,0 +1,8 @@
+-- Per-account destination allowlist; an account with no rows may pay anyone
+CREATE TABLE IF NOT EXISTS account_allowed_destinations (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    destination_account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, destination_account_id),
+    CHECK (account_id <> destination_account_id)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transfers_from_account_completed;
+ALTER TABLE accounts DROP COLUMN IF EXISTS daily_transfer_limit;
@@ -0,0 +1,4 @@
+-- Optional cap on an account's completed outbound transfers per UTC day; NULL means no limit
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS daily_transfer_limit DECIMAL(19,4) CHECK (daily_transfer_limit > 0);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_from_account_completed ON transfers(from_account_id, completed_at) WHERE status = 'completed';
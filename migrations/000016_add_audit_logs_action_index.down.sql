@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_audit_logs_action_created_at;
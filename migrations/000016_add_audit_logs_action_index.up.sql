@@ -0,0 +1,2 @@
+-- Supports listing audit entries of one action, newest first (e.g. refresh token reuse)
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action_created_at ON audit_logs(action, created_at DESC);
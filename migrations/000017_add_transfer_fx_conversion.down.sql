@@ -0,0 +1,3 @@
+ALTER TABLE transfers DROP COLUMN IF EXISTS converted_currency;
+ALTER TABLE transfers DROP COLUMN IF EXISTS converted_amount;
+ALTER TABLE transfers DROP COLUMN IF EXISTS exchange_rate;
@@ -0,0 +1,4 @@
+-- Cross-currency transfers: the rate applied and the amount credited in the destination currency
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(24,10) CHECK (exchange_rate > 0);
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS converted_amount DECIMAL(19,4) CHECK (converted_amount > 0);
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS converted_currency VARCHAR(3);
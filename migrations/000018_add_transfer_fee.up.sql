@@ -0,0 +1,2 @@
+-- Fee charged to the sender on top of the transfer amount
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS fee DECIMAL(19,4) NOT NULL DEFAULT 0 CHECK (fee >= 0);
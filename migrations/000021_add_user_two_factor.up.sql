@@ -0,0 +1,5 @@
+-- TOTP two-factor authentication. The secret is stored encrypted; it is set
+-- when enrollment starts and two_factor_enabled flips once the first code is
+-- confirmed.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE;
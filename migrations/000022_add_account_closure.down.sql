@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_accounts_user_id_open;
+ALTER TABLE accounts DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+-- Closed accounts are soft-deleted so their ledger history is kept
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_accounts_user_id_open ON accounts(user_id, created_at DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,18 @@
+-- Balance alert subscriptions, evaluated whenever an account's balance changes
+CREATE TABLE IF NOT EXISTS balance_alerts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    condition VARCHAR(10) NOT NULL CHECK (condition IN ('above', 'below', 'crosses')),
+    threshold DECIMAL(19,4) NOT NULL CHECK (threshold >= 0),
+    currency VARCHAR(3) NOT NULL,
+    recurring BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_email BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_triggered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_alerts_user_id ON balance_alerts(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_balance_alerts_account_active ON balance_alerts(account_id) WHERE active;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_transactions_account_category;
+
+ALTER TABLE transfers DROP COLUMN IF EXISTS tags;
+ALTER TABLE transfers DROP COLUMN IF EXISTS category;
+ALTER TABLE transactions DROP COLUMN IF EXISTS tags;
+ALTER TABLE transactions DROP COLUMN IF EXISTS category;
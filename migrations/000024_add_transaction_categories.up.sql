@@ -0,0 +1,8 @@
+-- Optional spending category and tags. Transfers carry them until they post
+-- so scheduled transfers can apply them to the sender's ledger entry.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS category VARCHAR(50);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS category VARCHAR(50);
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_category ON transactions(account_id, category, created_at DESC);
@@ -0,0 +1,4 @@
+-- Row version for optimistic concurrency. Every write to an account checks
+-- the version it read and increments it, so a write based on a stale read
+-- fails instead of overwriting a concurrent change.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- Fails if any account is overdrawn; settle those balances first.
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_within_overdraft;
+ALTER TABLE accounts ADD CONSTRAINT accounts_balance_check CHECK (balance >= 0);
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS overdraft_limit;
+ALTER TABLE accounts DROP COLUMN IF EXISTS min_balance;
@@ -0,0 +1,8 @@
+-- Balance floors. Savings accounts may not be debited below min_balance, and
+-- an account with an overdraft_limit may go that far below zero. The balance
+-- check moves from >= 0 to >= -overdraft_limit to match.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS min_balance DECIMAL(19,4) NOT NULL DEFAULT 0 CHECK (min_balance >= 0);
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS overdraft_limit DECIMAL(19,4) NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0);
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_balance_within_overdraft CHECK (balance >= -overdraft_limit);